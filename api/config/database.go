@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 
+	"api/database"
 	"api/models"
 
 	"gorm.io/driver/sqlite"
@@ -33,6 +34,9 @@ func ConnectDatabase() {
 	}
 
 	// Migration automatique des modèles
+	if err := database.SetupJoinTables(DB); err != nil {
+		log.Fatal("Erreur lors de la migration de la base de données:", err)
+	}
 	err = DB.AutoMigrate(
 		// Base models
 		&models.User{},
@@ -47,11 +51,57 @@ func ConnectDatabase() {
 		&models.Mission{},
 		&models.Course{},
 		&models.Payment{},
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.InvoiceSequence{},
+		&models.Payout{},
+		&models.PayoutLine{},
+		&models.AdvanceAllocation{},
+		&models.SEPATransferBatch{},
+		&models.SEPAMandate{},
+		&models.SEPADebitBatch{},
+		&models.TaxCertificate{},
+		&models.TaxCertificateLine{},
+		&models.LedgerAccount{},
+		&models.LedgerEntry{},
+		&models.LedgerLine{},
+		&models.HourPackage{},
+		&models.HourPackagePurchase{},
+		&models.WalletTransaction{},
+		&models.PricingRule{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.PromoDiscount{},
+		&models.Referral{},
 		&models.Report{},
+		&models.ReportRevision{},
+		&models.ReportReminder{},
+		&models.Skill{},
+		&models.ReportTemplate{},
+		&models.ReportTemplateSection{},
+		&models.ReportTemplateItem{},
+		&models.ReportAnswer{},
 		&models.Offer{},
+		&models.OfferApplication{},
+		&models.OfferRevision{},
 		&models.Option{},
 		&models.Resource{},
+		&models.CalendarFeed{},
+		&models.TimesheetEntry{},
+		&models.CourseEvent{},
+		&models.CourseDispute{},
+		&models.RescheduleRequest{},
+		&models.CancellationPolicy{},
+		&models.CourseCancellation{},
+		&models.CourseAttendance{},
+		&models.Notification{},
 	)
+	if err == nil {
+		err = database.MigrateMoneyColumns(DB)
+	}
+	if err == nil {
+		err = database.SetupOfferSearch(DB)
+	}
 
 	if err != nil {
 		log.Fatal("Erreur lors de la migration de la base de données:", err)
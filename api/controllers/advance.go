@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAdvances godoc
+// @Summary      Rapprochement des avances
+// @Description  Liste les avances (acomptes des familles, avances aux enseignants) avec le montant imputé, le solde restant et le détail des factures ou relevés sur lesquels elles ont été déduites. Les utilisateurs non administrateurs ne voient que leurs avances.
+// @Tags         advances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id     query     int  false  "Bénéficiaire (administrateurs uniquement)"
+// @Param        mission_id  query     int  false  "Mission"
+// @Success      200  {array}   models.AdvanceReport
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /advances [get]
+func ListAdvances(c *gin.Context) {
+	query := database.DB.Model(&models.Payment{})
+	if !middleware.IsAdmin(c) {
+		userID, _ := middleware.GetUserID(c)
+		query = query.Where("user_id = ?", userID)
+	} else if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if missionID := c.Query("mission_id"); missionID != "" {
+		query = query.Where("mission_id = ?", missionID)
+	}
+	reports, err := services.AdvanceReports(database.DB, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des avances"})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetAdvanceByID godoc
+// @Summary      Détail d'une avance
+// @Description  Montant imputé, solde restant et imputations successives d'une avance
+// @Tags         advances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'avance"
+// @Success      200  {object}  models.AdvanceReport
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /advances/{id} [get]
+func GetAdvanceByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	query := database.DB.Model(&models.Payment{}).Where("id = ?", id)
+	if !middleware.IsAdmin(c) {
+		userID, _ := middleware.GetUserID(c)
+		query = query.Where("user_id = ?", userID)
+	}
+	reports, err := services.AdvanceReports(database.DB, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération de l'avance"})
+		return
+	}
+	if len(reports) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avance non trouvée"})
+		return
+	}
+	c.JSON(http.StatusOK, reports[0])
+}
+
+// CreateAdvance godoc
+// @Summary      Enregistrement d'une avance
+// @Description  Enregistre une avance réglée hors prestataire de paiement : acompte versé par une famille, imputé sur ses prochaines factures, ou avance versée à un enseignant, déduite de ses prochains relevés. L'avance peut être réservée à une mission.
+// @Tags         advances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.AdvanceCreateRequest  true  "Avance"
+// @Success      201  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /advances [post]
+func CreateAdvance(c *gin.Context) {
+	var req models.AdvanceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Amount.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le montant doit être strictement positif"})
+		return
+	}
+	advance, err := services.RecordAdvance(database.DB, req)
+	if err != nil {
+		if errors.Is(err, services.ErrAdvanceRecipient) || errors.Is(err, services.ErrAdvanceMission) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement de l'avance"})
+		return
+	}
+	c.JSON(http.StatusCreated, advance)
+}
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckInCourse godoc
+// @Summary      Pointage d'arrivée
+// @Description  L'enseignant pointe son arrivée (position GPS facultative) ; le cours passe en cours. Une arrivée tardive ou hors de la zone de l'adresse est signalée aux administrateurs.
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true   "ID du cours"
+// @Param        request  body      models.AttendanceCheckRequest  false  "Position GPS"
+// @Success      200  {object}  CourseResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /courses/{id}/check-in [post]
+func CheckInCourse(c *gin.Context) {
+	course, req, ok := loadAttendanceCourse(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	attendance, err := services.CheckIn(database.DB, &course, currentID, req, time.Now())
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CourseResponse{Course: course, Attendance: attendance})
+}
+
+// CheckOutCourse godoc
+// @Summary      Pointage de départ
+// @Description  L'enseignant pointe son départ (position GPS facultative) ; la durée mesurée pré-remplit la feuille de temps soumise à la famille ; le cours reste en cours jusqu'à son approbation
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true   "ID du cours"
+// @Param        request  body      models.AttendanceCheckRequest  false  "Position GPS"
+// @Success      200  {object}  CourseResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /courses/{id}/check-out [post]
+func CheckOutCourse(c *gin.Context) {
+	course, req, ok := loadAttendanceCourse(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	attendance, entry, err := services.CheckOut(database.DB, &course, currentID, req, time.Now())
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CourseResponse{Course: course, Attendance: attendance, Timesheet: entry})
+}
+
+// GetCourseAttendance godoc
+// @Summary      Pointage d'un cours
+// @Description  Récupère les pointages d'arrivée et de départ d'un cours (famille et enseignant du cours, administrateurs)
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du cours"
+// @Success      200  {object}  models.CourseAttendance
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /courses/{id}/attendance [get]
+func GetCourseAttendance(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return
+	}
+	var course models.Course
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+	if !isCourseParticipant(c, &course) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	var attendance models.CourseAttendance
+	if err := database.DB.Where("course_id = ?", courseID).First(&attendance).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucun pointage pour ce cours"})
+		return
+	}
+	c.JSON(http.StatusOK, attendance)
+}
+
+// ListAttendances godoc
+// @Summary      Liste des pointages
+// @Description  Récupère les pointages, par exemple ceux signalés en attente de vérification (admin seulement)
+// @Tags         attendances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        flagged        query     bool  false  "Uniquement les pointages signalés"
+// @Param        reviewed       query     bool  false  "Filtrer sur l'état de vérification"
+// @Param        enseignant_id  query     int   false  "ID de l'enseignant"
+// @Success      200  {array}   models.CourseAttendance
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /attendances [get]
+func ListAttendances(c *gin.Context) {
+	var attendances []models.CourseAttendance
+	query := database.DB
+	if c.Query("flagged") == "true" {
+		query = query.Where("flagged = ?", true)
+	}
+	switch c.Query("reviewed") {
+	case "true":
+		query = query.Where("reviewed_at IS NOT NULL")
+	case "false":
+		query = query.Where("reviewed_at IS NULL")
+	}
+	if enseignantID := c.Query("enseignant_id"); enseignantID != "" {
+		query = query.Where("enseignant_id = ?", enseignantID)
+	}
+	if err := query.Preload("Course").Order("check_in_at DESC").Find(&attendances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des pointages"})
+		return
+	}
+	c.JSON(http.StatusOK, attendances)
+}
+
+// GetAttendanceByID godoc
+// @Summary      Détails d'un pointage
+// @Tags         attendances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du pointage"
+// @Success      200  {object}  models.CourseAttendance
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /attendances/{id} [get]
+func GetAttendanceByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var attendance models.CourseAttendance
+	if err := database.DB.Preload("Course").First(&attendance, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pointage non trouvé"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != attendance.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	c.JSON(http.StatusOK, attendance)
+}
+
+// ReviewAttendance godoc
+// @Summary      Vérification d'un pointage
+// @Description  Un administrateur marque un pointage signalé comme vérifié
+// @Tags         attendances
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true   "ID du pointage"
+// @Param        request  body      models.AttendanceReviewRequest  false  "Commentaire"
+// @Success      200  {object}  models.CourseAttendance
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /attendances/{id}/review [put]
+func ReviewAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.AttendanceReviewRequest
+	c.ShouldBindJSON(&req)
+	var attendance models.CourseAttendance
+	if err := database.DB.First(&attendance, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pointage non trouvé"})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	attendance.Review(adminID, req.Note)
+	if err := database.DB.Save(&attendance).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du pointage"})
+		return
+	}
+	c.JSON(http.StatusOK, attendance)
+}
+
+// loadAttendanceCourse charge le cours désigné par :id et la position transmise, et
+// vérifie que l'utilisateur en est l'enseignant. Écrit la réponse d'erreur le cas échéant.
+func loadAttendanceCourse(c *gin.Context) (models.Course, models.AttendanceCheckRequest, bool) {
+	var course models.Course
+	var req models.AttendanceCheckRequest
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return course, req, false
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return course, req, false
+		}
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La latitude et la longitude doivent être fournies ensemble"})
+		return course, req, false
+	}
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return course, req, false
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if currentID != course.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seul l'enseignant du cours peut pointer"})
+		return course, req, false
+	}
+	return course, req, true
+}
+
+// respondAttendanceError traduit les erreurs de pointage en 409
+func respondAttendanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCheckInNotAllowed), errors.Is(err, services.ErrCheckInTooEarly),
+		errors.Is(err, services.ErrAlreadyCheckedIn), errors.Is(err, services.ErrNotCheckedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du pointage"})
+	}
+}
@@ -3,7 +3,9 @@ package controllers
 import (
 	"api/database"
 	"api/models"
+	"api/services"
 	"api/utils"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -21,6 +23,7 @@ type RegisterRequest struct {
 	FamilyName     string          `json:"family_name,omitempty"`    // Pour les familles
 	Specialization string          `json:"specialization,omitempty"` // Pour les enseignants
 	Qualifications string          `json:"qualifications,omitempty"` // Pour les enseignants
+	ReferralCode   string          `json:"referral_code,omitempty"`  // Code de parrainage reçu par une famille
 }
 
 // LoginRequest représente la structure de la requête de connexion
@@ -37,7 +40,7 @@ type AuthResponse struct {
 
 // Register godoc
 // @Summary      Inscription d'un utilisateur
-// @Description  Créer un nouveau compte utilisateur
+// @Description  Créer un nouveau compte utilisateur. Une famille invitée est rattachée à son parrain par le code de parrainage ou l'adresse e-mail invitée.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -66,6 +69,21 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// Retrouver l'invitation de parrainage de la famille, par son code ou son adresse e-mail
+	var referral *models.Referral
+	if req.Role == models.RoleFamille {
+		var err error
+		referral, err = services.FindPendingReferral(database.DB, req.ReferralCode, req.Email)
+		if errors.Is(err, models.ErrReferralNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du code de parrainage"})
+			return
+		}
+	}
+
 	// Créer le nouvel utilisateur (le mot de passe sera haché automatiquement par BeforeCreate)
 	user := models.User{
 		Username:    req.Username,
@@ -92,6 +110,12 @@ func Register(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du profil famille"})
 			return
 		}
+		if referral != nil {
+			if err := services.RegisterReferral(database.DB, referral, user.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du parrainage"})
+				return
+			}
+		}
 	case models.RoleEnseignant:
 		enseignant := models.Enseignant{
 			UserID:         user.ID,
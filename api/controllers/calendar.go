@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCalendarFeed godoc
+// @Summary      Abonnement calendrier
+// @Description  Retourne (et crée si besoin) l'URL secrète d'abonnement iCalendar de l'utilisateur connecté
+// @Tags         calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  models.CalendarFeedResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /calendar/feed [get]
+func GetCalendarFeed(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
+		return
+	}
+
+	var feed models.CalendarFeed
+	if err := database.DB.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		token, err := utils.GenerateSecureToken(24)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du jeton"})
+			return
+		}
+		feed = models.CalendarFeed{UserID: userID, Token: token}
+		if err := database.DB.Create(&feed).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de l'abonnement"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(c, feed))
+}
+
+// RegenerateCalendarFeed godoc
+// @Summary      Régénération de l'abonnement calendrier
+// @Description  Remplace le jeton secret de l'abonnement iCalendar ; l'ancienne URL cesse de fonctionner
+// @Tags         calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  models.CalendarFeedResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /calendar/feed/regenerate [post]
+func RegenerateCalendarFeed(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
+		return
+	}
+
+	token, err := utils.GenerateSecureToken(24)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du jeton"})
+		return
+	}
+
+	var feed models.CalendarFeed
+	if err := database.DB.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		feed = models.CalendarFeed{UserID: userID}
+	}
+	feed.Token = token
+	if err := database.DB.Save(&feed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la régénération de l'abonnement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(c, feed))
+}
+
+// GetCalendarFeedICS godoc
+// @Summary      Flux iCalendar
+// @Description  Flux iCalendar (RFC 5545) des cours de l'utilisateur, accessible sans authentification via le jeton secret
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        token  path      string  true  "Jeton secret (suffixe .ics facultatif)"
+// @Success      200  {string}  string
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /calendar/feeds/{token} [get]
+func GetCalendarFeedICS(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var feed models.CalendarFeed
+	if token == "" || database.DB.Where("token = ?", token).First(&feed).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendrier non trouvé"})
+		return
+	}
+
+	var courses []models.Course
+	if err := database.DB.Preload("Address").Preload("Enseignant.User").Preload("Famille").Preload("Mission").
+		Where("famille_id = ? OR enseignant_id = ?", feed.UserID, feed.UserID).
+		Order("scheduled_time").Find(&courses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des cours"})
+		return
+	}
+
+	now := time.Now()
+	feed.LastAccess = &now
+	database.DB.Model(&feed).Update("last_access", now)
+
+	cal := utils.ICalCalendar{
+		Name:     "Mes cours",
+		Method:   "PUBLISH",
+		Location: utils.CalendarLocation(),
+	}
+	for _, course := range courses {
+		cal.Events = append(cal.Events, courseICalEvent(course, feed.UserID))
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(cal.Render()))
+}
+
+// ExportCourseICS godoc
+// @Summary      Export .ics d'un cours
+// @Description  Télécharge un cours au format iCalendar
+// @Tags         courses
+// @Produce      text/calendar
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du cours"
+// @Success      200  {string}  string
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /courses/{id}/ics [get]
+func ExportCourseICS(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return
+	}
+	var course models.Course
+	if err := database.DB.Preload("Address").Preload("Enseignant.User").Preload("Famille").Preload("Mission").
+		First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != course.FamilleID && currentID != course.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+
+	cal := utils.ICalCalendar{
+		Method:   "PUBLISH",
+		Location: utils.CalendarLocation(),
+		Events:   []utils.ICalEvent{courseICalEvent(course, currentID)},
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"cours-%d.ics\"", course.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(cal.Render()))
+}
+
+// courseICalEvent convertit un cours en évènement iCalendar du point de vue de viewerID
+func courseICalEvent(course models.Course, viewerID uint) utils.ICalEvent {
+	domain := os.Getenv("CALENDAR_UID_DOMAIN")
+	if domain == "" {
+		domain = "help-us.local"
+	}
+
+	summary := "Cours"
+	if viewerID == course.EnseignantID && course.Famille.FamilyName != "" {
+		summary = "Cours - " + course.Famille.FamilyName
+	} else if course.Enseignant.User.Username != "" {
+		summary = "Cours avec " + course.Enseignant.User.Username
+	}
+
+	ev := utils.ICalEvent{
+		UID:          fmt.Sprintf("course-%d@%s", course.ID, domain),
+		Summary:      summary,
+		Description:  course.Mission.Description,
+		Location:     course.Location,
+		Start:        course.ScheduledTime,
+		End:          course.ScheduledTime.Add(time.Duration(course.Duration) * time.Minute),
+		Status:       "CONFIRMED",
+		Sequence:     int(course.UpdatedAt.Sub(course.CreatedAt) / time.Minute),
+		LastModified: course.UpdatedAt,
+	}
+	if course.Status == models.CourseStatusCancelled {
+		ev.Status = "CANCELLED"
+	}
+	if course.Address.ID != 0 {
+		ev.Location = fmt.Sprintf("%s, %s %s, %s", course.Address.Street, course.Address.PostalCode, course.Address.City, course.Address.Country)
+		if course.Address.Latitude != 0 || course.Address.Longitude != 0 {
+			ev.HasGeo = true
+			ev.Latitude = course.Address.Latitude
+			ev.Longitude = course.Address.Longitude
+		}
+	}
+	return ev
+}
+
+// calendarFeedResponse construit l'URL publique d'abonnement (API_BASE_URL ou hôte de la requête)
+func calendarFeedResponse(c *gin.Context, feed models.CalendarFeed) models.CalendarFeedResponse {
+	base := os.Getenv("API_BASE_URL")
+	if base == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + c.Request.Host
+	}
+	return models.CalendarFeedResponse{
+		Token:     feed.Token,
+		URL:       strings.TrimSuffix(base, "/") + "/api/v1/calendar/feeds/" + feed.Token + ".ics",
+		CreatedAt: feed.CreatedAt,
+	}
+}
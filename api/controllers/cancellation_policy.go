@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"api/database"
+	"api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCancellationPolicies godoc
+// @Summary      Liste des politiques d'annulation
+// @Description  Récupère les règles appliquées lors de l'annulation d'un cours (préavis, frais, avoirs, pénalités)
+// @Tags         cancellation-policies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Uniquement les règles actives"
+// @Success      200  {array}   models.CancellationPolicy
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /cancellation-policies [get]
+func ListCancellationPolicies(c *gin.Context) {
+	var policies []models.CancellationPolicy
+	query := database.DB
+	if c.Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("id").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des politiques d'annulation"})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// CreateCancellationPolicy godoc
+// @Summary      Création d'une politique d'annulation
+// @Description  Ajoute une règle d'annulation (admin seulement)
+// @Tags         cancellation-policies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CancellationPolicyRequest  true  "Règle d'annulation"
+// @Success      201  {object}  models.CancellationPolicy
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /cancellation-policies [post]
+func CreateCancellationPolicy(c *gin.Context) {
+	var req models.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var policy models.CancellationPolicy
+	applyCancellationPolicyRequest(&policy, req)
+	active := policy.IsActive
+	if err := database.DB.Create(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la politique d'annulation"})
+		return
+	}
+	// La valeur par défaut de la colonne ignore un is_active=false à la création
+	if !active {
+		database.DB.Model(&policy).Update("is_active", false)
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdateCancellationPolicy godoc
+// @Summary      Mise à jour d'une politique d'annulation
+// @Description  Modifie une règle d'annulation existante (admin seulement). Les annulations passées ne sont pas recalculées.
+// @Tags         cancellation-policies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true  "ID de la règle"
+// @Param        request  body      models.CancellationPolicyRequest  true  "Règle d'annulation"
+// @Success      200  {object}  models.CancellationPolicy
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /cancellation-policies/{id} [put]
+func UpdateCancellationPolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var policy models.CancellationPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Politique d'annulation non trouvée"})
+		return
+	}
+	applyCancellationPolicyRequest(&policy, req)
+	if err := database.DB.Save(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la politique d'annulation"})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteCancellationPolicy godoc
+// @Summary      Suppression d'une politique d'annulation
+// @Description  Supprime une règle d'annulation (admin seulement)
+// @Tags         cancellation-policies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la règle"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /cancellation-policies/{id} [delete]
+func DeleteCancellationPolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if err := database.DB.Delete(&models.CancellationPolicy{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression de la politique d'annulation"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func applyCancellationPolicyRequest(policy *models.CancellationPolicy, req models.CancellationPolicyRequest) {
+	policy.Name = req.Name
+	policy.CancelledBy = req.CancelledBy
+	policy.ReasonCode = req.ReasonCode
+	policy.NoticeWindowHours = req.NoticeWindowHours
+	policy.FeePercent = req.FeePercent
+	policy.CreditPercent = req.CreditPercent
+	policy.PenaltyPercent = req.PenaltyPercent
+	policy.IsActive = req.IsActive == nil || *req.IsActive
+}
@@ -2,16 +2,27 @@ package controllers
 
 import (
 	"api/database"
+	"api/middleware"
 	"api/models"
+	"api/services"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CourseResponse struct {
 	models.Course
-	Payments []models.Payment `json:"payments,omitempty"`
+	Payments     []models.Payment           `json:"payments,omitempty"`
+	Timesheet    *models.TimesheetEntry     `json:"timesheet,omitempty"`
+	Disputes     []models.CourseDispute     `json:"disputes,omitempty"`
+	History      []models.CourseEvent       `json:"history,omitempty"`
+	Attendance   *models.CourseAttendance   `json:"attendance,omitempty"`
+	Cancellation *models.CourseCancellation `json:"cancellation,omitempty"`
 }
 
 // ListCourses godoc
@@ -57,7 +68,7 @@ func ListCourses(c *gin.Context) {
 
 // GetCourseByID godoc
 // @Summary      Détails d'un cours
-// @Description  Récupère les détails d'un cours spécifique
+// @Description  Récupère les détails d'un cours spécifique ; la feuille de temps, les litiges, l'historique, le pointage et l'annulation ne sont renvoyés qu'à la famille et à l'enseignant du cours et aux administrateurs
 // @Tags         courses
 // @Accept       json
 // @Produce      json
@@ -78,7 +89,33 @@ func GetCourseByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
-	c.JSON(http.StatusOK, CourseResponse{Course: course, Payments: course.Payments})
+	resp := CourseResponse{Course: course, Payments: course.Payments}
+	if !isCourseParticipant(c, &course) {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	var entry models.TimesheetEntry
+	if err := database.DB.Where("course_id = ?", course.ID).First(&entry).Error; err == nil {
+		resp.Timesheet = &entry
+	}
+	database.DB.Where("course_id = ?", course.ID).Order("created_at").Find(&resp.Disputes)
+	database.DB.Where("course_id = ?", course.ID).Order("created_at, id").Find(&resp.History)
+	var attendance models.CourseAttendance
+	if err := database.DB.Where("course_id = ?", course.ID).First(&attendance).Error; err == nil {
+		resp.Attendance = &attendance
+	}
+	var cancellation models.CourseCancellation
+	if err := database.DB.Preload("Policy").Where("course_id = ?", course.ID).First(&cancellation).Error; err == nil {
+		resp.Cancellation = &cancellation
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// isCourseParticipant indique si l'utilisateur est la famille ou l'enseignant du
+// cours, ou un administrateur
+func isCourseParticipant(c *gin.Context, course *models.Course) bool {
+	currentID, _ := middleware.GetUserID(c)
+	return middleware.IsAdmin(c) || currentID == course.FamilleID || currentID == course.EnseignantID
 }
 
 // CreateCourse godoc
@@ -125,7 +162,7 @@ func CreateCourse(c *gin.Context) {
 
 // UpdateCourse godoc
 // @Summary      Mise à jour d'un cours
-// @Description  Met à jour les informations d'un cours existant
+// @Description  Met à jour les informations d'un cours existant. Le changement d'horaire et de statut est réservé aux administrateurs ; les parties passent par une demande de report, et l'annulation par PUT /courses/{id}/cancel. Un cours terminé ou annulé ne change plus de statut, et un cours ne passe à l'état terminé que par la validation de la famille.
 // @Tags         courses
 // @Accept       json
 // @Produce      json
@@ -134,7 +171,9 @@ func CreateCourse(c *gin.Context) {
 // @Param        request  body      models.CourseUpdateRequest  true  "Données de mise à jour"
 // @Success      200  {object}  CourseResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Router       /courses/{id} [put]
 func UpdateCourse(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -152,6 +191,10 @@ func UpdateCourse(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
+	if (req.ScheduledTime != nil || req.Duration != nil) && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Le changement d'horaire doit passer par une demande de report (POST /courses/{id}/reschedule-requests)"})
+		return
+	}
 	if req.ScheduledTime != nil {
 		course.ScheduledTime = *req.ScheduledTime
 	}
@@ -161,7 +204,23 @@ func UpdateCourse(c *gin.Context) {
 	if req.Location != "" {
 		course.Location = req.Location
 	}
-	if req.Status != "" {
+	if req.Status != "" && req.Status != course.Status {
+		if req.Status == models.CourseStatusCancelled {
+			c.JSON(http.StatusConflict, gin.H{"error": "L'annulation d'un cours doit passer par PUT /courses/{id}/cancel"})
+			return
+		}
+		if !middleware.IsAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Seul un administrateur peut modifier le statut d'un cours"})
+			return
+		}
+		if req.Status == models.CourseStatusCompleted {
+			c.JSON(http.StatusConflict, gin.H{"error": models.ErrCourseCompletion.Error()})
+			return
+		}
+		if !course.CanTransitionTo(req.Status) {
+			c.JSON(http.StatusConflict, gin.H{"error": models.ErrCourseStatusTransition.Error()})
+			return
+		}
 		course.Status = req.Status
 	}
 	if err := database.DB.Save(&course).Error; err != nil {
@@ -198,7 +257,7 @@ func DeleteCourse(c *gin.Context) {
 
 // ScheduleCourse godoc
 // @Summary      Planification d'un cours
-// @Description  Planifie un cours à une date spécifique
+// @Description  Planifie un cours à une date spécifique. Un cours terminé ou annulé ne peut plus être replanifié.
 // @Tags         courses
 // @Accept       json
 // @Produce      json
@@ -208,6 +267,7 @@ func DeleteCourse(c *gin.Context) {
 // @Success      200  {object}  CourseResponse
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Router       /courses/{id}/schedule [put]
 func ScheduleCourse(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -220,7 +280,10 @@ func ScheduleCourse(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
-	course.Status = models.CourseStatusScheduled
+	if err := course.Schedule(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err := database.DB.Save(&course).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la planification du cours"})
 		return
@@ -230,15 +293,18 @@ func ScheduleCourse(c *gin.Context) {
 
 // CancelCourse godoc
 // @Summary      Annulation d'un cours
-// @Description  Annule un cours planifié
+// @Description  Annule un cours planifié en enregistrant l'auteur et le motif. La politique d'annulation applicable génère automatiquement les frais à régler par la famille, l'avoir crédité en heures sur son porte-monnaie et la pénalité déduite du prochain relevé de l'enseignant.
 // @Tags         courses
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "ID du cours"
+// @Param        id       path      int                         true  "ID du cours"
+// @Param        request  body      models.CourseCancelRequest  true  "Motif de l'annulation"
 // @Success      200  {object}  CourseResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Router       /courses/{id}/cancel [put]
 func CancelCourse(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -246,62 +312,194 @@ func CancelCourse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
 		return
 	}
+	var req models.CourseCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	var course models.Course
 	if err := database.DB.First(&course, courseID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
-	course.Status = models.CourseStatusCancelled
-	if err := database.DB.Save(&course).Error; err != nil {
+	party, ok := cancellationParty(c, &course, req.CancelledBy)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	cancellation, err := services.CancelCourse(database.DB, &course, currentID, party, req)
+	if err != nil {
+		if errors.Is(err, services.ErrCourseNotCancellable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'annulation du cours"})
 		return
 	}
-	c.JSON(http.StatusOK, CourseResponse{Course: course})
+	resp := CourseResponse{Course: course, Cancellation: cancellation}
+	database.DB.Where("course_id = ?", course.ID).Find(&resp.Payments)
+	c.JSON(http.StatusOK, resp)
 }
 
-// CompleteCourse godoc
-// @Summary      Validation d'un cours
-// @Description  Marque un cours comme terminé
+// GetCancellationPreview godoc
+// @Summary      Simulation d'annulation d'un cours
+// @Description  Calcule les frais, avoirs et pénalités qu'entraînerait l'annulation du cours maintenant
 // @Tags         courses
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "ID du cours"
-// @Success      200  {object}  CourseResponse
+// @Param        id            path      int     true   "ID du cours"
+// @Param        reason_code   query     string  true   "Motif (illness, emergency, schedule_conflict, family_no_show, teacher_no_show, force_majeure, other)"
+// @Param        cancelled_by  query     string  false  "Partie qui annule (admin seulement : famille, enseignant, admin)"
+// @Success      200  {object}  models.CancellationPreviewResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
-// @Router       /courses/{id}/complete [put]
-func CompleteCourse(c *gin.Context) {
+// @Router       /courses/{id}/cancellation-preview [get]
+func GetCancellationPreview(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
 		return
 	}
+	reason := models.CancellationReason(c.Query("reason_code"))
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le motif (reason_code) est requis"})
+		return
+	}
 	var course models.Course
 	if err := database.DB.First(&course, courseID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
+	party, ok := cancellationParty(c, &course, models.CancellationParty(c.Query("cancelled_by")))
+	if !ok {
+		return
+	}
+	preview, err := services.PreviewCancellation(database.DB, &course, party, reason, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de l'annulation"})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// cancellationParty détermine au nom de quelle partie l'utilisateur annule le cours.
+// Les administrateurs peuvent annuler au nom d'une partie ; écrit la réponse d'erreur le cas échéant.
+func cancellationParty(c *gin.Context, course *models.Course, requested models.CancellationParty) (models.CancellationParty, bool) {
+	if middleware.IsAdmin(c) {
+		if requested == "" {
+			return models.CancellationByAdmin, true
+		}
+		return requested, true
+	}
+	currentID, _ := middleware.GetUserID(c)
+	switch currentID {
+	case course.FamilleID:
+		return models.CancellationByFamille, true
+	case course.EnseignantID:
+		return models.CancellationByEnseignant, true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Seules les parties du cours peuvent l'annuler"})
+	return "", false
+}
+
+// CompleteCourse godoc
+// @Summary      Validation d'un cours par la famille
+// @Description  La famille confirme le cours déclaré par l'enseignant ; le cours passe à l'état terminé et devient facturable
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du cours"
+// @Success      200  {object}  CourseResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /courses/{id}/complete [put]
+func CompleteCourse(c *gin.Context) {
+	course, entry, ok := loadDeclaredCourse(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if err := services.ApproveTimesheetEntry(database.DB, &entry, currentID); err != nil {
+		respondApprovalError(c, err, "Erreur lors de la validation du cours")
+		return
+	}
 	course.Status = models.CourseStatusCompleted
-	if err := database.DB.Save(&course).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la validation du cours"})
+	c.JSON(http.StatusOK, CourseResponse{Course: course, Timesheet: &entry})
+}
+
+// DeclineCourse godoc
+// @Summary      Refus d'un cours par la famille
+// @Description  La famille refuse le cours déclaré en indiquant un motif ; un litige est ouvert pour les administrateurs
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "ID du cours"
+// @Param        request  body      models.CourseDeclineRequest  true  "Motif du refus"
+// @Success      200  {object}  CourseResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /courses/{id}/decline [put]
+func DeclineCourse(c *gin.Context) {
+	var req models.CourseDeclineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, CourseResponse{Course: course})
+	course, entry, ok := loadDeclaredCourse(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	dispute, err := services.DeclineCourse(database.DB, &entry, currentID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du refus du cours"})
+		return
+	}
+	c.JSON(http.StatusOK, CourseResponse{Course: course, Timesheet: &entry, Disputes: []models.CourseDispute{*dispute}})
+}
+
+// loadDeclaredCourse charge le cours :id et sa déclaration en attente de validation
+func loadDeclaredCourse(c *gin.Context) (models.Course, models.TimesheetEntry, bool) {
+	var course models.Course
+	var entry models.TimesheetEntry
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return course, entry, false
+	}
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return course, entry, false
+	}
+	if err := database.DB.Where("course_id = ?", course.ID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "L'enseignant n'a pas encore déclaré ce cours"})
+		return course, entry, false
+	}
+	return course, entry, checkTimesheetReviewable(c, &entry)
 }
 
 // DeclareCourse godoc
 // @Summary      Déclaration des heures d'un cours
-// @Description  Déclare les heures effectuées pour un cours
+// @Description  L'enseignant déclare l'horaire réel du cours (début/fin ou nombre d'heures) ; la famille dispose ensuite d'un délai pour approuver ou contester
 // @Tags         courses
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id       path      int                          true  "ID du cours"
-// @Param        request  body      map[string]float64           true  "Heures effectuées"
+// @Param        id       path      int                             true  "ID du cours"
+// @Param        request  body      models.TimesheetDeclareRequest  true  "Heures effectuées"
 // @Success      200  {object}  CourseResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Router       /courses/{id}/declare [post]
 func DeclareCourse(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -309,10 +507,8 @@ func DeclareCourse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
 		return
 	}
-	var payload struct {
-		Hours float64 `json:"hours" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	var req models.TimesheetDeclareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -321,14 +517,71 @@ func DeclareCourse(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
 		return
 	}
-	// Ici, on pourrait stocker les heures déclarées dans un champ ou un modèle associé
-	// Pour l'instant, on change juste le statut
-	course.Status = models.CourseStatusInProgress
-	if err := database.DB.Save(&course).Error; err != nil {
+
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != course.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seul l'enseignant du cours peut déclarer les heures"})
+		return
+	}
+	if course.Status == models.CourseStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Impossible de déclarer un cours annulé"})
+		return
+	}
+
+	var start, end time.Time
+	switch {
+	case req.DeclaredStart != nil && req.DeclaredEnd != nil:
+		start, end = *req.DeclaredStart, *req.DeclaredEnd
+	case req.Hours > 0:
+		start = course.ScheduledTime
+		if req.DeclaredStart != nil {
+			start = *req.DeclaredStart
+		}
+		end = start.Add(time.Duration(req.Hours * float64(time.Hour)))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Indiquez declared_start et declared_end, ou hours"})
+		return
+	}
+	if !end.After(start) || end.Sub(start) > 12*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Horaires déclarés invalides"})
+		return
+	}
+
+	var entry models.TimesheetEntry
+	if err := database.DB.Where("course_id = ?", course.ID).First(&entry).Error; err == nil {
+		switch entry.Status {
+		case models.TimesheetStatusApproved:
+			c.JSON(http.StatusConflict, gin.H{"error": "Les heures de ce cours ont déjà été approuvées"})
+			return
+		case models.TimesheetStatusDisputed:
+			c.JSON(http.StatusConflict, gin.H{"error": "Un litige est en cours sur ce cours"})
+			return
+		}
+	}
+	entry.CourseID = course.ID
+	entry.EnseignantID = course.EnseignantID
+	entry.FamilleID = course.FamilleID
+	entry.Declare(start, end, req.Notes, time.Now().Add(services.TimesheetApprovalDelay()))
+
+	// Un cours déjà démarré par le pointage d'arrivée est déjà en cours
+	if course.Status == models.CourseStatusScheduled {
+		course.Status = models.CourseStatusInProgress
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&course).Error; err != nil {
+			return err
+		}
+		return services.RecordCourseEvent(tx, course.ID, currentID, models.CourseEventDeclared,
+			fmt.Sprintf("Heures déclarées : %d min", entry.DurationMinutes))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la déclaration du cours"})
 		return
 	}
-	c.JSON(http.StatusOK, CourseResponse{Course: course})
+	c.JSON(http.StatusOK, CourseResponse{Course: course, Timesheet: &entry})
 }
 
 // GetCoursePayments godoc
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDisputes godoc
+// @Summary      Liste des litiges
+// @Description  Récupère les litiges ouverts suite au refus d'un cours par une famille (admin seulement)
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status     query     string  false  "Statut (open, resolved)"
+// @Param        course_id  query     int     false  "ID du cours"
+// @Success      200  {array}   models.CourseDispute
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /disputes [get]
+func ListDisputes(c *gin.Context) {
+	var disputes []models.CourseDispute
+	query := database.DB
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if courseID := c.Query("course_id"); courseID != "" {
+		query = query.Where("course_id = ?", courseID)
+	}
+	if err := query.Preload("Course").Order("created_at").Find(&disputes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des litiges"})
+		return
+	}
+	c.JSON(http.StatusOK, disputes)
+}
+
+// GetDisputeByID godoc
+// @Summary      Détails d'un litige
+// @Description  Récupère un litige ; accessible aux administrateurs et aux parties du cours
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du litige"
+// @Success      200  {object}  models.CourseDispute
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /disputes/{id} [get]
+func GetDisputeByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var dispute models.CourseDispute
+	if err := database.DB.Preload("Course").First(&dispute, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Litige non trouvé"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && (dispute.Course == nil ||
+		(currentID != dispute.Course.FamilleID && currentID != dispute.Course.EnseignantID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	c.JSON(http.StatusOK, dispute)
+}
+
+// ResolveDispute godoc
+// @Summary      Résolution d'un litige
+// @Description  Un administrateur tranche le litige : approbation (facturation, durée éventuellement corrigée) ou rejet (cours annulé, non facturé)
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "ID du litige"
+// @Param        request  body      models.DisputeResolveRequest  true  "Décision"
+// @Success      200  {object}  models.CourseDispute
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /disputes/{id}/resolve [put]
+func ResolveDispute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.DisputeResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dispute models.CourseDispute
+	if err := database.DB.First(&dispute, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Litige non trouvé"})
+		return
+	}
+	if dispute.Status != models.DisputeStatusOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ce litige est déjà résolu"})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	if err := services.ResolveDispute(database.DB, &dispute, adminID, req); err != nil {
+		respondApprovalError(c, err, "Erreur lors de la résolution du litige")
+		return
+	}
+	c.JSON(http.StatusOK, dispute)
+}
@@ -4,10 +4,13 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/services"
+	"api/utils"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // EnseignantResponse regroupe le user + profile + relations
@@ -76,7 +79,7 @@ func GetEnseignantByID(c *gin.Context) {
 	// relations
 	database.DB.Where("enseignant_id = ?", user.ID).Find(&prof.Missions)
 	database.DB.Where("enseignant_id = ?", user.ID).Find(&prof.Courses)
-	database.DB.Where("enseignant_id = ?", user.ID).Find(&prof.Reports)
+	database.DB.Scopes(visibleReports(c)).Where("enseignant_id = ?", user.ID).Find(&prof.Reports)
 	database.DB.Where("enseignant_id = ?", user.ID).Find(&prof.Options)
 
 	c.JSON(http.StatusOK, EnseignantResponse{User: user, Enseignant: prof, Missions: prof.Missions, Courses: prof.Courses, Reports: prof.Reports, Options: prof.Options})
@@ -282,20 +285,40 @@ func GetEnseignantCourses(c *gin.Context) {
 }
 
 // GetEnseignantPayments godoc
-// @Summary      Liste des paiements d'un enseignant
-// @Description  Récupère la liste des paiements associés à un enseignant
+// @Summary      Relevés de paiement d'un enseignant
+// @Description  Récupère les relevés de paiement (verrouillés ou payés) d'un enseignant avec leurs lignes, du plus récent au plus ancien
 // @Tags         enseignants
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "ID de l'enseignant"
-// @Success      200  {array}   models.Payment
+// @Param        id      path      int     true   "ID de l'enseignant"
+// @Param        status  query     string  false  "Statut (locked, paid)"
+// @Success      200  {array}   models.Payout
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
 // @Router       /enseignants/{id}/payments [get]
 func GetEnseignantPayments(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	var payments []models.Payment
-	database.DB.Where("user_id = ?", id).Find(&payments)
-	c.JSON(http.StatusOK, payments)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	query := database.DB.Where("enseignant_id = ?", id).
+		Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") })
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var payouts []models.Payout
+	if err := query.Order("period_start DESC, id DESC").Find(&payouts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des relevés"})
+		return
+	}
+	c.JSON(http.StatusOK, payouts)
 }
 
 // GetEnseignantReports godoc
@@ -311,7 +334,7 @@ func GetEnseignantPayments(c *gin.Context) {
 func GetEnseignantReports(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	var reports []models.Report
-	database.DB.Where("enseignant_id = ?", id).Find(&reports)
+	database.DB.Scopes(visibleReports(c)).Where("enseignant_id = ?", id).Find(&reports)
 	c.JSON(http.StatusOK, reports)
 }
 
@@ -348,3 +371,87 @@ func GetEnseignantsNearby(c *gin.Context) {
 	// Stub: renvoie tous les enseignants pour l'instant
 	ListEnseignants(c)
 }
+
+// GetEnseignantBankAccount godoc
+// @Summary      Coordonnées bancaires d'un enseignant
+// @Description  Titulaire, IBAN et BIC du compte sur lequel l'enseignant est payé
+// @Tags         enseignants
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'enseignant"
+// @Success      200  {object}  models.BankAccountResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /enseignants/{id}/bank-account [get]
+func GetEnseignantBankAccount(c *gin.Context) {
+	enseignant, ok := loadBankAccountOwner(c)
+	if !ok {
+		return
+	}
+	if enseignant.IBAN == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucune coordonnée bancaire enregistrée"})
+		return
+	}
+	c.JSON(http.StatusOK, bankAccountResponse(enseignant))
+}
+
+// UpdateEnseignantBankAccount godoc
+// @Summary      Mise à jour des coordonnées bancaires
+// @Description  Enregistre le compte sur lequel l'enseignant est payé. L'IBAN est vérifié (pays SEPA, longueur, clé de contrôle ISO 13616), le BIC est facultatif.
+// @Tags         enseignants
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                        true  "ID de l'enseignant"
+// @Param        request  body      models.BankAccountRequest  true  "Coordonnées bancaires"
+// @Success      200  {object}  models.BankAccountResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /enseignants/{id}/bank-account [put]
+func UpdateEnseignantBankAccount(c *gin.Context) {
+	var req models.BankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	enseignant, ok := loadBankAccountOwner(c)
+	if !ok {
+		return
+	}
+	if err := services.UpdateBankAccount(database.DB, enseignant, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bankAccountResponse(enseignant))
+}
+
+// loadBankAccountOwner charge le profil enseignant désigné par :id, accessible à
+// l'enseignant lui-même et aux administrateurs. Écrit la réponse d'erreur le cas échéant.
+func loadBankAccountOwner(c *gin.Context) (*models.Enseignant, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return nil, false
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return nil, false
+	}
+	var enseignant models.Enseignant
+	if err := database.DB.First(&enseignant, "user_id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Enseignant non trouvé"})
+		return nil, false
+	}
+	return &enseignant, true
+}
+
+func bankAccountResponse(enseignant *models.Enseignant) models.BankAccountResponse {
+	return models.BankAccountResponse{
+		AccountHolder: enseignant.AccountHolder,
+		IBAN:          utils.FormatIBAN(enseignant.IBAN),
+		BIC:           enseignant.BIC,
+	}
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListInvoices godoc
+// @Summary      Liste des factures
+// @Description  Les administrateurs voient toutes les factures et avoirs, les familles uniquement leurs documents émis
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        famille_id  query     int     false  "Famille (administrateurs uniquement)"
+// @Param        kind        query     string  false  "Type de document (invoice, credit_note)"
+// @Param        status      query     string  false  "Statut (draft, issued)"
+// @Success      200  {array}   models.Invoice
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /invoices [get]
+func ListInvoices(c *gin.Context) {
+	query := database.DB.Model(&models.Invoice{})
+	if middleware.IsAdmin(c) {
+		if familleID := c.Query("famille_id"); familleID != "" {
+			query = query.Where("famille_id = ?", familleID)
+		}
+		if status := c.Query("status"); status != "" {
+			query = query.Where("status = ?", status)
+		}
+	} else {
+		userID, _ := middleware.GetUserID(c)
+		query = query.Where("famille_id = ? AND status = ?", userID, models.InvoiceStatusIssued)
+	}
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	var invoices []models.Invoice
+	if err := query.Order("created_at DESC").Find(&invoices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des factures"})
+		return
+	}
+	c.JSON(http.StatusOK, invoices)
+}
+
+// GetInvoiceByID godoc
+// @Summary      Détail d'une facture
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la facture"
+// @Success      200  {object}  models.Invoice
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /invoices/{id} [get]
+func GetInvoiceByID(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// GetInvoicePDF godoc
+// @Summary      Document PDF d'une facture
+// @Description  Génère le PDF d'une facture ou d'un avoir ; les brouillons portent la mention BROUILLON
+// @Tags         invoices
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la facture"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /invoices/{id}/pdf [get]
+func GetInvoicePDF(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+services.InvoiceFileName(invoice)+`"`)
+	c.Data(http.StatusOK, "application/pdf", services.InvoicePDF(database.DB, invoice))
+}
+
+// CreateInvoice godoc
+// @Summary      Création d'une facture en brouillon
+// @Description  Prépare une facture à partir des cours terminés désignés, ou de tous les cours terminés non encore facturés de la mission ou de la famille, sur la base des heures approuvées de leur feuille de temps et hors heures prépayées. Les cours déjà réglés par un paiement de cours ne sont pas refacturés ; les paiements de cours encore en attente sont annulés à l'émission de la facture. Les codes promo des missions et celui éventuellement saisi sont déduits sur des lignes de remise.
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.InvoiceCreateRequest  true  "Famille, mission et cours à facturer"
+// @Success      201  {object}  models.Invoice
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /invoices [post]
+func CreateInvoice(c *gin.Context) {
+	var req models.InvoiceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	invoice, err := services.CreateInvoiceDraft(database.DB, req, adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Famille introuvable"})
+		return
+	}
+	if err != nil {
+		if respondPromoError(c, err) {
+			return
+		}
+		respondInvoiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// UpdateInvoice godoc
+// @Summary      Mise à jour d'un brouillon de facture
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "ID de la facture"
+// @Param        request  body      models.InvoiceUpdateRequest  true  "Notes"
+// @Success      200  {object}  models.Invoice
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /invoices/{id} [put]
+func UpdateInvoice(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	var req models.InvoiceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.UpdateInvoiceDraft(database.DB, invoice, req); err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// DeleteInvoice godoc
+// @Summary      Suppression d'un brouillon de facture
+// @Description  Seuls les brouillons, qui n'ont pas encore de numéro, peuvent être supprimés
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la facture"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /invoices/{id} [delete]
+func DeleteInvoice(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	if err := services.DeleteInvoiceDraft(database.DB, invoice); err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// IssueInvoice godoc
+// @Summary      Émission d'une facture
+// @Description  Attribue le numéro définitif (continu par série et par année) et fige la facture. Les paiements en attente des cours facturés sont annulés ; l'émission est refusée si l'un de ces cours a été réglé entre-temps.
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la facture"
+// @Success      200  {object}  models.Invoice
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /invoices/{id}/issue [post]
+func IssueInvoice(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	if err := services.IssueInvoice(database.DB, invoice, adminID); err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// CreateCreditNote godoc
+// @Summary      Avoir sur une facture
+// @Description  Émet un avoir annulant intégralement une facture émise ; les cours concernés peuvent ensuite être refacturés
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                       true  "ID de la facture"
+// @Param        request  body      models.CreditNoteRequest  true  "Motif de l'avoir"
+// @Success      201  {object}  models.Invoice
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /invoices/{id}/credit-note [post]
+func CreateCreditNote(c *gin.Context) {
+	invoice, ok := loadInvoice(c)
+	if !ok {
+		return
+	}
+	var req models.CreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	creditNote, err := services.CreateCreditNote(database.DB, invoice, adminID, req.Reason)
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, creditNote)
+}
+
+// loadInvoice charge la facture désignée par :id avec ses lignes. Les familles
+// n'accèdent qu'à leurs documents émis. Écrit la réponse d'erreur le cas échéant.
+func loadInvoice(c *gin.Context) (*models.Invoice, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return nil, false
+	}
+	invoice, err := services.LoadInvoice(database.DB, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Facture non trouvée"})
+		return nil, false
+	}
+	userID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && (invoice.FamilleID != userID || !invoice.IsIssued()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Facture non trouvée"})
+		return nil, false
+	}
+	return invoice, true
+}
+
+// respondInvoiceError traduit les opérations interdites sur une facture en 409
+func respondInvoiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvoiceIssued), errors.Is(err, models.ErrInvoiceNotIssued),
+		errors.Is(err, services.ErrCourseAlreadyInvoiced), errors.Is(err, services.ErrInvoiceAlreadyCredited),
+		errors.Is(err, services.ErrNotAnInvoice), errors.Is(err, services.ErrCourseAlreadyPaid),
+		errors.Is(err, services.ErrCoursePrepaid):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvoiceEmpty), errors.Is(err, services.ErrCourseNotInvoiceable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du traitement de la facture"})
+	}
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"api/database"
+	"api/models"
+	"api/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListLedgerAccounts godoc
+// @Summary      Plan comptable
+// @Description  Comptes utilisés pour passer les écritures, créés avec leur numéro par défaut lors de la première utilisation
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.LedgerAccount
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/accounts [get]
+func ListLedgerAccounts(c *gin.Context) {
+	accounts, err := services.LedgerAccounts(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du plan comptable"})
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+// UpdateLedgerAccount godoc
+// @Summary      Modification d'un compte du plan comptable
+// @Description  Change le numéro ou le libellé utilisé pour les prochaines écritures ; les écritures déjà passées ne sont pas modifiées
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                true  "ID du compte"
+// @Param        request  body      models.LedgerAccountUpdateRequest  true  "Numéro et libellé"
+// @Success      200  {object}  models.LedgerAccount
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/accounts/{id} [put]
+func UpdateLedgerAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var account models.LedgerAccount
+	if err := database.DB.First(&account, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Compte non trouvé"})
+		return
+	}
+	var req models.LedgerAccountUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.UpdateLedgerAccount(database.DB, &account, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour du compte"})
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// ListLedgerEntries godoc
+// @Summary      Écritures comptables
+// @Description  Écritures de la période, dans l'ordre de leur numéro, avec leurs lignes
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from         query     string  false  "Début de période (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to           query     string  false  "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Param        journal      query     string  false  "Journal (VT, HA, BQ, OD)"
+// @Param        source_type  query     string  false  "Document d'origine (invoice, payment, payout)"
+// @Param        source_id    query     int     false  "ID du document d'origine"
+// @Success      200  {array}   models.LedgerEntry
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/entries [get]
+func ListLedgerEntries(c *gin.Context) {
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	query := database.DB.Where("entry_date >= ? AND entry_date < ?", start, end)
+	if journal := c.Query("journal"); journal != "" {
+		query = query.Where("journal = ?", journal)
+	}
+	if sourceType := c.Query("source_type"); sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+	if sourceID := c.Query("source_id"); sourceID != "" {
+		query = query.Where("source_id = ?", sourceID)
+	}
+	var entries []models.LedgerEntry
+	if err := query.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		Order("number").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des écritures"})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetLedgerTrialBalance godoc
+// @Summary      Balance générale
+// @Description  Total des débits et des crédits de chaque compte sur la période, et solde (débit moins crédit)
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from  query     string  false  "Début de période (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to    query     string  false  "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Success      200  {object}  models.LedgerTrialBalanceResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/trial-balance [get]
+func GetLedgerTrialBalance(c *gin.Context) {
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	balance, err := services.LedgerTrialBalance(database.DB, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de la balance"})
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetLedgerAccountStatement godoc
+// @Summary      Relevé d'un compte
+// @Description  Solde d'ouverture, mouvements de la période avec le solde progressif et solde de clôture d'un compte, éventuellement limité au compte auxiliaire d'une famille (FAM000042) ou d'un enseignant (ENS000042)
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        account  query     string  true   "Numéro de compte"
+// @Param        aux      query     string  false  "Compte auxiliaire"
+// @Param        from     query     string  false  "Début de période (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to       query     string  false  "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Success      200  {object}  models.LedgerStatementResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/statement [get]
+func GetLedgerAccountStatement(c *gin.Context) {
+	account := c.Query("account")
+	if account == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Numéro de compte requis"})
+		return
+	}
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	statement, err := services.LedgerAccountStatement(database.DB, account, c.Query("aux"), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du relevé de compte"})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// ExportLedgerFEC godoc
+// @Summary      Fichier des écritures comptables (FEC)
+// @Description  Exporte les écritures de la période au format FEC (article A. 47 A-1 du livre des procédures fiscales) : fichier texte à 18 colonnes séparées par une barre verticale, nommé d'après le SIREN et la date de clôture
+// @Tags         ledger
+// @Produce      plain
+// @Security     BearerAuth
+// @Param        from  query     string  false  "Début de l'exercice (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to    query     string  false  "Date de clôture incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /ledger/fec [get]
+func ExportLedgerFEC(c *gin.Context) {
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	content, fileName, err := services.ExportFEC(database.DB, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du fichier des écritures comptables"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", content)
+}
+
+func ledgerPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	start, end, err := services.ParseLedgerPeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
@@ -2,12 +2,15 @@ package controllers
 
 import (
 	"api/database"
+	"api/middleware"
 	"api/models"
+	"api/services"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // MissionResponse représente la mission avec ses relations principales
@@ -48,7 +51,7 @@ func ListMissions(c *gin.Context) {
 		query = query.Where("famille_id = ?", familleID)
 	}
 
-	if err := query.Preload("Courses").Preload("Reports").Find(&missions).Error; err != nil {
+	if err := query.Preload("Courses").Preload("Reports", visibleReports(c)).Find(&missions).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des missions"})
 		return
 	}
@@ -85,7 +88,7 @@ func GetMissionByID(c *gin.Context) {
 	}
 
 	var mission models.Mission
-	if err := database.DB.Preload("Courses").Preload("Reports").First(&mission, missionID).Error; err != nil {
+	if err := database.DB.Preload("Courses").Preload("Reports", visibleReports(c)).First(&mission, missionID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Mission non trouvée"})
 		return
 	}
@@ -95,7 +98,7 @@ func GetMissionByID(c *gin.Context) {
 
 // CreateMission godoc
 // @Summary      Création d'une mission
-// @Description  Crée une nouvelle mission
+// @Description  Crée une nouvelle mission. Un code promo peut être appliqué à tous ses cours.
 // @Tags         missions
 // @Accept       json
 // @Produce      json
@@ -103,6 +106,7 @@ func GetMissionByID(c *gin.Context) {
 // @Param        request  body      models.MissionCreateRequest  true  "Données de la mission"
 // @Success      201  {object}  MissionResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /missions [post]
 func CreateMission(c *gin.Context) {
@@ -111,12 +115,23 @@ func CreateMission(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.HourlyRate != nil && req.HourlyRate.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le taux horaire ne peut pas être négatif"})
+		return
+	}
 
 	mission := models.Mission{
 		StartDate:    req.StartDate,
 		EndDate:      req.EndDate,
 		Description:  req.Description,
 		EnseignantID: req.EnseignantID,
+		OfferID:      req.OfferID,
+
+		ReportFrequencyDays:        req.ReportFrequencyDays,
+		BlockPayoutOnOverdueReport: req.BlockPayoutOnOverdueReport,
+	}
+	if req.HourlyRate != nil {
+		mission.HourlyRate = *req.HourlyRate
 	}
 	// FamilleID: on peut récupérer depuis contexte utilisateur si rôle famille
 	if familleIDStr := c.Query("famille_id"); familleIDStr != "" {
@@ -125,10 +140,35 @@ func CreateMission(c *gin.Context) {
 		}
 	}
 
-	if err := database.DB.Create(&mission).Error; err != nil {
+	if req.PromoCode != "" && mission.FamilleID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La famille de la mission est requise pour appliquer un code promo"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&mission).Error; err != nil {
+			return err
+		}
+		if req.PromoCode == "" {
+			return nil
+		}
+		userID, _ := middleware.GetUserID(c)
+		_, err := services.RedeemMissionPromoCode(tx, &mission, req.PromoCode, userID)
+		return err
+	})
+	if err != nil {
+		if respondPromoError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la mission"})
 		return
 	}
+	due, err := services.RefreshNextReportDue(database.DB, mission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de l'échéance de rapport"})
+		return
+	}
+	mission.NextReportDueAt = due
 
 	c.JSON(http.StatusCreated, MissionResponse{Mission: mission})
 }
@@ -156,6 +196,10 @@ func UpdateMission(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.HourlyRate != nil && req.HourlyRate.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le taux horaire ne peut pas être négatif"})
+		return
+	}
 
 	var mission models.Mission
 	if err := database.DB.First(&mission, missionID).Error; err != nil {
@@ -173,11 +217,27 @@ func UpdateMission(c *gin.Context) {
 	if req.Status != "" {
 		mission.Status = req.Status
 	}
+	if req.HourlyRate != nil {
+		mission.HourlyRate = *req.HourlyRate
+	}
+	if req.ReportFrequencyDays != nil {
+		mission.ReportFrequencyDays = *req.ReportFrequencyDays
+	}
+	if req.BlockPayoutOnOverdueReport != nil {
+		mission.BlockPayoutOnOverdueReport = *req.BlockPayoutOnOverdueReport
+	}
 
 	if err := database.DB.Save(&mission).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour"})
 		return
 	}
+	// Le statut et la périodicité influent sur l'échéance du prochain rapport
+	due, err := services.RefreshNextReportDue(database.DB, mission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de l'échéance de rapport"})
+		return
+	}
+	mission.NextReportDueAt = due
 
 	c.JSON(http.StatusOK, MissionResponse{Mission: mission})
 }
@@ -289,7 +349,7 @@ func GetMissionCourses(c *gin.Context) {
 
 // GetMissionReports godoc
 // @Summary      Liste des rapports d'une mission
-// @Description  Récupère la liste des rapports associés à une mission
+// @Description  Récupère la liste des rapports associés à une mission (rapports validés uniquement pour une famille)
 // @Tags         missions
 // @Accept       json
 // @Produce      json
@@ -307,7 +367,7 @@ func GetMissionReports(c *gin.Context) {
 	}
 
 	var reports []models.Report
-	if err := database.DB.Where("mission_id = ?", missionID).Find(&reports).Error; err != nil {
+	if err := database.DB.Scopes(visibleReports(c)).Where("mission_id = ?", missionID).Find(&reports).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des rapports"})
 		return
 	}
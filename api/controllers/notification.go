@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotifications godoc
+// @Summary      Notifications de l'utilisateur
+// @Description  Récupère les notifications de l'utilisateur connecté, les plus récentes en premier
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unread  query     bool  false  "Uniquement les notifications non lues"
+// @Success      200  {array}   models.Notification
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /notifications [get]
+func ListNotifications(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
+		return
+	}
+	var notifications []models.Notification
+	query := database.DB.Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Order("created_at DESC").Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationRead godoc
+// @Summary      Marquer une notification comme lue
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la notification"
+// @Success      200  {object}  models.Notification
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /notifications/{id}/read [put]
+func MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	var notification models.Notification
+	if err := database.DB.Where("user_id = ?", userID).First(&notification, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification non trouvée"})
+		return
+	}
+	notification.MarkAsRead()
+	database.DB.Save(&notification)
+	c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary      Marquer toutes les notifications comme lues
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /notifications/read-all [put]
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour des notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": result.RowsAffected})
+}
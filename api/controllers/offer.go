@@ -2,12 +2,18 @@ package controllers
 
 import (
 	"api/database"
+	"api/middleware"
 	"api/models"
+	"api/services"
+	"api/utils"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OfferResponse struct {
@@ -17,7 +23,7 @@ type OfferResponse struct {
 
 // ListOffers godoc
 // @Summary      Liste toutes les offres
-// @Description  Récupère la liste des offres avec possibilité de filtrage par statut, sujet et niveau
+// @Description  Récupère la liste des offres avec possibilité de filtrage par statut, sujet et niveau. Les brouillons ne sont visibles que des administrateurs.
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -40,7 +46,10 @@ func ListOffers(c *gin.Context) {
 	if level := c.Query("level"); level != "" {
 		query = query.Where("level = ?", level)
 	}
-	if err := query.Preload("Options").Find(&offers).Error; err != nil {
+	if !middleware.IsAdmin(c) {
+		query = query.Where("status <> ?", models.OfferStatusDraft)
+	}
+	if err := query.Preload("Options").Preload("CreatedBy.User").Find(&offers).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des offres"})
 		return
 	}
@@ -53,7 +62,7 @@ func ListOffers(c *gin.Context) {
 
 // GetOfferByID godoc
 // @Summary      Détails d'une offre
-// @Description  Récupère les détails d'une offre spécifique
+// @Description  Récupère les détails d'une offre spécifique et de son auteur. Les brouillons ne sont visibles que des administrateurs.
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -70,7 +79,8 @@ func GetOfferByID(c *gin.Context) {
 		return
 	}
 	var offer models.Offer
-	if err := database.DB.Preload("Options").First(&offer, id).Error; err != nil {
+	if err := database.DB.Preload("Options").Preload("CreatedBy.User").First(&offer, id).Error; err != nil ||
+		(offer.Status == models.OfferStatusDraft && !middleware.IsAdmin(c)) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Offre non trouvée"})
 		return
 	}
@@ -79,7 +89,7 @@ func GetOfferByID(c *gin.Context) {
 
 // CreateOffer godoc
 // @Summary      Création d'une offre
-// @Description  Crée une nouvelle offre
+// @Description  Crée une offre en brouillon au nom de l'administrateur connecté (admin seulement). Elle est publiée par l'action publish, ou automatiquement à la date publish_at si elle est indiquée.
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -87,6 +97,7 @@ func GetOfferByID(c *gin.Context) {
 // @Param        request  body      models.OfferCreateRequest  true  "Données de l'offre"
 // @Success      201  {object}  OfferResponse
 // @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router      /offers [post]
 func CreateOffer(c *gin.Context) {
@@ -95,15 +106,32 @@ func CreateOffer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.HourlyRate.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le taux horaire ne peut pas être négatif"})
+		return
+	}
 	offer := models.Offer{
-		Title:           req.Title,
-		Description:     req.Description,
-		HourlyRate:      req.HourlyRate,
-		Requirements:    req.Requirements,
-		Subject:         req.Subject,
-		Level:           req.Level,
-		Status:          models.OfferStatusOpen,
-		PublicationDate: time.Now(),
+		Title:               req.Title,
+		Description:         req.Description,
+		HourlyRate:          req.HourlyRate,
+		Requirements:        req.Requirements,
+		Subject:             req.Subject,
+		Level:               req.Level,
+		ReportFrequencyDays: req.ReportFrequencyDays,
+		Deadline:            req.Deadline,
+	}
+	adminID, _ := middleware.GetUserID(c)
+	offer.CreateOffer(adminID)
+	now := time.Now()
+	if req.Deadline != nil && !req.Deadline.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrOfferDeadlinePassed.Error()})
+		return
+	}
+	if req.PublishAt != nil {
+		if err := offer.SchedulePublication(*req.PublishAt, now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 	if err := database.DB.Create(&offer).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de l'offre"})
@@ -114,7 +142,7 @@ func CreateOffer(c *gin.Context) {
 
 // UpdateOffer godoc
 // @Summary      Mise à jour d'une offre
-// @Description  Met à jour les informations d'une offre existante
+// @Description  Met à jour les informations d'une offre existante. Les modifications d'une offre déjà publiée sont historisées. Le statut ne change que vers closed ou filled ; la publication passe par les actions publish et unpublish. (admin seulement)
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -136,22 +164,37 @@ func UpdateOffer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.HourlyRate != nil && req.HourlyRate.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le taux horaire ne peut pas être négatif"})
+		return
+	}
+	if req.Deadline != nil && !req.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrOfferDeadlinePassed.Error()})
+		return
+	}
 	var offer models.Offer
 	if err := database.DB.First(&offer, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Offre non trouvée"})
 		return
 	}
+	if req.Status != "" && req.Status != offer.Status &&
+		req.Status != models.OfferStatusClosed && req.Status != models.OfferStatusFilled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrOfferStatusTransition.Error()})
+		return
+	}
+	published := offer.Status != models.OfferStatusDraft
+	closing := req.Status != "" && req.Status != offer.Status
 	if req.Title != "" {
 		offer.Title = req.Title
 	}
 	if req.Description != "" {
 		offer.Description = req.Description
 	}
-	if req.HourlyRate != 0 {
-		offer.HourlyRate = req.HourlyRate
+	if req.HourlyRate != nil {
+		offer.HourlyRate = *req.HourlyRate
 	}
-	if req.Status != "" {
-		offer.Status = req.Status
+	if closing {
+		offer.CloseOffer(req.Status, time.Now())
 	}
 	if req.Requirements != "" {
 		offer.Requirements = req.Requirements
@@ -162,13 +205,45 @@ func UpdateOffer(c *gin.Context) {
 	if req.Level != "" {
 		offer.Level = req.Level
 	}
-	database.DB.Save(&offer)
+	if req.ReportFrequencyDays != nil {
+		offer.ReportFrequencyDays = *req.ReportFrequencyDays
+	}
+	if req.Deadline != nil {
+		offer.Deadline = req.Deadline
+	}
+	userID, _ := middleware.GetUserID(c)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&offer).Error; err != nil {
+			return err
+		}
+		if !published {
+			return nil
+		}
+		action := models.OfferActionUpdated
+		switch {
+		case closing && offer.Status == models.OfferStatusFilled:
+			action = models.OfferActionFilled
+		case closing:
+			action = models.OfferActionClosed
+		}
+		return services.RecordOfferRevision(tx, &offer, action, userID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de l'offre"})
+		return
+	}
+	if req.ReportFrequencyDays != nil {
+		if err := services.RefreshOfferReportSchedules(database.DB, offer.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du recalcul des échéances de rapport"})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, OfferResponse{Offer: offer})
 }
 
 // DeleteOffer godoc
 // @Summary      Suppression d'une offre
-// @Description  Supprime une offre existante
+// @Description  Supprime une offre existante (admin seulement)
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -209,7 +284,7 @@ func GetOfferOptions(c *gin.Context) {
 
 // CloseOffer godoc
 // @Summary      Fermeture d'une offre
-// @Description  Marque une offre comme fermée
+// @Description  Marque une offre comme fermée : elle n'accepte plus de candidatures, mais les candidats déjà reçus peuvent encore être retenus (admin seulement)
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -218,8 +293,85 @@ func GetOfferOptions(c *gin.Context) {
 // @Success      200  {object}  OfferResponse
 // @Failure      400  {object}  map[string]interface{}
 // @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
 // @Router      /offers/{id}/close [put]
 func CloseOffer(c *gin.Context) {
+	changeOfferLifecycle(c, services.CloseOffer)
+}
+
+// PublishOffer godoc
+// @Summary      Publication d'une offre
+// @Description  Publie immédiatement une offre en brouillon, qui s'ouvre aux candidatures (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'offre"
+// @Success      200  {object}  OfferResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router      /offers/{id}/publish [put]
+func PublishOffer(c *gin.Context) {
+	changeOfferLifecycle(c, services.PublishOffer)
+}
+
+// UnpublishOffer godoc
+// @Summary      Retrait d'une offre
+// @Description  Repasse une offre publiée en brouillon, ou annule sa publication programmée. Les candidatures déjà reçues sont conservées (admin seulement).
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'offre"
+// @Success      200  {object}  OfferResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router      /offers/{id}/unpublish [put]
+func UnpublishOffer(c *gin.Context) {
+	changeOfferLifecycle(c, services.UnpublishOffer)
+}
+
+// ScheduleOfferPublication godoc
+// @Summary      Publication programmée d'une offre
+// @Description  Programme la publication d'une offre en brouillon à une date future (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "ID de l'offre"
+// @Param        request  body      models.OfferScheduleRequest  true  "Date de publication"
+// @Success      200  {object}  OfferResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router      /offers/{id}/schedule [put]
+func ScheduleOfferPublication(c *gin.Context) {
+	var req models.OfferScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changeOfferLifecycle(c, func(db *gorm.DB, offerID, adminID uint) (*models.Offer, error) {
+		return services.ScheduleOfferPublication(db, offerID, req.PublishAt, adminID)
+	})
+}
+
+// GetOfferRevisions godoc
+// @Summary      Historique d'une offre
+// @Description  Versions successives d'une offre depuis sa première publication : publication, modifications, retrait et clôture (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'offre"
+// @Success      200  {array}   models.OfferRevision
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router      /offers/{id}/revisions [get]
+func GetOfferRevisions(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
@@ -230,14 +382,43 @@ func CloseOffer(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Offre non trouvée"})
 		return
 	}
-	offer.Status = models.OfferStatusClosed
-	database.DB.Save(&offer)
-	c.JSON(http.StatusOK, OfferResponse{Offer: offer})
+	var revisions []models.OfferRevision
+	if err := database.DB.Where("offer_id = ?", id).Order("revision").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération de l'historique de l'offre"})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// changeOfferLifecycle applique une étape du cycle de publication à l'offre de l'URL
+func changeOfferLifecycle(c *gin.Context, change func(db *gorm.DB, offerID, adminID uint) (*models.Offer, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	offer, err := change(database.DB, uint(id), adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Offre non trouvée"})
+		case errors.Is(err, models.ErrOfferPublishInPast), errors.Is(err, models.ErrOfferDeadlinePassed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrOfferNotDraft), errors.Is(err, models.ErrOfferNotPublished),
+			errors.Is(err, models.ErrOfferNotOpen):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de l'offre"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, OfferResponse{Offer: *offer})
 }
 
 // ListActiveOffers godoc
 // @Summary      Liste des offres actives
-// @Description  Récupère la liste des offres avec le statut 'open'
+// @Description  Récupère la liste des offres avec le statut 'open' dont la date limite de candidature n'est pas passée
 // @Tags         offers
 // @Accept       json
 // @Produce      json
@@ -247,22 +428,105 @@ func CloseOffer(c *gin.Context) {
 // @Router      /offers/active [get]
 func ListActiveOffers(c *gin.Context) {
 	var offers []models.Offer
-	database.DB.Where("status = ?", models.OfferStatusOpen).Find(&offers)
+	database.DB.Where("status = ? AND (deadline IS NULL OR deadline > ?)", models.OfferStatusOpen, time.Now()).Find(&offers)
 	c.JSON(http.StatusOK, offers)
 }
 
 // SearchOffers godoc
 // @Summary      Recherche d'offres
-// @Description  Recherche des offres selon différents critères
+// @Description  Recherche plein texte dans le titre, la description et les prérequis des offres, classée par pertinence (titre d'abord). Renvoie les facettes matière, niveau et tranche de taux horaire, chacune calculée sans son propre filtre. Sans statut, seules les offres ouvertes sont recherchées.
 // @Tags         offers
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        query  query     string  false  "Terme de recherche"
-// @Success      200  {array}   OfferResponse
+// @Param        query           query     string  false  "Terme de recherche"
+// @Param        status          query     string  false  "Statut de l'offre (open par défaut)"
+// @Param        subject         query     string  false  "Matière enseignée"
+// @Param        level           query     string  false  "Niveau d'étude"
+// @Param        min_rate_cents  query     int     false  "Taux horaire minimum, en centimes"
+// @Param        max_rate_cents  query     int     false  "Taux horaire maximum, en centimes"
+// @Param        date_from       query     string  false  "Publiée à partir du (YYYY-MM-DD)"
+// @Param        date_to         query     string  false  "Publiée jusqu'au (YYYY-MM-DD, inclus)"
+// @Param        limit           query     int     false  "Nombre de résultats (20 par défaut, 100 au plus)"
+// @Param        offset          query     int     false  "Décalage pour la pagination"
+// @Success      200  {object}  models.OfferSearchResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router      /offers/search [get]
 func SearchOffers(c *gin.Context) {
-	// Pour l'instant, même logique que ListOffers avec plus de filtres
-	ListOffers(c)
+	req, ok := offerSearchRequest(c)
+	if !ok {
+		return
+	}
+	if req.Status == models.OfferStatusDraft && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	response, err := services.SearchOffers(database.DB, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la recherche des offres"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// offerSearchRequest lit les critères de recherche passés en paramètres de requête
+func offerSearchRequest(c *gin.Context) (models.OfferSearchRequest, bool) {
+	req := models.OfferSearchRequest{Query: c.Query("query")}
+	req.Status = models.OfferStatus(c.Query("status"))
+	req.Subject = c.Query("subject")
+	req.Level = c.Query("level")
+
+	var err error
+	if req.MinRate, err = queryCents(c, "min_rate_cents"); err == nil {
+		req.MaxRate, err = queryCents(c, "max_rate_cents")
+	}
+	if err != nil || (req.MinRate != nil && req.MaxRate != nil && *req.MinRate > *req.MaxRate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fourchette de taux horaire invalide"})
+		return req, false
+	}
+	if req.DateFrom, err = queryDate(c, "date_from"); err == nil {
+		req.DateTo, err = queryDate(c, "date_to")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Date invalide (format attendu YYYY-MM-DD)"})
+		return req, false
+	}
+	if req.DateFrom != nil && req.DateTo != nil && req.DateTo.Before(*req.DateFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La date de fin doit être postérieure à la date de début"})
+		return req, false
+	}
+	if req.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "0")); err == nil {
+		req.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	}
+	if err != nil || req.Limit < 0 || req.Offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pagination invalide"})
+		return req, false
+	}
+	return req, true
+}
+
+func queryCents(c *gin.Context, param string) (*int64, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	cents, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cents < 0 {
+		return nil, strconv.ErrSyntax
+	}
+	return &cents, nil
+}
+
+func queryDate(c *gin.Context, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, utils.CalendarLocation())
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
 }
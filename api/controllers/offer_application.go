@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ApplyToOffer godoc
+// @Summary      Candidature à une offre
+// @Description  L'enseignant connecté postule à une offre ouverte avec une lettre de motivation et ses disponibilités. L'auteur de l'offre est notifié. Une candidature retirée peut être renouvelée.
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "ID de l'offre"
+// @Param        request  body      models.OfferApplicationRequest  true  "Candidature"
+// @Success      201  {object}  models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications [post]
+func ApplyToOffer(c *gin.Context) {
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if role, _ := middleware.GetUserRole(c); role != string(models.RoleEnseignant) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seuls les enseignants peuvent postuler à une offre"})
+		return
+	}
+	var req models.OfferApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.OfferID = uint(offerID)
+
+	userID, _ := middleware.GetUserID(c)
+	application, err := services.ApplyForOffer(database.DB, uint(offerID), userID, req)
+	if err != nil {
+		respondOfferApplicationError(c, err, "Erreur lors de l'enregistrement de la candidature")
+		return
+	}
+	c.JSON(http.StatusCreated, application)
+}
+
+// WithdrawOfferApplication godoc
+// @Summary      Retrait d'une candidature
+// @Description  L'enseignant connecté retire sa candidature tant qu'elle n'a été ni acceptée ni refusée
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'offre"
+// @Success      200  {object}  models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications/withdraw [put]
+func WithdrawOfferApplication(c *gin.Context) {
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	application, err := services.WithdrawApplication(database.DB, uint(offerID), userID)
+	if err != nil {
+		respondOfferApplicationError(c, err, "Erreur lors du retrait de la candidature")
+		return
+	}
+	application.Offer = nil
+	c.JSON(http.StatusOK, application)
+}
+
+// ListOfferApplications godoc
+// @Summary      Candidatures à une offre
+// @Description  Liste les candidats à une offre, les plus anciens en premier (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "ID de l'offre"
+// @Param        status  query     string  false  "Statut (pending, shortlisted, rejected, accepted, withdrawn)"
+// @Success      200  {array}   models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications [get]
+func ListOfferApplications(c *gin.Context) {
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var offer models.Offer
+	if err := database.DB.First(&offer, offerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offre non trouvée"})
+		return
+	}
+
+	query := database.DB.Preload("Enseignant.User").Where("offer_id = ?", offerID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var applications []models.OfferApplication
+	if err := query.Order("applied_at, enseignant_user_id").Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des candidatures"})
+		return
+	}
+	c.JSON(http.StatusOK, applications)
+}
+
+// ShortlistOfferApplication godoc
+// @Summary      Présélection d'une candidature
+// @Description  Présélectionne un candidat à une offre, qui en est notifié (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      int                                   true   "ID de l'offre"
+// @Param        enseignant_id  path      int                                   true   "ID de l'enseignant"
+// @Param        request        body      models.OfferApplicationReviewRequest  false  "Message à l'enseignant"
+// @Success      200  {object}  models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications/{enseignant_id}/shortlist [put]
+func ShortlistOfferApplication(c *gin.Context) {
+	reviewOfferApplication(c, models.OfferApplicationShortlisted)
+}
+
+// RejectOfferApplication godoc
+// @Summary      Refus d'une candidature
+// @Description  Refuse la candidature d'un enseignant à une offre, qui en est notifié (admin seulement)
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      int                                   true   "ID de l'offre"
+// @Param        enseignant_id  path      int                                   true   "ID de l'enseignant"
+// @Param        request        body      models.OfferApplicationReviewRequest  false  "Motif du refus"
+// @Success      200  {object}  models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications/{enseignant_id}/reject [put]
+func RejectOfferApplication(c *gin.Context) {
+	reviewOfferApplication(c, models.OfferApplicationRejected)
+}
+
+func reviewOfferApplication(c *gin.Context, status models.OfferApplicationStatus) {
+	offerID, enseignantID, ok := offerApplicationParams(c)
+	if !ok {
+		return
+	}
+	var req models.OfferApplicationReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	adminID, _ := middleware.GetUserID(c)
+	application, err := services.ReviewApplication(database.DB, offerID, enseignantID, status, req.Note, adminID)
+	if err != nil {
+		respondOfferApplicationError(c, err, "Erreur lors du traitement de la candidature")
+		return
+	}
+	application.Offer = nil
+	c.JSON(http.StatusOK, application)
+}
+
+// AcceptOfferApplication godoc
+// @Summary      Acceptation d'une candidature
+// @Description  Retient un candidat (admin seulement) : l'offre passe au statut pourvue, les autres candidatures en cours sont refusées et chaque enseignant est notifié. Avec create_mission, la mission de l'enseignant auprès de la famille indiquée est créée et la famille est prévenue.
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      int                                   true  "ID de l'offre"
+// @Param        enseignant_id  path      int                                   true  "ID de l'enseignant"
+// @Param        request        body      models.OfferApplicationAcceptRequest  true  "Décision et mission à créer"
+// @Success      200  {object}  models.OfferApplicationAcceptResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /offers/{id}/applications/{enseignant_id}/accept [put]
+func AcceptOfferApplication(c *gin.Context) {
+	offerID, enseignantID, ok := offerApplicationParams(c)
+	if !ok {
+		return
+	}
+	var req models.OfferApplicationAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.HourlyRate != nil && req.HourlyRate.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le taux horaire ne peut pas être négatif"})
+		return
+	}
+	if req.EndDate != nil && req.StartDate != nil && req.EndDate.Before(*req.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La date de fin doit être postérieure à la date de début"})
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(c)
+	response, err := services.AcceptApplication(database.DB, offerID, enseignantID, req, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMissionFamilleRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrMissionFamilleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			respondOfferApplicationError(c, err, "Erreur lors de l'acceptation de la candidature")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEnseignantApplications godoc
+// @Summary      Candidatures d'un enseignant
+// @Description  Liste les candidatures d'un enseignant aux offres, les plus récentes en premier
+// @Tags         enseignants
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "ID de l'enseignant"
+// @Param        status  query     string  false  "Statut (pending, shortlisted, rejected, accepted, withdrawn)"
+// @Success      200  {array}   models.OfferApplication
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /enseignants/{id}/applications [get]
+func GetEnseignantApplications(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+
+	query := database.DB.Preload("Offer").Where("enseignant_user_id = ?", id)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var applications []models.OfferApplication
+	if err := query.Order("applied_at DESC").Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des candidatures"})
+		return
+	}
+	c.JSON(http.StatusOK, applications)
+}
+
+func offerApplicationParams(c *gin.Context) (uint, uint, bool) {
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return 0, 0, false
+	}
+	enseignantID, err := strconv.ParseUint(c.Param("enseignant_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID enseignant invalide"})
+		return 0, 0, false
+	}
+	return uint(offerID), uint(enseignantID), true
+}
+
+func respondOfferApplicationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offre ou candidature non trouvée"})
+	case errors.Is(err, models.ErrOfferNotOpen), errors.Is(err, models.ErrAlreadyApplied),
+		errors.Is(err, models.ErrApplicationNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// paymentScope restreint les paiements à ceux de l'utilisateur connecté, sauf pour les administrateurs
+func paymentScope(c *gin.Context) *gorm.DB {
+	query := database.DB.Model(&models.Payment{})
+	if !middleware.IsAdmin(c) {
+		userID, _ := middleware.GetUserID(c)
+		query = query.Where("user_id = ?", userID)
+	} else if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if paymentType := c.Query("type"); paymentType != "" {
+		query = query.Where("type = ?", paymentType)
+	}
+	return query
+}
+
+// ListPayments godoc
+// @Summary      Liste des paiements
+// @Description  Les administrateurs voient tous les paiements, les autres utilisateurs uniquement les leurs
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status   query     string  false  "Statut (pending, completed, failed, refunded)"
+// @Param        type     query     string  false  "Type de paiement"
+// @Param        user_id  query     int     false  "Utilisateur (administrateurs uniquement)"
+// @Success      200  {array}   models.Payment
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /payments [get]
+func ListPayments(c *gin.Context) {
+	var payments []models.Payment
+	if err := paymentScope(c).Order("created_at DESC").Find(&payments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des paiements"})
+		return
+	}
+	c.JSON(http.StatusOK, payments)
+}
+
+// GetPaymentStats godoc
+// @Summary      Statistiques des paiements
+// @Description  Totaux des paiements visibles par l'utilisateur, avec les mêmes filtres que la liste
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status   query     string  false  "Statut"
+// @Param        type     query     string  false  "Type de paiement"
+// @Param        user_id  query     int     false  "Utilisateur (administrateurs uniquement)"
+// @Param        currency query     string  false  "Devise des montants totalisés (EUR par défaut)"
+// @Success      200  {object}  models.PaymentStatsResponse
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /payments/stats [get]
+func GetPaymentStats(c *gin.Context) {
+	currency := models.Currency(strings.ToUpper(c.DefaultQuery("currency", string(models.DefaultCurrency))))
+	var payments []models.Payment
+	if err := paymentScope(c).Where("amount_currency = ?", currency).Find(&payments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul des statistiques"})
+		return
+	}
+	zero := models.NewMoney(0, currency)
+	stats := models.PaymentStatsResponse{TotalAmount: zero, CompletedAmount: zero, PendingAmount: zero}
+	for _, p := range payments {
+		stats.TotalAmount = stats.TotalAmount.Add(p.Amount)
+		stats.TotalCount++
+		switch p.Status {
+		case models.PaymentStatusCompleted:
+			stats.CompletedAmount = stats.CompletedAmount.Add(p.Amount)
+			stats.CompletedCount++
+		case models.PaymentStatusPending:
+			stats.PendingAmount = stats.PendingAmount.Add(p.Amount)
+			stats.PendingCount++
+		}
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetPaymentByID godoc
+// @Summary      Détail d'un paiement
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du paiement"
+// @Success      200  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /payments/{id} [get]
+func GetPaymentByID(c *gin.Context) {
+	payment, ok := loadPayment(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// CreatePayment godoc
+// @Summary      Création d'un paiement
+// @Description  Enregistre un paiement en attente et ouvre l'intention de paiement chez le prestataire. Seuls les administrateurs peuvent créer un paiement pour un autre utilisateur.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PaymentCreateRequest  true  "Données du paiement"
+// @Success      201  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      502  {object}  map[string]interface{}
+// @Router       /payments [post]
+func CreatePayment(c *gin.Context) {
+	var req models.PaymentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Amount.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le montant doit être strictement positif"})
+		return
+	}
+	if !middleware.IsAdmin(c) && req.Type != models.PaymentTypeCourse && req.Type != models.PaymentTypeMission {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Type de paiement réservé aux administrateurs"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	userID := currentID
+	if req.UserID != 0 && req.UserID != currentID {
+		if !middleware.IsAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Vous ne pouvez créer un paiement que pour vous-même"})
+			return
+		}
+		userID = req.UserID
+	}
+	if req.CourseID != nil {
+		var course models.Course
+		if err := database.DB.First(&course, *req.CourseID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cours introuvable"})
+			return
+		}
+		if !middleware.IsAdmin(c) && course.FamilleID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Ce cours ne vous concerne pas"})
+			return
+		}
+	}
+	payment, err := services.CreatePayment(database.DB, req, userID)
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ProcessPayment godoc
+// @Summary      Traitement d'un paiement
+// @Description  Présente un paiement en attente (ou précédemment refusé) au prestataire. Seuls les paiements de cours, de facture, d'acompte, de forfait d'heures et de frais d'annulation peuvent être réglés. Le paiement est réservé (en cours de traitement) le temps de la capture, puis passe à l'état encaissé ou échoué selon la réponse du prestataire.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "ID du paiement"
+// @Param        request  body      models.PaymentProcessRequest  true  "Moyen de paiement"
+// @Success      200  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      502  {object}  map[string]interface{}
+// @Router       /payments/{id}/process [post]
+func ProcessPayment(c *gin.Context) {
+	payment, ok := loadPayment(c)
+	if !ok {
+		return
+	}
+	var req models.PaymentProcessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.ProcessPayment(database.DB, &payment, req.PaymentMethod); err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// RefundPayment godoc
+// @Summary      Remboursement d'un paiement
+// @Description  Rembourse tout ou partie d'un paiement encaissé via le prestataire, dans la limite du montant encaissé restant (le solde par défaut). Le remboursement est enregistré comme un paiement lié au paiement d'origine et, pour un règlement de facture ou de cours, un avoir du même montant est émis.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "ID du paiement"
+// @Param        request  body      models.PaymentRefundRequest  true  "Montant et motif du remboursement"
+// @Success      201  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      502  {object}  map[string]interface{}
+// @Router       /payments/{id}/refund [post]
+func RefundPayment(c *gin.Context) {
+	payment, ok := loadPayment(c)
+	if !ok {
+		return
+	}
+	var req models.PaymentRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	refund, err := services.RefundPayment(database.DB, &payment, req.Amount, req.Reason, adminID)
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, refund)
+}
+
+// PaymentWebhook godoc
+// @Summary      Notification du prestataire de paiement
+// @Description  Point d'entrée public des webhooks du prestataire ; la signature de l'en-tête X-Payment-Signature est vérifiée avant toute mise à jour
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        X-Payment-Signature  header    string                      true  "Signature du contenu"
+// @Param        request              body      models.PaymentWebhookEvent  true  "Événement"
+// @Success      200  {object}  models.Payment
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /payments/webhook [post]
+func PaymentWebhook(c *gin.Context) {
+	provider, err := services.CurrentPaymentProvider()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Contenu illisible"})
+		return
+	}
+	event, err := provider.VerifyWebhook(payload, c.GetHeader("X-Payment-Signature"))
+	if errors.Is(err, services.ErrInvalidWebhookSignature) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Événement invalide"})
+		return
+	}
+	payment, err := services.HandlePaymentWebhook(database.DB, event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paiement non trouvé"})
+		return
+	}
+	if err != nil {
+		respondPaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// loadPayment charge le paiement désigné par :id et vérifie que l'utilisateur en est
+// le titulaire ou un administrateur. Écrit la réponse d'erreur le cas échéant.
+func loadPayment(c *gin.Context) (models.Payment, bool) {
+	var payment models.Payment
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return payment, false
+	}
+	if err := database.DB.First(&payment, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paiement non trouvé"})
+		return payment, false
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != payment.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé à ce paiement"})
+		return payment, false
+	}
+	return payment, true
+}
+
+// respondPaymentError traduit les transitions de statut invalides en 409 et les
+// erreurs du prestataire en 502 ; toute autre erreur est une erreur interne
+func respondPaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRefundAmount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrPaymentNotProcessable), errors.Is(err, models.ErrPaymentNotRefundable),
+		errors.Is(err, models.ErrRefundExceedsPayment), errors.Is(err, models.ErrHourPackageUsed),
+		errors.Is(err, models.ErrPaymentNotPayable),
+		errors.Is(err, services.ErrPaymentOutsideProvider), errors.Is(err, services.ErrAdvanceAllocated):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPaymentProvider):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownPaymentProvider), errors.Is(err, services.ErrNoPaymentProvider):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du traitement du paiement"})
+	}
+}
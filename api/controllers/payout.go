@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPayouts godoc
+// @Summary      Liste des relevés de paiement des enseignants
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        enseignant_id  query     int     false  "Enseignant"
+// @Param        status         query     string  false  "Statut (locked, paid)"
+// @Success      200  {array}   models.Payout
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /payouts [get]
+func ListPayouts(c *gin.Context) {
+	query := database.DB.Model(&models.Payout{})
+	if enseignantID := c.Query("enseignant_id"); enseignantID != "" {
+		query = query.Where("enseignant_id = ?", enseignantID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var payouts []models.Payout
+	if err := query.Order("period_start DESC, id DESC").Find(&payouts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des relevés"})
+		return
+	}
+	c.JSON(http.StatusOK, payouts)
+}
+
+// GetPayoutByID godoc
+// @Summary      Détail d'un relevé de paiement
+// @Description  Accessible aux administrateurs et à l'enseignant concerné
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du relevé"
+// @Success      200  {object}  models.Payout
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /payouts/{id} [get]
+func GetPayoutByID(c *gin.Context) {
+	payout, ok := loadPayout(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// PreviewPayouts godoc
+// @Summary      Prévisualisation des relevés de paiement
+// @Description  Calcule, sans les enregistrer, les sommes dues à chaque enseignant pour la période : cours terminés aux heures approuvées, au taux de la mission ou de l'offre, moins les avances et les pénalités d'annulation en attente. Les cours écartés (versement bloqué, absence de taux) sont listés avec leur motif.
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PayoutPeriodRequest  true  "Période et enseignant"
+// @Success      200  {object}  models.PayoutPreviewResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /payouts/preview [post]
+func PreviewPayouts(c *gin.Context) {
+	var req models.PayoutPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, end, err := services.ParsePayoutPeriod(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	preview, err := services.PreviewPayouts(database.DB, start, end, req.EnseignantID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul des relevés"})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// LockPayouts godoc
+// @Summary      Verrouillage des relevés de paiement
+// @Description  Enregistre les relevés de la période tels que prévisualisés et notifie les enseignants. Les cours, avances et pénalités repris ne figureront sur aucun autre relevé ; les pénalités déduites sont soldées.
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PayoutPeriodRequest  true  "Période et enseignant"
+// @Success      201  {array}   models.Payout
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /payouts [post]
+func LockPayouts(c *gin.Context) {
+	var req models.PayoutPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, end, err := services.ParsePayoutPeriod(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	payouts, err := services.LockPayouts(database.DB, start, end, req.EnseignantID, adminID)
+	if err != nil {
+		respondPayoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payouts)
+}
+
+// MarkPayoutPaid godoc
+// @Summary      Marquer un relevé comme payé
+// @Description  Enregistre le virement d'un relevé verrouillé et en informe l'enseignant
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                      true   "ID du relevé"
+// @Param        request  body      models.PayoutPayRequest  false  "Référence du virement"
+// @Success      200  {object}  models.Payout
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /payouts/{id}/pay [post]
+func MarkPayoutPaid(c *gin.Context) {
+	var req models.PayoutPayRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	payout, ok := loadPayout(c)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	if err := services.MarkPayoutPaid(database.DB, payout, adminID, req.PaymentReference); err != nil {
+		respondPayoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// loadPayout charge le relevé désigné par :id, visible des administrateurs et de
+// l'enseignant concerné. Écrit la réponse d'erreur le cas échéant.
+func loadPayout(c *gin.Context) (*models.Payout, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return nil, false
+	}
+	payout, err := services.LoadPayout(database.DB, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relevé non trouvé"})
+		return nil, false
+	}
+	userID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && payout.EnseignantID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relevé non trouvé"})
+		return nil, false
+	}
+	return payout, true
+}
+
+// respondPayoutError traduit les opérations interdites sur un relevé en 409
+func respondPayoutError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrPayoutAlreadyPaid), errors.Is(err, services.ErrNothingToPay):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du traitement du relevé"})
+	}
+}
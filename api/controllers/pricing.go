@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListPricingRules godoc
+// @Summary      Liste des règles de tarification
+// @Description  Règles appliquées au taux horaire des cours, par priorité croissante : taux de base par matière et niveau, majorations (soirée, week-end, distance) et remises (mission longue, fratrie) (admin seulement)
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Uniquement les règles actives"
+// @Success      200  {array}   models.PricingRule
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /pricing/rules [get]
+func ListPricingRules(c *gin.Context) {
+	var rules []models.PricingRule
+	query := database.DB
+	if c.Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("priority, id").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des règles de tarification"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreatePricingRule godoc
+// @Summary      Création d'une règle de tarification
+// @Description  Ajoute une règle de tarification (admin seulement). Un taux de base exige un taux horaire ; une majoration ou une remise, un montant horaire ou un pourcentage.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PricingRuleRequest  true  "Règle de tarification"
+// @Success      201  {object}  models.PricingRule
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /pricing/rules [post]
+func CreatePricingRule(c *gin.Context) {
+	var req models.PricingRuleRequest
+	if !bindPricingRuleRequest(c, &req) {
+		return
+	}
+	var rule models.PricingRule
+	applyPricingRuleRequest(&rule, req)
+	active := rule.IsActive
+	if err := database.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la règle de tarification"})
+		return
+	}
+	// La valeur par défaut de la colonne ignore un is_active=false à la création
+	if !active {
+		database.DB.Model(&rule).Update("is_active", false)
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdatePricingRule godoc
+// @Summary      Mise à jour d'une règle de tarification
+// @Description  Modifie une règle de tarification (admin seulement). Les cours déjà facturés ne sont pas recalculés.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                        true  "ID de la règle"
+// @Param        request  body      models.PricingRuleRequest  true  "Règle de tarification"
+// @Success      200  {object}  models.PricingRule
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /pricing/rules/{id} [put]
+func UpdatePricingRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.PricingRuleRequest
+	if !bindPricingRuleRequest(c, &req) {
+		return
+	}
+	var rule models.PricingRule
+	if err := database.DB.First(&rule, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Règle de tarification non trouvée"})
+		return
+	}
+	applyPricingRuleRequest(&rule, req)
+	if err := database.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la règle de tarification"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeletePricingRule godoc
+// @Summary      Suppression d'une règle de tarification
+// @Description  Supprime une règle de tarification (admin seulement)
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la règle"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /pricing/rules/{id} [delete]
+func DeletePricingRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if err := database.DB.Delete(&models.PricingRule{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression de la règle de tarification"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func bindPricingRuleRequest(c *gin.Context, req *models.PricingRuleRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func applyPricingRuleRequest(rule *models.PricingRule, req models.PricingRuleRequest) {
+	rule.Name = req.Name
+	rule.Kind = req.Kind
+	rule.Priority = req.Priority
+	rule.Subject = req.Subject
+	rule.Level = req.Level
+	rule.Condition = req.Condition
+	rule.Threshold = req.Threshold
+	rule.Rate = req.Rate
+	rule.Percent = req.Percent
+	rule.StopProcessing = req.StopProcessing
+	rule.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// QuotePrice godoc
+// @Summary      Devis d'un cours ou d'une mission
+// @Description  Calcule le taux horaire et le prix d'un cours avant sa création, avec le détail des règles de tarification appliquées. Les valeurs absentes sont reprises de la mission, puis de l'offre.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PricingQuoteRequest  true  "Cours ou mission à chiffrer"
+// @Success      200  {object}  models.PricingQuoteResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /pricing/quote [post]
+func QuotePrice(c *gin.Context) {
+	var req models.PricingQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.EndDate != nil && req.StartDate != nil && req.EndDate.Before(*req.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La date de fin doit être postérieure à la date de début"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	familleID := req.FamilleID
+	if !middleware.IsAdmin(c) {
+		familleID = 0
+		if role, _ := middleware.GetUserRole(c); role == "famille" {
+			familleID = userID
+		}
+		if req.MissionID != nil {
+			var mission models.Mission
+			if err := database.DB.First(&mission, *req.MissionID).Error; err != nil ||
+				(mission.FamilleID != userID && mission.EnseignantID != userID) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Mission non trouvée"})
+				return
+			}
+		}
+	}
+
+	quote, err := services.QuotePrice(database.DB, req, familleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Mission ou offre non trouvée"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul du devis"})
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPromoCodes godoc
+// @Summary      Liste des codes promo
+// @Description  Codes promo des campagnes d'acquisition, avec leur nombre d'utilisations (admin seulement)
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Uniquement les codes actifs"
+// @Success      200  {array}   models.PromoCode
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes [get]
+func ListPromoCodes(c *gin.Context) {
+	var codes []models.PromoCode
+	query := database.DB
+	if c.Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("code").Find(&codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des codes promo"})
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// CreatePromoCode godoc
+// @Summary      Création d'un code promo
+// @Description  Ajoute un code promo en pourcentage ou à montant fixe, avec ses limites d'utilisation et sa période de validité (admin seulement)
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PromoCodeRequest  true  "Code promo"
+// @Success      201  {object}  models.PromoCode
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes [post]
+func CreatePromoCode(c *gin.Context) {
+	var req models.PromoCodeRequest
+	if !bindPromoCodeRequest(c, &req) {
+		return
+	}
+	var existing int64
+	database.DB.Unscoped().Model(&models.PromoCode{}).Where("code = ?", models.NormalizePromoCode(req.Code)).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ce code promo existe déjà"})
+		return
+	}
+	var promo models.PromoCode
+	applyPromoCodeRequest(&promo, req)
+	active := promo.IsActive
+	if err := database.DB.Create(&promo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du code promo"})
+		return
+	}
+	// La valeur par défaut de la colonne ignore un is_active=false à la création
+	if !active {
+		database.DB.Model(&promo).Update("is_active", false)
+	}
+	c.JSON(http.StatusCreated, promo)
+}
+
+// UpdatePromoCode godoc
+// @Summary      Mise à jour d'un code promo
+// @Description  Modifie un code promo (admin seulement). Le code lui-même ne change pas ; les remises déjà appliquées ne sont pas recalculées.
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                      true  "ID du code promo"
+// @Param        request  body      models.PromoCodeRequest  true  "Code promo"
+// @Success      200  {object}  models.PromoCode
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /promo-codes/{id} [put]
+func UpdatePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.PromoCodeRequest
+	if !bindPromoCodeRequest(c, &req) {
+		return
+	}
+	var promo models.PromoCode
+	if err := database.DB.First(&promo, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Code promo non trouvé"})
+		return
+	}
+	code := promo.Code
+	applyPromoCodeRequest(&promo, req)
+	promo.Code = code
+	if err := database.DB.Save(&promo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour du code promo"})
+		return
+	}
+	c.JSON(http.StatusOK, promo)
+}
+
+// DeletePromoCode godoc
+// @Summary      Suppression d'un code promo
+// @Description  Retire un code promo (admin seulement). Les remises déjà accordées sur les missions restent acquises.
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du code promo"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes/{id} [delete]
+func DeletePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if err := database.DB.Delete(&models.PromoCode{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression du code promo"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetPromoCodeRedemptions godoc
+// @Summary      Utilisations d'un code promo
+// @Description  Missions et factures auxquelles le code a été appliqué, avec les remises accordées cours par cours (admin seulement)
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du code promo"
+// @Success      200  {array}   models.PromoRedemption
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes/{id}/redemptions [get]
+func GetPromoCodeRedemptions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var redemptions []models.PromoRedemption
+	if err := database.DB.Preload("Discounts").Where("promo_code_id = ?", id).Order("id").Find(&redemptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des utilisations du code promo"})
+		return
+	}
+	c.JSON(http.StatusOK, redemptions)
+}
+
+// CheckPromoCode godoc
+// @Summary      Vérification d'un code promo
+// @Description  Indique, sans l'appliquer, si la famille peut utiliser le code promo et pour quelle remise
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.PromoCodeCheckRequest  true  "Code saisi"
+// @Success      200  {object}  models.PromoCodeCheckResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes/check [post]
+func CheckPromoCode(c *gin.Context) {
+	var req models.PromoCodeCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	familleID := req.FamilleID
+	if !middleware.IsAdmin(c) {
+		familleID, _ = middleware.GetUserID(c)
+	}
+	response, err := services.CheckPromoCode(database.DB, req, familleID)
+	if errors.Is(err, models.ErrPromoCodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du code promo"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPromotionReport godoc
+// @Summary      Bilan des campagnes d'acquisition
+// @Description  Utilisations et remises accordées par code promo, invitations, inscriptions et récompenses de parrainage sur la période (admin seulement)
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from  query     string  false  "Début de période (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to    query     string  false  "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Success      200  {object}  models.PromotionReportResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /promo-codes/report [get]
+func GetPromotionReport(c *gin.Context) {
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	report, err := services.PromotionReport(database.DB, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du bilan des campagnes"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func bindPromoCodeRequest(c *gin.Context, req *models.PromoCodeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func applyPromoCodeRequest(promo *models.PromoCode, req models.PromoCodeRequest) {
+	promo.Code = models.NormalizePromoCode(req.Code)
+	promo.Description = req.Description
+	promo.Kind = req.Kind
+	promo.Percent = req.Percent
+	promo.Amount = req.Amount
+	if req.Kind == models.PromoCodePercentage {
+		promo.Amount = models.NewMoney(0, models.DefaultCurrency)
+	}
+	promo.MaxUses = req.MaxUses
+	promo.MaxUsesPerFamille = 1
+	if req.MaxUsesPerFamille != nil {
+		promo.MaxUsesPerFamille = *req.MaxUsesPerFamille
+	}
+	promo.ValidFrom = req.ValidFrom
+	promo.ValidUntil = req.ValidUntil
+	promo.FirstMissionOnly = req.FirstMissionOnly
+	promo.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// respondPromoError traduit le refus d'un code promo saisi à la création d'une
+// mission ou d'une facture ; retourne false pour toute autre erreur
+func respondPromoError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, models.ErrPromoCodeNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrPromoCodeNotValid), errors.Is(err, models.ErrPromoCodeExhausted),
+		errors.Is(err, models.ErrPromoCodeAlreadyUsed), errors.Is(err, models.ErrPromoCodeFirstMissionOnly):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// ListReferrals godoc
+// @Summary      Liste des parrainages
+// @Description  Invitations de toutes les familles et leur avancement (admin seulement)
+// @Tags         referrals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status  query     string  false  "Statut (pending, registered, rewarded, cancelled)"
+// @Success      200  {array}   models.Referral
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /referrals [get]
+func ListReferrals(c *gin.Context) {
+	var referrals []models.Referral
+	query := database.DB
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&referrals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des parrainages"})
+		return
+	}
+	c.JSON(http.StatusOK, referrals)
+}
+
+// ListFamilleReferrals godoc
+// @Summary      Parrainages d'une famille
+// @Description  Familles invitées par la famille et avancement de chaque invitation jusqu'à la récompense
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la famille"
+// @Success      200  {array}   models.Referral
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /familles/{id}/referrals [get]
+func ListFamilleReferrals(c *gin.Context) {
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	var referrals []models.Referral
+	if err := database.DB.Where("referrer_id = ?", id).Order("created_at DESC").Find(&referrals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des parrainages"})
+		return
+	}
+	c.JSON(http.StatusOK, referrals)
+}
+
+// InviteFamilleReferral godoc
+// @Summary      Parrainage d'une nouvelle famille
+// @Description  Invite une famille non inscrite ; le code de parrainage retourné est à saisir à l'inscription. Les deux familles reçoivent des heures offertes lorsque le premier cours de la famille invitée est payé.
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "ID de la famille"
+// @Param        request  body      models.ReferralInviteRequest  true  "Adresse e-mail de la famille invitée"
+// @Success      201  {object}  models.Referral
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /familles/{id}/referrals [post]
+func InviteFamilleReferral(c *gin.Context) {
+	var req models.ReferralInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	var famille models.Famille
+	if err := database.DB.First(&famille, "user_id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Famille non trouvée"})
+		return
+	}
+	referral, err := services.InviteFamille(database.DB, id, req.Email)
+	switch {
+	case errors.Is(err, models.ErrReferralEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement de l'invitation"})
+	default:
+		c.JSON(http.StatusCreated, referral)
+	}
+}
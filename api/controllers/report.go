@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListReports godoc
+// @Summary      Liste des rapports
+// @Description  Récupère les rapports visibles par l'utilisateur : tous pour un administrateur, ses propres rapports pour un enseignant, les rapports validés de ses missions pour une famille
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status         query     string  false  "Statut (draft, submitted, validated, rejected)"
+// @Param        mission_id     query     int     false  "ID de la mission"
+// @Param        enseignant_id  query     int     false  "ID de l'enseignant"
+// @Success      200  {array}   models.Report
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /reports [get]
+func ListReports(c *gin.Context) {
+	var reports []models.Report
+	query := database.DB.Scopes(visibleReports(c))
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if missionID := c.Query("mission_id"); missionID != "" {
+		query = query.Where("mission_id = ?", missionID)
+	}
+	if enseignantID := c.Query("enseignant_id"); enseignantID != "" {
+		query = query.Where("enseignant_id = ?", enseignantID)
+	}
+	if err := query.Order("updated_at DESC").Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des rapports"})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetReportByID godoc
+// @Summary      Détails d'un rapport
+// @Description  Récupère un rapport ; une famille ne peut consulter que les rapports validés
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du rapport"
+// @Success      200  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /reports/{id} [get]
+func GetReportByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var report models.Report
+	if err := database.DB.Scopes(visibleReports(c)).Preload("Answers").First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rapport non trouvé"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// CreateReport godoc
+// @Summary      Rédaction d'un rapport
+// @Description  L'enseignant de la mission crée un rapport en brouillon, ou le soumet directement avec submit=true. Un modèle (template_id) permet de joindre des réponses structurées au texte libre.
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.ReportCreateRequest  true  "Contenu du rapport"
+// @Success      201  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /reports [post]
+func CreateReport(c *gin.Context) {
+	var req models.ReportCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var mission models.Mission
+	if err := database.DB.First(&mission, req.MissionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mission non trouvée"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if currentID != mission.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seul l'enseignant de la mission peut rédiger un rapport"})
+		return
+	}
+	report, err := services.CreateReport(database.DB, &mission, req)
+	if err != nil {
+		respondReportError(c, err, "Erreur lors de la création du rapport")
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// UpdateReport godoc
+// @Summary      Modification d'un rapport
+// @Description  L'enseignant modifie un rapport en brouillon ou rejeté (texte et réponses structurées) ; chaque modification est conservée dans l'historique
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                         true  "ID du rapport"
+// @Param        request  body      models.ReportUpdateRequest  true  "Nouveau contenu"
+// @Success      200  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reports/{id} [put]
+func UpdateReport(c *gin.Context) {
+	var req models.ReportUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, ok := loadAuthoredReport(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if err := services.UpdateReport(database.DB, &report, currentID, req); err != nil {
+		respondReportError(c, err, "Erreur lors de la mise à jour du rapport")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// DeleteReport godoc
+// @Summary      Suppression d'un rapport
+// @Description  L'enseignant supprime un brouillon ; un administrateur peut supprimer n'importe quel rapport
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du rapport"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reports/{id} [delete]
+func DeleteReport(c *gin.Context) {
+	report, ok := loadAuthoredReport(c)
+	if !ok {
+		return
+	}
+	if !middleware.IsAdmin(c) && report.Status != models.ReportStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "Seul un brouillon peut être supprimé"})
+		return
+	}
+	if err := database.DB.Delete(&report).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression du rapport"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SubmitReport godoc
+// @Summary      Soumission d'un rapport
+// @Description  L'enseignant soumet un brouillon, ou une nouvelle révision d'un rapport rejeté, à la validation des administrateurs
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du rapport"
+// @Success      200  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reports/{id}/submit [put]
+func SubmitReport(c *gin.Context) {
+	report, ok := loadAuthoredReport(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if err := services.SubmitReport(database.DB, &report, currentID); err != nil {
+		respondReportError(c, err, "Erreur lors de la soumission du rapport")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ValidateReport godoc
+// @Summary      Validation d'un rapport
+// @Description  Un administrateur valide un rapport soumis ; il devient visible pour la famille
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                         true   "ID du rapport"
+// @Param        request  body      models.ReportReviewRequest  false  "Commentaires"
+// @Success      200  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reports/{id}/validate [put]
+func ValidateReport(c *gin.Context) {
+	var req models.ReportReviewRequest
+	c.ShouldBindJSON(&req)
+	report, ok := loadReport(c)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	if err := services.ValidateReport(database.DB, &report, adminID, req.Comments); err != nil {
+		respondReportError(c, err, "Erreur lors de la validation du rapport")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RejectReport godoc
+// @Summary      Rejet d'un rapport
+// @Description  Un administrateur rejette un rapport soumis avec des commentaires ; le rapport revient à l'enseignant pour révision
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                         true  "ID du rapport"
+// @Param        request  body      models.ReportReviewRequest  true  "Commentaires"
+// @Success      200  {object}  models.Report
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reports/{id}/reject [put]
+func RejectReport(c *gin.Context) {
+	var req models.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Comments == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Un commentaire est requis pour rejeter un rapport"})
+		return
+	}
+	report, ok := loadReport(c)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	if err := services.RejectReport(database.DB, &report, adminID, req.Comments); err != nil {
+		respondReportError(c, err, "Erreur lors du rejet du rapport")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetReportRevisions godoc
+// @Summary      Historique d'un rapport
+// @Description  Récupère toutes les versions du rapport (création, modifications, soumissions, décisions)
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du rapport"
+// @Success      200  {array}   models.ReportRevision
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /reports/{id}/revisions [get]
+func GetReportRevisions(c *gin.Context) {
+	report, ok := loadAuthoredReport(c)
+	if !ok {
+		return
+	}
+	var revisions []models.ReportRevision
+	database.DB.Where("report_id = ?", report.ID).Order("id").Find(&revisions)
+	c.JSON(http.StatusOK, revisions)
+}
+
+// visibleReports restreint les rapports à ceux que l'utilisateur peut consulter :
+// tous pour un administrateur ; sinon ses propres rapports et les rapports validés
+// des missions auxquelles il participe
+func visibleReports(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if middleware.IsAdmin(c) {
+			return db
+		}
+		currentID, _ := middleware.GetUserID(c)
+		return db.Where("enseignant_id = ? OR (status = ? AND mission_id IN (?))",
+			currentID, models.ReportStatusValidated,
+			database.DB.Model(&models.Mission{}).Select("id").Where("famille_id = ? OR enseignant_id = ?", currentID, currentID))
+	}
+}
+
+// loadReport charge le rapport désigné par :id
+func loadReport(c *gin.Context) (models.Report, bool) {
+	var report models.Report
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return report, false
+	}
+	if err := database.DB.First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rapport non trouvé"})
+		return report, false
+	}
+	return report, true
+}
+
+// loadAuthoredReport charge le rapport désigné par :id et vérifie que l'utilisateur
+// en est l'auteur ou un administrateur. Écrit la réponse d'erreur le cas échéant.
+func loadAuthoredReport(c *gin.Context) (models.Report, bool) {
+	report, ok := loadReport(c)
+	if !ok {
+		return report, false
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != report.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seul l'auteur du rapport peut effectuer cette action"})
+		return report, false
+	}
+	return report, true
+}
+
+// respondReportError traduit les transitions de statut invalides en 409 et les
+// réponses structurées invalides en 400
+func respondReportError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrReportNotEditable), errors.Is(err, models.ErrReportNotSubmitted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrInvalidAnswer), errors.Is(err, services.ErrTemplateInactive):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Modèle de rapport non trouvé"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
+
+// ListOverdueReports godoc
+// @Summary      Rapports en retard
+// @Description  Liste les missions actives dont l'échéance de rapport est dépassée, du retard le plus ancien au plus récent
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.OverdueReportResponse
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /reports/overdue [get]
+func ListOverdueReports(c *gin.Context) {
+	overdue, err := services.OverdueReports(database.DB, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des rapports en retard"})
+		return
+	}
+	c.JSON(http.StatusOK, overdue)
+}
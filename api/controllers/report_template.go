@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"api/database"
+	"api/models"
+	"api/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListReportTemplates godoc
+// @Summary      Liste des modèles de rapport
+// @Description  Récupère les modèles de rapport définis par les administrateurs
+// @Tags         report-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        subject  query     string  false  "Matière"
+// @Param        level    query     string  false  "Niveau"
+// @Param        active   query     bool    false  "Uniquement les modèles actifs"
+// @Success      200  {array}   models.ReportTemplate
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /report-templates [get]
+func ListReportTemplates(c *gin.Context) {
+	var templates []models.ReportTemplate
+	query := database.DB
+	if subject := c.Query("subject"); subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	if level := c.Query("level"); level != "" {
+		query = query.Where("level = ?", level)
+	}
+	if c.Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("name").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des modèles de rapport"})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetReportTemplateByID godoc
+// @Summary      Détails d'un modèle de rapport
+// @Description  Récupère un modèle avec ses sections, questions et compétences évaluées
+// @Tags         report-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du modèle"
+// @Success      200  {object}  models.ReportTemplate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /report-templates/{id} [get]
+func GetReportTemplateByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	template, err := services.LoadReportTemplate(database.DB, uint(id))
+	if err != nil || template.DeletedAt.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modèle de rapport non trouvé"})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateReportTemplate godoc
+// @Summary      Création d'un modèle de rapport
+// @Description  Définit un modèle de rapport : échelle de notation, sections et questions liées aux compétences (admin seulement)
+// @Tags         report-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.ReportTemplateRequest  true  "Modèle de rapport"
+// @Success      201  {object}  models.ReportTemplate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /report-templates [post]
+func CreateReportTemplate(c *gin.Context) {
+	var req models.ReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template, err := services.BuildReportTemplate(database.DB, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	active := template.IsActive
+	if err := database.DB.Create(template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du modèle de rapport"})
+		return
+	}
+	// La valeur par défaut de la colonne ignore un is_active=false à la création
+	if !active {
+		database.DB.Model(template).Update("is_active", false)
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateReportTemplate godoc
+// @Summary      Mise à jour d'un modèle de rapport
+// @Description  Remplace la définition d'un modèle (admin seulement). Un modèle déjà utilisé par des rapports ne peut plus être modifié.
+// @Tags         report-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "ID du modèle"
+// @Param        request  body      models.ReportTemplateRequest  true  "Modèle de rapport"
+// @Success      200  {object}  models.ReportTemplate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /report-templates/{id} [put]
+func UpdateReportTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.ReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var existing models.ReportTemplate
+	if err := database.DB.First(&existing, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modèle de rapport non trouvé"})
+		return
+	}
+	if services.ReportTemplateInUse(database.DB, existing.ID) {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrTemplateInUse.Error()})
+		return
+	}
+	template, err := services.BuildReportTemplate(database.DB, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template.ID = existing.ID
+	template.CreatedAt = existing.CreatedAt
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var sectionIDs []uint
+		tx.Model(&models.ReportTemplateSection{}).Where("template_id = ?", existing.ID).Pluck("id", &sectionIDs)
+		if len(sectionIDs) > 0 {
+			if err := tx.Where("section_id IN ?", sectionIDs).Delete(&models.ReportTemplateItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("template_id = ?", existing.ID).Delete(&models.ReportTemplateSection{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(template).Error; err != nil {
+			return err
+		}
+		return tx.Model(template).Update("is_active", req.IsActive == nil || *req.IsActive).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour du modèle de rapport"})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteReportTemplate godoc
+// @Summary      Suppression d'un modèle de rapport
+// @Description  Supprime un modèle (admin seulement). Un modèle déjà utilisé est seulement désactivé afin de préserver les rapports existants.
+// @Tags         report-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du modèle"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /report-templates/{id} [delete]
+func DeleteReportTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var result *gorm.DB
+	if services.ReportTemplateInUse(database.DB, uint(id)) {
+		result = database.DB.Model(&models.ReportTemplate{}).Where("id = ?", id).Update("is_active", false)
+	} else {
+		result = database.DB.Delete(&models.ReportTemplate{}, id)
+	}
+	if err := result.Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression du modèle de rapport"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
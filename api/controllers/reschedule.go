@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateRescheduleRequest godoc
+// @Summary      Demande de report d'un cours
+// @Description  L'enseignant ou la famille propose un nouveau créneau ; le cours garde son horaire tant que l'autre partie n'a pas accepté
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "ID du cours"
+// @Param        request  body      models.RescheduleCreateRequest  true  "Créneau proposé"
+// @Success      201  {object}  models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /courses/{id}/reschedule-requests [post]
+func CreateRescheduleRequest(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return
+	}
+	var req models.RescheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var course models.Course
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if currentID != course.FamilleID && currentID != course.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seules les parties du cours peuvent demander un report"})
+		return
+	}
+
+	request, err := services.CreateRescheduleRequest(database.DB, &course, currentID, req, nil)
+	if err != nil {
+		respondRescheduleError(c, err, "Erreur lors de la création de la demande de report")
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// GetCourseRescheduleRequests godoc
+// @Summary      Demandes de report d'un cours
+// @Description  Récupère l'historique des demandes de report et contre-propositions d'un cours
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du cours"
+// @Success      200  {array}   models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /courses/{id}/reschedule-requests [get]
+func GetCourseRescheduleRequests(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return
+	}
+	var course models.Course
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != course.FamilleID && currentID != course.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	var requests []models.RescheduleRequest
+	database.DB.Where("course_id = ?", courseID).Order("created_at").Find(&requests)
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetRescheduleRequestByID godoc
+// @Summary      Détails d'une demande de report
+// @Tags         reschedule
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la demande"
+// @Success      200  {object}  models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /reschedule-requests/{id} [get]
+func GetRescheduleRequestByID(c *gin.Context) {
+	request, ok := loadRescheduleRequest(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != request.RequestedByID && currentID != request.RespondentID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// AcceptRescheduleRequest godoc
+// @Summary      Acceptation d'une demande de report
+// @Description  L'autre partie accepte le créneau proposé ; les disponibilités sont revérifiées puis le cours est déplacé
+// @Tags         reschedule
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true   "ID de la demande"
+// @Param        request  body      models.RescheduleResponseRequest  false  "Commentaire"
+// @Success      200  {object}  CourseResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reschedule-requests/{id}/accept [put]
+func AcceptRescheduleRequest(c *gin.Context) {
+	var req models.RescheduleResponseRequest
+	c.ShouldBindJSON(&req)
+	request, ok := loadOpenRescheduleRequest(c, true)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	course, err := services.AcceptRescheduleRequest(database.DB, &request, currentID, req.Note)
+	if err != nil {
+		respondRescheduleError(c, err, "Erreur lors de l'acceptation de la demande de report")
+		return
+	}
+	c.JSON(http.StatusOK, CourseResponse{Course: *course})
+}
+
+// RejectRescheduleRequest godoc
+// @Summary      Refus d'une demande de report
+// @Description  L'autre partie refuse le créneau proposé ; le cours garde son horaire initial
+// @Tags         reschedule
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true   "ID de la demande"
+// @Param        request  body      models.RescheduleResponseRequest  false  "Motif du refus"
+// @Success      200  {object}  models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reschedule-requests/{id}/reject [put]
+func RejectRescheduleRequest(c *gin.Context) {
+	var req models.RescheduleResponseRequest
+	c.ShouldBindJSON(&req)
+	request, ok := loadOpenRescheduleRequest(c, true)
+	if !ok {
+		return
+	}
+	if err := services.CloseRescheduleRequest(database.DB, &request, models.RescheduleStatusRejected, req.Note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du refus de la demande de report"})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// CounterRescheduleRequest godoc
+// @Summary      Contre-proposition de report
+// @Description  L'autre partie propose un créneau différent ; la demande initiale est close et une nouvelle demande est adressée à son auteur
+// @Tags         reschedule
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "ID de la demande"
+// @Param        request  body      models.RescheduleCreateRequest  true  "Créneau contre-proposé"
+// @Success      201  {object}  models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reschedule-requests/{id}/counter [put]
+func CounterRescheduleRequest(c *gin.Context) {
+	var req models.RescheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	request, ok := loadOpenRescheduleRequest(c, true)
+	if !ok {
+		return
+	}
+	var course models.Course
+	if err := database.DB.First(&course, request.CourseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	counter, err := services.CounterRescheduleRequest(database.DB, &request, &course, currentID, req)
+	if err != nil {
+		respondRescheduleError(c, err, "Erreur lors de la contre-proposition")
+		return
+	}
+	c.JSON(http.StatusCreated, counter)
+}
+
+// CancelRescheduleRequest godoc
+// @Summary      Retrait d'une demande de report
+// @Description  L'auteur retire sa demande tant qu'elle n'a pas reçu de réponse
+// @Tags         reschedule
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la demande"
+// @Success      200  {object}  models.RescheduleRequest
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /reschedule-requests/{id}/cancel [put]
+func CancelRescheduleRequest(c *gin.Context) {
+	request, ok := loadOpenRescheduleRequest(c, false)
+	if !ok {
+		return
+	}
+	if err := services.CloseRescheduleRequest(database.DB, &request, models.RescheduleStatusCancelled, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du retrait de la demande de report"})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// loadRescheduleRequest charge la demande désignée par :id
+func loadRescheduleRequest(c *gin.Context) (models.RescheduleRequest, bool) {
+	var request models.RescheduleRequest
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return request, false
+	}
+	if err := database.DB.First(&request, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Demande de report non trouvée"})
+		return request, false
+	}
+	return request, true
+}
+
+// loadOpenRescheduleRequest charge une demande encore en attente et vérifie que
+// l'utilisateur est le destinataire (respondent) ou l'auteur de la demande.
+// Écrit la réponse d'erreur le cas échéant.
+func loadOpenRescheduleRequest(c *gin.Context, respondent bool) (models.RescheduleRequest, bool) {
+	request, ok := loadRescheduleRequest(c)
+	if !ok {
+		return request, false
+	}
+	currentID, _ := middleware.GetUserID(c)
+	expected := request.RequestedByID
+	if respondent {
+		expected = request.RespondentID
+	}
+	if !middleware.IsAdmin(c) && currentID != expected {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Vous ne pouvez pas traiter cette demande de report"})
+		return request, false
+	}
+	if !request.IsOpen(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cette demande de report n'est plus en attente"})
+		return request, false
+	}
+	return request, true
+}
+
+// respondRescheduleError traduit les erreurs de disponibilité en 409
+func respondRescheduleError(c *gin.Context, err error, fallback string) {
+	if services.IsSlotError(err) || errors.Is(err, services.ErrReschedulePending) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
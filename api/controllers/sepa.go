@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"api/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ExportPayoutTransfers godoc
+// @Summary      Export des virements SEPA des relevés
+// @Description  Génère le fichier de virements SEPA (ISO 20022 pain.001.001.03) des relevés verrouillés indiqués, ou de tous ceux qui n'ont pas encore été exportés, à transmettre à la banque. Chaque relevé exporté est rattaché au lot et ne peut figurer dans aucun autre fichier. L'identifiant du lot est renvoyé dans l'en-tête X-SEPA-Batch-ID.
+// @Tags         payouts
+// @Accept       json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        request  body      models.SEPAExportRequest  false  "Relevés et date d'exécution"
+// @Success      201  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /payouts/sepa-export [post]
+func ExportPayoutTransfers(c *gin.Context) {
+	var req models.SEPAExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	executionDate, err := services.ParseExecutionDate(req.ExecutionDate, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	batch, err := services.ExportPayoutTransfers(database.DB, req.PayoutIDs, executionDate, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Relevé non trouvé"})
+		case errors.Is(err, services.ErrMissingBankAccount):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrNoPayoutToExport), errors.Is(err, services.ErrPayoutAlreadyExported),
+			errors.Is(err, services.ErrPayoutNotTransferable), errors.Is(err, services.ErrSEPAAccountNotConfigured):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du fichier de virements"})
+		}
+		return
+	}
+	c.Header("X-SEPA-Batch-ID", strconv.FormatUint(uint64(batch.ID), 10))
+	c.Header("Content-Disposition", `attachment; filename="`+batch.MessageID+`.xml"`)
+	c.Data(http.StatusCreated, "application/xml; charset=utf-8", []byte(batch.Content))
+}
+
+// ListSEPATransfers godoc
+// @Summary      Liste des fichiers de virements SEPA
+// @Description  Lots de virements exportés, avec les relevés qu'ils couvrent
+// @Tags         payouts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.SEPATransferBatch
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /sepa-transfers [get]
+func ListSEPATransfers(c *gin.Context) {
+	var batches []models.SEPATransferBatch
+	if err := database.DB.Preload("Payouts").Order("created_at DESC, id DESC").Find(&batches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des fichiers de virements"})
+		return
+	}
+	c.JSON(http.StatusOK, batches)
+}
+
+// GetSEPATransferXML godoc
+// @Summary      Téléchargement d'un fichier de virements SEPA
+// @Description  Renvoie le fichier pain.001 tel qu'il a été généré lors de l'export
+// @Tags         payouts
+// @Accept       json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du lot"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /sepa-transfers/{id}/xml [get]
+func GetSEPATransferXML(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var batch models.SEPATransferBatch
+	if err := database.DB.First(&batch, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fichier de virements non trouvé"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+batch.MessageID+`.xml"`)
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(batch.Content))
+}
+
+// ListFamilleMandates godoc
+// @Summary      Mandats de prélèvement d'une famille
+// @Description  Mandats SEPA signés par la famille, actif et révoqués, avec leur référence unique (RUM). L'IBAN est masqué.
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la famille"
+// @Success      200  {array}   models.SEPAMandate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /familles/{id}/mandates [get]
+func ListFamilleMandates(c *gin.Context) {
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	mandates, err := services.FamilleMandates(database.DB, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des mandats"})
+		return
+	}
+	c.JSON(http.StatusOK, mandates)
+}
+
+// CreateFamilleMandate godoc
+// @Summary      Signature d'un mandat de prélèvement
+// @Description  Enregistre le mandat SEPA (schéma CORE) autorisant le prélèvement des factures de la famille. L'IBAN est vérifié ; le mandat actif précédent est révoqué. Une référence unique de mandat (RUM) est attribuée si elle n'est pas fournie.
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                              true  "ID de la famille"
+// @Param        request  body      models.SEPAMandateCreateRequest  true  "Mandat signé"
+// @Success      201  {object}  models.SEPAMandate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /familles/{id}/mandates [post]
+func CreateFamilleMandate(c *gin.Context) {
+	var req models.SEPAMandateCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	var famille models.Famille
+	if err := database.DB.First(&famille, "user_id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Famille non trouvée"})
+		return
+	}
+	mandate, err := services.CreateMandate(database.DB, id, req)
+	if err != nil {
+		if errors.Is(err, services.ErrMandateReferenceTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, mandate)
+}
+
+// RevokeFamilleMandate godoc
+// @Summary      Révocation d'un mandat de prélèvement
+// @Description  Révoque le mandat : aucune facture ne sera plus prélevée à ce titre. Les prélèvements déjà transmis à la banque ne sont pas annulés.
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int  true  "ID de la famille"
+// @Param        mandate_id  path      int  true  "ID du mandat"
+// @Success      200  {object}  models.SEPAMandate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /familles/{id}/mandates/{mandate_id}/revoke [post]
+func RevokeFamilleMandate(c *gin.Context) {
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	mandateID, err := strconv.ParseUint(c.Param("mandate_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var mandate models.SEPAMandate
+	if err := database.DB.Where("famille_id = ?", id).First(&mandate, mandateID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mandat non trouvé"})
+		return
+	}
+	if err := services.RevokeMandate(database.DB, &mandate); err != nil {
+		if errors.Is(err, models.ErrMandateRevoked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation du mandat"})
+		return
+	}
+	c.JSON(http.StatusOK, mandate)
+}
+
+// familleIDParam lit l'identifiant de la famille, accessible à la famille elle-même
+// et aux administrateurs. Écrit la réponse d'erreur le cas échéant.
+func familleIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return 0, false
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ExportInvoiceDebits godoc
+// @Summary      Export des prélèvements SEPA des factures
+// @Description  Génère le fichier de prélèvements SEPA (ISO 20022 pain.008.001.02, schéma CORE) des factures indiquées, ou de toutes les factures à prélever des familles ayant un mandat actif. Les premiers prélèvements d'un mandat (FRST) sont séparés des prélèvements récurrents (RCUR). Chaque prélèvement est enregistré comme un paiement en attente et la famille est prévenue du montant et de l'échéance, qui doit respecter le délai de préavis. L'identifiant du lot est renvoyé dans l'en-tête X-SEPA-Batch-ID.
+// @Tags         invoices
+// @Accept       json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        request  body      models.SEPADebitExportRequest  false  "Factures et date d'échéance"
+// @Success      201  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /invoices/sepa-debit-export [post]
+func ExportInvoiceDebits(c *gin.Context) {
+	var req models.SEPADebitExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	settings, err := services.CurrentSEPASettings()
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	collectionDate, err := services.ParseCollectionDate(req.CollectionDate, time.Now(), settings.PrenotificationDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	batch, err := services.ExportInvoiceDebits(database.DB, req.InvoiceIDs, collectionDate, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Facture non trouvée"})
+		case errors.Is(err, services.ErrNoActiveMandate):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrNoInvoiceToCollect), errors.Is(err, services.ErrInvoiceNotCollectable),
+			errors.Is(err, services.ErrSEPAAccountNotConfigured), errors.Is(err, services.ErrSEPACreditorNotConfigured):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du fichier de prélèvements"})
+		}
+		return
+	}
+	c.Header("X-SEPA-Batch-ID", strconv.FormatUint(uint64(batch.ID), 10))
+	c.Header("Content-Disposition", `attachment; filename="`+batch.MessageID+`.xml"`)
+	c.Data(http.StatusCreated, "application/xml; charset=utf-8", []byte(batch.Content))
+}
+
+// ListSEPADebits godoc
+// @Summary      Liste des fichiers de prélèvements SEPA
+// @Description  Lots de prélèvements exportés, avec les paiements qu'ils ont créés et leur état
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.SEPADebitBatch
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /sepa-debits [get]
+func ListSEPADebits(c *gin.Context) {
+	var batches []models.SEPADebitBatch
+	if err := database.DB.Preload("Payments").Order("created_at DESC, id DESC").Find(&batches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des fichiers de prélèvements"})
+		return
+	}
+	c.JSON(http.StatusOK, batches)
+}
+
+// GetSEPADebitXML godoc
+// @Summary      Téléchargement d'un fichier de prélèvements SEPA
+// @Description  Renvoie le fichier pain.008 tel qu'il a été généré lors de l'export
+// @Tags         invoices
+// @Accept       json
+// @Produce      xml
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du lot"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /sepa-debits/{id}/xml [get]
+func GetSEPADebitXML(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var batch models.SEPADebitBatch
+	if err := database.DB.First(&batch, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fichier de prélèvements non trouvé"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+batch.MessageID+`.xml"`)
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(batch.Content))
+}
+
+// ImportSEPADebitReturns godoc
+// @Summary      Import d'un avis de la banque (camt.054)
+// @Description  Applique un avis de crédit ou de débit camt.054 aux prélèvements exportés : les prélèvements crédités et comptabilisés sont encaissés, les rejets et retours (R-transactions) passent à l'état échoué avec leur motif et la famille est prévenue. Le fichier XML est transmis tel quel dans le corps de la requête ; un fichier déjà importé peut l'être de nouveau sans effet.
+// @Tags         invoices
+// @Accept       xml
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  body      string  true  "Fichier camt.054"
+// @Success      200  {object}  models.SEPAReturnImportResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /sepa-debits/returns [post]
+func ImportSEPADebitReturns(c *gin.Context) {
+	content, err := c.GetRawData()
+	if err != nil || len(content) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fichier camt.054 manquant"})
+		return
+	}
+	result, err := services.ImportDebitReturns(database.DB, content)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidCamt054) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'import de l'avis"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
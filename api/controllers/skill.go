@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSkills godoc
+// @Summary      Liste des compétences
+// @Description  Récupère le référentiel des compétences évaluées, par matière et niveau
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        subject  query     string  false  "Matière"
+// @Param        level    query     string  false  "Niveau"
+// @Success      200  {array}   models.Skill
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /skills [get]
+func ListSkills(c *gin.Context) {
+	var skills []models.Skill
+	query := database.DB
+	if subject := c.Query("subject"); subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	if level := c.Query("level"); level != "" {
+		query = query.Where("level = ?", level)
+	}
+	if err := query.Order("subject, level, name").Find(&skills).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des compétences"})
+		return
+	}
+	c.JSON(http.StatusOK, skills)
+}
+
+// CreateSkill godoc
+// @Summary      Création d'une compétence
+// @Description  Ajoute une compétence au référentiel (admin seulement)
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.SkillRequest  true  "Compétence"
+// @Success      201  {object}  models.Skill
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /skills [post]
+func CreateSkill(c *gin.Context) {
+	var req models.SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	skill := models.Skill{Subject: req.Subject, Level: req.Level, Name: req.Name, Description: req.Description}
+	if err := database.DB.Create(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la compétence"})
+		return
+	}
+	c.JSON(http.StatusCreated, skill)
+}
+
+// UpdateSkill godoc
+// @Summary      Mise à jour d'une compétence
+// @Description  Modifie une compétence du référentiel (admin seulement)
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                  true  "ID de la compétence"
+// @Param        request  body      models.SkillRequest  true  "Compétence"
+// @Success      200  {object}  models.Skill
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /skills/{id} [put]
+func UpdateSkill(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var skill models.Skill
+	if err := database.DB.First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Compétence non trouvée"})
+		return
+	}
+	skill.Subject, skill.Level, skill.Name, skill.Description = req.Subject, req.Level, req.Name, req.Description
+	if err := database.DB.Save(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la compétence"})
+		return
+	}
+	c.JSON(http.StatusOK, skill)
+}
+
+// DeleteSkill godoc
+// @Summary      Suppression d'une compétence
+// @Description  Retire une compétence du référentiel (admin seulement) ; l'historique des notes est conservé
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la compétence"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /skills/{id} [delete]
+func DeleteSkill(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if err := database.DB.Delete(&models.Skill{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression de la compétence"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetMissionSkillProgression godoc
+// @Summary      Progression des compétences sur une mission
+// @Description  Retrace, compétence par compétence, les notes des rapports validés de la mission
+// @Tags         missions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int     true   "ID de la mission"
+// @Param        subject  query     string  false  "Matière"
+// @Success      200  {array}   models.SkillProgressionResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /missions/{id}/skill-progression [get]
+func GetMissionSkillProgression(c *gin.Context) {
+	missionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID mission invalide"})
+		return
+	}
+	var mission models.Mission
+	if err := database.DB.First(&mission, missionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mission non trouvée"})
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != mission.FamilleID && currentID != mission.EnseignantID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	progression, err := services.SkillProgression(database.DB, []uint{mission.ID}, c.Query("subject"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de la progression"})
+		return
+	}
+	c.JSON(http.StatusOK, progression)
+}
+
+// GetFamilleSkillProgression godoc
+// @Summary      Progression des compétences d'une famille
+// @Description  Retrace, compétence par compétence, les notes des rapports validés de toutes les missions de la famille
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int     true   "ID de la famille"
+// @Param        subject  query     string  false  "Matière"
+// @Success      200  {array}   models.SkillProgressionResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /familles/{id}/skill-progression [get]
+func GetFamilleSkillProgression(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	var missionIDs []uint
+	database.DB.Model(&models.Mission{}).Where("famille_id = ?", id).Pluck("id", &missionIDs)
+	progression, err := services.SkillProgression(database.DB, missionIDs, c.Query("subject"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul de la progression"})
+		return
+	}
+	c.JSON(http.StatusOK, progression)
+}
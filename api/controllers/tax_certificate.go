@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTaxCertificates godoc
+// @Summary      Liste des attestations fiscales
+// @Description  Les administrateurs voient toutes les attestations, les familles uniquement leurs attestations publiées
+// @Tags         tax-certificates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        year        query     int     false  "Année civile"
+// @Param        famille_id  query     int     false  "Famille (administrateurs uniquement)"
+// @Param        status      query     string  false  "Statut (draft, published) (administrateurs uniquement)"
+// @Success      200  {array}   models.TaxCertificate
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /tax-certificates [get]
+func ListTaxCertificates(c *gin.Context) {
+	query := database.DB.Model(&models.TaxCertificate{})
+	if middleware.IsAdmin(c) {
+		if familleID := c.Query("famille_id"); familleID != "" {
+			query = query.Where("famille_id = ?", familleID)
+		}
+		if status := c.Query("status"); status != "" {
+			query = query.Where("status = ?", status)
+		}
+	} else {
+		userID, _ := middleware.GetUserID(c)
+		query = query.Where("famille_id = ? AND status = ?", userID, models.TaxCertificateStatusPublished)
+	}
+	if year := c.Query("year"); year != "" {
+		query = query.Where("year = ?", year)
+	}
+	var certificates []models.TaxCertificate
+	if err := query.Order("year DESC, famille_id").Find(&certificates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des attestations fiscales"})
+		return
+	}
+	c.JSON(http.StatusOK, certificates)
+}
+
+// GetTaxCertificateByID godoc
+// @Summary      Détail d'une attestation fiscale
+// @Description  Attestation avec le détail des heures et montants par intervenant
+// @Tags         tax-certificates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'attestation"
+// @Success      200  {object}  models.TaxCertificate
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /tax-certificates/{id} [get]
+func GetTaxCertificateByID(c *gin.Context) {
+	certificate, ok := loadTaxCertificate(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, certificate)
+}
+
+// GetTaxCertificatePDF godoc
+// @Summary      Document PDF d'une attestation fiscale
+// @Description  Génère le PDF de l'attestation à joindre à la déclaration de revenus ; les brouillons portent la mention BROUILLON
+// @Tags         tax-certificates
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'attestation"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /tax-certificates/{id}/pdf [get]
+func GetTaxCertificatePDF(c *gin.Context) {
+	certificate, ok := loadTaxCertificate(c)
+	if !ok {
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+services.TaxCertificateFileName(certificate)+`"`)
+	c.Data(http.StatusOK, "application/pdf", services.RenderTaxCertificatePDF(certificate))
+}
+
+// GenerateTaxCertificates godoc
+// @Summary      Génération des attestations fiscales
+// @Description  Calcule, pour l'année civile indiquée, les sommes versées et les heures de cours de chaque famille (ou d'une seule famille), ventilées par intervenant. Les brouillons existants sont recalculés ; les attestations publiées ne sont pas modifiées.
+// @Tags         tax-certificates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.TaxCertificateRequest  true  "Année et famille"
+// @Success      200  {object}  models.TaxCertificateBatchResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /tax-certificates/generate [post]
+func GenerateTaxCertificates(c *gin.Context) {
+	var req models.TaxCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := services.GenerateTaxCertificates(database.DB, req.Year, req.FamilleID)
+	if err != nil {
+		if errors.Is(err, models.ErrTaxCertificatePublished) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération des attestations fiscales"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// PublishTaxCertificates godoc
+// @Summary      Publication des attestations fiscales
+// @Description  Met à disposition des familles les attestations de l'année encore en brouillon et leur envoie une notification
+// @Tags         tax-certificates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.TaxCertificateRequest  true  "Année et famille"
+// @Success      200  {object}  models.TaxCertificateBatchResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /tax-certificates/publish [post]
+func PublishTaxCertificates(c *gin.Context) {
+	var req models.TaxCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	result, err := services.PublishTaxCertificates(database.DB, req.Year, req.FamilleID, adminID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la publication des attestations fiscales"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func loadTaxCertificate(c *gin.Context) (*models.TaxCertificate, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return nil, false
+	}
+	certificate, err := services.LoadTaxCertificate(database.DB, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attestation non trouvée"})
+		return nil, false
+	}
+	userID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && (certificate.FamilleID != userID || !certificate.IsPublished()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attestation non trouvée"})
+		return nil, false
+	}
+	return certificate, true
+}
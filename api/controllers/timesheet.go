@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"api/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTimesheets godoc
+// @Summary      Liste des feuilles de temps
+// @Description  Récupère les heures déclarées avec filtrage par enseignant, famille ou statut
+// @Tags         timesheets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        enseignant_id  query     int     false  "ID de l'enseignant"
+// @Param        famille_id     query     int     false  "ID de la famille"
+// @Param        status         query     string  false  "Statut (pending, approved, disputed)"
+// @Success      200  {array}   models.TimesheetEntry
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /timesheets [get]
+func ListTimesheets(c *gin.Context) {
+	var entries []models.TimesheetEntry
+	query := database.DB
+
+	// Les non-administrateurs ne voient que leurs propres entrées
+	if !middleware.IsAdmin(c) {
+		currentID, _ := middleware.GetUserID(c)
+		query = query.Where("enseignant_id = ? OR famille_id = ?", currentID, currentID)
+	}
+	if enseignantID := c.Query("enseignant_id"); enseignantID != "" {
+		query = query.Where("enseignant_id = ?", enseignantID)
+	}
+	if familleID := c.Query("famille_id"); familleID != "" {
+		query = query.Where("famille_id = ?", familleID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("declared_start DESC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des feuilles de temps"})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetCourseTimesheet godoc
+// @Summary      Feuille de temps d'un cours
+// @Description  Récupère les heures déclarées pour un cours (famille et enseignant du cours, administrateurs)
+// @Tags         courses
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du cours"
+// @Success      200  {object}  models.TimesheetEntry
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /courses/{id}/timesheet [get]
+func GetCourseTimesheet(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de cours invalide"})
+		return
+	}
+	var course models.Course
+	if err := database.DB.First(&course, courseID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cours non trouvé"})
+		return
+	}
+	if !isCourseParticipant(c, &course) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+	var entry models.TimesheetEntry
+	if err := database.DB.Where("course_id = ?", courseID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucune heure déclarée pour ce cours"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// ApproveTimesheet godoc
+// @Summary      Approbation d'une feuille de temps
+// @Description  La famille approuve les heures déclarées avant la date limite ; le paiement du cours est alors généré
+// @Tags         timesheets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de l'entrée"
+// @Success      200  {object}  models.TimesheetEntry
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /timesheets/{id}/approve [put]
+func ApproveTimesheet(c *gin.Context) {
+	entry, ok := loadReviewableTimesheet(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if err := services.ApproveTimesheetEntry(database.DB, &entry, currentID); err != nil {
+		respondApprovalError(c, err, "Erreur lors de l'approbation")
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DisputeTimesheet godoc
+// @Summary      Contestation d'une feuille de temps
+// @Description  La famille conteste les heures déclarées avant la date limite ; un litige est ouvert pour les administrateurs
+// @Tags         timesheets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "ID de l'entrée"
+// @Param        request  body      models.TimesheetDisputeRequest  true  "Motif de la contestation"
+// @Success      200  {object}  models.TimesheetEntry
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /timesheets/{id}/dispute [put]
+func DisputeTimesheet(c *gin.Context) {
+	var req models.TimesheetDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, ok := loadReviewableTimesheet(c)
+	if !ok {
+		return
+	}
+	currentID, _ := middleware.GetUserID(c)
+	if _, err := services.DeclineCourse(database.DB, &entry, currentID, req.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la contestation"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetEnseignantTimesheets godoc
+// @Summary      Récapitulatif mensuel des heures d'un enseignant
+// @Description  Agrège les heures déclarées, approuvées, en attente et contestées d'un enseignant pour un mois
+// @Tags         enseignants
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int     true   "ID de l'enseignant"
+// @Param        month  query     string  false  "Mois au format YYYY-MM (mois courant par défaut)"
+// @Success      200  {object}  models.TimesheetSummaryResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /enseignants/{id}/timesheets [get]
+func GetEnseignantTimesheets(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if !middleware.CanAccessUser(c, uint(id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé"})
+		return
+	}
+
+	loc := utils.CalendarLocation()
+	month := time.Now().In(loc)
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.ParseInLocation("2006-01", m, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mois invalide (format attendu YYYY-MM)"})
+			return
+		}
+		month = parsed
+	}
+
+	summary, err := services.MonthlyTimesheetSummary(database.DB, uint(id), month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul du récapitulatif"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// loadReviewableTimesheet charge l'entrée désignée par :id et vérifie qu'elle peut être traitée
+func loadReviewableTimesheet(c *gin.Context) (models.TimesheetEntry, bool) {
+	var entry models.TimesheetEntry
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return entry, false
+	}
+	if err := database.DB.First(&entry, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feuille de temps non trouvée"})
+		return entry, false
+	}
+	return entry, checkTimesheetReviewable(c, &entry)
+}
+
+// respondApprovalError répond à l'échec d'une approbation : conflit si le cours a
+// été annulé ou la déclaration traitée entre-temps, erreur interne sinon
+func respondApprovalError(c *gin.Context, err error, message string) {
+	if errors.Is(err, models.ErrTimesheetCourseCancelled) || errors.Is(err, models.ErrTimesheetNotPending) ||
+		errors.Is(err, models.ErrCourseStatusTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// checkTimesheetReviewable vérifie que l'utilisateur (famille du cours ou admin) peut
+// encore approuver ou contester l'entrée. Écrit la réponse d'erreur le cas échéant.
+func checkTimesheetReviewable(c *gin.Context, entry *models.TimesheetEntry) bool {
+	currentID, _ := middleware.GetUserID(c)
+	if !middleware.IsAdmin(c) && currentID != entry.FamilleID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seule la famille du cours peut traiter cette déclaration"})
+		return false
+	}
+	if !entry.CanBeReviewed(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cette déclaration n'est plus modifiable"})
+		return false
+	}
+	return true
+}
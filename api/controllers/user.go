@@ -48,12 +48,12 @@ func GetAllUsers(c *gin.Context) {
 			}
 		case models.RoleEnseignant:
 			var enseignant models.Enseignant
-			if err := database.DB.Where("user_id = ?", user.ID).Preload("Missions").Preload("Courses").Preload("Reports").Preload("Options").Preload("Offers").First(&enseignant).Error; err == nil {
+			if err := database.DB.Where("user_id = ?", user.ID).Preload("Missions").Preload("Courses").Preload("Reports", visibleReports(c)).Preload("Options").Preload("Offers").First(&enseignant).Error; err == nil {
 				userResponse.Enseignant = &enseignant
 			}
 		case models.RoleAdministrator:
 			var admin models.Administrator
-			if err := database.DB.Where("user_id = ?", user.ID).Preload("Reports").Preload("Offers").Preload("Resources").First(&admin).Error; err == nil {
+			if err := database.DB.Where("user_id = ?", user.ID).Preload("Reports", visibleReports(c)).Preload("Offers").Preload("Resources").First(&admin).Error; err == nil {
 				userResponse.Administrator = &admin
 			}
 		}
@@ -102,12 +102,12 @@ func GetUserByID(c *gin.Context) {
 		}
 	case models.RoleEnseignant:
 		var enseignant models.Enseignant
-		if err := database.DB.Where("user_id = ?", user.ID).Preload("Missions").Preload("Courses").Preload("Reports").Preload("Options").Preload("Offers").First(&enseignant).Error; err == nil {
+		if err := database.DB.Where("user_id = ?", user.ID).Preload("Missions").Preload("Courses").Preload("Reports", visibleReports(c)).Preload("Options").Preload("Offers").First(&enseignant).Error; err == nil {
 			userResponse.Enseignant = &enseignant
 		}
 	case models.RoleAdministrator:
 		var admin models.Administrator
-		if err := database.DB.Where("user_id = ?", user.ID).Preload("Reports").Preload("Offers").Preload("Resources").First(&admin).Error; err == nil {
+		if err := database.DB.Where("user_id = ?", user.ID).Preload("Reports", visibleReports(c)).Preload("Offers").Preload("Resources").First(&admin).Error; err == nil {
 			userResponse.Administrator = &admin
 		}
 	}
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListHourPackages godoc
+// @Summary      Liste des forfaits d'heures
+// @Description  Formules d'heures prépayées proposées aux familles. Les familles et enseignants ne voient que les formules actives.
+// @Tags         hour-packages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Uniquement les formules actives (administrateurs)"
+// @Success      200  {array}   models.HourPackage
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /hour-packages [get]
+func ListHourPackages(c *gin.Context) {
+	var packages []models.HourPackage
+	query := database.DB
+	if !middleware.IsAdmin(c) || c.Query("active") == "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Order("minutes, id").Find(&packages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des forfaits d'heures"})
+		return
+	}
+	c.JSON(http.StatusOK, packages)
+}
+
+// CreateHourPackage godoc
+// @Summary      Création d'un forfait d'heures
+// @Description  Ajoute une formule d'heures prépayées (admin seulement)
+// @Tags         hour-packages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.HourPackageRequest  true  "Forfait d'heures"
+// @Success      201  {object}  models.HourPackage
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /hour-packages [post]
+func CreateHourPackage(c *gin.Context) {
+	var req models.HourPackageRequest
+	if !bindHourPackageRequest(c, &req) {
+		return
+	}
+	var pkg models.HourPackage
+	applyHourPackageRequest(&pkg, req)
+	active := pkg.IsActive
+	if err := database.DB.Create(&pkg).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du forfait d'heures"})
+		return
+	}
+	// La valeur par défaut de la colonne ignore un is_active=false à la création
+	if !active {
+		database.DB.Model(&pkg).Update("is_active", false)
+	}
+	c.JSON(http.StatusCreated, pkg)
+}
+
+// UpdateHourPackage godoc
+// @Summary      Mise à jour d'un forfait d'heures
+// @Description  Modifie une formule d'heures prépayées (admin seulement). Les forfaits déjà achetés ne sont pas modifiés.
+// @Tags         hour-packages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                        true  "ID du forfait"
+// @Param        request  body      models.HourPackageRequest  true  "Forfait d'heures"
+// @Success      200  {object}  models.HourPackage
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Router       /hour-packages/{id} [put]
+func UpdateHourPackage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	var req models.HourPackageRequest
+	if !bindHourPackageRequest(c, &req) {
+		return
+	}
+	var pkg models.HourPackage
+	if err := database.DB.First(&pkg, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Forfait d'heures non trouvé"})
+		return
+	}
+	applyHourPackageRequest(&pkg, req)
+	if err := database.DB.Save(&pkg).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour du forfait d'heures"})
+		return
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// DeleteHourPackage godoc
+// @Summary      Suppression d'un forfait d'heures
+// @Description  Retire une formule du catalogue (admin seulement). Les heures déjà achetées restent utilisables.
+// @Tags         hour-packages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID du forfait"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /hour-packages/{id} [delete]
+func DeleteHourPackage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID invalide"})
+		return
+	}
+	if err := database.DB.Delete(&models.HourPackage{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression du forfait d'heures"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func bindHourPackageRequest(c *gin.Context, req *models.HourPackageRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if !req.Price.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le prix du forfait doit être strictement positif"})
+		return false
+	}
+	return true
+}
+
+func applyHourPackageRequest(pkg *models.HourPackage, req models.HourPackageRequest) {
+	pkg.Name = req.Name
+	pkg.Description = req.Description
+	pkg.Minutes = req.Minutes
+	pkg.Price = req.Price
+	pkg.ValidityDays = req.ValidityDays
+	pkg.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// GetFamilleWallet godoc
+// @Summary      Porte-monnaie d'heures d'une famille
+// @Description  Heures prépayées disponibles et leur valeur, prochaine expiration, alerte de solde bas, achats actifs ou en attente de paiement, et acomptes non encore imputés
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID de la famille"
+// @Success      200  {object}  models.WalletBalanceResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /familles/{id}/wallet [get]
+func GetFamilleWallet(c *gin.Context) {
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	balance, err := services.WalletBalance(database.DB, id, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul du solde d'heures"})
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetFamilleWalletStatement godoc
+// @Summary      Relevé du porte-monnaie d'heures
+// @Description  Solde d'ouverture, mouvements de la période (achats, cours, expirations, ajustements, remboursements) avec le solde progressif en heures et en valeur, et solde de clôture
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true   "ID de la famille"
+// @Param        from  query     string  false  "Début de période (YYYY-MM-DD), 1er janvier par défaut"
+// @Param        to    query     string  false  "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut"
+// @Success      200  {object}  models.WalletStatementResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /familles/{id}/wallet/statement [get]
+func GetFamilleWalletStatement(c *gin.Context) {
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	start, end, ok := ledgerPeriod(c)
+	if !ok {
+		return
+	}
+	statement, err := services.WalletStatement(database.DB, id, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du relevé d'heures"})
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// PurchaseFamilleHourPackage godoc
+// @Summary      Achat d'un forfait d'heures
+// @Description  Enregistre l'achat d'une formule par la famille et ouvre le paiement correspondant, à régler via /payments/{id}/process. Les heures sont créditées une fois le paiement encaissé.
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                true  "ID de la famille"
+// @Param        request  body      models.HourPackagePurchaseRequest  true  "Forfait choisi"
+// @Success      201  {object}  models.HourPackagePurchase
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Router       /familles/{id}/wallet/purchases [post]
+func PurchaseFamilleHourPackage(c *gin.Context) {
+	var req models.HourPackagePurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	var famille models.Famille
+	if err := database.DB.First(&famille, "user_id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Famille non trouvée"})
+		return
+	}
+	var pkg models.HourPackage
+	if err := database.DB.First(&pkg, req.HourPackageID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Forfait d'heures non trouvé"})
+		return
+	}
+	purchase, err := services.PurchaseHourPackage(database.DB, &pkg, id)
+	if err != nil {
+		if errors.Is(err, models.ErrHourPackageInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		respondPaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, purchase)
+}
+
+// AdjustFamilleWallet godoc
+// @Summary      Ajustement du porte-monnaie d'heures
+// @Description  Crédite des heures offertes à la famille (minutes positives, avec une date d'expiration facultative) ou lui en retire (minutes négatives), avec le motif de l'ajustement (admin seulement)
+// @Tags         familles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "ID de la famille"
+// @Param        request  body      models.WalletAdjustmentRequest  true  "Ajustement"
+// @Success      201  {array}   models.WalletTransaction
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /familles/{id}/wallet/adjustments [post]
+func AdjustFamilleWallet(c *gin.Context) {
+	var req models.WalletAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, ok := familleIDParam(c)
+	if !ok {
+		return
+	}
+	var famille models.Famille
+	if err := database.DB.First(&famille, "user_id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Famille non trouvée"})
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	movements, err := services.AdjustWallet(database.DB, id, req, adminID)
+	switch {
+	case errors.Is(err, models.ErrWalletExpiryInPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInsufficientWalletHours):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'ajustement du solde d'heures"})
+	default:
+		c.JSON(http.StatusCreated, movements)
+	}
+}
@@ -61,7 +61,10 @@ func InitDatabase() {
 
 // AutoMigrate effectue la migration automatique de tous les modèles
 func AutoMigrate() error {
-	return DB.AutoMigrate(
+	if err := SetupJoinTables(DB); err != nil {
+		return err
+	}
+	err := DB.AutoMigrate(
 		// Modèles de base
 		&models.User{},
 		&models.Address{},
@@ -75,15 +78,97 @@ func AutoMigrate() error {
 		&models.Course{},
 		&models.Mission{},
 		&models.Report{},
+		&models.ReportRevision{},
+		&models.ReportReminder{},
+
+		// Modèles de rapports structurés
+		&models.Skill{},
+		&models.ReportTemplate{},
+		&models.ReportTemplateSection{},
+		&models.ReportTemplateItem{},
+		&models.ReportAnswer{},
 
 		// Modèles de paiement et offres
 		&models.Payment{},
 		&models.Offer{},
+		&models.OfferApplication{},
+		&models.OfferRevision{},
 		&models.Option{},
 
+		// Modèles de facturation
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.InvoiceSequence{},
+
+		// Modèles de versement aux enseignants et d'imputation des avances
+		&models.Payout{},
+		&models.PayoutLine{},
+		&models.AdvanceAllocation{},
+		&models.SEPATransferBatch{},
+		&models.SEPAMandate{},
+		&models.SEPADebitBatch{},
+
+		// Modèles d'attestations fiscales
+		&models.TaxCertificate{},
+		&models.TaxCertificateLine{},
+
+		// Modèles de comptabilité
+		&models.LedgerAccount{},
+		&models.LedgerEntry{},
+		&models.LedgerLine{},
+
+		// Modèles d'heures prépayées (forfaits et porte-monnaie des familles)
+		&models.HourPackage{},
+		&models.HourPackagePurchase{},
+		&models.WalletTransaction{},
+
+		// Modèles de tarification
+		&models.PricingRule{},
+
+		// Modèles de campagnes d'acquisition (codes promo et parrainage)
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.PromoDiscount{},
+		&models.Referral{},
+
 		// Modèles de ressources
 		&models.Resource{},
+
+		// Modèles de calendrier
+		&models.CalendarFeed{},
+
+		// Modèles de suivi des cours (heures déclarées, historique, litiges, reports, annulations, pointages)
+		&models.TimesheetEntry{},
+		&models.CourseEvent{},
+		&models.CourseDispute{},
+		&models.RescheduleRequest{},
+		&models.CancellationPolicy{},
+		&models.CourseCancellation{},
+		&models.CourseAttendance{},
+
+		// Notifications
+		&models.Notification{},
 	)
+	if err != nil {
+		return err
+	}
+
+	// Conversion des anciens montants décimaux en centimes
+	if err := MigrateMoneyColumns(DB); err != nil {
+		return err
+	}
+
+	// Index de recherche plein texte des offres
+	return SetupOfferSearch(DB)
+}
+
+// SetupJoinTables déclare les tables de liaison many2many qui portent des données :
+// enseignant_offers enregistre les candidatures des enseignants aux offres
+func SetupJoinTables(db *gorm.DB) error {
+	if err := db.SetupJoinTable(&models.Offer{}, "Enseignants", &models.OfferApplication{}); err != nil {
+		return err
+	}
+	return db.SetupJoinTable(&models.Enseignant{}, "Offers", &models.OfferApplication{})
 }
 
 // CloseDatabase ferme la connexion à la base de données
@@ -112,12 +197,52 @@ func ResetDatabase() error {
 		&models.Course{},
 		&models.Mission{},
 		&models.Report{},
+		&models.ReportRevision{},
+		&models.ReportReminder{},
+		&models.Skill{},
+		&models.ReportTemplate{},
+		&models.ReportTemplateSection{},
+		&models.ReportTemplateItem{},
+		&models.ReportAnswer{},
 		&models.Payment{},
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.InvoiceSequence{},
+		&models.Payout{},
+		&models.PayoutLine{},
+		&models.AdvanceAllocation{},
+		&models.SEPATransferBatch{},
+		&models.SEPAMandate{},
+		&models.SEPADebitBatch{},
+		&models.TaxCertificate{},
+		&models.TaxCertificateLine{},
+		&models.LedgerAccount{},
+		&models.LedgerEntry{},
+		&models.LedgerLine{},
+		&models.HourPackage{},
+		&models.HourPackagePurchase{},
+		&models.WalletTransaction{},
+		&models.PricingRule{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.PromoDiscount{},
+		&models.Referral{},
 		&models.Offer{},
+		&models.OfferRevision{},
 		&models.Option{},
 		&models.Resource{},
+		&models.CalendarFeed{},
+		&models.TimesheetEntry{},
+		&models.CourseEvent{},
+		&models.CourseDispute{},
+		&models.RescheduleRequest{},
+		&models.CancellationPolicy{},
+		&models.CourseCancellation{},
+		&models.CourseAttendance{},
+		&models.Notification{},
 		"user_resources",    // Table de liaison many2many
 		"enseignant_offers", // Table de liaison many2many
+		OfferSearchTable,    // Index plein texte des offres (SQLite)
 	)
 	if err != nil {
 		return err
@@ -129,6 +254,10 @@ func ResetDatabase() error {
 
 // SeedDatabase ajoute des données de test
 func SeedDatabase() error {
+	if err := seedCancellationPolicies(); err != nil {
+		return err
+	}
+
 	// Vérifier si des données existent déjà
 	var userCount int64
 	DB.Model(&models.User{}).Count(&userCount)
@@ -219,3 +348,20 @@ func SeedDatabase() error {
 
 	return nil
 }
+
+// seedCancellationPolicies crée les règles d'annulation par défaut si aucune n'existe
+func seedCancellationPolicies() error {
+	var count int64
+	DB.Model(&models.CancellationPolicy{}).Count(&count)
+	if count > 0 {
+		return nil
+	}
+	policies := []models.CancellationPolicy{
+		{Name: "Annulation tardive par la famille", CancelledBy: models.CancellationByFamille, NoticeWindowHours: 24, FeePercent: 100, IsActive: true},
+		{Name: "Absence de la famille", ReasonCode: models.CancellationReasonFamilyNoShow, FeePercent: 100, IsActive: true},
+		{Name: "Annulation tardive par l'enseignant", CancelledBy: models.CancellationByEnseignant, NoticeWindowHours: 24, PenaltyPercent: 25, IsActive: true},
+		{Name: "Absence de l'enseignant", ReasonCode: models.CancellationReasonTeacherNoShow, CreditPercent: 100, PenaltyPercent: 50, IsActive: true},
+		{Name: "Force majeure", ReasonCode: models.CancellationReasonForceMajeure, IsActive: true},
+	}
+	return DB.Create(&policies).Error
+}
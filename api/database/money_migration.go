@@ -0,0 +1,54 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// legacyMoneyColumn décrit une ancienne colonne de montant en décimal et les
+// colonnes <prefix>cents / <prefix>currency qui la remplacent
+type legacyMoneyColumn struct {
+	table  string
+	column string
+	prefix string
+}
+
+var legacyMoneyColumns = []legacyMoneyColumn{
+	{"payments", "amount", "amount_"},
+	{"offers", "hourly_rate", "hourly_rate_"},
+	{"missions", "hourly_rate", "hourly_rate_"},
+	{"course_cancellations", "fee_amount", "fee_amount_"},
+	{"course_cancellations", "credit_amount", "credit_amount_"},
+	{"course_cancellations", "penalty_amount", "penalty_amount_"},
+	{"invoices", "total_ht", "total_ht_"},
+	{"invoices", "vat_amount", "vat_amount_"},
+	{"invoices", "total_ttc", "total_ttc_"},
+	{"invoice_lines", "unit_price", "unit_price_"},
+	{"invoice_lines", "total_ht", "total_ht_"},
+}
+
+// MigrateMoneyColumns convertit les montants stockés en décimal (euros) vers les
+// colonnes en centimes avec devise, puis supprime les anciennes colonnes. À appeler
+// après AutoMigrate ; sans effet sur une base déjà migrée.
+func MigrateMoneyColumns(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, legacy := range legacyMoneyColumns {
+		if !migrator.HasTable(legacy.table) || !migrator.HasColumn(legacy.table, legacy.column) {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			update := fmt.Sprintf("UPDATE %s SET %scents = ROUND(COALESCE(%s, 0) * 100), %scurrency = 'EUR'",
+				legacy.table, legacy.prefix, legacy.column, legacy.prefix)
+			if err := tx.Exec(update).Error; err != nil {
+				return err
+			}
+			// DROP COLUMN explicite : le migrateur SQLite de GORM exige un modèle
+			return tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", legacy.table, legacy.column)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("conversion de %s.%s en centimes : %w", legacy.table, legacy.column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// OfferSearchTable est la table FTS5 qui indexe les offres sous SQLite
+const OfferSearchTable = "offers_fts"
+
+// offerSearchPostgres ajoute aux offres un vecteur de recherche pondéré (titre,
+// description puis prérequis) calculé avec le dictionnaire français
+var offerSearchPostgres = []string{
+	`ALTER TABLE offers ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('french', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('french', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('french', coalesce(requirements, '')), 'C')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_offers_search_vector ON offers USING GIN (search_vector)`,
+}
+
+// offerSearchSQLite crée l'index FTS5 des offres et les triggers qui le tiennent à jour
+var offerSearchSQLite = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS offers_fts USING fts5(
+		title, description, requirements,
+		content='offers', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS offers_fts_insert AFTER INSERT ON offers BEGIN
+		INSERT INTO offers_fts(rowid, title, description, requirements)
+		VALUES (new.id, new.title, new.description, new.requirements);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS offers_fts_delete AFTER DELETE ON offers BEGIN
+		INSERT INTO offers_fts(offers_fts, rowid, title, description, requirements)
+		VALUES ('delete', old.id, old.title, old.description, old.requirements);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS offers_fts_update AFTER UPDATE ON offers BEGIN
+		INSERT INTO offers_fts(offers_fts, rowid, title, description, requirements)
+		VALUES ('delete', old.id, old.title, old.description, old.requirements);
+		INSERT INTO offers_fts(rowid, title, description, requirements)
+		VALUES (new.id, new.title, new.description, new.requirements);
+	END`,
+	// Les triggers disparaissent quand SQLite recrée la table des offres lors d'une migration
+	`INSERT INTO offers_fts(offers_fts) VALUES ('rebuild')`,
+}
+
+// SetupOfferSearch prépare la recherche plein texte des offres : vecteur tsvector
+// indexé en GIN sous PostgreSQL, table FTS5 sous SQLite. Si SQLite a été compilé
+// sans FTS5, la recherche se replie sur des filtres LIKE. À appeler après AutoMigrate.
+func SetupOfferSearch(db *gorm.DB) error {
+	var statements []string
+	switch db.Dialector.Name() {
+	case "postgres":
+		statements = offerSearchPostgres
+	case "sqlite":
+		statements = offerSearchSQLite
+	default:
+		return nil
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			if strings.Contains(err.Error(), "no such module: fts5") {
+				log.Println("Module FTS5 indisponible : recherche des offres par LIKE")
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
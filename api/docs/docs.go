@@ -386,9 +386,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Authentifier un utilisateur et retourner un token JWT",
+        "/advances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Liste les avances (acomptes des familles, avances aux enseignants) avec le montant imputé, le solde restant et le détail des factures ou relevés sur lesquels elles ont été déduites. Les utilisateurs non administrateurs ne voient que leurs avances.",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,52 +401,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advances"
                 ],
-                "summary": "Connexion d'un utilisateur",
+                "summary": "Rapprochement des avances",
                 "parameters": [
                     {
-                        "description": "Identifiants de connexion",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Bénéficiaire (administrateurs uniquement)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mission",
+                        "name": "mission_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Connexion réussie",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.AuthResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Erreur de validation",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AdvanceReport"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Identifiants incorrects",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/auth/logout": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Déconnecter l'utilisateur (invalide le token côté client)",
+                "description": "Enregistre une avance réglée hors prestataire de paiement : acompte versé par une famille, imputé sur ses prochaines factures, ou avance versée à un enseignant, déduite de ses prochains relevés. L'avance peut être réservée à une mission.",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,48 +451,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advances"
                 ],
-                "summary": "Déconnexion",
-                "responses": {
-                    "200": {
-                        "description": "Déconnexion réussie",
+                "summary": "Enregistrement d'une avance",
+                "parameters": [
+                    {
+                        "description": "Avance",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.AdvanceCreateRequest"
                         }
                     }
-                }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Générer un nouveau token JWT à partir d'un token existant",
-                "consumes": [
-                    "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Rafraîchir le token JWT",
                 "responses": {
-                    "200": {
-                        "description": "Nouveau token généré",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Token invalide",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -499,9 +489,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Créer un nouveau compte utilisateur",
+        "/advances/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Montant imputé, solde restant et imputations successives d'une avance",
                 "consumes": [
                     "application/json"
                 ],
@@ -509,36 +504,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advances"
                 ],
-                "summary": "Inscription d'un utilisateur",
+                "summary": "Détail d'une avance",
                 "parameters": [
                     {
-                        "description": "Données d'inscription",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.RegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID de l'avance",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Utilisateur créé avec succès",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.AuthResponse"
+                            "$ref": "#/definitions/models.AdvanceReport"
                         }
                     },
                     "400": {
-                        "description": "Erreur de validation",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "409": {
-                        "description": "Utilisateur déjà existant",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -547,14 +540,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses": {
+        "/attendances": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des cours avec possibilité de filtrage par statut, enseignant, famille ou mission",
+                "description": "Récupère les pointages, par exemple ceux signalés en attente de vérification (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -562,32 +555,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "attendances"
                 ],
-                "summary": "Liste tous les cours",
+                "summary": "Liste des pointages",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Statut du cours",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID de l'enseignant",
-                        "name": "enseignant_id",
+                        "type": "boolean",
+                        "description": "Uniquement les pointages signalés",
+                        "name": "flagged",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "ID de la famille",
-                        "name": "famille_id",
+                        "type": "boolean",
+                        "description": "Filtrer sur l'état de vérification",
+                        "name": "reviewed",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "ID de la mission",
-                        "name": "mission_id",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
                         "in": "query"
                     }
                 ],
@@ -597,7 +584,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.CourseResponse"
+                                "$ref": "#/definitions/models.CourseAttendance"
                             }
                         }
                     },
@@ -609,14 +596,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/attendances/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crée un nouveau cours",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,25 +612,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "attendances"
                 ],
-                "summary": "Création d'un cours",
+                "summary": "Détails d'un pointage",
                 "parameters": [
                     {
-                        "description": "Données du cours",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CourseCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID du pointage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
+                            "$ref": "#/definitions/models.CourseAttendance"
                         }
                     },
                     "400": {
@@ -652,8 +638,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -662,14 +655,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}": {
-            "get": {
+        "/attendances/{id}/review": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère les détails d'un cours spécifique",
+                "description": "Un administrateur marque un pointage signalé comme vérifié",
                 "consumes": [
                     "application/json"
                 ],
@@ -677,23 +670,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "attendances"
                 ],
-                "summary": "Détails d'un cours",
+                "summary": "Vérification d'un pointage",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID du cours",
+                        "description": "ID du pointage",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Commentaire",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.AttendanceReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
+                            "$ref": "#/definitions/models.CourseAttendance"
                         }
                     },
                     "400": {
@@ -711,14 +712,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Met à jour les informations d'un cours existant",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authentifier un utilisateur et retourner un token JWT",
                 "consumes": [
                     "application/json"
                 ],
@@ -726,57 +724,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "auth"
                 ],
-                "summary": "Mise à jour d'un cours",
+                "summary": "Connexion d'un utilisateur",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Données de mise à jour",
+                        "description": "Identifiants de connexion",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CourseUpdateRequest"
+                            "$ref": "#/definitions/controllers.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Connexion réussie",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
+                            "$ref": "#/definitions/controllers.AuthResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Erreur de validation",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Identifiants incorrects",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime un cours existant",
+                "description": "Déconnecter l'utilisateur (invalide le token côté client)",
                 "consumes": [
                     "application/json"
                 ],
@@ -784,31 +777,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
-                ],
-                "summary": "Suppression d'un cours",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Déconnexion",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "200": {
+                        "description": "Déconnexion réussie",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -817,14 +791,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}/cancel": {
-            "put": {
+        "/auth/refresh": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Annule un cours planifié",
+                "description": "Générer un nouveau token JWT à partir d'un token existant",
                 "consumes": [
                     "application/json"
                 ],
@@ -832,34 +806,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
-                ],
-                "summary": "Annulation d'un cours",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Rafraîchir le token JWT",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Nouveau token généré",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Token invalide",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -868,14 +827,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}/complete": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Marque un cours comme terminé",
+        "/auth/register": {
+            "post": {
+                "description": "Créer un nouveau compte utilisateur. Une famille invitée est rattachée à son parrain par le code de parrainage ou l'adresse e-mail invitée.",
                 "consumes": [
                     "application/json"
                 ],
@@ -883,34 +837,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "auth"
                 ],
-                "summary": "Validation d'un cours",
+                "summary": "Inscription d'un utilisateur",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Données d'inscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Utilisateur créé avec succès",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
+                            "$ref": "#/definitions/controllers.AuthResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Erreur de validation",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Utilisateur déjà existant",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -919,14 +875,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}/declare": {
-            "post": {
+        "/calendar/feed": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Déclare les heures effectuées pour un cours",
+                "description": "Retourne (et crée si besoin) l'URL secrète d'abonnement iCalendar de l'utilisateur connecté",
                 "consumes": [
                     "application/json"
                 ],
@@ -934,46 +890,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
-                ],
-                "summary": "Déclaration des heures d'un cours",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Heures effectuées",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "number"
-                            }
-                        }
-                    }
+                    "calendar"
                 ],
+                "summary": "Abonnement calendrier",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
+                            "$ref": "#/definitions/models.CalendarFeedResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -982,14 +917,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}/payments": {
-            "get": {
+        "/calendar/feed/regenerate": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère tous les paiements associés à un cours",
+                "description": "Remplace le jeton secret de l'abonnement iCalendar ; l'ancienne URL cesse de fonctionner",
                 "consumes": [
                     "application/json"
                 ],
@@ -997,37 +932,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
-                ],
-                "summary": "Liste des paiements d'un cours",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "calendar"
                 ],
+                "summary": "Régénération de l'abonnement calendrier",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Payment"
-                            }
+                            "$ref": "#/definitions/models.CalendarFeedResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1036,54 +959,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/courses/{id}/schedule": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Planifie un cours à une date spécifique",
-                "consumes": [
-                    "application/json"
-                ],
+        "/calendar/feeds/{token}": {
+            "get": {
+                "description": "Flux iCalendar (RFC 5545) des cours de l'utilisateur, accessible sans authentification via le jeton secret",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "courses"
+                    "calendar"
                 ],
-                "summary": "Planification d'un cours",
+                "summary": "Flux iCalendar",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID du cours",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Jeton secret (suffixe .ics facultatif)",
+                        "name": "token",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de planification",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CourseScheduleRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.CourseResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
                         }
                     },
                     "404": {
@@ -1096,14 +995,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/enseignants": {
+        "/cancellation-policies": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste de tous les enseignants",
+                "description": "Récupère les règles appliquées lors de l'annulation d'un cours (préavis, frais, avoirs, pénalités)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1111,16 +1010,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "cancellation-policies"
+                ],
+                "summary": "Liste des politiques d'annulation",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les règles actives",
+                        "name": "active",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Liste tous les enseignants",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.EnseignantResponse"
+                                "$ref": "#/definitions/models.CancellationPolicy"
                             }
                         }
                     },
@@ -1139,7 +1046,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crée un nouveau compte enseignant (admin seulement)",
+                "description": "Ajoute une règle d'annulation (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1147,17 +1054,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "cancellation-policies"
                 ],
-                "summary": "Création d'un enseignant",
+                "summary": "Création d'une politique d'annulation",
                 "parameters": [
                     {
-                        "description": "Données de l'enseignant",
+                        "description": "Règle d'annulation",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserCreateRequest"
+                            "$ref": "#/definitions/models.CancellationPolicyRequest"
                         }
                     }
                 ],
@@ -1165,7 +1072,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/controllers.EnseignantResponse"
+                            "$ref": "#/definitions/models.CancellationPolicy"
                         }
                     },
                     "400": {
@@ -1175,13 +1082,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1192,14 +1092,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/enseignants/nearby": {
-            "get": {
+        "/cancellation-policies/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des enseignants proches d'une localisation donnée",
+                "description": "Modifie une règle d'annulation existante (admin seulement). Les annulations passées ne sont pas recalculées.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1207,52 +1107,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "cancellation-policies"
                 ],
-                "summary": "Liste des enseignants à proximité",
+                "summary": "Mise à jour d'une politique d'annulation",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "Latitude",
-                        "name": "lat",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Longitude",
-                        "name": "lng",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "ID de la règle",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "number",
-                        "description": "Rayon de recherche en km",
-                        "name": "radius",
-                        "in": "query"
+                        "description": "Règle d'annulation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CancellationPolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.EnseignantResponse"
-                            }
+                            "$ref": "#/definitions/models.CancellationPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/enseignants/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère les détails d'un enseignant spécifique",
+                "description": "Supprime une règle d'annulation (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1260,24 +1165,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "cancellation-policies"
                 ],
-                "summary": "Détails d'un enseignant",
+                "summary": "Suppression d'une politique d'annulation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID de la règle",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.EnseignantResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1286,22 +1188,24 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/courses": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour les informations d'un enseignant (admin ou propriétaire)",
+                "description": "Récupère la liste des cours avec possibilité de filtrage par statut, enseignant, famille ou mission",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,51 +1213,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Mise à jour d'un enseignant",
+                "summary": "Liste tous les cours",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut du cours",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "ID de l'enseignant",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "enseignant_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Données de mise à jour",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UserUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "famille_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "mission_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.CourseResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1361,13 +1261,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime un compte enseignant (admin seulement)",
+                "description": "Crée un nouveau cours",
                 "consumes": [
                     "application/json"
                 ],
@@ -1375,24 +1275,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Suppression d'un enseignant",
+                "summary": "Création d'un cours",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de l'enseignant",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Données du cours",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1401,14 +1313,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/enseignants/{id}/courses": {
+        "/courses/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des cours associés à un enseignant",
+                "description": "Récupère les détails d'un cours spécifique ; la feuille de temps, les litiges, l'historique, le pointage et l'annulation ne sont renvoyés qu'à la famille et à l'enseignant du cours et aux administrateurs",
                 "consumes": [
                     "application/json"
                 ],
@@ -1416,13 +1328,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des cours d'un enseignant",
+                "summary": "Détails d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1432,23 +1344,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/enseignants/{id}/missions": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des missions associées à un enseignant",
+                "description": "Met à jour les informations d'un cours existant. Le changement d'horaire et de statut est réservé aux administrateurs ; les parties passent par une demande de report, et l'annulation par PUT /courses/{id}/cancel. Un cours terminé ou annulé ne change plus de statut, et un cours ne passe à l'état terminé que par la validation de la famille.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1456,39 +1377,71 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des missions d'un enseignant",
+                "summary": "Mise à jour d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Mission"
-                            }
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/enseignants/{id}/options": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des options associées à un enseignant",
+                "description": "Supprime un cours existant",
                 "consumes": [
                     "application/json"
                 ],
@@ -1496,39 +1449,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des options d'un enseignant",
+                "summary": "Suppression d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Option"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/enseignants/{id}/payments": {
+        "/courses/{id}/attendance": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des paiements associés à un enseignant",
+                "description": "Récupère les pointages d'arrivée et de départ d'un cours (famille et enseignant du cours, administrateurs)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1536,13 +1497,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des paiements d'un enseignant",
+                "summary": "Pointage d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1552,23 +1513,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Payment"
-                            }
+                            "$ref": "#/definitions/models.CourseAttendance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/enseignants/{id}/reports": {
-            "get": {
+        "/courses/{id}/cancel": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des rapports soumis par un enseignant",
+                "description": "Annule un cours planifié en enregistrant l'auteur et le motif. La politique d'annulation applicable génère automatiquement les frais à régler par la famille, l'avoir crédité en heures sur son porte-monnaie et la pénalité déduite du prochain relevé de l'enseignant.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1576,39 +1555,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des rapports d'un enseignant",
+                "summary": "Annulation d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Motif de l'annulation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseCancelRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Report"
-                            }
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/enseignants/{id}/students": {
+        "/courses/{id}/cancellation-preview": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des élèves associés à un enseignant",
+                "description": "Calcule les frais, avoirs et pénalités qu'entraînerait l'annulation du cours maintenant",
                 "consumes": [
                     "application/json"
                 ],
@@ -1616,61 +1629,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "enseignants"
+                    "courses"
                 ],
-                "summary": "Liste des élèves d'un enseignant",
+                "summary": "Simulation d'annulation d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'enseignant",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Motif (illness, emergency, schedule_conflict, family_no_show, teacher_no_show, force_majeure, other)",
+                        "name": "reason_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partie qui annule (admin seulement : famille, enseignant, admin)",
+                        "name": "cancelled_by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.CancellationPreviewResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/familles": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Récupère la liste de toutes les familles",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "familles"
-                ],
-                "summary": "Liste toutes les familles",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.FamilleResponse"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1679,14 +1685,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/familles/{id}": {
-            "get": {
+        "/courses/{id}/check-in": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère les détails d'une famille spécifique",
+                "description": "L'enseignant pointe son arrivée (position GPS facultative) ; le cours passe en cours. Une arrivée tardive ou hors de la zone de l'adresse est signalée aux administrateurs.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1694,23 +1700,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Détails d'une famille",
+                "summary": "Pointage d'arrivée",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Position GPS",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.AttendanceCheckRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.FamilleResponse"
+                            "$ref": "#/definitions/controllers.CourseResponse"
                         }
                     },
                     "400": {
@@ -1720,22 +1734,38 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/courses/{id}/check-out": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour les informations d'une famille (admin ou propriétaire)",
+                "description": "L'enseignant pointe son départ (position GPS facultative) ; la durée mesurée pré-remplit la feuille de temps soumise à la famille ; le cours reste en cours jusqu'à son approbation",
                 "consumes": [
                     "application/json"
                 ],
@@ -1743,24 +1773,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Mise à jour d'une famille",
+                "summary": "Pointage de départ",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Données de mise à jour",
+                        "description": "Position GPS",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserUpdateRequest"
+                            "$ref": "#/definitions/models.AttendanceCheckRequest"
                         }
                     }
                 ],
@@ -1768,8 +1797,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controllers.CourseResponse"
                         }
                     },
                     "400": {
@@ -1792,16 +1820,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/courses/{id}/complete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime un compte famille (admin seulement)",
+                "description": "La famille confirme le cours déclaré par l'enseignant ; le cours passe à l'état terminé et devient facturable",
                 "consumes": [
                     "application/json"
                 ],
@@ -1809,21 +1846,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Suppression d'une famille",
+                "summary": "Validation d'un cours par la famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "403": {
                         "description": "Forbidden",
@@ -1831,18 +1878,32 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/familles/{id}/courses": {
-            "get": {
+        "/courses/{id}/declare": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des cours associés à une famille",
+                "description": "L'enseignant déclare l'horaire réel du cours (début/fin ou nombre d'heures) ; la famille dispose ensuite d'un délai pour approuver ou contester",
                 "consumes": [
                     "application/json"
                 ],
@@ -1850,26 +1911,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Liste des cours d'une famille",
+                "summary": "Déclaration des heures d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Heures effectuées",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TimesheetDeclareRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/controllers.CourseResponse"
                         }
                     },
                     "400": {
@@ -1879,24 +1946,38 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/familles/{id}/missions": {
-            "get": {
+        "/courses/{id}/decline": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des missions associées à une famille",
+                "description": "La famille refuse le cours déclaré en indiquant un motif ; un litige est ouvert pour les administrateurs",
                 "consumes": [
                     "application/json"
                 ],
@@ -1904,26 +1985,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Liste des missions d'une famille",
+                "summary": "Refus d'un cours par la famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Motif du refus",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseDeclineRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Mission"
-                            }
+                            "$ref": "#/definitions/controllers.CourseResponse"
                         }
                     },
                     "400": {
@@ -1933,38 +2020,49 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/familles/{id}/options": {
+        "/courses/{id}/ics": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des options associées à une famille",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Télécharge un cours au format iCalendar",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Liste des options d'une famille",
+                "summary": "Export .ics d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1974,10 +2072,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Option"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1987,6 +2082,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1997,14 +2099,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/familles/{id}/payments": {
+        "/courses/{id}/payments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des paiements associés à une famille",
+                "description": "Récupère tous les paiements associés à un cours",
                 "consumes": [
                     "application/json"
                 ],
@@ -2012,13 +2114,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Liste des paiements d'une famille",
+                "summary": "Liste des paiements d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2051,14 +2153,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/familles/{id}/reviews": {
-            "post": {
+        "/courses/{id}/reschedule-requests": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Ajoute un avis sur une famille (non implémenté)",
+                "description": "Récupère l'historique des demandes de report et contre-propositions d'un cours",
                 "consumes": [
                     "application/json"
                 ],
@@ -2066,33 +2168,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Ajouter un avis sur une famille",
+                "summary": "Demandes de report d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de l'avis",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RescheduleRequest"
+                            }
                         }
                     },
                     "400": {
@@ -2102,6 +2197,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2110,16 +2212,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/familles/{id}/teachers": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des enseignants associés à une famille",
+                "description": "L'enseignant ou la famille propose un nouveau créneau ; le cours garde son horaire tant que l'autre partie n'a pas accepté",
                 "consumes": [
                     "application/json"
                 ],
@@ -2127,119 +2227,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "familles"
+                    "courses"
                 ],
-                "summary": "Liste des enseignants d'une famille",
+                "summary": "Demande de report d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la famille",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    {
+                        "description": "Créneau proposé",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.RescheduleCreateRequest"
                         }
                     }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Vérifie l'état de l'API",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
                 ],
-                "summary": "Vérification de santé",
                 "responses": {
-                    "200": {
-                        "description": "API fonctionnelle",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/missions": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Récupère la liste des missions avec possibilité de filtrage par statut, enseignant ou famille",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "missions"
-                ],
-                "summary": "Liste toutes les missions",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Statut de la mission",
-                        "name": "status",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "ID de l'enseignant",
-                        "name": "enseignant_id",
-                        "in": "query"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "ID de la famille",
-                        "name": "famille_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.MissionResponse"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/courses/{id}/schedule": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crée une nouvelle mission",
+                "description": "Planifie un cours à une date spécifique. Un cours terminé ou annulé ne peut plus être replanifié.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2247,25 +2301,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "courses"
                 ],
-                "summary": "Création d'une mission",
+                "summary": "Planification d'un cours",
                 "parameters": [
                     {
-                        "description": "Données de la mission",
+                        "type": "integer",
+                        "description": "ID du cours",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de planification",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MissionCreateRequest"
+                            "$ref": "#/definitions/models.CourseScheduleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MissionResponse"
+                            "$ref": "#/definitions/controllers.CourseResponse"
                         }
                     },
                     "400": {
@@ -2275,8 +2336,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2285,14 +2353,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}": {
+        "/courses/{id}/timesheet": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère les détails d'une mission spécifique",
+                "description": "Récupère les heures déclarées pour un cours (famille et enseignant du cours, administrateurs)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2300,13 +2368,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "courses"
                 ],
-                "summary": "Détails d'une mission",
+                "summary": "Feuille de temps d'un cours",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la mission",
+                        "description": "ID du cours",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2316,7 +2384,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MissionResponse"
+                            "$ref": "#/definitions/models.TimesheetEntry"
                         }
                     },
                     "400": {
@@ -2326,6 +2394,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2334,13 +2409,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/disputes": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Récupère les litiges ouverts suite au refus d'un cours par une famille (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2348,52 +2426,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "disputes"
                 ],
-                "summary": "Mise à jour d'une mission",
+                "summary": "Liste des litiges",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID mission",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Statut (open, resolved)",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
-                        "description": "Champs à mettre à jour",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.MissionUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID du cours",
+                        "name": "course_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MissionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CourseDispute"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/disputes/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime une mission existante",
+                "description": "Récupère un litige ; accessible aux administrateurs et aux parties du cours",
                 "consumes": [
                     "application/json"
                 ],
@@ -2401,21 +2478,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "disputes"
                 ],
-                "summary": "Suppression d'une mission",
+                "summary": "Détails d'un litige",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la mission",
+                        "description": "ID du litige",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseDispute"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2424,6 +2504,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2434,14 +2521,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}/courses": {
-            "get": {
+        "/disputes/{id}/resolve": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des cours associés à une mission",
+                "description": "Un administrateur tranche le litige : approbation (facturation, durée éventuellement corrigée) ou rejet (cours annulé, non facturé)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2449,26 +2536,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "disputes"
                 ],
-                "summary": "Liste des cours d'une mission",
+                "summary": "Résolution d'un litige",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de la mission",
+                        "description": "ID du litige",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Décision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DisputeResolveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/models.CourseDispute"
                         }
                     },
                     "400": {
@@ -2484,18 +2577,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/missions/{id}/payments": {
+        "/enseignants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des paiements associés à une mission",
+                "description": "Récupère la liste de tous les enseignants",
                 "consumes": [
                     "application/json"
                 ],
@@ -2503,53 +2603,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
-                ],
-                "summary": "Liste des paiements d'une mission",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID de la mission",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "enseignants"
                 ],
+                "summary": "Liste tous les enseignants",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Payment"
+                                "$ref": "#/definitions/controllers.EnseignantResponse"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/missions/{id}/reports": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des rapports associés à une mission",
+                "description": "Crée un nouveau compte enseignant (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2557,26 +2639,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "enseignants"
                 ],
-                "summary": "Liste des rapports d'une mission",
+                "summary": "Création d'un enseignant",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de la mission",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Données de l'enseignant",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Report"
-                            }
+                            "$ref": "#/definitions/controllers.EnseignantResponse"
                         }
                     },
                     "400": {
@@ -2586,8 +2667,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2596,14 +2684,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/offers": {
+        "/enseignants/nearby": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des offres avec possibilité de filtrage par statut, sujet et niveau",
+                "description": "Récupère la liste des enseignants proches d'une localisation donnée",
                 "consumes": [
                     "application/json"
                 ],
@@ -2611,26 +2699,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Liste toutes les offres",
+                "summary": "Liste des enseignants à proximité",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Statut de l'offre",
-                        "name": "status",
-                        "in": "query"
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Matière enseignée",
-                        "name": "subject",
-                        "in": "query"
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Niveau d'étude",
-                        "name": "level",
+                        "type": "number",
+                        "description": "Rayon de recherche en km",
+                        "name": "radius",
                         "in": "query"
                     }
                 ],
@@ -2640,26 +2730,21 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.OfferResponse"
+                                "$ref": "#/definitions/controllers.EnseignantResponse"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/enseignants/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crée une nouvelle offre",
+                "description": "Récupère les détails d'un enseignant spécifique",
                 "consumes": [
                     "application/json"
                 ],
@@ -2667,25 +2752,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Création d'une offre",
+                "summary": "Détails d'un enseignant",
                 "parameters": [
                     {
-                        "description": "Données de l'offre",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.OfferCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OfferResponse"
+                            "$ref": "#/definitions/controllers.EnseignantResponse"
                         }
                     },
                     "400": {
@@ -2695,24 +2778,22 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/offers/active": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des offres avec le statut 'open'",
+                "description": "Met à jour les informations d'un enseignant (admin ou propriétaire)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2720,21 +2801,90 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
+                ],
+                "summary": "Mise à jour d'un enseignant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdateRequest"
+                        }
+                    }
                 ],
-                "summary": "Liste des offres actives",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.OfferResponse"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un compte enseignant (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enseignants"
+                ],
+                "summary": "Suppression d'un enseignant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2743,14 +2893,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/offers/search": {
+        "/enseignants/{id}/applications": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recherche des offres selon différents critères",
+                "description": "Liste les candidatures d'un enseignant aux offres, les plus récentes en premier",
                 "consumes": [
                     "application/json"
                 ],
@@ -2758,14 +2908,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Recherche d'offres",
+                "summary": "Candidatures d'un enseignant",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Terme de recherche",
-                        "name": "query",
+                        "description": "Statut (pending, shortlisted, rejected, accepted, withdrawn)",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -2775,10 +2932,24 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.OfferResponse"
+                                "$ref": "#/definitions/models.OfferApplication"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2789,14 +2960,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/offers/{id}": {
+        "/enseignants/{id}/bank-account": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère les détails d'une offre spécifique",
+                "description": "Titulaire, IBAN et BIC du compte sur lequel l'enseignant est payé",
                 "consumes": [
                     "application/json"
                 ],
@@ -2804,13 +2975,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Détails d'une offre",
+                "summary": "Coordonnées bancaires d'un enseignant",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'offre",
+                        "description": "ID de l'enseignant",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2820,7 +2991,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OfferResponse"
+                            "$ref": "#/definitions/models.BankAccountResponse"
                         }
                     },
                     "400": {
@@ -2830,6 +3001,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2845,7 +3023,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour les informations d'une offre existante",
+                "description": "Enregistre le compte sur lequel l'enseignant est payé. L'IBAN est vérifié (pays SEPA, longueur, clé de contrôle ISO 13616), le BIC est facultatif.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2853,24 +3031,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Mise à jour d'une offre",
+                "summary": "Mise à jour des coordonnées bancaires",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'offre",
+                        "description": "ID de l'enseignant",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Données de mise à jour",
+                        "description": "Coordonnées bancaires",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OfferUpdateRequest"
+                            "$ref": "#/definitions/models.BankAccountRequest"
                         }
                     }
                 ],
@@ -2878,7 +3056,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OfferResponse"
+                            "$ref": "#/definitions/models.BankAccountResponse"
                         }
                     },
                     "400": {
@@ -2888,6 +3066,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2896,14 +3081,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/enseignants/{id}/courses": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime une offre existante",
+                "description": "Récupère la liste des cours associés à un enseignant",
                 "consumes": [
                     "application/json"
                 ],
@@ -2911,47 +3098,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Suppression d'une offre",
+                "summary": "Liste des cours d'un enseignant",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'offre",
+                        "description": "ID de l'enseignant",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Course"
+                            }
                         }
                     }
                 }
             }
         },
-        "/offers/{id}/close": {
-            "put": {
+        "/enseignants/{id}/missions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Marque une offre comme fermée",
+                "description": "Récupère la liste des missions associées à un enseignant",
                 "consumes": [
                     "application/json"
                 ],
@@ -2959,13 +3138,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Fermeture d'une offre",
+                "summary": "Liste des missions d'un enseignant",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'offre",
+                        "description": "ID de l'enseignant",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2975,34 +3154,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OfferResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Mission"
+                            }
                         }
                     }
                 }
             }
         },
-        "/offers/{id}/options": {
+        "/enseignants/{id}/options": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère toutes les options associées à une offre",
+                "description": "Récupère la liste des options associées à un enseignant",
                 "consumes": [
                     "application/json"
                 ],
@@ -3010,13 +3178,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "enseignants"
                 ],
-                "summary": "Liste des options d'une offre",
+                "summary": "Liste des options d'un enseignant",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'offre",
+                        "description": "ID de l'enseignant",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3031,32 +3199,18 @@ const docTemplate = `{
                                 "$ref": "#/definitions/models.Option"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
             }
         },
-        "/options": {
+        "/enseignants/{id}/payments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des options avec possibilité de filtrage",
+                "description": "Récupère les relevés de paiement (verrouillés ou payés) d'un enseignant avec leurs lignes, du plus récent au plus ancien",
                 "consumes": [
                     "application/json"
                 ],
@@ -3064,32 +3218,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "enseignants"
                 ],
-                "summary": "Liste toutes les options",
+                "summary": "Relevés de paiement d'un enseignant",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Statut de l'option",
-                        "name": "status",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "ID de l'enseignant",
-                        "name": "enseignant_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID de la famille",
-                        "name": "famille_id",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID de l'offre",
-                        "name": "offer_id",
+                        "type": "string",
+                        "description": "Statut (locked, paid)",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -3099,10 +3242,24 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Option"
+                                "$ref": "#/definitions/models.Payout"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3111,14 +3268,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/enseignants/{id}/reports": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crée une nouvelle option sur une offre",
+                "description": "Récupère la liste des rapports soumis par un enseignant",
                 "consumes": [
                     "application/json"
                 ],
@@ -3126,52 +3285,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "enseignants"
                 ],
-                "summary": "Création d'une option",
+                "summary": "Liste des rapports d'un enseignant",
                 "parameters": [
                     {
-                        "description": "Données de l'option",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.OptionCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Report"
+                            }
                         }
                     }
                 }
             }
         },
-        "/options/expiring": {
+        "/enseignants/{id}/students": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des options qui vont bientôt expirer",
+                "description": "Récupère la liste des élèves associés à un enseignant",
                 "consumes": [
                     "application/json"
                 ],
@@ -3179,18 +3325,84 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "enseignants"
+                ],
+                "summary": "Liste des élèves d'un enseignant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Liste des options expirant bientôt",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Option"
+                                "$ref": "#/definitions/models.User"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/enseignants/{id}/timesheets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Agrège les heures déclarées, approuvées, en attente et contestées d'un enseignant pour un mois",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enseignants"
+                ],
+                "summary": "Récapitulatif mensuel des heures d'un enseignant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mois au format YYYY-MM (mois courant par défaut)",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimesheetSummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -3202,14 +3414,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/options/pending": {
+        "/familles": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste des options avec le statut \"en attente\"",
+                "description": "Récupère la liste de toutes les familles",
                 "consumes": [
                     "application/json"
                 ],
@@ -3217,16 +3429,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Liste des options en attente",
+                "summary": "Liste toutes les familles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Option"
+                                "$ref": "#/definitions/controllers.FamilleResponse"
                             }
                         }
                     },
@@ -3240,14 +3452,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/options/{id}": {
-            "put": {
+        "/familles/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour les informations d'une option existante",
+                "description": "Récupère les détails d'une famille spécifique",
                 "consumes": [
                     "application/json"
                 ],
@@ -3255,32 +3467,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Mise à jour d'une option",
+                "summary": "Détails d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de mise à jour",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.OptionUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
+                            "$ref": "#/definitions/controllers.FamilleResponse"
                         }
                     },
                     "400": {
@@ -3299,13 +3502,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime une option existante",
+                "description": "Met à jour les informations d'une famille (admin ou propriétaire)",
                 "consumes": [
                     "application/json"
                 ],
@@ -3313,21 +3516,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Suppression d'une option",
+                "summary": "Mise à jour d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3336,6 +3552,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -3344,16 +3567,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/options/{id}/cancel": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Annule une option acceptée",
+                "description": "Supprime un compte famille (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -3361,34 +3582,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Annulation d'une option",
+                "summary": "Suppression d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3397,14 +3608,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/options/{id}/decline": {
-            "put": {
+        "/familles/{id}/courses": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Refuse une option en attente",
+                "description": "Récupère la liste des cours associés à une famille",
                 "consumes": [
                     "application/json"
                 ],
@@ -3412,13 +3623,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Refus d'une option",
+                "summary": "Liste des cours d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3428,7 +3639,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Course"
+                            }
                         }
                     },
                     "400": {
@@ -3448,14 +3662,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/options/{id}/expire": {
-            "put": {
+        "/familles/{id}/mandates": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Marque une option comme expirée",
+                "description": "Mandats SEPA signés par la famille, actif et révoqués, avec leur référence unique (RUM). L'IBAN est masqué.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3463,13 +3677,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Expiration d'une option",
+                "summary": "Mandats de prélèvement d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3479,7 +3693,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SEPAMandate"
+                            }
                         }
                     },
                     "400": {
@@ -3489,24 +3706,22 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/options/{id}/reject": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Rejette une option en attente",
+                "description": "Enregistre le mandat SEPA (schéma CORE) autorisant le prélèvement des factures de la famille. L'IBAN est vérifié ; le mandat actif précédent est révoqué. Une référence unique de mandat (RUM) est attribuée si elle n'est pas fournie.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3514,23 +3729,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "options"
+                    "familles"
                 ],
-                "summary": "Rejet d'une option",
+                "summary": "Signature d'un mandat de prélèvement",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'option",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Mandat signé",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SEPAMandateCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/controllers.OptionResponse"
+                            "$ref": "#/definitions/models.SEPAMandate"
                         }
                     },
                     "400": {
@@ -3540,24 +3764,38 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/profile": {
-            "get": {
+        "/familles/{id}/mandates/{mandate_id}/revoke": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtenir les informations du profil de l'utilisateur connecté",
+                "description": "Révoque le mandat : aucune facture ne sera plus prélevée à ce titre. Les prélèvements déjà transmis à la banque ne sont pas annulés.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3565,39 +3803,71 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "familles"
+                ],
+                "summary": "Révocation d'un mandat de prélèvement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du mandat",
+                        "name": "mandate_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Récupérer le profil utilisateur",
                 "responses": {
                     "200": {
-                        "description": "Profil utilisateur",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.SEPAMandate"
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Utilisateur non trouvé",
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/familles/{id}/missions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Modifier les informations du profil de l'utilisateur connecté",
+                "description": "Récupère la liste des missions associées à une famille",
                 "consumes": [
                     "application/json"
                 ],
@@ -3605,37 +3875,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "familles"
                 ],
-                "summary": "Mettre à jour le profil utilisateur",
+                "summary": "Liste des missions d'une famille",
                 "parameters": [
                     {
-                        "description": "Données de mise à jour",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profil mis à jour",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Mission"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Erreur de validation",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3644,14 +3914,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/familles/{id}/options": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Récupère la liste de tous les utilisateurs (admin seulement)",
+                "description": "Récupère la liste des options associées à une famille",
                 "consumes": [
                     "application/json"
                 ],
@@ -3659,28 +3929,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
+                ],
+                "summary": "Liste des options d'une famille",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Liste tous les utilisateurs",
                 "responses": {
                     "200": {
-                        "description": "Liste des utilisateurs",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.UserResponse"
+                                "$ref": "#/definitions/models.Option"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Accès refusé",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3689,14 +3968,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/familles/{id}/payments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtient les détails d'un utilisateur spécifique",
+                "description": "Récupère la liste des paiements associés à une famille",
                 "consumes": [
                     "application/json"
                 ],
@@ -3704,13 +3983,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Récupère un utilisateur par ID",
+                "summary": "Liste des paiements d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3718,41 +3997,39 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Détails de l'utilisateur",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payment"
+                            }
                         }
                     },
                     "400": {
-                        "description": "ID invalide",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Non authentifié",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Utilisateur non trouvé",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/familles/{id}/referrals": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour les informations d'un utilisateur (admin seulement)",
+                "description": "Familles invitées par la famille et avancement de chaque invitation jusqu'à la récompense",
                 "consumes": [
                     "application/json"
                 ],
@@ -3760,57 +4037,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Met à jour un utilisateur",
+                "summary": "Parrainages d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de mise à jour",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UserUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Utilisateur mis à jour",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Referral"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Erreur de validation",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Non authentifié",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "403": {
-                        "description": "Accès refusé",
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur non trouvé",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3818,13 +4082,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime un utilisateur du système (admin seulement)",
+                "description": "Invite une famille non inscrite ; le code de parrainage retourné est à saisir à l'inscription. Les deux familles reçoivent des heures offertes lorsque le premier cours de la famille invitée est payé.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3832,49 +4096,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Supprime un utilisateur",
+                "summary": "Parrainage d'une nouvelle famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Adresse e-mail de la famille invitée",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralInviteRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Utilisateur supprimé",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Referral"
                         }
                     },
                     "400": {
-                        "description": "ID invalide",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Accès refusé",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur non trouvé",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3883,14 +4155,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/addresses": {
-            "get": {
+        "/familles/{id}/reviews": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtient toutes les adresses associées à un utilisateur",
+                "description": "Ajoute un avis sur une famille (non implémenté)",
                 "consumes": [
                     "application/json"
                 ],
@@ -3898,44 +4170,104 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Récupère les adresses d'un utilisateur",
+                "summary": "Ajouter un avis sur une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Données de l'avis",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Liste des adresses",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Address"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "ID invalide",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/familles/{id}/skill-progression": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrace, compétence par compétence, les notes des rapports validés de toutes les missions de la famille",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "familles"
+                ],
+                "summary": "Progression des compétences d'une famille",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Matière",
+                        "name": "subject",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SkillProgressionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur non trouvé",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3944,14 +4276,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/payments": {
+        "/familles/{id}/teachers": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtient tous les paiements associés à un utilisateur",
+                "description": "Récupère la liste des enseignants associés à une famille",
                 "consumes": [
                     "application/json"
                 ],
@@ -3959,13 +4291,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Récupère les paiements d'un utilisateur",
+                "summary": "Liste des enseignants d'une famille",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3973,30 +4305,67 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Liste des paiements",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Payment"
+                                "$ref": "#/definitions/models.User"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/familles/{id}/wallet": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Heures prépayées disponibles et leur valeur, prochaine expiration, alerte de solde bas, achats actifs ou en attente de paiement, et acomptes non encore imputés",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "familles"
+                ],
+                "summary": "Porte-monnaie d'heures d'une famille",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletBalanceResponse"
+                        }
                     },
                     "400": {
-                        "description": "ID invalide",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur non trouvé",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -4005,14 +4374,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/resources": {
-            "get": {
+        "/familles/{id}/wallet/adjustments": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtient toutes les ressources accessibles à un utilisateur",
+                "description": "Crédite des heures offertes à la famille (minutes positives, avec une date d'expiration facultative) ou lui en retire (minutes négatives), avec le motif de l'ajustement (admin seulement)",
                 "consumes": [
                     "application/json"
                 ],
@@ -4020,44 +4389,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "familles"
                 ],
-                "summary": "Récupère les ressources d'un utilisateur",
+                "summary": "Ajustement du porte-monnaie d'heures",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID de l'utilisateur",
+                        "description": "ID de la famille",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Ajustement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletAdjustmentRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Liste des ressources",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Resource"
+                                "$ref": "#/definitions/models.WalletTransaction"
                             }
                         }
                     },
                     "400": {
-                        "description": "ID invalide",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Utilisateur non trouvé",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -4065,1100 +4450,13138 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.AddressResponse": {
-            "type": "object",
-            "properties": {
-                "city": {
-                    "type": "string"
-                },
-                "country": {
-                    "type": "string"
-                },
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
+        },
+        "/familles/{id}/wallet/purchases": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
-                },
-                "postal_code": {
-                    "type": "string"
-                },
-                "street": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
+                ],
+                "description": "Enregistre l'achat d'une formule par la famille et ouvre le paiement correspondant, à régler via /payments/{id}/process. Les heures sont créditées une fois le paiement encaissé.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "familles"
+                ],
+                "summary": "Achat d'un forfait d'heures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Forfait choisi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackagePurchaseRequest"
                         }
-                    ]
-                },
-                "user_id": {
-                    "description": "Foreign Key",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/models.User"
-                }
-            }
-        },
-        "controllers.CourseResponse": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "$ref": "#/definitions/models.Address"
-                },
-                "address_id": {
-                    "type": "integer"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackagePurchase"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/familles/{id}/wallet/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Solde d'ouverture, mouvements de la période (achats, cours, expirations, ajustements, remboursements) avec le solde progressif en heures et en valeur, et solde de clôture",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "familles"
+                ],
+                "summary": "Relevé du porte-monnaie d'heures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Début de période (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletStatementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Vérifie l'état de l'API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Vérification de santé",
+                "responses": {
+                    "200": {
+                        "description": "API fonctionnelle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/hour-packages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Formules d'heures prépayées proposées aux familles. Les familles et enseignants ne voient que les formules actives.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hour-packages"
+                ],
+                "summary": "Liste des forfaits d'heures",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les formules actives (administrateurs)",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HourPackage"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une formule d'heures prépayées (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hour-packages"
+                ],
+                "summary": "Création d'un forfait d'heures",
+                "parameters": [
+                    {
+                        "description": "Forfait d'heures",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/hour-packages/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie une formule d'heures prépayées (admin seulement). Les forfaits déjà achetés ne sont pas modifiés.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hour-packages"
+                ],
+                "summary": "Mise à jour d'un forfait d'heures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du forfait",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Forfait d'heures",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HourPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire une formule du catalogue (admin seulement). Les heures déjà achetées restent utilisables.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hour-packages"
+                ],
+                "summary": "Suppression d'un forfait d'heures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du forfait",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Les administrateurs voient toutes les factures et avoirs, les familles uniquement leurs documents émis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Liste des factures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Famille (administrateurs uniquement)",
+                        "name": "famille_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type de document (invoice, credit_note)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut (draft, issued)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Invoice"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Prépare une facture à partir des cours terminés désignés, ou de tous les cours terminés non encore facturés de la mission ou de la famille, sur la base des heures approuvées de leur feuille de temps et hors heures prépayées. Les cours déjà réglés par un paiement de cours ne sont pas refacturés ; les paiements de cours encore en attente sont annulés à l'émission de la facture. Les codes promo des missions et celui éventuellement saisi sont déduits sur des lignes de remise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Création d'une facture en brouillon",
+                "parameters": [
+                    {
+                        "description": "Famille, mission et cours à facturer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvoiceCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/sepa-debit-export": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Génère le fichier de prélèvements SEPA (ISO 20022 pain.008.001.02, schéma CORE) des factures indiquées, ou de toutes les factures à prélever des familles ayant un mandat actif. Les premiers prélèvements d'un mandat (FRST) sont séparés des prélèvements récurrents (RCUR). Chaque prélèvement est enregistré comme un paiement en attente et la famille est prévenue du montant et de l'échéance, qui doit respecter le délai de préavis. L'identifiant du lot est renvoyé dans l'en-tête X-SEPA-Batch-ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Export des prélèvements SEPA des factures",
+                "parameters": [
+                    {
+                        "description": "Factures et date d'échéance",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SEPADebitExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Détail d'une facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Mise à jour d'un brouillon de facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvoiceUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Seuls les brouillons, qui n'ont pas encore de numéro, peuvent être supprimés",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Suppression d'un brouillon de facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/credit-note": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Émet un avoir annulant intégralement une facture émise ; les cours concernés peuvent ensuite être refacturés",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Avoir sur une facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motif de l'avoir",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreditNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/issue": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attribue le numéro définitif (continu par série et par année) et fige la facture. Les paiements en attente des cours facturés sont annulés ; l'émission est refusée si l'un de ces cours a été réglé entre-temps.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Émission d'une facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Génère le PDF d'une facture ou d'un avoir ; les brouillons portent la mention BROUILLON",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Document PDF d'une facture",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la facture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Comptes utilisés pour passer les écritures, créés avec leur numéro par défaut lors de la première utilisation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Plan comptable",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LedgerAccount"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/accounts/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change le numéro ou le libellé utilisé pour les prochaines écritures ; les écritures déjà passées ne sont pas modifiées",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Modification d'un compte du plan comptable",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du compte",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Numéro et libellé",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerAccountUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Écritures de la période, dans l'ordre de leur numéro, avec leurs lignes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Écritures comptables",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Début de période (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Journal (VT, HA, BQ, OD)",
+                        "name": "journal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document d'origine (invoice, payment, payout)",
+                        "name": "source_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du document d'origine",
+                        "name": "source_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LedgerEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fec": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exporte les écritures de la période au format FEC (article A. 47 A-1 du livre des procédures fiscales) : fichier texte à 18 colonnes séparées par une barre verticale, nommé d'après le SIREN et la date de clôture",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Fichier des écritures comptables (FEC)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Début de l'exercice (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de clôture incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Solde d'ouverture, mouvements de la période avec le solde progressif et solde de clôture d'un compte, éventuellement limité au compte auxiliaire d'une famille (FAM000042) ou d'un enseignant (ENS000042)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Relevé d'un compte",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Numéro de compte",
+                        "name": "account",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Compte auxiliaire",
+                        "name": "aux",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Début de période (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerStatementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/trial-balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Total des débits et des crédits de chaque compte sur la période, et solde (débit moins crédit)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Balance générale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Début de période (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerTrialBalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des missions avec possibilité de filtrage par statut, enseignant ou famille",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Liste toutes les missions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut de la mission",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "famille_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.MissionResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une nouvelle mission. Un code promo peut être appliqué à tous ses cours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Création d'une mission",
+                "parameters": [
+                    {
+                        "description": "Données de la mission",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MissionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les détails d'une mission spécifique",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Détails d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Mise à jour d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Champs à mettre à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MissionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MissionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une mission existante",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Suppression d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/courses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des cours associés à une mission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Liste des cours d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Course"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des paiements associés à une mission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Liste des paiements d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des rapports associés à une mission (rapports validés uniquement pour une famille)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Liste des rapports d'une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Report"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/skill-progression": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrace, compétence par compétence, les notes des rapports validés de la mission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Progression des compétences sur une mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Matière",
+                        "name": "subject",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SkillProgressionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les notifications de l'utilisateur connecté, les plus récentes en premier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Notifications de l'utilisateur",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les notifications non lues",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Notification"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read-all": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Marquer toutes les notifications comme lues",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}/read": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Marquer une notification comme lue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la notification",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des offres avec possibilité de filtrage par statut, sujet et niveau. Les brouillons ne sont visibles que des administrateurs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Liste toutes les offres",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut de l'offre",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Matière enseignée",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Niveau d'étude",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.OfferResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une offre en brouillon au nom de l'administrateur connecté (admin seulement). Elle est publiée par l'action publish, ou automatiquement à la date publish_at si elle est indiquée.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Création d'une offre",
+                "parameters": [
+                    {
+                        "description": "Données de l'offre",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/active": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des offres avec le statut 'open' dont la date limite de candidature n'est pas passée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Liste des offres actives",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.OfferResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recherche plein texte dans le titre, la description et les prérequis des offres, classée par pertinence (titre d'abord). Renvoie les facettes matière, niveau et tranche de taux horaire, chacune calculée sans son propre filtre. Sans statut, seules les offres ouvertes sont recherchées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Recherche d'offres",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terme de recherche",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut de l'offre (open par défaut)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Matière enseignée",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Niveau d'étude",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Taux horaire minimum, en centimes",
+                        "name": "min_rate_cents",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Taux horaire maximum, en centimes",
+                        "name": "max_rate_cents",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publiée à partir du (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publiée jusqu'au (YYYY-MM-DD, inclus)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de résultats (20 par défaut, 100 au plus)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Décalage pour la pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les détails d'une offre spécifique et de son auteur. Les brouillons ne sont visibles que des administrateurs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Détails d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour les informations d'une offre existante. Les modifications d'une offre déjà publiée sont historisées. Le statut ne change que vers closed ou filled ; la publication passe par les actions publish et unpublish. (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Mise à jour d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une offre existante (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Suppression d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/applications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Liste les candidats à une offre, les plus anciens en premier (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Candidatures à une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut (pending, shortlisted, rejected, accepted, withdrawn)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OfferApplication"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant connecté postule à une offre ouverte avec une lettre de motivation et ses disponibilités. L'auteur de l'offre est notifié. Une candidature retirée peut être renouvelée.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Candidature à une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Candidature",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/applications/withdraw": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant connecté retire sa candidature tant qu'elle n'a été ni acceptée ni refusée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Retrait d'une candidature",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/applications/{enseignant_id}/accept": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retient un candidat (admin seulement) : l'offre passe au statut pourvue, les autres candidatures en cours sont refusées et chaque enseignant est notifié. Avec create_mission, la mission de l'enseignant auprès de la famille indiquée est créée et la famille est prévenue.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Acceptation d'une candidature",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Décision et mission à créer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplicationAcceptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplicationAcceptResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/applications/{enseignant_id}/reject": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refuse la candidature d'un enseignant à une offre, qui en est notifié (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Refus d'une candidature",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motif du refus",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplicationReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/applications/{enseignant_id}/shortlist": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Présélectionne un candidat à une offre, qui en est notifié (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Présélection d'une candidature",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message à l'enseignant",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplicationReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/close": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marque une offre comme fermée : elle n'accepte plus de candidatures, mais les candidats déjà reçus peuvent encore être retenus (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Fermeture d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/options": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère toutes les options associées à une offre",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Liste des options d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Option"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/publish": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publie immédiatement une offre en brouillon, qui s'ouvre aux candidatures (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Publication d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Versions successives d'une offre depuis sa première publication : publication, modifications, retrait et clôture (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Historique d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OfferRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/schedule": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Programme la publication d'une offre en brouillon à une date future (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Publication programmée d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Date de publication",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OfferScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/unpublish": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Repasse une offre publiée en brouillon, ou annule sa publication programmée. Les candidatures déjà reçues sont conservées (admin seulement).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Retrait d'une offre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des options avec possibilité de filtrage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Liste toutes les options",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut de l'option",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "famille_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'offre",
+                        "name": "offer_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Option"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une nouvelle option sur une offre",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Création d'une option",
+                "parameters": [
+                    {
+                        "description": "Données de l'option",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OptionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des options qui vont bientôt expirer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Liste des options expirant bientôt",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Option"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/pending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste des options avec le statut \"en attente\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Liste des options en attente",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Option"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour les informations d'une option existante",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Mise à jour d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OptionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une option existante",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Suppression d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/{id}/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Annule une option acceptée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Annulation d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/{id}/decline": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refuse une option en attente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Refus d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/{id}/expire": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marque une option comme expirée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Expiration d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/options/{id}/reject": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rejette une option en attente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "options"
+                ],
+                "summary": "Rejet d'une option",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Les administrateurs voient tous les paiements, les autres utilisateurs uniquement les leurs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Liste des paiements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut (pending, completed, failed, refunded)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type de paiement",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Utilisateur (administrateurs uniquement)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payment"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enregistre un paiement en attente et ouvre l'intention de paiement chez le prestataire. Seuls les administrateurs peuvent créer un paiement pour un autre utilisateur.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Création d'un paiement",
+                "parameters": [
+                    {
+                        "description": "Données du paiement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Totaux des paiements visibles par l'utilisateur, avec les mêmes filtres que la liste",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Statistiques des paiements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type de paiement",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Utilisateur (administrateurs uniquement)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Devise des montants totalisés (EUR par défaut)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentStatsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Point d'entrée public des webhooks du prestataire ; la signature de l'en-tête X-Payment-Signature est vérifiée avant toute mise à jour",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Notification du prestataire de paiement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature du contenu",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Événement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentWebhookEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Détail d'un paiement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du paiement",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/process": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Présente un paiement en attente (ou précédemment refusé) au prestataire. Seuls les paiements de cours, de facture, d'acompte, de forfait d'heures et de frais d'annulation peuvent être réglés. Le paiement est réservé (en cours de traitement) le temps de la capture, puis passe à l'état encaissé ou échoué selon la réponse du prestataire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Traitement d'un paiement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du paiement",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moyen de paiement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentProcessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rembourse tout ou partie d'un paiement encaissé via le prestataire, dans la limite du montant encaissé restant (le solde par défaut). Le remboursement est enregistré comme un paiement lié au paiement d'origine et, pour un règlement de facture ou de cours, un avoir du même montant est émis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Remboursement d'un paiement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du paiement",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Montant et motif du remboursement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentRefundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Liste des relevés de paiement des enseignants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Enseignant",
+                        "name": "enseignant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut (locked, paid)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payout"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enregistre les relevés de la période tels que prévisualisés et notifie les enseignants. Les cours, avances et pénalités repris ne figureront sur aucun autre relevé ; les pénalités déduites sont soldées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Verrouillage des relevés de paiement",
+                "parameters": [
+                    {
+                        "description": "Période et enseignant",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutPeriodRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calcule, sans les enregistrer, les sommes dues à chaque enseignant pour la période : cours terminés aux heures approuvées, au taux de la mission ou de l'offre, moins les avances et les pénalités d'annulation en attente. Les cours écartés (versement bloqué, absence de taux) sont listés avec leur motif.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Prévisualisation des relevés de paiement",
+                "parameters": [
+                    {
+                        "description": "Période et enseignant",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutPeriodRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/sepa-export": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Génère le fichier de virements SEPA (ISO 20022 pain.001.001.03) des relevés verrouillés indiqués, ou de tous ceux qui n'ont pas encore été exportés, à transmettre à la banque. Chaque relevé exporté est rattaché au lot et ne peut figurer dans aucun autre fichier. L'identifiant du lot est renvoyé dans l'en-tête X-SEPA-Batch-ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Export des virements SEPA des relevés",
+                "parameters": [
+                    {
+                        "description": "Relevés et date d'exécution",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SEPAExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accessible aux administrateurs et à l'enseignant concerné",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Détail d'un relevé de paiement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du relevé",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/{id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enregistre le virement d'un relevé verrouillé et en informe l'enseignant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Marquer un relevé comme payé",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du relevé",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Référence du virement",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutPayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/quote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calcule le taux horaire et le prix d'un cours avant sa création, avec le détail des règles de tarification appliquées. Les valeurs absentes sont reprises de la mission, puis de l'offre.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Devis d'un cours ou d'une mission",
+                "parameters": [
+                    {
+                        "description": "Cours ou mission à chiffrer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingQuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Règles appliquées au taux horaire des cours, par priorité croissante : taux de base par matière et niveau, majorations (soirée, week-end, distance) et remises (mission longue, fratrie) (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Liste des règles de tarification",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les règles actives",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PricingRule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une règle de tarification (admin seulement). Un taux de base exige un taux horaire ; une majoration ou une remise, un montant horaire ou un pourcentage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Création d'une règle de tarification",
+                "parameters": [
+                    {
+                        "description": "Règle de tarification",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/rules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie une règle de tarification (admin seulement). Les cours déjà facturés ne sont pas recalculés.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Mise à jour d'une règle de tarification",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la règle",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Règle de tarification",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PricingRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une règle de tarification (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Suppression d'une règle de tarification",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la règle",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtenir les informations du profil de l'utilisateur connecté",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Récupérer le profil utilisateur",
+                "responses": {
+                    "200": {
+                        "description": "Profil utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifier les informations du profil de l'utilisateur connecté",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Mettre à jour le profil utilisateur",
+                "parameters": [
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profil mis à jour",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Erreur de validation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Codes promo des campagnes d'acquisition, avec leur nombre d'utilisations (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Liste des codes promo",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les codes actifs",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PromoCode"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute un code promo en pourcentage ou à montant fixe, avec ses limites d'utilisation et sa période de validité (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Création d'un code promo",
+                "parameters": [
+                    {
+                        "description": "Code promo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/check": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Indique, sans l'appliquer, si la famille peut utiliser le code promo et pour quelle remise",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Vérification d'un code promo",
+                "parameters": [
+                    {
+                        "description": "Code saisi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeCheckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Utilisations et remises accordées par code promo, invitations, inscriptions et récompenses de parrainage sur la période (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Bilan des campagnes d'acquisition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Début de période (YYYY-MM-DD), 1er janvier par défaut",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fin de période incluse (YYYY-MM-DD), 31 décembre par défaut",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie un code promo (admin seulement). Le code lui-même ne change pas ; les remises déjà appliquées ne sont pas recalculées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Mise à jour d'un code promo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du code promo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code promo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire un code promo (admin seulement). Les remises déjà accordées sur les missions restent acquises.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Suppression d'un code promo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du code promo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}/redemptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Missions et factures auxquelles le code a été appliqué, avec les remises accordées cours par cours (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Utilisations d'un code promo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du code promo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PromoRedemption"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/referrals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invitations de toutes les familles et leur avancement (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "referrals"
+                ],
+                "summary": "Liste des parrainages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut (pending, registered, rewarded, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Referral"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/report-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les modèles de rapport définis par les administrateurs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report-templates"
+                ],
+                "summary": "Liste des modèles de rapport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Matière",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Niveau",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les modèles actifs",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReportTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Définit un modèle de rapport : échelle de notation, sections et questions liées aux compétences (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report-templates"
+                ],
+                "summary": "Création d'un modèle de rapport",
+                "parameters": [
+                    {
+                        "description": "Modèle de rapport",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/report-templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère un modèle avec ses sections, questions et compétences évaluées",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report-templates"
+                ],
+                "summary": "Détails d'un modèle de rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du modèle",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remplace la définition d'un modèle (admin seulement). Un modèle déjà utilisé par des rapports ne peut plus être modifié.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report-templates"
+                ],
+                "summary": "Mise à jour d'un modèle de rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du modèle",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Modèle de rapport",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un modèle (admin seulement). Un modèle déjà utilisé est seulement désactivé afin de préserver les rapports existants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report-templates"
+                ],
+                "summary": "Suppression d'un modèle de rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du modèle",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les rapports visibles par l'utilisateur : tous pour un administrateur, ses propres rapports pour un enseignant, les rapports validés de ses missions pour une famille",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Liste des rapports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statut (draft, submitted, validated, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la mission",
+                        "name": "mission_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Report"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant de la mission crée un rapport en brouillon, ou le soumet directement avec submit=true. Un modèle (template_id) permet de joindre des réponses structurées au texte libre.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Rédaction d'un rapport",
+                "parameters": [
+                    {
+                        "description": "Contenu du rapport",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/overdue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Liste les missions actives dont l'échéance de rapport est dépassée, du retard le plus ancien au plus récent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Rapports en retard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OverdueReportResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère un rapport ; une famille ne peut consulter que les rapports validés",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Détails d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant modifie un rapport en brouillon ou rejeté (texte et réponses structurées) ; chaque modification est conservée dans l'historique",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Modification d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouveau contenu",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant supprime un brouillon ; un administrateur peut supprimer n'importe quel rapport",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Suppression d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/reject": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Un administrateur rejette un rapport soumis avec des commentaires ; le rapport revient à l'enseignant pour révision",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Rejet d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Commentaires",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère toutes les versions du rapport (création, modifications, soumissions, décisions)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Historique d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReportRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/submit": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'enseignant soumet un brouillon, ou une nouvelle révision d'un rapport rejeté, à la validation des administrateurs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Soumission d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{id}/validate": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Un administrateur valide un rapport soumis ; il devient visible pour la famille",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Validation d'un rapport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du rapport",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Commentaires",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reschedule-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reschedule"
+                ],
+                "summary": "Détails d'une demande de report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la demande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reschedule-requests/{id}/accept": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'autre partie accepte le créneau proposé ; les disponibilités sont revérifiées puis le cours est déplacé",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reschedule"
+                ],
+                "summary": "Acceptation d'une demande de report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la demande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Commentaire",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleResponseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CourseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reschedule-requests/{id}/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'auteur retire sa demande tant qu'elle n'a pas reçu de réponse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reschedule"
+                ],
+                "summary": "Retrait d'une demande de report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la demande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reschedule-requests/{id}/counter": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'autre partie propose un créneau différent ; la demande initiale est close et une nouvelle demande est adressée à son auteur",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reschedule"
+                ],
+                "summary": "Contre-proposition de report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la demande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Créneau contre-proposé",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reschedule-requests/{id}/reject": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "L'autre partie refuse le créneau proposé ; le cours garde son horaire initial",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reschedule"
+                ],
+                "summary": "Refus d'une demande de report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la demande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motif du refus",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleResponseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RescheduleRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sepa-debits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lots de prélèvements exportés, avec les paiements qu'ils ont créés et leur état",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Liste des fichiers de prélèvements SEPA",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SEPADebitBatch"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sepa-debits/returns": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applique un avis de crédit ou de débit camt.054 aux prélèvements exportés : les prélèvements crédités et comptabilisés sont encaissés, les rejets et retours (R-transactions) passent à l'état échoué avec leur motif et la famille est prévenue. Le fichier XML est transmis tel quel dans le corps de la requête ; un fichier déjà importé peut l'être de nouveau sans effet.",
+                "consumes": [
+                    "text/xml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Import d'un avis de la banque (camt.054)",
+                "parameters": [
+                    {
+                        "description": "Fichier camt.054",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SEPAReturnImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sepa-debits/{id}/xml": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renvoie le fichier pain.008 tel qu'il a été généré lors de l'export",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Téléchargement d'un fichier de prélèvements SEPA",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du lot",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sepa-transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lots de virements exportés, avec les relevés qu'ils couvrent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Liste des fichiers de virements SEPA",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SEPATransferBatch"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sepa-transfers/{id}/xml": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renvoie le fichier pain.001 tel qu'il a été généré lors de l'export",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "payouts"
+                ],
+                "summary": "Téléchargement d'un fichier de virements SEPA",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du lot",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/skills": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère le référentiel des compétences évaluées, par matière et niveau",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skills"
+                ],
+                "summary": "Liste des compétences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Matière",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Niveau",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Skill"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une compétence au référentiel (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skills"
+                ],
+                "summary": "Création d'une compétence",
+                "parameters": [
+                    {
+                        "description": "Compétence",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Skill"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie une compétence du référentiel (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skills"
+                ],
+                "summary": "Mise à jour d'une compétence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la compétence",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Compétence",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Skill"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire une compétence du référentiel (admin seulement) ; l'historique des notes est conservé",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skills"
+                ],
+                "summary": "Suppression d'une compétence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la compétence",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-certificates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Les administrateurs voient toutes les attestations, les familles uniquement leurs attestations publiées",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-certificates"
+                ],
+                "summary": "Liste des attestations fiscales",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Année civile",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Famille (administrateurs uniquement)",
+                        "name": "famille_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut (draft, published) (administrateurs uniquement)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaxCertificate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-certificates/generate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calcule, pour l'année civile indiquée, les sommes versées et les heures de cours de chaque famille (ou d'une seule famille), ventilées par intervenant. Les brouillons existants sont recalculés ; les attestations publiées ne sont pas modifiées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-certificates"
+                ],
+                "summary": "Génération des attestations fiscales",
+                "parameters": [
+                    {
+                        "description": "Année et famille",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxCertificateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxCertificateBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-certificates/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à disposition des familles les attestations de l'année encore en brouillon et leur envoie une notification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-certificates"
+                ],
+                "summary": "Publication des attestations fiscales",
+                "parameters": [
+                    {
+                        "description": "Année et famille",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxCertificateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxCertificateBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-certificates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attestation avec le détail des heures et montants par intervenant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-certificates"
+                ],
+                "summary": "Détail d'une attestation fiscale",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'attestation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxCertificate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-certificates/{id}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Génère le PDF de l'attestation à joindre à la déclaration de revenus ; les brouillons portent la mention BROUILLON",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "tax-certificates"
+                ],
+                "summary": "Document PDF d'une attestation fiscale",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'attestation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère les heures déclarées avec filtrage par enseignant, famille ou statut",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Liste des feuilles de temps",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'enseignant",
+                        "name": "enseignant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la famille",
+                        "name": "famille_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut (pending, approved, disputed)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimesheetEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/{id}/approve": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "La famille approuve les heures déclarées avant la date limite ; le paiement du cours est alors généré",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Approbation d'une feuille de temps",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'entrée",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimesheetEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/{id}/dispute": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "La famille conteste les heures déclarées avant la date limite ; un litige est ouvert pour les administrateurs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Contestation d'une feuille de temps",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'entrée",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motif de la contestation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TimesheetDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimesheetEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Récupère la liste de tous les utilisateurs (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Liste tous les utilisateurs",
+                "responses": {
+                    "200": {
+                        "description": "Liste des utilisateurs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.UserResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtient les détails d'un utilisateur spécifique",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Récupère un utilisateur par ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Détails de l'utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour les informations d'un utilisateur (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Met à jour un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de mise à jour",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Utilisateur mis à jour",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erreur de validation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un utilisateur du système (admin seulement)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Supprime un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Utilisateur supprimé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "ID invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/addresses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtient toutes les adresses associées à un utilisateur",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Récupère les adresses d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des adresses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Address"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtient tous les paiements associés à un utilisateur",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Récupère les paiements d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des paiements",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtient toutes les ressources accessibles à un utilisateur",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Récupère les ressources d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des ressources",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.AddressResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "Foreign Key",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "controllers.CourseResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "address_id": {
+                    "type": "integer"
+                },
+                "attendance": {
+                    "$ref": "#/definitions/models.CourseAttendance"
+                },
+                "cancellation": {
+                    "$ref": "#/definitions/models.CourseCancellation"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disputes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CourseDispute"
+                    }
+                },
+                "duration": {
+                    "description": "Duration in minutes",
+                    "type": "integer"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Famille"
+                        }
+                    ]
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CourseEvent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CourseStatus"
+                },
+                "timesheet": {
+                    "$ref": "#/definitions/models.TimesheetEntry"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.EnseignantResponse": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Address"
+                    }
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "missions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Resource"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/models.UserRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.FamilleResponse": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Address"
+                    }
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "famille": {
+                    "$ref": "#/definitions/models.Famille"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "missions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Resource"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/models.UserRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.MissionResponse": {
+            "type": "object",
+            "properties": {
+                "block_payout_on_overdue_report": {
+                    "type": "boolean"
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Famille"
+                        }
+                    ]
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "description": "Overrides the offer rate when positive",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_report_due_at": {
+                    "type": "string"
+                },
+                "offer": {
+                    "$ref": "#/definitions/models.Offer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "report_frequency_days": {
+                    "description": "Report schedule",
+                    "type": "integer"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MissionStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.OfferResponse": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Administrator"
+                        }
+                    ]
+                },
+                "created_by_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "deadline": {
+                    "description": "Applications close after this date",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enseignants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Enseignant"
+                    }
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "publication_date": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "description": "Scheduled publication of a draft",
+                    "type": "string"
+                },
+                "report_frequency_days": {
+                    "description": "Days between two teacher reports, 0 when none are expected",
+                    "type": "integer"
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Incremented at each change of the published offer",
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferRevision"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.OptionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "creation_date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Enseignant"
+                        }
+                    ]
+                },
+                "enseignant_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "famille": {
+                    "$ref": "#/definitions/models.Famille"
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "offer": {
+                    "$ref": "#/definitions/models.Offer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OptionStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "role",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "family_name": {
+                    "description": "Pour les familles",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "qualifications": {
+                    "description": "Pour les enseignants",
+                    "type": "string"
+                },
+                "referral_code": {
+                    "description": "Code de parrainage reçu par une famille",
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.UserRole"
+                },
+                "specialization": {
+                    "description": "Pour les enseignants",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "controllers.UserResponse": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Address"
+                    }
+                },
+                "administrator": {
+                    "$ref": "#/definitions/models.Administrator"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "famille": {
+                    "$ref": "#/definitions/models.Famille"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Resource"
+                    }
+                },
+                "role": {
+                    "$ref": "#/definitions/models.UserRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "Foreign Key",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AddressCreateRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "country",
+                "postal_code",
+                "street"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AddressUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Administrator": {
+            "type": "object",
+            "properties": {
+                "created_offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Offer"
+                    }
+                },
+                "managed_resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Resource"
+                    }
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "validated_reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                }
+            }
+        },
+        "models.AdvanceAllocation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "payout_id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "description": "e.g. \"Facture FA-2026-000042\"",
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdvanceCreateRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "mission_id": {
+                    "description": "Restricts the advance to this mission",
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "description": "Defaults to now",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Family paying or teacher receiving the advance",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AdvanceReport": {
+            "type": "object",
+            "properties": {
+                "advance": {
+                    "$ref": "#/definitions/models.Payment"
+                },
+                "allocated": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdvanceAllocation"
+                    }
+                },
+                "remaining": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.AttendanceCheckRequest": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                }
+            }
+        },
+        "models.AttendanceReviewRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BankAccountRequest": {
+            "type": "object",
+            "required": [
+                "account_holder",
+                "iban"
+            ],
+            "properties": {
+                "account_holder": {
+                    "type": "string",
+                    "maxLength": 70
+                },
+                "bic": {
+                    "type": "string"
+                },
+                "iban": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BankAccountResponse": {
+            "type": "object",
+            "properties": {
+                "account_holder": {
+                    "type": "string"
+                },
+                "bic": {
+                    "type": "string"
+                },
+                "iban": {
+                    "description": "Grouped by four characters",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CalendarFeedResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancellationParty": {
+            "type": "string",
+            "enum": [
+                "famille",
+                "enseignant",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "CancellationByFamille",
+                "CancellationByEnseignant",
+                "CancellationByAdmin"
+            ]
+        },
+        "models.CancellationPolicy": {
+            "type": "object",
+            "properties": {
+                "cancelled_by": {
+                    "description": "Empty = any party",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationParty"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_percent": {
+                    "description": "Credited to the family wallet, % of the course duration",
+                    "type": "number"
+                },
+                "fee_percent": {
+                    "description": "Charged to the family, % of the course price",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notice_window_hours": {
+                    "description": "Applies when notice is shorter; 0 = whatever the notice",
+                    "type": "integer"
+                },
+                "penalty_percent": {
+                    "description": "Deducted from the teacher, % of the course price",
+                    "type": "number"
+                },
+                "reason_code": {
+                    "description": "Empty = any reason",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationReason"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancellationPolicyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "cancelled_by": {
+                    "enum": [
+                        "famille",
+                        "enseignant",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationParty"
+                        }
+                    ]
+                },
+                "credit_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "fee_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notice_window_hours": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "penalty_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/models.CancellationReason"
+                }
+            }
+        },
+        "models.CancellationPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled_by": {
+                    "$ref": "#/definitions/models.CancellationParty"
+                },
+                "course_price": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "credit_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "credit_minutes": {
+                    "type": "integer"
+                },
+                "fee_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "notice_hours": {
+                    "type": "number"
+                },
+                "penalty_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.CancellationPolicy"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/models.CancellationReason"
+                }
+            }
+        },
+        "models.CancellationReason": {
+            "type": "string",
+            "enum": [
+                "illness",
+                "emergency",
+                "schedule_conflict",
+                "family_no_show",
+                "teacher_no_show",
+                "force_majeure",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "CancellationReasonIllness",
+                "CancellationReasonEmergency",
+                "CancellationReasonScheduleConflict",
+                "CancellationReasonFamilyNoShow",
+                "CancellationReasonTeacherNoShow",
+                "CancellationReasonForceMajeure",
+                "CancellationReasonOther"
+            ]
+        },
+        "models.Course": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "address_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "Duration in minutes",
+                    "type": "integer"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Famille"
+                        }
+                    ]
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CourseStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseAttendance": {
+            "type": "object",
+            "properties": {
+                "check_in_at": {
+                    "type": "string"
+                },
+                "check_in_distance": {
+                    "description": "Meters from the course address",
+                    "type": "number"
+                },
+                "check_in_latitude": {
+                    "type": "number"
+                },
+                "check_in_longitude": {
+                    "type": "number"
+                },
+                "check_out_at": {
+                    "type": "string"
+                },
+                "check_out_distance": {
+                    "description": "Meters from the course address",
+                    "type": "number"
+                },
+                "check_out_latitude": {
+                    "type": "number"
+                },
+                "check_out_longitude": {
+                    "type": "number"
+                },
+                "course": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Course"
+                        }
+                    ]
+                },
+                "course_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "flag_reasons": {
+                    "description": "Semicolon separated",
+                    "type": "string"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "late_minutes": {
+                    "type": "integer"
+                },
+                "measured_minutes": {
+                    "type": "integer"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseCancelRequest": {
+            "type": "object",
+            "required": [
+                "reason_code"
+            ],
+            "properties": {
+                "cancelled_by": {
+                    "description": "Admin only: party cancelling",
+                    "enum": [
+                        "famille",
+                        "enseignant",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationParty"
+                        }
+                    ]
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "enum": [
+                        "illness",
+                        "emergency",
+                        "schedule_conflict",
+                        "family_no_show",
+                        "teacher_no_show",
+                        "force_majeure",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationReason"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CourseCancellation": {
+            "type": "object",
+            "properties": {
+                "cancelled_by": {
+                    "$ref": "#/definitions/models.CancellationParty"
+                },
+                "cancelled_by_id": {
+                    "type": "integer"
+                },
+                "course_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "credit_minutes": {
+                    "description": "Hours credited to the family wallet",
+                    "type": "integer"
+                },
+                "fee_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "notice_hours": {
+                    "description": "Hours between cancellation and scheduled time",
+                    "type": "number"
+                },
+                "penalty_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "policy": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CancellationPolicy"
+                        }
+                    ]
+                },
+                "policy_id": {
+                    "type": "integer"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/models.CancellationReason"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseCreateRequest": {
+            "type": "object",
+            "required": [
+                "address_id",
+                "duration",
+                "enseignant_id",
+                "location",
+                "scheduled_time"
+            ],
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "description": "30 min to 8 hours",
+                    "type": "integer",
+                    "maximum": 480,
+                    "minimum": 30
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseDeclineRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseDispute": {
+            "type": "object",
+            "properties": {
+                "course": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Course"
+                        }
+                    ]
+                },
+                "course_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "opened_by_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "$ref": "#/definitions/models.DisputeResolution"
+                },
+                "resolution_note": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.DisputeStatus"
+                },
+                "timesheet_entry_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseEvent": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "0 for automatic actions",
+                    "type": "integer"
+                },
+                "course_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.CourseEventType"
+                }
+            }
+        },
+        "models.CourseEventType": {
+            "type": "string",
+            "enum": [
+                "declared",
+                "confirmed",
+                "auto_confirmed",
+                "declined",
+                "dispute_resolved",
+                "cancelled",
+                "rescheduled",
+                "checked_in",
+                "checked_out"
+            ],
+            "x-enum-varnames": [
+                "CourseEventDeclared",
+                "CourseEventConfirmed",
+                "CourseEventAutoConfirmed",
+                "CourseEventDeclined",
+                "CourseEventDisputeResolved",
+                "CourseEventCancelled",
+                "CourseEventRescheduled",
+                "CourseEventCheckedIn",
+                "CourseEventCheckedOut"
+            ]
+        },
+        "models.CourseScheduleRequest": {
+            "type": "object",
+            "required": [
+                "address_id",
+                "duration",
+                "location",
+                "scheduled_time"
+            ],
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer",
+                    "maximum": 480,
+                    "minimum": 30
+                },
+                "location": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseStatus": {
+            "type": "string",
+            "enum": [
+                "scheduled",
+                "completed",
+                "cancelled",
+                "in_progress"
+            ],
+            "x-enum-varnames": [
+                "CourseStatusScheduled",
+                "CourseStatusCompleted",
+                "CourseStatusCancelled",
+                "CourseStatusInProgress"
+            ]
+        },
+        "models.CourseUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CourseStatus"
+                }
+            }
+        },
+        "models.CreditNoteRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Currency": {
+            "type": "string",
+            "enum": [
+                "EUR",
+                "EUR"
+            ],
+            "x-enum-varnames": [
+                "CurrencyEUR",
+                "DefaultCurrency"
+            ]
+        },
+        "models.DisputeResolution": {
+            "type": "string",
+            "enum": [
+                "approved",
+                "rejected"
+            ],
+            "x-enum-comments": {
+                "DisputeResolutionApproved": "Hours are billed (possibly adjusted)",
+                "DisputeResolutionRejected": "Course is not billed"
+            },
+            "x-enum-varnames": [
+                "DisputeResolutionApproved",
+                "DisputeResolutionRejected"
+            ]
+        },
+        "models.DisputeResolveRequest": {
+            "type": "object",
+            "required": [
+                "resolution"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "description": "Adjusted duration when approved",
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1
+                },
+                "note": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DisputeResolution"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.DisputeStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "DisputeStatusOpen",
+                "DisputeStatusResolved"
+            ]
+        },
+        "models.Enseignant": {
+            "type": "object",
+            "properties": {
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "missions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Offer"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "qualifications": {
+                    "type": "string"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                },
+                "specialization": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Famille": {
+            "type": "object",
+            "properties": {
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "family_name": {
+                    "type": "string"
+                },
+                "missions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HourPackage": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "minutes": {
+                    "description": "Hours sold, in minutes",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "validity_days": {
+                    "description": "Unused hours expire after this delay; 0 = never",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HourPackagePurchase": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "hour_package_id": {
+                    "description": "Nil for hours granted by an administrator",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    ]
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "remaining_minutes": {
+                    "type": "integer"
+                },
+                "remaining_value": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.HourPackagePurchaseStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HourPackagePurchaseRequest": {
+            "type": "object",
+            "required": [
+                "hour_package_id"
+            ],
+            "properties": {
+                "hour_package_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HourPackagePurchaseStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "active",
+                "exhausted",
+                "expired",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "HourPackagePurchaseActive": "Hours available",
+                "HourPackagePurchaseCancelled": "Payment refunded",
+                "HourPackagePurchaseExhausted": "All hours used",
+                "HourPackagePurchaseExpired": "Unused hours lost",
+                "HourPackagePurchasePending": "Waiting for the payment"
+            },
+            "x-enum-varnames": [
+                "HourPackagePurchasePending",
+                "HourPackagePurchaseActive",
+                "HourPackagePurchaseExhausted",
+                "HourPackagePurchaseExpired",
+                "HourPackagePurchaseCancelled"
+            ]
+        },
+        "models.HourPackageRequest": {
+            "type": "object",
+            "required": [
+                "minutes",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "minutes": {
+                    "type": "integer",
+                    "minimum": 30
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "price": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "validity_days": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.Invoice": {
+            "type": "object",
+            "properties": {
+                "advance_amount": {
+                    "description": "Family advances deducted on issue; the amount due is TotalTTC - AdvanceAmount",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credited_invoice": {
+                    "$ref": "#/definitions/models.Invoice"
+                },
+                "credited_invoice_id": {
+                    "description": "Invoice corrected by this credit note",
+                    "type": "integer"
+                },
+                "currency": {
+                    "$ref": "#/definitions/models.Currency"
+                },
+                "customer_address": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issue_date": {
+                    "type": "string"
+                },
+                "issued_by_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.InvoiceKind"
+                },
+                "lines": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvoiceLine"
+                    }
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "e.g. FA-2026-000042, set on issue",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Why a credit note was issued",
+                    "type": "string"
+                },
+                "seller_address": {
+                    "type": "string"
+                },
+                "seller_name": {
+                    "type": "string"
+                },
+                "seller_siret": {
+                    "type": "string"
+                },
+                "seller_vat_number": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "series": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.InvoiceStatus"
+                },
+                "total_ht": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_ttc": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vat_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "vat_mention": {
+                    "description": "Legal mentions, frozen on issue",
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InvoiceCreateRequest": {
+            "type": "object",
+            "required": [
+                "famille_id"
+            ],
+            "properties": {
+                "course_ids": {
+                    "description": "Completed courses to invoice, all uninvoiced ones of the mission when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "promo_code": {
+                    "description": "Discount on the courses of this invoice",
+                    "type": "string"
+                },
+                "series": {
+                    "type": "string",
+                    "maxLength": 10
+                }
+            }
+        },
+        "models.InvoiceKind": {
+            "type": "string",
+            "enum": [
+                "invoice",
+                "credit_note"
+            ],
+            "x-enum-varnames": [
+                "InvoiceKindInvoice",
+                "InvoiceKindCreditNote"
+            ]
+        },
+        "models.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "course_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Hours for course lines",
+                    "type": "number"
+                },
+                "total_ht": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "unit_price": {
+                    "description": "Hourly rate for course lines",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "vat_rate": {
+                    "description": "Percentage",
+                    "type": "number"
+                }
+            }
+        },
+        "models.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "issued"
+            ],
+            "x-enum-varnames": [
+                "InvoiceStatusDraft",
+                "InvoiceStatusIssued"
+            ]
+        },
+        "models.InvoiceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerAccount": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.LedgerAccountRole"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerAccountRole": {
+            "type": "string",
+            "enum": [
+                "customers",
+                "customer_advances",
+                "teachers",
+                "teacher_advances",
+                "sales",
+                "sales_discounts",
+                "other_income",
+                "vat_collected",
+                "teacher_fees",
+                "bank"
+            ],
+            "x-enum-varnames": [
+                "LedgerAccountCustomers",
+                "LedgerAccountCustomerAdvances",
+                "LedgerAccountTeachers",
+                "LedgerAccountTeacherAdvances",
+                "LedgerAccountSales",
+                "LedgerAccountSalesDiscounts",
+                "LedgerAccountOtherIncome",
+                "LedgerAccountVATCollected",
+                "LedgerAccountTeacherFees",
+                "LedgerAccountBank"
+            ]
+        },
+        "models.LedgerAccountUpdateRequest": {
+            "type": "object",
+            "required": [
+                "label",
+                "number"
+            ],
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.LedgerEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "ValidDate",
+                    "type": "string"
+                },
+                "entry_date": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.LedgerEvent"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "journal": {
+                    "$ref": "#/definitions/models.LedgerJournal"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lines": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerLine"
+                    }
+                },
+                "number": {
+                    "description": "EcritureNum, e.g. EC-2026-000042",
+                    "type": "string"
+                },
+                "piece_date": {
+                    "type": "string"
+                },
+                "piece_ref": {
+                    "description": "Supporting document, e.g. invoice number",
+                    "type": "string"
+                },
+                "reversed_entry_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "description": "Source document",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LedgerSourceType"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.LedgerEvent": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "advances",
+                "advances_released",
+                "completed",
+                "returned",
+                "locked",
+                "paid"
+            ],
+            "x-enum-comments": {
+                "LedgerEventAdvances": "Advances allocated to an invoice",
+                "LedgerEventAdvancesReleased": "Allocations released by a credit note",
+                "LedgerEventCompleted": "Payment collected or paid out",
+                "LedgerEventIssued": "Invoice or credit note issued",
+                "LedgerEventLocked": "Payout statement locked",
+                "LedgerEventPaid": "Payout transferred",
+                "LedgerEventReturned": "Direct debit returned by the bank"
+            },
+            "x-enum-varnames": [
+                "LedgerEventIssued",
+                "LedgerEventAdvances",
+                "LedgerEventAdvancesReleased",
+                "LedgerEventCompleted",
+                "LedgerEventReturned",
+                "LedgerEventLocked",
+                "LedgerEventPaid"
+            ]
+        },
+        "models.LedgerJournal": {
+            "type": "string",
+            "enum": [
+                "VT",
+                "HA",
+                "BQ",
+                "OD"
+            ],
+            "x-enum-varnames": [
+                "LedgerJournalSales",
+                "LedgerJournalPurchases",
+                "LedgerJournalBank",
+                "LedgerJournalMisc"
+            ]
+        },
+        "models.LedgerLine": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "aux_label": {
+                    "type": "string"
+                },
+                "aux_number": {
+                    "description": "Family or teacher auxiliary account",
+                    "type": "string"
+                },
+                "credit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "debit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "entry_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LedgerSourceType": {
+            "type": "string",
+            "enum": [
+                "invoice",
+                "payment",
+                "payout"
+            ],
+            "x-enum-varnames": [
+                "LedgerSourceInvoice",
+                "LedgerSourcePayment",
+                "LedgerSourcePayout"
+            ]
+        },
+        "models.LedgerStatementLine": {
+            "type": "object",
+            "properties": {
+                "aux_number": {
+                    "type": "string"
+                },
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "credit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "debit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "entry_date": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "entry_number": {
+                    "type": "string"
+                },
+                "journal": {
+                    "$ref": "#/definitions/models.LedgerJournal"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "piece_ref": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerStatementResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string"
+                },
+                "aux_number": {
+                    "type": "string"
+                },
+                "closing_balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerStatementLine"
+                    }
+                },
+                "opening_balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "to": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "total_credit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_debit": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.LedgerTrialBalanceAccount": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "balance": {
+                    "description": "Debit minus credit",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "credit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "debit": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.LedgerTrialBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerTrialBalanceAccount"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "total_credit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_debit": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.MandateStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "revoked"
+            ],
+            "x-enum-varnames": [
+                "MandateStatusActive",
+                "MandateStatusRevoked"
+            ]
+        },
+        "models.Mission": {
+            "type": "object",
+            "properties": {
+                "block_payout_on_overdue_report": {
+                    "type": "boolean"
+                },
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Famille"
+                        }
+                    ]
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "description": "Overrides the offer rate when positive",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_report_due_at": {
+                    "type": "string"
+                },
+                "offer": {
+                    "$ref": "#/definitions/models.Offer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "report_frequency_days": {
+                    "description": "Report schedule",
+                    "type": "integer"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MissionStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MissionCreateRequest": {
+            "type": "object",
+            "required": [
+                "enseignant_id",
+                "start_date"
+            ],
+            "properties": {
+                "block_payout_on_overdue_report": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "promo_code": {
+                    "description": "Discount on every course of the mission",
+                    "type": "string"
+                },
+                "report_frequency_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MissionStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "completed",
+                "stopped",
+                "paused"
+            ],
+            "x-enum-varnames": [
+                "MissionStatusActive",
+                "MissionStatusCompleted",
+                "MissionStatusStopped",
+                "MissionStatusPaused"
+            ]
+        },
+        "models.MissionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "block_payout_on_overdue_report": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "report_frequency_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MissionStatus"
+                }
+            }
+        },
+        "models.Money": {
+            "type": "object",
+            "properties": {
+                "cents": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "$ref": "#/definitions/models.Currency"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "description": "API path of the related resource",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.NotificationType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Foreign Key",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationType": {
+            "type": "string",
+            "enum": [
+                "reschedule_requested",
+                "reschedule_accepted",
+                "reschedule_rejected",
+                "reschedule_countered",
+                "reschedule_expired",
+                "reschedule_cancelled",
+                "course_cancelled",
+                "attendance_flagged",
+                "report_submitted",
+                "report_validated",
+                "report_rejected",
+                "report_due_soon",
+                "report_overdue",
+                "payment_completed",
+                "payment_failed",
+                "payment_refunded",
+                "payout_locked",
+                "payout_paid",
+                "advance_recorded",
+                "debit_scheduled",
+                "tax_certificate_published",
+                "wallet_low_balance",
+                "wallet_hours_expiring",
+                "wallet_hours_expired",
+                "referral_rewarded",
+                "offer_application_received",
+                "offer_application_withdrawn",
+                "offer_application_shortlisted",
+                "offer_application_rejected",
+                "offer_application_accepted",
+                "mission_created",
+                "offer_published",
+                "offer_closed"
+            ],
+            "x-enum-varnames": [
+                "NotificationRescheduleRequested",
+                "NotificationRescheduleAccepted",
+                "NotificationRescheduleRejected",
+                "NotificationRescheduleCountered",
+                "NotificationRescheduleExpired",
+                "NotificationRescheduleCancelled",
+                "NotificationCourseCancelled",
+                "NotificationAttendanceFlagged",
+                "NotificationReportSubmitted",
+                "NotificationReportValidated",
+                "NotificationReportRejected",
+                "NotificationReportDueSoon",
+                "NotificationReportOverdue",
+                "NotificationPaymentCompleted",
+                "NotificationPaymentFailed",
+                "NotificationPaymentRefunded",
+                "NotificationPayoutLocked",
+                "NotificationPayoutPaid",
+                "NotificationAdvanceRecorded",
+                "NotificationDebitScheduled",
+                "NotificationTaxCertificatePublished",
+                "NotificationWalletLowBalance",
+                "NotificationWalletHoursExpiring",
+                "NotificationWalletHoursExpired",
+                "NotificationReferralRewarded",
+                "NotificationApplicationReceived",
+                "NotificationApplicationWithdrawn",
+                "NotificationApplicationShortlisted",
+                "NotificationApplicationRejected",
+                "NotificationApplicationAccepted",
+                "NotificationMissionCreated",
+                "NotificationOfferPublished",
+                "NotificationOfferClosed"
+            ]
+        },
+        "models.Offer": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Administrator"
+                        }
+                    ]
+                },
+                "created_by_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "deadline": {
+                    "description": "Applications close after this date",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enseignants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Enseignant"
+                    }
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "publication_date": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "description": "Scheduled publication of a draft",
+                    "type": "string"
+                },
+                "report_frequency_days": {
+                    "description": "Days between two teacher reports, 0 when none are expected",
+                    "type": "integer"
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Incremented at each change of the published offer",
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferRevision"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferApplication": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "availability": {
+                    "type": "string"
+                },
+                "cover_letter": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "$ref": "#/definitions/models.Enseignant"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "description": "Mission created when the application is accepted",
+                    "type": "integer"
+                },
+                "offer": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Offer"
+                        }
+                    ]
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "review_note": {
+                    "description": "Message from the administrator",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferApplicationStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferApplicationAcceptRequest": {
+            "type": "object",
+            "properties": {
+                "create_mission": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Required to create the mission",
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "description": "Negotiated rate, the offer rate by default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "start_date": {
+                    "description": "Now by default",
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferApplicationAcceptResponse": {
+            "type": "object",
+            "properties": {
+                "application": {
+                    "$ref": "#/definitions/models.OfferApplication"
+                },
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
+                },
+                "offer": {
+                    "$ref": "#/definitions/models.Offer"
+                }
+            }
+        },
+        "models.OfferApplicationRequest": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "cover_letter": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "offer_id": {
+                    "description": "Taken from the URL",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OfferApplicationReviewRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "models.OfferApplicationStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "shortlisted",
+                "rejected",
+                "accepted",
+                "withdrawn"
+            ],
+            "x-enum-varnames": [
+                "OfferApplicationPending",
+                "OfferApplicationShortlisted",
+                "OfferApplicationRejected",
+                "OfferApplicationAccepted",
+                "OfferApplicationWithdrawn"
+            ]
+        },
+        "models.OfferCreateRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "level",
+                "subject",
+                "title"
+            ],
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "description": "Schedules the publication of the draft",
+                    "type": "string"
+                },
+                "report_frequency_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferFacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferRateRangeFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "max_cents": {
+                    "description": "Exclusive",
+                    "type": "integer"
+                },
+                "min_cents": {
+                    "description": "Inclusive",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OfferRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.OfferRevisionAction"
+                },
+                "actor_id": {
+                    "description": "0 for the scheduler",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deadline": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferRevisionAction": {
+            "type": "string",
+            "enum": [
+                "published",
+                "scheduled",
+                "updated",
+                "unpublished",
+                "closed",
+                "filled"
+            ],
+            "x-enum-varnames": [
+                "OfferActionPublished",
+                "OfferActionScheduled",
+                "OfferActionUpdated",
+                "OfferActionUnpublished",
+                "OfferActionClosed",
+                "OfferActionFilled"
+            ]
+        },
+        "models.OfferScheduleRequest": {
+            "type": "object",
+            "required": [
+                "publish_at"
+            ],
+            "properties": {
+                "publish_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferSearchFacets": {
+            "type": "object",
+            "properties": {
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferFacetCount"
+                    }
+                },
+                "rate_ranges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferRateRangeFacet"
+                    }
+                },
+                "subjects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferFacetCount"
+                    }
+                }
+            }
+        },
+        "models.OfferSearchResponse": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/models.OfferSearchFacets"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferSearchResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OfferSearchResult": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Administrator"
+                        }
+                    ]
+                },
+                "created_by_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "deadline": {
+                    "description": "Applications close after this date",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enseignants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Enseignant"
+                    }
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Option"
+                    }
+                },
+                "publication_date": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "description": "Scheduled publication of a draft",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Relevance to the query, higher is better; 0 without query",
+                    "type": "number"
+                },
+                "report_frequency_days": {
+                    "description": "Days between two teacher reports, 0 when none are expected",
+                    "type": "integer"
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Incremented at each change of the published offer",
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OfferRevision"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfferStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "closed",
+                "filled",
+                "draft"
+            ],
+            "x-enum-varnames": [
+                "OfferStatusOpen",
+                "OfferStatusClosed",
+                "OfferStatusFilled",
+                "OfferStatusDraft"
+            ]
+        },
+        "models.OfferUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "report_frequency_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "requirements": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OfferStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Option": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "creation_date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enseignant": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Enseignant"
+                        }
+                    ]
+                },
+                "enseignant_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "famille": {
+                    "$ref": "#/definitions/models.Famille"
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "offer": {
+                    "$ref": "#/definitions/models.Offer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OptionStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OptionCreateRequest": {
+            "type": "object",
+            "required": [
+                "enseignant_id",
+                "famille_id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OptionStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "expired",
+                "accepted",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "OptionStatusActive",
+                "OptionStatusExpired",
+                "OptionStatusAccepted",
+                "OptionStatusCancelled"
+            ]
+        },
+        "models.OptionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OptionStatus"
+                }
+            }
+        },
+        "models.OverdueReportResponse": {
+            "type": "object",
+            "properties": {
+                "days_overdue": {
+                    "type": "integer"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "frequency_days": {
+                    "type": "integer"
+                },
+                "last_submission_at": {
+                    "type": "string"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "payout_blocked": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "course": {
+                    "$ref": "#/definitions/models.Course"
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "debit_batch_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "description": "Invoice settled by this payment, or credit note of a refund",
+                    "type": "integer"
+                },
+                "mandate_id": {
+                    "description": "SEPA direct debit tracking",
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "description": "Mission an advance is reserved for",
+                    "type": "integer"
+                },
+                "payment_date": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Payment provider tracking",
+                    "type": "string"
+                },
+                "provider_intent_id": {
+                    "type": "string"
+                },
+                "provider_refund_id": {
+                    "type": "string"
+                },
+                "refund_reason": {
+                    "type": "string"
+                },
+                "refunded_amount": {
+                    "description": "Refunds: the amount already refunded on a captured payment, and on refund\npayments the reason given by the admin",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "refunded_at": {
+                    "type": "string"
+                },
+                "refunded_payment_id": {
+                    "description": "Refund payments point to the captured payment they refund",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.PaymentType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentCreateRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.PaymentType"
+                },
+                "user_id": {
+                    "description": "Admin only, defaults to the current user",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentProcessRequest": {
+            "type": "object",
+            "required": [
+                "payment_method"
+            ],
+            "properties": {
+                "payment_method": {
+                    "description": "Provider token of the card or account to charge",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentRefundRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Remaining refundable amount when omitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.PaymentStatsResponse": {
+            "type": "object",
+            "properties": {
+                "completed_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "completed_count": {
+                    "type": "integer"
+                },
+                "pending_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "pending_count": {
+                    "type": "integer"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "processing",
+                "completed",
+                "failed",
+                "refunded",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "PaymentStatusCancelled": "Never collected, e.g. a course payment taken over by an invoice",
+                "PaymentStatusProcessing": "Claimed while the provider captures it"
+            },
+            "x-enum-varnames": [
+                "PaymentStatusPending",
+                "PaymentStatusProcessing",
+                "PaymentStatusCompleted",
+                "PaymentStatusFailed",
+                "PaymentStatusRefunded",
+                "PaymentStatusCancelled"
+            ]
+        },
+        "models.PaymentType": {
+            "type": "string",
+            "enum": [
+                "course",
+                "mission",
+                "advance",
+                "refund",
+                "invoice",
+                "cancellation_fee",
+                "credit",
+                "penalty",
+                "hour_package"
+            ],
+            "x-enum-comments": {
+                "PaymentTypeHourPackage": "Purchase of prepaid hours",
+                "PaymentTypeInvoice": "Settlement of an issued invoice, e.g. by SEPA direct debit"
+            },
+            "x-enum-varnames": [
+                "PaymentTypeCourse",
+                "PaymentTypeMission",
+                "PaymentTypeAdvance",
+                "PaymentTypeRefund",
+                "PaymentTypeInvoice",
+                "PaymentTypeCancellationFee",
+                "PaymentTypeCredit",
+                "PaymentTypePenalty",
+                "PaymentTypeHourPackage"
+            ]
+        },
+        "models.PaymentWebhookEvent": {
+            "type": "object",
+            "required": [
+                "intent_id",
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Refunded amount, the remaining captured amount when omitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "intent_id": {
+                    "type": "string"
+                },
+                "refund_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.PaymentWebhookEventType"
+                }
+            }
+        },
+        "models.PaymentWebhookEventType": {
+            "type": "string",
+            "enum": [
+                "payment.succeeded",
+                "payment.failed",
+                "payment.refunded"
+            ],
+            "x-enum-varnames": [
+                "PaymentEventSucceeded",
+                "PaymentEventFailed",
+                "PaymentEventRefunded"
+            ]
+        },
+        "models.Payout": {
+            "type": "object",
+            "properties": {
+                "advances_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "course_minutes": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "$ref": "#/definitions/models.Currency"
+                },
+                "enseignant_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "gross_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PayoutLine"
+                    }
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "locked_by_id": {
+                    "type": "integer"
+                },
+                "net_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "paid_by_id": {
+                    "type": "integer"
+                },
+                "payment_reference": {
+                    "description": "Bank transfer reference",
+                    "type": "string"
+                },
+                "penalties_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "period_end": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PayoutStatus"
+                },
+                "transfer_batch_id": {
+                    "description": "SEPA export covering this payout",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Negative for advances and penalties",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PayoutLineKind"
+                },
+                "minutes": {
+                    "description": "Approved duration for course lines",
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "description": "Advance deducted, possibly in part, or penalty",
+                    "type": "integer"
+                },
+                "payout_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PayoutLineKind": {
+            "type": "string",
+            "enum": [
+                "course",
+                "advance",
+                "penalty"
+            ],
+            "x-enum-comments": {
+                "PayoutLinePenalty": "Cancellation penalty charged to the teacher"
+            },
+            "x-enum-varnames": [
+                "PayoutLineCourse",
+                "PayoutLineAdvance",
+                "PayoutLinePenalty"
+            ]
+        },
+        "models.PayoutPayRequest": {
+            "type": "object",
+            "properties": {
+                "payment_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutPeriodRequest": {
+            "type": "object",
+            "properties": {
+                "enseignant_id": {
+                    "description": "All teachers when omitted",
+                    "type": "integer"
+                },
+                "month": {
+                    "description": "YYYY-MM, or period_start/period_end",
+                    "type": "string"
+                },
+                "period_end": {
+                    "description": "YYYY-MM-DD, inclusive",
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payout"
+                    }
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PayoutSkippedCourse"
+                    }
+                }
+            }
+        },
+        "models.PayoutSkippedCourse": {
+            "type": "object",
+            "properties": {
+                "course_id": {
+                    "type": "integer"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutStatus": {
+            "type": "string",
+            "enum": [
+                "locked",
+                "paid"
+            ],
+            "x-enum-varnames": [
+                "PayoutStatusLocked",
+                "PayoutStatusPaid"
+            ]
+        },
+        "models.PricingCondition": {
+            "type": "string",
+            "enum": [
+                "",
+                "evening",
+                "weekend",
+                "distance",
+                "long_mission",
+                "siblings"
+            ],
+            "x-enum-comments": {
+                "PricingConditionDistance": "Teacher travelling more than Threshold km",
+                "PricingConditionEvening": "Course starting at or after Threshold o'clock",
+                "PricingConditionLongMission": "Mission lasting at least Threshold weeks",
+                "PricingConditionSiblings": "Family with at least Threshold other active missions",
+                "PricingConditionWeekend": "Course on Saturday or Sunday"
+            },
+            "x-enum-varnames": [
+                "PricingConditionAlways",
+                "PricingConditionEvening",
+                "PricingConditionWeekend",
+                "PricingConditionDistance",
+                "PricingConditionLongMission",
+                "PricingConditionSiblings"
+            ]
+        },
+        "models.PricingQuoteLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Change of the hourly rate",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PricingRuleKind"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Hourly rate after this step",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "rule_id": {
+                    "description": "Nil for the offer or negotiated rate",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PricingQuoteRequest": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "description": "Minutes, 1 hour by default",
+                    "type": "integer",
+                    "maximum": 480,
+                    "minimum": 30
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "enseignant_id": {
+                    "type": "integer"
+                },
+                "famille_id": {
+                    "description": "Admin only, defaults to the current family",
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "description": "Rate negotiated for the mission",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "level": {
+                    "type": "string"
+                },
+                "mission_id": {
+                    "description": "Course of an existing mission",
+                    "type": "integer"
+                },
+                "offer_id": {
+                    "type": "integer"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PricingQuoteResponse": {
+            "type": "object",
+            "properties": {
+                "base_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "hourly_rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PricingQuoteLine"
+                    }
+                },
+                "mission_weeks": {
+                    "type": "number"
+                },
+                "sibling_missions": {
+                    "type": "integer"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Price of a course of this duration",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PricingRule": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "$ref": "#/definitions/models.PricingCondition"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PricingRuleKind"
+                },
+                "level": {
+                    "description": "Empty = any level",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "Surcharge or discount in % of the current rate, instead of Rate",
+                    "type": "number"
+                },
+                "priority": {
+                    "description": "Lower priorities are evaluated first",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "Base rate, or fixed amount per hour",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "stop_processing": {
+                    "description": "No further surcharge or discount once applied",
+                    "type": "boolean"
+                },
+                "subject": {
+                    "description": "Empty = any subject",
+                    "type": "string"
+                },
+                "threshold": {
+                    "description": "Hour, km, weeks or missions depending on the condition",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PricingRuleKind": {
+            "type": "string",
+            "enum": [
+                "base_rate",
+                "surcharge",
+                "discount"
+            ],
+            "x-enum-comments": {
+                "PricingRuleBaseRate": "Sets the base hourly rate",
+                "PricingRuleDiscount": "Decreases the rate",
+                "PricingRuleSurcharge": "Increases the rate"
+            },
+            "x-enum-varnames": [
+                "PricingRuleBaseRate",
+                "PricingRuleSurcharge",
+                "PricingRuleDiscount"
+            ]
+        },
+        "models.PricingRuleRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "condition": {
+                    "enum": [
+                        "evening",
+                        "weekend",
+                        "distance",
+                        "long_mission",
+                        "siblings"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingCondition"
+                        }
+                    ]
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "enum": [
+                        "base_rate",
+                        "surcharge",
+                        "discount"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PricingRuleKind"
+                        }
+                    ]
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "stop_processing": {
+                    "type": "boolean"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.PromoCode": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "code": {
+                    "description": "Stored in upper case",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "first_mission_only": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PromoCodeKind"
+                },
+                "max_uses": {
+                    "description": "0 = unlimited",
+                    "type": "integer"
+                },
+                "max_uses_per_famille": {
+                    "description": "0 = unlimited",
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "used_count": {
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromoCodeCheckRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Admin only, defaults to the current family",
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "description": "Existing mission the code would apply to",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PromoCodeCheckResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "created_at": {
+                "code": {
                     "type": "string"
                 },
-                "duration": {
-                    "description": "Duration in minutes",
-                    "type": "integer"
+                "first_mission_only": {
+                    "type": "boolean"
                 },
-                "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
+                "kind": {
+                    "$ref": "#/definitions/models.PromoCodeKind"
                 },
-                "enseignant_id": {
+                "percent": {
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "Why the code cannot be used",
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromoCodeKind": {
+            "type": "string",
+            "enum": [
+                "percentage",
+                "fixed"
+            ],
+            "x-enum-comments": {
+                "PromoCodeFixed": "Amount off, consumed course after course",
+                "PromoCodePercentage": "Percent off every course billed"
+            },
+            "x-enum-varnames": [
+                "PromoCodePercentage",
+                "PromoCodeFixed"
+            ]
+        },
+        "models.PromoCodeReport": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
                     "type": "integer"
                 },
-                "famille": {
-                    "description": "Relationships",
+                "code": {
+                    "type": "string"
+                },
+                "discount_total": {
+                    "description": "Discounts granted during the period",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.Famille"
+                            "$ref": "#/definitions/models.Money"
                         }
                     ]
                 },
-                "famille_id": {
-                    "description": "Foreign Keys",
+                "familles": {
                     "type": "integer"
                 },
-                "id": {
+                "promo_code_id": {
                     "type": "integer"
                 },
-                "location": {
+                "redemptions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "kind"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "description": {
                     "type": "string"
                 },
-                "mission": {
-                    "$ref": "#/definitions/models.Mission"
+                "first_mission_only": {
+                    "type": "boolean"
                 },
-                "mission_id": {
-                    "type": "integer"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "kind": {
+                    "enum": [
+                        "percentage",
+                        "fixed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PromoCodeKind"
+                        }
+                    ]
                 },
-                "scheduled_time": {
-                    "type": "string"
+                "max_uses": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "status": {
-                    "$ref": "#/definitions/models.CourseStatus"
+                "max_uses_per_famille": {
+                    "description": "1 by default",
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "updated_at": {
+                "percent": {
+                    "type": "number"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
                     "type": "string"
                 }
             }
         },
-        "controllers.EnseignantResponse": {
+        "models.PromoDiscount": {
             "type": "object",
             "properties": {
-                "addresses": {
-                    "description": "Relationships",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Address"
-                    }
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
-                    }
+                "course_id": {
+                    "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "email": {
-                    "type": "string"
-                },
-                "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "invoice_id": {
+                    "type": "integer"
                 },
-                "missions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Mission"
-                    }
+                "payment_id": {
+                    "type": "integer"
                 },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Option"
-                    }
+                "redemption_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PromoRedemption": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "code": {
+                    "type": "string"
                 },
-                "phone_number": {
+                "created_at": {
                     "type": "string"
                 },
-                "reports": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Report"
-                    }
+                "discount_total": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "resources": {
+                "discounts": {
+                    "description": "Relationships",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Resource"
+                        "$ref": "#/definitions/models.PromoDiscount"
                     }
                 },
-                "role": {
-                    "$ref": "#/definitions/models.UserRole"
+                "famille_id": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "username": {
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PromoCodeKind"
+                },
+                "mission_id": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "promo_code_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "remaining_amount": {
+                    "description": "Fixed codes only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PromoRedemptionStatus"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "controllers.FamilleResponse": {
+        "models.PromoRedemptionStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "exhausted",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "PromoRedemptionCancelled": "Invoice draft deleted",
+                "PromoRedemptionExhausted": "Fixed amount fully used"
+            },
+            "x-enum-varnames": [
+                "PromoRedemptionActive",
+                "PromoRedemptionExhausted",
+                "PromoRedemptionCancelled"
+            ]
+        },
+        "models.PromotionReportResponse": {
             "type": "object",
             "properties": {
-                "addresses": {
-                    "description": "Relationships",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Address"
-                    }
+                "discount_total": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "courses": {
+                "from": {
+                    "type": "string"
+                },
+                "promo_codes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Course"
+                        "$ref": "#/definitions/models.PromoCodeReport"
                     }
                 },
+                "referrals": {
+                    "$ref": "#/definitions/models.ReferralReport"
+                },
+                "to": {
+                    "description": "Exclusive",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Referral": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "email": {
                     "type": "string"
                 },
-                "famille": {
-                    "$ref": "#/definitions/models.Famille"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "missions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Mission"
-                    }
-                },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Option"
-                    }
+                "referee_id": {
+                    "type": "integer"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "referrer_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
                 },
-                "phone_number": {
+                "registered_at": {
                     "type": "string"
                 },
-                "resources": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Resource"
-                    }
+                "reward_minutes": {
+                    "description": "Granted to each family",
+                    "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/models.UserRole"
+                "reward_payment_id": {
+                    "description": "Payment that triggered the reward",
+                    "type": "integer"
                 },
-                "updated_at": {
+                "rewarded_at": {
                     "type": "string"
                 },
-                "username": {
+                "status": {
+                    "$ref": "#/definitions/models.ReferralStatus"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "controllers.LoginRequest": {
+        "models.ReferralInviteRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "email"
             ],
             "properties": {
                 "email": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ReferralReport": {
+            "type": "object",
+            "properties": {
+                "invited": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string"
+                "minutes_granted": {
+                    "description": "Referrers and referees together",
+                    "type": "integer"
+                },
+                "registered": {
+                    "type": "integer"
+                },
+                "rewarded": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.MissionResponse": {
+        "models.ReferralStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "registered",
+                "rewarded",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "ReferralStatusPending": "Invitation sent",
+                "ReferralStatusRegistered": "Referee signed up",
+                "ReferralStatusRewarded": "Referee's first course paid, credits granted"
+            },
+            "x-enum-varnames": [
+                "ReferralStatusPending",
+                "ReferralStatusRegistered",
+                "ReferralStatusRewarded",
+                "ReferralStatusCancelled"
+            ]
+        },
+        "models.Report": {
             "type": "object",
             "properties": {
-                "courses": {
+                "answers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Course"
+                        "$ref": "#/definitions/models.ReportAnswer"
                     }
                 },
-                "created_at": {
+                "comments": {
                     "type": "string"
                 },
-                "description": {
+                "content": {
                     "type": "string"
                 },
-                "end_date": {
+                "created_at": {
                     "type": "string"
                 },
                 "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
-                },
-                "enseignant_id": {
-                    "type": "integer"
-                },
-                "famille": {
                     "description": "Relationships",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.Famille"
+                            "$ref": "#/definitions/models.Enseignant"
                         }
                     ]
                 },
-                "famille_id": {
+                "enseignant_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
                 },
-                "reports": {
+                "mission_id": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "description": "Incremented at each submission",
+                    "type": "integer"
+                },
+                "revisions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Report"
+                        "$ref": "#/definitions/models.ReportRevision"
                     }
                 },
-                "start_date": {
+                "status": {
+                    "$ref": "#/definitions/models.ReportStatus"
+                },
+                "submission_date": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.MissionStatus"
+                "template": {
+                    "$ref": "#/definitions/models.ReportTemplate"
+                },
+                "template_id": {
+                    "type": "integer"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "validated_by": {
+                    "$ref": "#/definitions/models.Administrator"
+                },
+                "validated_by_id": {
+                    "description": "Reviewer of the last decision",
+                    "type": "integer"
+                },
+                "validation_date": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.OfferResponse": {
+        "models.ReportAnswer": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "created_by": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Administrator"
-                        }
-                    ]
+                "item_id": {
+                    "type": "integer"
                 },
-                "created_by_id": {
-                    "description": "Foreign Keys",
+                "rating": {
                     "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "report_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
                 },
-                "enseignants": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Enseignant"
-                    }
+                "skill_id": {
+                    "description": "Copied from the item for aggregation",
+                    "type": "integer"
                 },
-                "hourly_rate": {
-                    "type": "number"
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportAnswerRequest": {
+            "type": "object",
+            "required": [
+                "item_id"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "integer"
                 },
-                "id": {
+                "rating": {
                     "type": "integer"
                 },
-                "level": {
+                "text": {
                     "type": "string"
-                },
-                "options": {
+                }
+            }
+        },
+        "models.ReportCreateRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "mission_id"
+            ],
+            "properties": {
+                "answers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Option"
+                        "$ref": "#/definitions/models.ReportAnswerRequest"
                     }
                 },
-                "publication_date": {
-                    "type": "string"
-                },
-                "requirements": {
+                "content": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.OfferStatus"
-                },
-                "subject": {
-                    "type": "string"
+                "mission_id": {
+                    "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "submit": {
+                    "description": "Submit immediately instead of keeping a draft",
+                    "type": "boolean"
                 },
-                "updated_at": {
-                    "type": "string"
+                "template_id": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.OptionResponse": {
+        "models.ReportItemKind": {
+            "type": "string",
+            "enum": [
+                "rating",
+                "text"
+            ],
+            "x-enum-varnames": [
+                "ReportItemRating",
+                "ReportItemText"
+            ]
+        },
+        "models.ReportReviewRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "comments": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ReportRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.ReportRevisionAction"
                 },
-                "creation_date": {
-                    "type": "string"
+                "actor_id": {
+                    "type": "integer"
                 },
-                "description": {
+                "answers": {
+                    "description": "JSON snapshot of the structured answers",
                     "type": "string"
                 },
-                "enseignant": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Enseignant"
-                        }
-                    ]
-                },
-                "enseignant_id": {
-                    "description": "Foreign Keys",
-                    "type": "integer"
-                },
-                "expiration_date": {
+                "comments": {
                     "type": "string"
                 },
-                "famille": {
-                    "$ref": "#/definitions/models.Famille"
+                "content": {
+                    "type": "string"
                 },
-                "famille_id": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "offer": {
-                    "$ref": "#/definitions/models.Offer"
+                "report_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
                 },
-                "offer_id": {
+                "revision": {
                     "type": "integer"
                 },
                 "status": {
-                    "$ref": "#/definitions/models.OptionStatus"
-                },
-                "updated_at": {
-                    "type": "string"
+                    "$ref": "#/definitions/models.ReportStatus"
                 }
             }
         },
-        "controllers.RegisterRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "role",
-                "username"
+        "models.ReportRevisionAction": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "submitted",
+                "validated",
+                "rejected"
             ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "family_name": {
-                    "description": "Pour les familles",
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
-                },
-                "phone_number": {
-                    "type": "string"
-                },
-                "qualifications": {
-                    "description": "Pour les enseignants",
-                    "type": "string"
-                },
-                "role": {
-                    "$ref": "#/definitions/models.UserRole"
-                },
-                "specialization": {
-                    "description": "Pour les enseignants",
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
-                }
-            }
+            "x-enum-varnames": [
+                "ReportActionCreated",
+                "ReportActionUpdated",
+                "ReportActionSubmitted",
+                "ReportActionValidated",
+                "ReportActionRejected"
+            ]
         },
-        "controllers.UserResponse": {
+        "models.ReportStatus": {
+            "type": "string",
+            "enum": [
+                "submitted",
+                "validated",
+                "rejected",
+                "pending",
+                "draft"
+            ],
+            "x-enum-varnames": [
+                "ReportStatusSubmitted",
+                "ReportStatusValidated",
+                "ReportStatusRejected",
+                "ReportStatusPending",
+                "ReportStatusDraft"
+            ]
+        },
+        "models.ReportTemplate": {
             "type": "object",
             "properties": {
-                "addresses": {
-                    "description": "Relationships",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Address"
-                    }
-                },
-                "administrator": {
-                    "$ref": "#/definitions/models.Administrator"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
-                },
-                "famille": {
-                    "$ref": "#/definitions/models.Famille"
-                },
                 "id": {
                     "type": "integer"
                 },
                 "is_active": {
                     "type": "boolean"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "level": {
+                    "type": "string"
                 },
-                "phone_number": {
+                "name": {
                     "type": "string"
                 },
-                "resources": {
+                "scale_labels": {
+                    "description": "Comma separated, from ScaleMin to ScaleMax",
+                    "type": "string"
+                },
+                "scale_max": {
+                    "type": "integer"
+                },
+                "scale_min": {
+                    "type": "integer"
+                },
+                "sections": {
+                    "description": "Relationships",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Resource"
+                        "$ref": "#/definitions/models.ReportTemplateSection"
                     }
                 },
-                "role": {
-                    "$ref": "#/definitions/models.UserRole"
-                },
-                "updated_at": {
+                "subject": {
                     "type": "string"
                 },
-                "username": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.Address": {
+        "models.ReportTemplateItem": {
             "type": "object",
             "properties": {
-                "city": {
-                    "type": "string"
-                },
-                "country": {
-                    "type": "string"
-                },
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
-                    }
-                },
-                "created_at": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
+                "kind": {
+                    "$ref": "#/definitions/models.ReportItemKind"
                 },
-                "postal_code": {
+                "label": {
                     "type": "string"
                 },
-                "street": {
-                    "type": "string"
+                "position": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "section_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
                 },
-                "user": {
+                "skill": {
                     "description": "Relationships",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Skill"
                         }
                     ]
                 },
-                "user_id": {
-                    "description": "Foreign Key",
+                "skill_id": {
+                    "description": "Skill assessed by a rating item",
                     "type": "integer"
                 }
             }
         },
-        "models.AddressCreateRequest": {
+        "models.ReportTemplateItemRequest": {
             "type": "object",
             "required": [
-                "city",
-                "country",
-                "postal_code",
-                "street"
+                "kind",
+                "label"
             ],
             "properties": {
-                "city": {
-                    "type": "string"
-                },
-                "country": {
-                    "type": "string"
-                },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
+                "kind": {
+                    "enum": [
+                        "rating",
+                        "text"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReportItemKind"
+                        }
+                    ]
                 },
-                "postal_code": {
+                "label": {
                     "type": "string"
                 },
-                "street": {
-                    "type": "string"
+                "skill_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.AddressUpdateRequest": {
+        "models.ReportTemplateRequest": {
             "type": "object",
+            "required": [
+                "name",
+                "scale_max",
+                "sections"
+            ],
             "properties": {
-                "city": {
+                "description": {
                     "type": "string"
                 },
-                "country": {
-                    "type": "string"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "latitude": {
-                    "type": "number"
+                "level": {
+                    "type": "string"
                 },
-                "longitude": {
-                    "type": "number"
+                "name": {
+                    "type": "string"
                 },
-                "postal_code": {
+                "scale_labels": {
                     "type": "string"
                 },
-                "street": {
+                "scale_max": {
+                    "type": "integer"
+                },
+                "scale_min": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "sections": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.ReportTemplateSectionRequest"
+                    }
+                },
+                "subject": {
                     "type": "string"
                 }
             }
         },
-        "models.Administrator": {
+        "models.ReportTemplateSection": {
             "type": "object",
             "properties": {
-                "created_offers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Offer"
-                    }
+                "id": {
+                    "type": "integer"
                 },
-                "managed_resources": {
+                "items": {
+                    "description": "Relationships",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Resource"
+                        "$ref": "#/definitions/models.ReportTemplateItem"
                     }
                 },
-                "user": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
+                "position": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "template_id": {
+                    "description": "Foreign Key",
                     "type": "integer"
                 },
-                "validated_reports": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportTemplateSectionRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "title"
+            ],
+            "properties": {
+                "items": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/models.Report"
+                        "$ref": "#/definitions/models.ReportTemplateItemRequest"
                     }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "models.Course": {
+        "models.ReportUpdateRequest": {
             "type": "object",
+            "required": [
+                "content"
+            ],
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/models.Address"
-                },
-                "address_id": {
-                    "type": "integer"
+                "answers": {
+                    "description": "Replaces the structured answers when provided",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReportAnswerRequest"
+                    }
                 },
-                "created_at": {
+                "content": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RescheduleCreateRequest": {
+            "type": "object",
+            "required": [
+                "proposed_time"
+            ],
+            "properties": {
+                "proposed_duration": {
+                    "description": "Keeps the current duration when omitted",
+                    "type": "integer",
+                    "maximum": 480,
+                    "minimum": 30
                 },
-                "duration": {
-                    "description": "Duration in minutes",
-                    "type": "integer"
-                },
-                "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
-                },
-                "enseignant_id": {
-                    "type": "integer"
+                "proposed_time": {
+                    "type": "string"
                 },
-                "famille": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RescheduleRequest": {
+            "type": "object",
+            "properties": {
+                "course": {
                     "description": "Relationships",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.Famille"
+                            "$ref": "#/definitions/models.Course"
                         }
                     ]
                 },
-                "famille_id": {
+                "course_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "location": {
+                "parent_id": {
+                    "description": "Request this one counter-proposes",
+                    "type": "integer"
+                },
+                "proposed_duration": {
+                    "description": "Duration in minutes",
+                    "type": "integer"
+                },
+                "proposed_time": {
                     "type": "string"
                 },
-                "mission": {
-                    "$ref": "#/definitions/models.Mission"
+                "reason": {
+                    "type": "string"
                 },
-                "mission_id": {
+                "requested_by_id": {
                     "type": "integer"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Payment"
-                    }
+                "responded_at": {
+                    "type": "string"
                 },
-                "scheduled_time": {
+                "respondent_id": {
+                    "type": "integer"
+                },
+                "response_note": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/models.CourseStatus"
+                    "$ref": "#/definitions/models.RescheduleStatus"
                 },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.CourseCreateRequest": {
+        "models.RescheduleResponseRequest": {
             "type": "object",
-            "required": [
-                "address_id",
-                "duration",
-                "enseignant_id",
-                "location",
-                "scheduled_time"
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RescheduleStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "accepted",
+                "rejected",
+                "countered",
+                "expired",
+                "cancelled"
             ],
+            "x-enum-varnames": [
+                "RescheduleStatusPending",
+                "RescheduleStatusAccepted",
+                "RescheduleStatusRejected",
+                "RescheduleStatusCountered",
+                "RescheduleStatusExpired",
+                "RescheduleStatusCancelled"
+            ]
+        },
+        "models.Resource": {
+            "type": "object",
             "properties": {
-                "address_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "description": "Size in bytes",
                     "type": "integer"
                 },
-                "duration": {
-                    "description": "30 min to 8 hours",
-                    "type": "integer",
-                    "maximum": 480,
-                    "minimum": 30
+                "id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "managed_by": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Administrator"
+                        }
+                    ]
                 },
-                "enseignant_id": {
+                "managed_by_id": {
+                    "description": "Foreign Keys",
                     "type": "integer"
                 },
-                "location": {
+                "mime_type": {
                     "type": "string"
                 },
-                "scheduled_time": {
+                "title": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CourseScheduleRequest": {
-            "type": "object",
-            "required": [
-                "address_id",
-                "duration",
-                "location",
-                "scheduled_time"
-            ],
-            "properties": {
-                "address_id": {
-                    "type": "integer"
                 },
-                "duration": {
-                    "type": "integer",
-                    "maximum": 480,
-                    "minimum": 30
+                "type": {
+                    "$ref": "#/definitions/models.ResourceType"
                 },
-                "location": {
+                "updated_at": {
                     "type": "string"
                 },
-                "scheduled_time": {
+                "upload_date": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
                 }
             }
         },
-        "models.CourseStatus": {
+        "models.ResourceType": {
             "type": "string",
             "enum": [
-                "scheduled",
-                "completed",
-                "cancelled",
-                "in_progress"
+                "document",
+                "video",
+                "audio",
+                "image",
+                "link"
             ],
             "x-enum-varnames": [
-                "CourseStatusScheduled",
-                "CourseStatusCompleted",
-                "CourseStatusCancelled",
-                "CourseStatusInProgress"
+                "ResourceTypeDocument",
+                "ResourceTypeVideo",
+                "ResourceTypeAudio",
+                "ResourceTypeImage",
+                "ResourceTypeLink"
             ]
         },
-        "models.CourseUpdateRequest": {
+        "models.SEPADebitBatch": {
             "type": "object",
             "properties": {
-                "duration": {
-                    "type": "integer"
-                },
-                "location": {
+                "collection_date": {
                     "type": "string"
                 },
-                "scheduled_time": {
+                "created_at": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.CourseStatus"
-                }
-            }
-        },
-        "models.Enseignant": {
-            "type": "object",
-            "properties": {
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
-                    }
-                },
-                "missions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Mission"
-                    }
-                },
-                "offers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Offer"
-                    }
+                "created_by_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
                 },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Option"
-                    }
+                "id": {
+                    "type": "integer"
                 },
-                "qualifications": {
+                "message_id": {
+                    "description": "e.g. \"DEBIT-20261031-000004\"",
                     "type": "string"
                 },
-                "reports": {
+                "payment_count": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "description": "Relationships",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Report"
+                        "$ref": "#/definitions/models.Payment"
                     }
                 },
-                "specialization": {
+                "prenotified_at": {
+                    "description": "Families were notified of amount and date",
                     "type": "string"
                 },
-                "user": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
-                },
-                "user_id": {
-                    "type": "integer"
+                "total": {
+                    "$ref": "#/definitions/models.Money"
                 }
             }
         },
-        "models.Famille": {
+        "models.SEPADebitExportRequest": {
             "type": "object",
             "properties": {
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
-                    }
-                },
-                "family_name": {
+                "collection_date": {
+                    "description": "YYYY-MM-DD, defaults to the end of the pre-notification period",
                     "type": "string"
                 },
-                "missions": {
+                "invoice_ids": {
+                    "description": "All open invoices of families with an active mandate when omitted",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Mission"
+                        "type": "integer"
                     }
+                }
+            }
+        },
+        "models.SEPAExportRequest": {
+            "type": "object",
+            "properties": {
+                "execution_date": {
+                    "description": "YYYY-MM-DD, defaults to the next business day",
+                    "type": "string"
                 },
-                "options": {
+                "payout_ids": {
+                    "description": "All locked payouts not yet exported when omitted",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Option"
+                        "type": "integer"
                     }
-                },
-                "user": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
-                },
-                "user_id": {
-                    "type": "integer"
                 }
             }
         },
-        "models.Mission": {
+        "models.SEPAMandate": {
             "type": "object",
             "properties": {
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Course"
-                    }
-                },
-                "created_at": {
+                "bic": {
                     "type": "string"
                 },
-                "description": {
+                "created_at": {
                     "type": "string"
                 },
-                "end_date": {
+                "debtor_name": {
                     "type": "string"
                 },
-                "enseignant": {
-                    "$ref": "#/definitions/models.Enseignant"
-                },
-                "enseignant_id": {
-                    "type": "integer"
-                },
-                "famille": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Famille"
-                        }
-                    ]
-                },
                 "famille_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
                 },
+                "iban": {
+                    "description": "Computed, not stored",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "reports": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Report"
-                    }
+                "reference": {
+                    "description": "RUM, e.g. \"MDT-000042\"",
+                    "type": "string"
                 },
-                "start_date": {
+                "revoked_at": {
+                    "type": "string"
+                },
+                "signed_at": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/models.MissionStatus"
+                    "$ref": "#/definitions/models.MandateStatus"
                 },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.MissionCreateRequest": {
+        "models.SEPAMandateCreateRequest": {
             "type": "object",
             "required": [
-                "enseignant_id",
-                "start_date"
+                "debtor_name",
+                "iban",
+                "signed_at"
             ],
             "properties": {
-                "description": {
+                "bic": {
                     "type": "string"
                 },
-                "end_date": {
+                "debtor_name": {
+                    "type": "string",
+                    "maxLength": 70
+                },
+                "iban": {
                     "type": "string"
                 },
-                "enseignant_id": {
-                    "type": "integer"
+                "reference": {
+                    "description": "Existing RUM of a paper mandate, generated when omitted",
+                    "type": "string"
                 },
-                "start_date": {
+                "signed_at": {
                     "type": "string"
                 }
             }
         },
-        "models.MissionStatus": {
-            "type": "string",
-            "enum": [
-                "active",
-                "completed",
-                "stopped",
-                "paused"
-            ],
-            "x-enum-varnames": [
-                "MissionStatusActive",
-                "MissionStatusCompleted",
-                "MissionStatusStopped",
-                "MissionStatusPaused"
-            ]
-        },
-        "models.MissionUpdateRequest": {
+        "models.SEPAReturnImportResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "completed": {
+                    "description": "Payments marked as completed",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "end_date": {
-                    "type": "string"
+                "failed": {
+                    "description": "Payments rejected or returned",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "status": {
-                    "$ref": "#/definitions/models.MissionStatus"
+                "ignored": {
+                    "description": "Already applied, not booked yet, or unrelated entries",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unmatched": {
+                    "description": "End-to-end references matching no collection",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.Offer": {
+        "models.SEPATransferBatch": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "created_by": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Administrator"
-                        }
-                    ]
-                },
                 "created_by_id": {
                     "description": "Foreign Keys",
-                    "type": "integer"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "enseignants": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Enseignant"
-                    }
+                    "type": "integer"
                 },
-                "hourly_rate": {
-                    "type": "number"
+                "debtor_iban": {
+                    "description": "Masked in API responses",
+                    "type": "string"
+                },
+                "debtor_name": {
+                    "type": "string"
+                },
+                "execution_date": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "level": {
+                "message_id": {
+                    "description": "e.g. \"PAYOUT-20261031-000004\"",
                     "type": "string"
                 },
-                "options": {
+                "payment_count": {
+                    "type": "integer"
+                },
+                "payouts": {
+                    "description": "Relationships",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Option"
+                        "$ref": "#/definitions/models.Payout"
                     }
                 },
-                "publication_date": {
+                "total": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.Skill": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "requirements": {
+                "description": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.OfferStatus"
+                "id": {
+                    "type": "integer"
                 },
-                "subject": {
+                "level": {
                     "type": "string"
                 },
-                "title": {
+                "name": {
+                    "type": "string"
+                },
+                "subject": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -5166,400 +17589,367 @@ const docTemplate = `{
                 }
             }
         },
-        "models.OfferCreateRequest": {
+        "models.SkillProgressionPoint": {
             "type": "object",
-            "required": [
-                "description",
-                "hourly_rate",
-                "level",
-                "subject",
-                "title"
-            ],
             "properties": {
-                "description": {
+                "date": {
                     "type": "string"
                 },
-                "hourly_rate": {
-                    "type": "number",
-                    "minimum": 0
+                "percent": {
+                    "description": "Rating normalised on a 0-100 scale",
+                    "type": "number"
                 },
-                "level": {
-                    "type": "string"
+                "rating": {
+                    "type": "integer"
                 },
-                "requirements": {
-                    "type": "string"
+                "report_id": {
+                    "type": "integer"
                 },
-                "subject": {
-                    "type": "string"
+                "scale_max": {
+                    "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "scale_min": {
+                    "type": "integer"
                 }
             }
         },
-        "models.OfferStatus": {
-            "type": "string",
-            "enum": [
-                "open",
-                "closed",
-                "filled",
-                "draft"
-            ],
-            "x-enum-varnames": [
-                "OfferStatusOpen",
-                "OfferStatusClosed",
-                "OfferStatusFilled",
-                "OfferStatusDraft"
-            ]
+        "models.SkillProgressionResponse": {
+            "type": "object",
+            "properties": {
+                "delta_percent": {
+                    "type": "number"
+                },
+                "first_percent": {
+                    "type": "number"
+                },
+                "last_percent": {
+                    "type": "number"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkillProgressionPoint"
+                    }
+                },
+                "skill": {
+                    "$ref": "#/definitions/models.Skill"
+                }
+            }
         },
-        "models.OfferUpdateRequest": {
+        "models.SkillRequest": {
             "type": "object",
+            "required": [
+                "name",
+                "subject"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
                 },
-                "hourly_rate": {
-                    "type": "number"
-                },
                 "level": {
                     "type": "string"
                 },
-                "requirements": {
+                "name": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.OfferStatus"
-                },
                 "subject": {
                     "type": "string"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "models.Option": {
+        "models.TaxCertificate": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "creation_date": {
+                "customer_address": {
                     "type": "string"
                 },
-                "description": {
+                "customer_name": {
                     "type": "string"
                 },
-                "enseignant": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Enseignant"
-                        }
-                    ]
+                "declaration_number": {
+                    "description": "Services à la personne declaration",
+                    "type": "string"
                 },
-                "enseignant_id": {
+                "famille_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
                 },
-                "expiration_date": {
+                "generated_at": {
                     "type": "string"
                 },
-                "famille": {
-                    "$ref": "#/definitions/models.Famille"
-                },
-                "famille_id": {
-                    "type": "integer"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "offer": {
-                    "$ref": "#/definitions/models.Offer"
+                "lines": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxCertificateLine"
+                    }
                 },
-                "offer_id": {
+                "number": {
+                    "description": "e.g. ATT-2026-000042",
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "published_by_id": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.OptionStatus"
+                "seller_address": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "seller_name": {
+                    "description": "Legal mentions, frozen on publication",
                     "type": "string"
-                }
-            }
-        },
-        "models.OptionCreateRequest": {
-            "type": "object",
-            "required": [
-                "enseignant_id",
-                "famille_id"
-            ],
-            "properties": {
-                "description": {
+                },
+                "seller_siret": {
                     "type": "string"
                 },
-                "enseignant_id": {
-                    "type": "integer"
+                "status": {
+                    "$ref": "#/definitions/models.TaxCertificateStatus"
                 },
-                "expiration_date": {
-                    "type": "string"
+                "total_amount": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "famille_id": {
+                "total_minutes": {
                     "type": "integer"
                 },
-                "offer_id": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "year": {
                     "type": "integer"
                 }
             }
         },
-        "models.OptionStatus": {
-            "type": "string",
-            "enum": [
-                "active",
-                "expired",
-                "accepted",
-                "cancelled"
-            ],
-            "x-enum-varnames": [
-                "OptionStatusActive",
-                "OptionStatusExpired",
-                "OptionStatusAccepted",
-                "OptionStatusCancelled"
-            ]
-        },
-        "models.OptionUpdateRequest": {
+        "models.TaxCertificateBatchResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxCertificate"
+                    }
                 },
-                "expiration_date": {
-                    "type": "string"
+                "skipped": {
+                    "description": "Families whose certificate is already published",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "status": {
-                    "$ref": "#/definitions/models.OptionStatus"
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Payment": {
+        "models.TaxCertificateLine": {
             "type": "object",
             "properties": {
                 "amount": {
-                    "type": "number"
-                },
-                "course": {
-                    "$ref": "#/definitions/models.Course"
+                    "$ref": "#/definitions/models.Money"
                 },
-                "course_id": {
+                "enseignant_id": {
                     "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "payment_date": {
-                    "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/models.PaymentStatus"
-                },
-                "type": {
-                    "$ref": "#/definitions/models.PaymentType"
-                },
-                "updated_at": {
-                    "type": "string"
+                "minutes": {
+                    "type": "integer"
                 },
-                "user": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
+                "position": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "tax_certificate_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
+                },
+                "teacher_name": {
+                    "type": "string"
                 }
             }
         },
-        "models.PaymentStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "completed",
-                "failed",
-                "refunded"
+        "models.TaxCertificateRequest": {
+            "type": "object",
+            "required": [
+                "year"
             ],
-            "x-enum-varnames": [
-                "PaymentStatusPending",
-                "PaymentStatusCompleted",
-                "PaymentStatusFailed",
-                "PaymentStatusRefunded"
-            ]
+            "properties": {
+                "famille_id": {
+                    "description": "All families when omitted",
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer",
+                    "maximum": 2100,
+                    "minimum": 2000
+                }
+            }
         },
-        "models.PaymentType": {
+        "models.TaxCertificateStatus": {
             "type": "string",
             "enum": [
-                "course",
-                "mission",
-                "advance",
-                "refund"
+                "draft",
+                "published"
             ],
             "x-enum-varnames": [
-                "PaymentTypeCourse",
-                "PaymentTypeMission",
-                "PaymentTypeAdvance",
-                "PaymentTypeRefund"
+                "TaxCertificateStatusDraft",
+                "TaxCertificateStatusPublished"
             ]
         },
-        "models.Report": {
+        "models.TimesheetDeclareRequest": {
+            "type": "object",
+            "properties": {
+                "declared_end": {
+                    "type": "string"
+                },
+                "declared_start": {
+                    "type": "string"
+                },
+                "hours": {
+                    "description": "Used when start/end are omitted",
+                    "type": "number",
+                    "maximum": 12
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimesheetDisputeRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "comments": {
+                "reason": {
                     "type": "string"
-                },
-                "content": {
+                }
+            }
+        },
+        "models.TimesheetEntry": {
+            "type": "object",
+            "properties": {
+                "approval_deadline": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "auto_approved": {
+                    "type": "boolean"
                 },
-                "enseignant": {
+                "course": {
                     "description": "Relationships",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.Enseignant"
+                            "$ref": "#/definitions/models.Course"
                         }
                     ]
                 },
-                "enseignant_id": {
+                "course_id": {
                     "description": "Foreign Keys",
                     "type": "integer"
                 },
-                "id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "declared_end": {
+                    "type": "string"
+                },
+                "declared_start": {
+                    "type": "string"
+                },
+                "dispute_reason": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "description": "Actual duration in minutes",
                     "type": "integer"
                 },
-                "mission": {
-                    "$ref": "#/definitions/models.Mission"
+                "enseignant_id": {
+                    "type": "integer"
                 },
-                "mission_id": {
+                "famille_id": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.ReportStatus"
+                "id": {
+                    "type": "integer"
                 },
-                "submission_date": {
+                "notes": {
                     "type": "string"
                 },
-                "updated_at": {
+                "reviewed_at": {
                     "type": "string"
                 },
-                "validated_by": {
-                    "$ref": "#/definitions/models.Administrator"
-                },
-                "validated_by_id": {
+                "reviewed_by_id": {
                     "type": "integer"
                 },
-                "validation_date": {
+                "status": {
+                    "$ref": "#/definitions/models.TimesheetStatus"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.ReportStatus": {
+        "models.TimesheetStatus": {
             "type": "string",
             "enum": [
-                "submitted",
-                "validated",
-                "rejected",
-                "pending"
+                "pending",
+                "approved",
+                "disputed",
+                "voided"
             ],
+            "x-enum-comments": {
+                "TimesheetStatusVoided": "Course cancelled before the hours were approved"
+            },
             "x-enum-varnames": [
-                "ReportStatusSubmitted",
-                "ReportStatusValidated",
-                "ReportStatusRejected",
-                "ReportStatusPending"
+                "TimesheetStatusPending",
+                "TimesheetStatusApproved",
+                "TimesheetStatusDisputed",
+                "TimesheetStatusVoided"
             ]
         },
-        "models.Resource": {
+        "models.TimesheetSummaryResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
+                "approved_hours": {
+                    "type": "number"
                 },
-                "file_size": {
-                    "description": "Size in bytes",
+                "approved_minutes": {
                     "type": "integer"
                 },
-                "id": {
+                "disputed_minutes": {
                     "type": "integer"
                 },
-                "is_public": {
-                    "type": "boolean"
-                },
-                "managed_by": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Administrator"
-                        }
-                    ]
-                },
-                "managed_by_id": {
-                    "description": "Foreign Keys",
+                "enseignant_id": {
                     "type": "integer"
                 },
-                "mime_type": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/models.ResourceType"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimesheetEntry"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
+                "entry_count": {
+                    "type": "integer"
                 },
-                "upload_date": {
+                "month": {
+                    "description": "YYYY-MM",
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
+                "pending_minutes": {
+                    "type": "integer"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.User"
-                    }
+                "total_minutes": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ResourceType": {
-            "type": "string",
-            "enum": [
-                "document",
-                "video",
-                "audio",
-                "image",
-                "link"
-            ],
-            "x-enum-varnames": [
-                "ResourceTypeDocument",
-                "ResourceTypeVideo",
-                "ResourceTypeAudio",
-                "ResourceTypeImage",
-                "ResourceTypeLink"
-            ]
-        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -5684,6 +18074,199 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.WalletAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "minutes",
+                "reason"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "Granted hours only; never expire when omitted",
+                    "type": "string"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.WalletBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "advance_credit": {
+                    "description": "Advances not yet allocated to an invoice",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "low_balance": {
+                    "type": "boolean"
+                },
+                "minutes": {
+                    "description": "Hours available",
+                    "type": "integer"
+                },
+                "next_expiry": {
+                    "type": "string"
+                },
+                "purchases": {
+                    "description": "Active and pending purchases",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HourPackagePurchase"
+                    }
+                },
+                "value": {
+                    "description": "Prepaid value of the hours available",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.WalletStatementLine": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "0 for automatic actions",
+                    "type": "integer"
+                },
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "balance_minutes": {
+                    "type": "integer"
+                },
+                "balance_value": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "description": "Positive when hours are credited",
+                    "type": "integer"
+                },
+                "purchase_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.WalletTransactionType"
+                }
+            }
+        },
+        "models.WalletStatementResponse": {
+            "type": "object",
+            "properties": {
+                "closing_minutes": {
+                    "type": "integer"
+                },
+                "closing_value": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "famille_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WalletStatementLine"
+                    }
+                },
+                "opening_minutes": {
+                    "type": "integer"
+                },
+                "opening_value": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "to": {
+                    "description": "Exclusive",
+                    "type": "string"
+                }
+            }
+        },
+        "models.WalletTransaction": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "0 for automatic actions",
+                    "type": "integer"
+                },
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "famille_id": {
+                    "description": "Foreign Keys",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "description": "Positive when hours are credited",
+                    "type": "integer"
+                },
+                "purchase_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.WalletTransactionType"
+                }
+            }
+        },
+        "models.WalletTransactionType": {
+            "type": "string",
+            "enum": [
+                "purchase",
+                "course",
+                "expiry",
+                "adjustment",
+                "cancellation"
+            ],
+            "x-enum-varnames": [
+                "WalletTransactionPurchase",
+                "WalletTransactionCourse",
+                "WalletTransactionExpiry",
+                "WalletTransactionAdjustment",
+                "WalletTransactionCancellation"
+            ]
         }
     },
     "securityDefinitions": {
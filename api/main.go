@@ -6,6 +6,7 @@ import (
 
 	"api/database"
 	"api/routes"
+	"api/services"
 
 	_ "api/docs" // This line is necessary for go-swagger to find your docs!
 
@@ -51,6 +52,14 @@ func main() {
 		}
 	}
 
+	// Vérifier le prestataire de paiement avant d'accepter des requêtes
+	if _, err := services.CurrentPaymentProvider(); err != nil {
+		log.Fatal("Erreur de configuration du paiement:", err)
+	}
+
+	// Lancer les tâches périodiques (auto-approbation, etc.)
+	services.StartScheduler(services.DefaultJobs()...)
+
 	// Configurer Gin
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
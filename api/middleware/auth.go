@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"api/models"
 	"api/utils"
 	"net/http"
 	"strings"
@@ -76,17 +77,17 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 
 // RequireAdmin vérifie que l'utilisateur est un administrateur
 func RequireAdmin() gin.HandlerFunc {
-	return RequireRole("admin")
+	return RequireRole(string(models.RoleAdministrator))
 }
 
 // RequireTeacher vérifie que l'utilisateur est un enseignant
 func RequireTeacher() gin.HandlerFunc {
-	return RequireRole("teacher")
+	return RequireRole(string(models.RoleEnseignant))
 }
 
 // RequireParent vérifie que l'utilisateur est un parent
 func RequireParent() gin.HandlerFunc {
-	return RequireRole("parent")
+	return RequireRole(string(models.RoleFamille))
 }
 
 // RequireChild vérifie que l'utilisateur est un enfant
@@ -96,12 +97,12 @@ func RequireChild() gin.HandlerFunc {
 
 // RequireTeacherOrAdmin vérifie que l'utilisateur est enseignant ou admin
 func RequireTeacherOrAdmin() gin.HandlerFunc {
-	return RequireRole("teacher", "admin")
+	return RequireRole(string(models.RoleEnseignant), string(models.RoleAdministrator))
 }
 
 // RequireParentOrAdmin vérifie que l'utilisateur est parent ou admin
 func RequireParentOrAdmin() gin.HandlerFunc {
-	return RequireRole("parent", "admin")
+	return RequireRole(string(models.RoleFamille), string(models.RoleAdministrator))
 }
 
 // RequireAnyRole vérifie que l'utilisateur a au moins un des rôles spécifiés
@@ -134,19 +135,19 @@ func GetUserRole(c *gin.Context) (string, bool) {
 // IsAdmin vérifie si l'utilisateur actuel est un administrateur
 func IsAdmin(c *gin.Context) bool {
 	role, exists := GetUserRole(c)
-	return exists && role == "admin"
+	return exists && role == string(models.RoleAdministrator)
 }
 
 // IsTeacher vérifie si l'utilisateur actuel est un enseignant
 func IsTeacher(c *gin.Context) bool {
 	role, exists := GetUserRole(c)
-	return exists && role == "teacher"
+	return exists && role == string(models.RoleEnseignant)
 }
 
 // IsParent vérifie si l'utilisateur actuel est un parent
 func IsParent(c *gin.Context) bool {
 	role, exists := GetUserRole(c)
-	return exists && role == "parent"
+	return exists && role == string(models.RoleFamille)
 }
 
 // IsChild vérifie si l'utilisateur actuel est un enfant
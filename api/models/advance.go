@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// AdvanceAllocation model - records the part of an advance (a completed payment
+// of type advance) consumed by an invoice, for families, or by a payout
+// statement, for teachers. Allocations released by a credit note no longer
+// count against the advance but are kept for the reconciliation report.
+type AdvanceAllocation struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Amount     Money      `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Reference  string     `json:"reference"` // e.g. "Facture FA-2026-000042"
+	ReleasedAt *time.Time `json:"released_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Foreign Keys
+	PaymentID uint  `json:"payment_id" gorm:"index;not null"` // The advance
+	InvoiceID *uint `json:"invoice_id,omitempty" gorm:"index"`
+	PayoutID  *uint `json:"payout_id,omitempty" gorm:"index"`
+}
+
+// IsActive indique si l'imputation est toujours déduite de l'avance
+func (a *AdvanceAllocation) IsActive() bool {
+	return a.ReleasedAt == nil
+}
+
+// Request/Response structures
+type AdvanceCreateRequest struct {
+	UserID      uint       `json:"user_id" binding:"required"` // Family paying or teacher receiving the advance
+	MissionID   *uint      `json:"mission_id,omitempty"`       // Restricts the advance to this mission
+	Amount      Money      `json:"amount"`
+	Description string     `json:"description"`
+	PaidAt      *time.Time `json:"paid_at,omitempty"` // Defaults to now
+}
+
+// AdvanceReport shows how an advance was consumed
+type AdvanceReport struct {
+	Advance     Payment             `json:"advance"`
+	Allocated   Money               `json:"allocated"`
+	Remaining   Money               `json:"remaining"`
+	Allocations []AdvanceAllocation `json:"allocations"`
+}
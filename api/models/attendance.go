@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CourseAttendance model - represents the teacher check-in/check-out of a course
+type CourseAttendance struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	CheckInAt        *time.Time `json:"check_in_at"`
+	CheckInLatitude  *float64   `json:"check_in_latitude,omitempty"`
+	CheckInLongitude *float64   `json:"check_in_longitude,omitempty"`
+	CheckInDistance  *float64   `json:"check_in_distance,omitempty"` // Meters from the course address
+	LateMinutes      int        `json:"late_minutes"`
+
+	CheckOutAt        *time.Time `json:"check_out_at"`
+	CheckOutLatitude  *float64   `json:"check_out_latitude,omitempty"`
+	CheckOutLongitude *float64   `json:"check_out_longitude,omitempty"`
+	CheckOutDistance  *float64   `json:"check_out_distance,omitempty"` // Meters from the course address
+
+	MeasuredMinutes int        `json:"measured_minutes"`
+	Flagged         bool       `json:"flagged" gorm:"index"`
+	FlagReasons     string     `json:"flag_reasons,omitempty"` // Semicolon separated
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	ReviewNote      string     `json:"review_note,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	CourseID     uint `json:"course_id" gorm:"uniqueIndex;not null"`
+	EnseignantID uint `json:"enseignant_id" gorm:"index"`
+	ReviewedByID uint `json:"reviewed_by_id,omitempty"`
+
+	// Relationships
+	Course *Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+}
+
+// CourseAttendance methods
+func (a *CourseAttendance) Flag(reason string) {
+	a.Flagged = true
+	if a.FlagReasons == "" {
+		a.FlagReasons = reason
+		return
+	}
+	a.FlagReasons = strings.Join([]string{a.FlagReasons, reason}, "; ")
+}
+
+func (a *CourseAttendance) CheckOut(at time.Time, latitude, longitude, distance *float64) {
+	a.CheckOutAt = &at
+	a.CheckOutLatitude = latitude
+	a.CheckOutLongitude = longitude
+	a.CheckOutDistance = distance
+	if a.CheckInAt != nil {
+		a.MeasuredMinutes = int(at.Sub(*a.CheckInAt).Minutes())
+	}
+}
+
+func (a *CourseAttendance) Review(reviewerID uint, note string) {
+	now := time.Now()
+	a.ReviewedAt = &now
+	a.ReviewedByID = reviewerID
+	a.ReviewNote = note
+}
+
+// Request/Response structures
+type AttendanceCheckRequest struct {
+	Latitude  *float64 `json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+}
+
+type AttendanceReviewRequest struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeed model - represents a secret iCalendar subscription for a user
+type CalendarFeed struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	Token      string         `json:"token" gorm:"uniqueIndex;not null"`
+	LastAccess *time.Time     `json:"last_access"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Foreign Key
+	UserID uint `json:"user_id" gorm:"uniqueIndex;not null"`
+
+	// Relationships
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// Request/Response structures
+type CalendarFeedResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CancellationParty represents who cancelled a course
+type CancellationParty string
+
+const (
+	CancellationByFamille    CancellationParty = "famille"
+	CancellationByEnseignant CancellationParty = "enseignant"
+	CancellationByAdmin      CancellationParty = "admin"
+)
+
+// CancellationReason represents the reason code of a cancellation
+type CancellationReason string
+
+const (
+	CancellationReasonIllness          CancellationReason = "illness"
+	CancellationReasonEmergency        CancellationReason = "emergency"
+	CancellationReasonScheduleConflict CancellationReason = "schedule_conflict"
+	CancellationReasonFamilyNoShow     CancellationReason = "family_no_show"
+	CancellationReasonTeacherNoShow    CancellationReason = "teacher_no_show"
+	CancellationReasonForceMajeure     CancellationReason = "force_majeure"
+	CancellationReasonOther            CancellationReason = "other"
+)
+
+// CancellationPolicy model - represents a rule applied when a course is cancelled
+type CancellationPolicy struct {
+	ID                uint               `json:"id" gorm:"primaryKey"`
+	Name              string             `json:"name" gorm:"not null"`
+	CancelledBy       CancellationParty  `json:"cancelled_by,omitempty"` // Empty = any party
+	ReasonCode        CancellationReason `json:"reason_code,omitempty"`  // Empty = any reason
+	NoticeWindowHours int                `json:"notice_window_hours"`    // Applies when notice is shorter; 0 = whatever the notice
+	FeePercent        float64            `json:"fee_percent"`            // Charged to the family, % of the course price
+	CreditPercent     float64            `json:"credit_percent"`         // Credited to the family wallet, % of the course duration
+	PenaltyPercent    float64            `json:"penalty_percent"`        // Deducted from the teacher, % of the course price
+	IsActive          bool               `json:"is_active" gorm:"default:true"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// Matches indique si la règle s'applique à une annulation
+func (p *CancellationPolicy) Matches(party CancellationParty, reason CancellationReason, noticeHours float64) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.CancelledBy != "" && p.CancelledBy != party {
+		return false
+	}
+	if p.ReasonCode != "" && p.ReasonCode != reason {
+		return false
+	}
+	return p.NoticeWindowHours == 0 || noticeHours < float64(p.NoticeWindowHours)
+}
+
+// CourseCancellation model - represents the cancellation record of a course
+type CourseCancellation struct {
+	ID            uint               `json:"id" gorm:"primaryKey"`
+	CancelledBy   CancellationParty  `json:"cancelled_by" gorm:"not null"`
+	ReasonCode    CancellationReason `json:"reason_code" gorm:"not null"`
+	Note          string             `json:"note,omitempty" gorm:"type:text"`
+	NoticeHours   float64            `json:"notice_hours"` // Hours between cancellation and scheduled time
+	FeeAmount     Money              `json:"fee_amount" gorm:"embedded;embeddedPrefix:fee_amount_"`
+	CreditAmount  Money              `json:"credit_amount" gorm:"embedded;embeddedPrefix:credit_amount_"`
+	CreditMinutes int                `json:"credit_minutes"` // Hours credited to the family wallet
+	PenaltyAmount Money              `json:"penalty_amount" gorm:"embedded;embeddedPrefix:penalty_amount_"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt     `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	CourseID      uint  `json:"course_id" gorm:"uniqueIndex;not null"`
+	CancelledByID uint  `json:"cancelled_by_id"`
+	PolicyID      *uint `json:"policy_id,omitempty"`
+
+	// Relationships
+	Policy *CancellationPolicy `json:"policy,omitempty" gorm:"foreignKey:PolicyID"`
+}
+
+// Request/Response structures
+type CourseCancelRequest struct {
+	ReasonCode  CancellationReason `json:"reason_code" binding:"required,oneof=illness emergency schedule_conflict family_no_show teacher_no_show force_majeure other"`
+	Note        string             `json:"note"`
+	CancelledBy CancellationParty  `json:"cancelled_by,omitempty" binding:"omitempty,oneof=famille enseignant admin"` // Admin only: party cancelling
+}
+
+type CancellationPolicyRequest struct {
+	Name              string             `json:"name" binding:"required"`
+	CancelledBy       CancellationParty  `json:"cancelled_by,omitempty" binding:"omitempty,oneof=famille enseignant admin"`
+	ReasonCode        CancellationReason `json:"reason_code,omitempty"`
+	NoticeWindowHours int                `json:"notice_window_hours" binding:"min=0"`
+	FeePercent        float64            `json:"fee_percent" binding:"min=0,max=100"`
+	CreditPercent     float64            `json:"credit_percent" binding:"min=0,max=100"`
+	PenaltyPercent    float64            `json:"penalty_percent" binding:"min=0,max=100"`
+	IsActive          *bool              `json:"is_active,omitempty"`
+}
+
+type CancellationPreviewResponse struct {
+	CancelledBy   CancellationParty   `json:"cancelled_by"`
+	ReasonCode    CancellationReason  `json:"reason_code"`
+	NoticeHours   float64             `json:"notice_hours"`
+	CoursePrice   Money               `json:"course_price"`
+	FeeAmount     Money               `json:"fee_amount"`
+	CreditAmount  Money               `json:"credit_amount"`
+	CreditMinutes int                 `json:"credit_minutes"`
+	PenaltyAmount Money               `json:"penalty_amount"`
+	Policy        *CancellationPolicy `json:"policy,omitempty"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,6 +17,18 @@ const (
 	CourseStatusInProgress CourseStatus = "in_progress"
 )
 
+var (
+	ErrCourseStatusTransition = errors.New("ce changement de statut n'est pas autorisé pour ce cours")
+	ErrCourseCompletion       = errors.New("un cours ne passe à l'état terminé qu'après validation de ses heures par la famille")
+)
+
+// courseTransitions lists the statuses a course may move to from each status;
+// completed and cancelled courses are final
+var courseTransitions = map[CourseStatus][]CourseStatus{
+	CourseStatusScheduled:  {CourseStatusInProgress, CourseStatusCompleted, CourseStatusCancelled},
+	CourseStatusInProgress: {CourseStatusScheduled, CourseStatusCompleted, CourseStatusCancelled},
+}
+
 // Course model - represents a scheduled course/session
 type Course struct {
 	ID            uint           `json:"id" gorm:"primaryKey"`
@@ -41,25 +54,70 @@ type Course struct {
 	Payments   []Payment  `json:"payments,omitempty" gorm:"foreignKey:CourseID"`
 }
 
+// CourseEventType represents a step of the course completion exchange
+type CourseEventType string
+
+const (
+	CourseEventDeclared        CourseEventType = "declared"
+	CourseEventConfirmed       CourseEventType = "confirmed"
+	CourseEventAutoConfirmed   CourseEventType = "auto_confirmed"
+	CourseEventDeclined        CourseEventType = "declined"
+	CourseEventDisputeResolved CourseEventType = "dispute_resolved"
+	CourseEventCancelled       CourseEventType = "cancelled"
+	CourseEventRescheduled     CourseEventType = "rescheduled"
+	CourseEventCheckedIn       CourseEventType = "checked_in"
+	CourseEventCheckedOut      CourseEventType = "checked_out"
+)
+
+// CourseEvent model - represents an entry of the course history
+type CourseEvent struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	Type      CourseEventType `json:"type" gorm:"not null"`
+	Message   string          `json:"message"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	// Foreign Keys
+	CourseID uint `json:"course_id" gorm:"index;not null"`
+	ActorID  uint `json:"actor_id,omitempty"` // 0 for automatic actions
+}
+
 // Course methods
-func (c *Course) Schedule() error {
-	c.Status = CourseStatusScheduled
+
+// CanTransitionTo indique si le cours peut passer au statut demandé
+func (c *Course) CanTransitionTo(status CourseStatus) bool {
+	if status == c.Status {
+		return true
+	}
+	for _, next := range courseTransitions[c.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Course) transitionTo(status CourseStatus) error {
+	if !c.CanTransitionTo(status) {
+		return ErrCourseStatusTransition
+	}
+	c.Status = status
 	return nil
 }
 
+func (c *Course) Schedule() error {
+	return c.transitionTo(CourseStatusScheduled)
+}
+
 func (c *Course) Cancel() error {
-	c.Status = CourseStatusCancelled
-	return nil
+	return c.transitionTo(CourseStatusCancelled)
 }
 
 func (c *Course) Validate() error {
-	c.Status = CourseStatusCompleted
-	return nil
+	return c.transitionTo(CourseStatusCompleted)
 }
 
 func (c *Course) Declare() error {
-	c.Status = CourseStatusInProgress
-	return nil
+	return c.transitionTo(CourseStatusInProgress)
 }
 
 // Request/Response structures
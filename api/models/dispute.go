@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DisputeStatus represents the status of a course dispute
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen     DisputeStatus = "open"
+	DisputeStatusResolved DisputeStatus = "resolved"
+)
+
+// DisputeResolution represents the outcome decided by an administrator
+type DisputeResolution string
+
+const (
+	DisputeResolutionApproved DisputeResolution = "approved" // Hours are billed (possibly adjusted)
+	DisputeResolutionRejected DisputeResolution = "rejected" // Course is not billed
+)
+
+// CourseDispute model - represents a family declining a declared course
+type CourseDispute struct {
+	ID             uint              `json:"id" gorm:"primaryKey"`
+	Reason         string            `json:"reason" gorm:"type:text;not null"`
+	Status         DisputeStatus     `json:"status" gorm:"default:'open'"`
+	Resolution     DisputeResolution `json:"resolution,omitempty"`
+	ResolutionNote string            `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedAt     *time.Time        `json:"resolved_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	CourseID         uint `json:"course_id" gorm:"index;not null"`
+	TimesheetEntryID uint `json:"timesheet_entry_id"`
+	OpenedByID       uint `json:"opened_by_id"`
+	ResolvedByID     uint `json:"resolved_by_id,omitempty"`
+
+	// Relationships
+	Course *Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+}
+
+// CourseDispute methods
+func (d *CourseDispute) Resolve(adminID uint, resolution DisputeResolution, note string) {
+	now := time.Now()
+	d.Status = DisputeStatusResolved
+	d.Resolution = resolution
+	d.ResolutionNote = note
+	d.ResolvedByID = adminID
+	d.ResolvedAt = &now
+}
+
+// Request/Response structures
+type CourseDeclineRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type DisputeResolveRequest struct {
+	Resolution      DisputeResolution `json:"resolution" binding:"required,oneof=approved rejected"`
+	Note            string            `json:"note"`
+	DurationMinutes *int              `json:"duration_minutes,omitempty" binding:"omitempty,min=1,max=720"` // Adjusted duration when approved
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvoiceKind distinguishes invoices from credit notes
+type InvoiceKind string
+
+const (
+	InvoiceKindInvoice    InvoiceKind = "invoice"
+	InvoiceKindCreditNote InvoiceKind = "credit_note"
+)
+
+// InvoiceStatus represents the status of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft  InvoiceStatus = "draft"
+	InvoiceStatusIssued InvoiceStatus = "issued"
+)
+
+var (
+	ErrInvoiceIssued    = errors.New("une facture émise ne peut plus être modifiée ; établissez un avoir")
+	ErrInvoiceNotIssued = errors.New("seule une facture émise peut faire l'objet de cette opération")
+	ErrInvoiceEmpty     = errors.New("la facture ne comporte aucune ligne")
+)
+
+// Invoice model - represents an invoice or a credit note. Drafts have no number:
+// numbers are allocated sequentially per series and year when the document is
+// issued, after which its content is frozen.
+type Invoice struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	Kind      InvoiceKind   `json:"kind" gorm:"not null;default:'invoice'"`
+	Status    InvoiceStatus `json:"status" gorm:"not null;default:'draft'"`
+	Series    string        `json:"series" gorm:"not null"`
+	Year      int           `json:"year,omitempty"`
+	Sequence  int           `json:"sequence,omitempty"`
+	Number    *string       `json:"number" gorm:"uniqueIndex"` // e.g. FA-2026-000042, set on issue
+	IssueDate *time.Time    `json:"issue_date"`
+	DueDate   *time.Time    `json:"due_date"`
+	Currency  Currency      `json:"currency" gorm:"not null;default:'EUR'"`
+	TotalHT   Money         `json:"total_ht" gorm:"embedded;embeddedPrefix:total_ht_"`
+	VATAmount Money         `json:"vat_amount" gorm:"embedded;embeddedPrefix:vat_amount_"`
+	TotalTTC  Money         `json:"total_ttc" gorm:"embedded;embeddedPrefix:total_ttc_"`
+	// Family advances deducted on issue; the amount due is TotalTTC - AdvanceAmount
+	AdvanceAmount Money  `json:"advance_amount" gorm:"embedded;embeddedPrefix:advance_amount_"`
+	Notes         string `json:"notes,omitempty" gorm:"type:text"`
+	Reason        string `json:"reason,omitempty"` // Why a credit note was issued
+
+	// Legal mentions, frozen on issue
+	VATMention      string `json:"vat_mention,omitempty"`
+	SellerName      string `json:"seller_name,omitempty"`
+	SellerAddress   string `json:"seller_address,omitempty"`
+	SellerSIRET     string `json:"seller_siret,omitempty"`
+	SellerVATNumber string `json:"seller_vat_number,omitempty"`
+	CustomerName    string `json:"customer_name,omitempty"`
+	CustomerAddress string `json:"customer_address,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	FamilleID         uint  `json:"famille_id" gorm:"index;not null"`
+	MissionID         *uint `json:"mission_id,omitempty"`
+	CreditedInvoiceID *uint `json:"credited_invoice_id,omitempty"` // Invoice corrected by this credit note
+	IssuedByID        *uint `json:"issued_by_id,omitempty"`
+
+	// Relationships
+	Lines           []InvoiceLine `json:"lines,omitempty" gorm:"foreignKey:InvoiceID"`
+	CreditedInvoice *Invoice      `json:"credited_invoice,omitempty" gorm:"foreignKey:CreditedInvoiceID"`
+}
+
+// InvoiceLine model - represents a line of an invoice. Credit note lines carry a
+// negative quantity so that the sums over a course cancel out.
+type InvoiceLine struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	Position    int     `json:"position"`
+	Description string  `json:"description" gorm:"not null"`
+	Quantity    float64 `json:"quantity"`                                              // Hours for course lines
+	UnitPrice   Money   `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"` // Hourly rate for course lines
+	VATRate     float64 `json:"vat_rate"`                                              // Percentage
+	TotalHT     Money   `json:"total_ht" gorm:"embedded;embeddedPrefix:total_ht_"`
+
+	// Foreign Keys
+	InvoiceID uint  `json:"invoice_id" gorm:"index;not null"`
+	CourseID  *uint `json:"course_id,omitempty" gorm:"index"`
+}
+
+// InvoiceSequence model - holds the last number allocated in a series for a year
+type InvoiceSequence struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Series     string `json:"series" gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	Year       int    `json:"year" gorm:"not null;uniqueIndex:idx_invoice_sequence"`
+	LastNumber int    `json:"last_number"`
+}
+
+// Invoice methods
+
+// IsIssued indique si la facture a reçu son numéro définitif
+func (i *Invoice) IsIssued() bool {
+	return i.Status == InvoiceStatusIssued
+}
+
+// ComputeTotals recalcule les totaux de la facture à partir de ses lignes. La TVA
+// est calculée et arrondie une fois par taux, sur le total hors taxes de ce taux.
+func (i *Invoice) ComputeTotals() {
+	i.TotalHT, i.VATAmount = NewMoney(0, i.Currency), NewMoney(0, i.Currency)
+	baseByRate := map[float64]Money{}
+	for _, line := range i.Lines {
+		i.TotalHT = i.TotalHT.Add(line.TotalHT)
+		baseByRate[line.VATRate] = baseByRate[line.VATRate].Add(line.TotalHT)
+	}
+	for rate, base := range baseByRate {
+		i.VATAmount = i.VATAmount.Add(base.Percent(rate))
+	}
+	i.TotalTTC = i.TotalHT.Add(i.VATAmount)
+	if i.AdvanceAmount.Currency == "" {
+		i.AdvanceAmount = NewMoney(0, i.Currency)
+	}
+}
+
+// AmountDue retourne le montant restant à régler après déduction des acomptes
+func (i *Invoice) AmountDue() Money {
+	return i.TotalTTC.Sub(i.AdvanceAmount)
+}
+
+// Issue attribue le numéro définitif et fige la facture ; adminID vaut 0 pour un
+// document émis automatiquement
+func (i *Invoice) Issue(number string, year, sequence int, issuedAt time.Time, adminID uint) error {
+	if i.IsIssued() {
+		return ErrInvoiceIssued
+	}
+	if len(i.Lines) == 0 {
+		return ErrInvoiceEmpty
+	}
+	i.Status = InvoiceStatusIssued
+	i.Number = &number
+	i.Year = year
+	i.Sequence = sequence
+	i.IssueDate = &issuedAt
+	if adminID != 0 {
+		i.IssuedByID = &adminID
+	}
+	return nil
+}
+
+// Request/Response structures
+type InvoiceCreateRequest struct {
+	FamilleID uint   `json:"famille_id" binding:"required"`
+	MissionID *uint  `json:"mission_id,omitempty"`
+	CourseIDs []uint `json:"course_ids,omitempty"` // Completed courses to invoice, all uninvoiced ones of the mission when empty
+	Series    string `json:"series,omitempty" binding:"omitempty,alphanum,max=10"`
+	Notes     string `json:"notes,omitempty"`
+	PromoCode string `json:"promo_code,omitempty"` // Discount on the courses of this invoice
+}
+
+type InvoiceUpdateRequest struct {
+	Notes string `json:"notes"`
+}
+
+type CreditNoteRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
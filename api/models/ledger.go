@@ -0,0 +1,213 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LedgerJournal is the code of an accounting journal (JournalCode in the FEC)
+type LedgerJournal string
+
+const (
+	LedgerJournalSales     LedgerJournal = "VT"
+	LedgerJournalPurchases LedgerJournal = "HA"
+	LedgerJournalBank      LedgerJournal = "BQ"
+	LedgerJournalMisc      LedgerJournal = "OD"
+)
+
+// LedgerAccountRole identifies the purpose of an account of the chart, whatever
+// its number
+type LedgerAccountRole string
+
+const (
+	LedgerAccountCustomers        LedgerAccountRole = "customers"
+	LedgerAccountCustomerAdvances LedgerAccountRole = "customer_advances"
+	LedgerAccountTeachers         LedgerAccountRole = "teachers"
+	LedgerAccountTeacherAdvances  LedgerAccountRole = "teacher_advances"
+	LedgerAccountSales            LedgerAccountRole = "sales"
+	LedgerAccountSalesDiscounts   LedgerAccountRole = "sales_discounts"
+	LedgerAccountOtherIncome      LedgerAccountRole = "other_income"
+	LedgerAccountVATCollected     LedgerAccountRole = "vat_collected"
+	LedgerAccountTeacherFees      LedgerAccountRole = "teacher_fees"
+	LedgerAccountBank             LedgerAccountRole = "bank"
+)
+
+// LedgerSourceType identifies the kind of document an entry was posted from
+type LedgerSourceType string
+
+const (
+	LedgerSourceInvoice LedgerSourceType = "invoice"
+	LedgerSourcePayment LedgerSourceType = "payment"
+	LedgerSourcePayout  LedgerSourceType = "payout"
+)
+
+// LedgerEvent identifies the step of the document life cycle an entry records
+type LedgerEvent string
+
+const (
+	LedgerEventIssued           LedgerEvent = "issued"            // Invoice or credit note issued
+	LedgerEventAdvances         LedgerEvent = "advances"          // Advances allocated to an invoice
+	LedgerEventAdvancesReleased LedgerEvent = "advances_released" // Allocations released by a credit note
+	LedgerEventCompleted        LedgerEvent = "completed"         // Payment collected or paid out
+	LedgerEventReturned         LedgerEvent = "returned"          // Direct debit returned by the bank
+	LedgerEventLocked           LedgerEvent = "locked"            // Payout statement locked
+	LedgerEventPaid             LedgerEvent = "paid"              // Payout transferred
+)
+
+var (
+	ErrLedgerImmutable  = errors.New("les écritures comptables ne peuvent être ni modifiées ni supprimées ; passez une écriture de contrepassation")
+	ErrLedgerUnbalanced = errors.New("écriture déséquilibrée : le total des débits doit être égal au total des crédits")
+)
+
+// DefaultLedgerAccounts is the chart of accounts created on first use, based on
+// the French Plan Comptable Général. Numbers and labels can then be changed.
+var DefaultLedgerAccounts = []LedgerAccount{
+	{Role: LedgerAccountCustomers, Number: "411000", Label: "Clients"},
+	{Role: LedgerAccountCustomerAdvances, Number: "419100", Label: "Clients - avances et acomptes reçus"},
+	{Role: LedgerAccountTeachers, Number: "401000", Label: "Fournisseurs - enseignants"},
+	{Role: LedgerAccountTeacherAdvances, Number: "409100", Label: "Fournisseurs - avances et acomptes versés"},
+	{Role: LedgerAccountSales, Number: "706000", Label: "Prestations de services"},
+	{Role: LedgerAccountSalesDiscounts, Number: "709000", Label: "Rabais, remises et ristournes accordés"},
+	{Role: LedgerAccountOtherIncome, Number: "758000", Label: "Produits divers de gestion courante"},
+	{Role: LedgerAccountVATCollected, Number: "445710", Label: "TVA collectée"},
+	{Role: LedgerAccountTeacherFees, Number: "604000", Label: "Achats de prestations de services"},
+	{Role: LedgerAccountBank, Number: "512000", Label: "Banque"},
+}
+
+// LedgerAccount model - represents an account of the chart used when posting
+// entries. Families and teachers are tracked as auxiliary accounts of the
+// customers and teachers accounts.
+type LedgerAccount struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	Role      LedgerAccountRole `json:"role" gorm:"uniqueIndex;not null"`
+	Number    string            `json:"number" gorm:"not null"`
+	Label     string            `json:"label" gorm:"not null"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// LedgerEntry model - represents a balanced accounting entry (écriture). The
+// ledger is append-only: entries are numbered without gaps per year and are
+// never updated or deleted; a cancelled operation is recorded by a reversing
+// entry. Each document event is posted at most once.
+type LedgerEntry struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	Number    string        `json:"number" gorm:"uniqueIndex;not null"` // EcritureNum, e.g. EC-2026-000042
+	Journal   LedgerJournal `json:"journal" gorm:"not null;index"`
+	EntryDate time.Time     `json:"entry_date" gorm:"not null;index"`
+	Label     string        `json:"label" gorm:"not null"`
+	PieceRef  string        `json:"piece_ref" gorm:"not null"` // Supporting document, e.g. invoice number
+	PieceDate time.Time     `json:"piece_date"`
+	CreatedAt time.Time     `json:"created_at"` // ValidDate
+
+	// Source document
+	SourceType LedgerSourceType `json:"source_type" gorm:"not null;uniqueIndex:idx_ledger_source"`
+	SourceID   uint             `json:"source_id" gorm:"not null;uniqueIndex:idx_ledger_source"`
+	Event      LedgerEvent      `json:"event" gorm:"not null;uniqueIndex:idx_ledger_source"`
+
+	// Foreign Keys
+	ReversedEntryID *uint `json:"reversed_entry_id,omitempty"` // Entry cancelled by this one
+
+	// Relationships
+	Lines []LedgerLine `json:"lines,omitempty" gorm:"foreignKey:EntryID"`
+}
+
+// LedgerLine model - represents the debit or credit of one account in an entry.
+// Account numbers and labels are copied so that later changes to the chart do
+// not alter posted entries.
+type LedgerLine struct {
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	Position      int    `json:"position"`
+	AccountNumber string `json:"account_number" gorm:"not null;index"`
+	AccountLabel  string `json:"account_label"`
+	AuxNumber     string `json:"aux_number,omitempty" gorm:"index"` // Family or teacher auxiliary account
+	AuxLabel      string `json:"aux_label,omitempty"`
+	Debit         Money  `json:"debit" gorm:"embedded;embeddedPrefix:debit_"`
+	Credit        Money  `json:"credit" gorm:"embedded;embeddedPrefix:credit_"`
+
+	// Foreign Keys
+	EntryID uint `json:"entry_id" gorm:"index;not null"`
+}
+
+// LedgerEntry methods
+
+// IsBalanced vérifie que l'écriture comporte des lignes et que débits et crédits s'équilibrent
+func (e *LedgerEntry) IsBalanced() bool {
+	var debit, credit int64
+	for _, line := range e.Lines {
+		debit += line.Debit.Cents
+		credit += line.Credit.Cents
+	}
+	return len(e.Lines) > 0 && debit == credit
+}
+
+// BeforeUpdate interdit la modification d'une écriture enregistrée
+func (e *LedgerEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+// BeforeDelete interdit la suppression d'une écriture enregistrée
+func (e *LedgerEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+// BeforeUpdate interdit la modification d'une ligne d'écriture
+func (l *LedgerLine) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+// BeforeDelete interdit la suppression d'une ligne d'écriture
+func (l *LedgerLine) BeforeDelete(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+// Request/Response structures
+type LedgerAccountUpdateRequest struct {
+	Number string `json:"number" binding:"required,numeric,min=3,max=20"`
+	Label  string `json:"label" binding:"required,max=100"`
+}
+
+// LedgerTrialBalanceAccount sums the movements of an account over the period
+type LedgerTrialBalanceAccount struct {
+	AccountNumber string `json:"account_number"`
+	AccountLabel  string `json:"account_label"`
+	Debit         Money  `json:"debit"`
+	Credit        Money  `json:"credit"`
+	Balance       Money  `json:"balance"` // Debit minus credit
+}
+
+type LedgerTrialBalanceResponse struct {
+	From        time.Time                   `json:"from"`
+	To          time.Time                   `json:"to"` // Exclusive
+	Accounts    []LedgerTrialBalanceAccount `json:"accounts"`
+	TotalDebit  Money                       `json:"total_debit"`
+	TotalCredit Money                       `json:"total_credit"`
+}
+
+// LedgerStatementLine is a movement of an account statement with the running balance
+type LedgerStatementLine struct {
+	EntryID     uint          `json:"entry_id"`
+	EntryNumber string        `json:"entry_number"`
+	Journal     LedgerJournal `json:"journal"`
+	EntryDate   time.Time     `json:"entry_date"`
+	Label       string        `json:"label"`
+	PieceRef    string        `json:"piece_ref"`
+	AuxNumber   string        `json:"aux_number,omitempty"`
+	Debit       Money         `json:"debit"`
+	Credit      Money         `json:"credit"`
+	Balance     Money         `json:"balance"`
+}
+
+type LedgerStatementResponse struct {
+	AccountNumber  string                `json:"account_number"`
+	AuxNumber      string                `json:"aux_number,omitempty"`
+	From           time.Time             `json:"from"`
+	To             time.Time             `json:"to"` // Exclusive
+	OpeningBalance Money                 `json:"opening_balance"`
+	Lines          []LedgerStatementLine `json:"lines"`
+	TotalDebit     Money                 `json:"total_debit"`
+	TotalCredit    Money                 `json:"total_credit"`
+	ClosingBalance Money                 `json:"closing_balance"`
+}
@@ -23,17 +23,25 @@ type Mission struct {
 	EndDate     *time.Time     `json:"end_date"`
 	Status      MissionStatus  `json:"status" gorm:"default:'active'"`
 	Description string         `json:"description"`
+	HourlyRate  Money          `json:"hourly_rate" gorm:"embedded;embeddedPrefix:hourly_rate_"` // Overrides the offer rate when positive
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Report schedule
+	ReportFrequencyDays        int        `json:"report_frequency_days,omitempty"` // Overrides the offer frequency when set
+	NextReportDueAt            *time.Time `json:"next_report_due_at" gorm:"index"`
+	BlockPayoutOnOverdueReport bool       `json:"block_payout_on_overdue_report"`
+
 	// Foreign Keys
-	FamilleID    uint `json:"famille_id"`
-	EnseignantID uint `json:"enseignant_id"`
+	FamilleID    uint  `json:"famille_id"`
+	EnseignantID uint  `json:"enseignant_id"`
+	OfferID      *uint `json:"offer_id,omitempty"`
 
 	// Relationships
 	Famille    Famille    `json:"famille,omitempty" gorm:"foreignKey:FamilleID"`
 	Enseignant Enseignant `json:"enseignant,omitempty" gorm:"foreignKey:EnseignantID"`
+	Offer      *Offer     `json:"offer,omitempty" gorm:"foreignKey:OfferID"`
 	Courses    []Course   `json:"courses,omitempty" gorm:"foreignKey:MissionID"`
 	Reports    []Report   `json:"reports,omitempty" gorm:"foreignKey:MissionID"`
 }
@@ -59,18 +67,71 @@ func (m *Mission) ExtendMission(newEndDate time.Time) error {
 	return nil
 }
 
+// EffectiveHourlyRate retourne le taux horaire de la mission ou, à défaut, celui de l'offre liée
+func (m *Mission) EffectiveHourlyRate() Money {
+	if m.HourlyRate.IsPositive() {
+		return m.HourlyRate
+	}
+	if m.Offer != nil {
+		return m.Offer.HourlyRate
+	}
+	return Money{Currency: DefaultCurrency}
+}
+
+// EffectiveReportFrequencyDays retourne la périodicité des rapports de la mission
+// ou, à défaut, celle de l'offre liée (0 : aucun rapport périodique attendu)
+func (m *Mission) EffectiveReportFrequencyDays() int {
+	if m.ReportFrequencyDays > 0 {
+		return m.ReportFrequencyDays
+	}
+	if m.Offer != nil {
+		return m.Offer.ReportFrequencyDays
+	}
+	return 0
+}
+
+// ComputeNextReportDue calcule l'échéance du prochain rapport à partir de la
+// dernière soumission (ou du début de la mission si aucun rapport n'a été soumis)
+func (m *Mission) ComputeNextReportDue(lastSubmission *time.Time) *time.Time {
+	frequency := m.EffectiveReportFrequencyDays()
+	if frequency <= 0 || m.Status != MissionStatusActive {
+		return nil
+	}
+	base := m.StartDate
+	if lastSubmission != nil && lastSubmission.After(base) {
+		base = *lastSubmission
+	}
+	due := base.AddDate(0, 0, frequency)
+	return &due
+}
+
+// IsReportOverdue indique si l'échéance du prochain rapport est dépassée
+func (m *Mission) IsReportOverdue(now time.Time) bool {
+	return m.NextReportDueAt != nil && now.After(*m.NextReportDueAt)
+}
+
 // Request/Response structures
 type MissionCreateRequest struct {
 	StartDate    time.Time  `json:"start_date" binding:"required"`
 	EndDate      *time.Time `json:"end_date,omitempty"`
 	Description  string     `json:"description"`
 	EnseignantID uint       `json:"enseignant_id" binding:"required"`
+	OfferID      *uint      `json:"offer_id,omitempty"`
+	HourlyRate   *Money     `json:"hourly_rate,omitempty"`
+	PromoCode    string     `json:"promo_code,omitempty"` // Discount on every course of the mission
+
+	ReportFrequencyDays        int  `json:"report_frequency_days,omitempty" binding:"omitempty,min=0"`
+	BlockPayoutOnOverdueReport bool `json:"block_payout_on_overdue_report,omitempty"`
 }
 
 type MissionUpdateRequest struct {
 	EndDate     *time.Time    `json:"end_date,omitempty"`
 	Status      MissionStatus `json:"status,omitempty"`
 	Description string        `json:"description,omitempty"`
+	HourlyRate  *Money        `json:"hourly_rate,omitempty"`
+
+	ReportFrequencyDays        *int  `json:"report_frequency_days,omitempty" binding:"omitempty,min=0"`
+	BlockPayoutOnOverdueReport *bool `json:"block_payout_on_overdue_report,omitempty"`
 }
 
 type MissionFilterRequest struct {
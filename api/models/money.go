@@ -0,0 +1,222 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+const (
+	CurrencyEUR     Currency = "EUR"
+	DefaultCurrency          = CurrencyEUR
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var ErrCurrencyMismatch = errors.New("opération impossible entre deux montants de devises différentes")
+
+// Money represents an amount as an integer number of minor units (cents) of a
+// currency. It is stored in two columns, <prefix>cents and <prefix>currency,
+// when embedded in a model with gorm:"embedded;embeddedPrefix:<prefix>".
+//
+// Rounding rules: every computation producing fractions of a cent (hourly
+// proration, percentages) rounds half away from zero to the nearest cent.
+type Money struct {
+	Cents    int64    `json:"cents" gorm:"not null;default:0"`
+	Currency Currency `json:"currency" gorm:"type:varchar(3);not null;default:'EUR'"`
+}
+
+// NewMoney crée un montant en centimes dans la devise donnée
+func NewMoney(cents int64, currency Currency) Money {
+	return Money{Cents: cents, Currency: currency}
+}
+
+// EUR crée un montant en centimes d'euro
+func EUR(cents int64) Money {
+	return Money{Cents: cents, Currency: CurrencyEUR}
+}
+
+// IsZero indique si le montant est nul
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+// IsPositive indique si le montant est strictement positif
+func (m Money) IsPositive() bool {
+	return m.Cents > 0
+}
+
+// IsNegative indique si le montant est strictement négatif
+func (m Money) IsNegative() bool {
+	return m.Cents < 0
+}
+
+// currencyOr retourne la devise du montant ou, si elle n'est pas renseignée, celle fournie
+func (m Money) currencyOr(fallback Currency) Currency {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return DefaultCurrency
+}
+
+// mustMatch vérifie que deux montants sont dans la même devise ; un montant sans
+// devise (valeur zéro) est compatible avec toutes. Additionner des devises
+// différentes est une erreur de programmation : l'opération panique avec
+// ErrCurrencyMismatch plutôt que de produire un total faux.
+func (m Money) mustMatch(other Money) {
+	if m.Currency != "" && other.Currency != "" && m.Currency != other.Currency {
+		panic(fmt.Errorf("%w (%s, %s)", ErrCurrencyMismatch, m.Currency, other.Currency))
+	}
+}
+
+// Add additionne deux montants de même devise. Un montant sans devise (valeur
+// zéro) prend celle de l'autre opérande.
+func (m Money) Add(other Money) Money {
+	m.mustMatch(other)
+	return Money{Cents: m.Cents + other.Cents, Currency: m.currencyOr(other.Currency)}
+}
+
+// Sub soustrait un montant de même devise
+func (m Money) Sub(other Money) Money {
+	m.mustMatch(other)
+	return Money{Cents: m.Cents - other.Cents, Currency: m.currencyOr(other.Currency)}
+}
+
+// Neg retourne l'opposé du montant
+func (m Money) Neg() Money {
+	return Money{Cents: -m.Cents, Currency: m.Currency}
+}
+
+// ProrateMinutes applique un taux horaire à une durée en minutes :
+// taux × minutes / 60, arrondi au centime le plus proche (demi-centime arrondi
+// en s'éloignant de zéro), calculé en entiers pour éviter toute dérive
+func (m Money) ProrateMinutes(minutes int) Money {
+	return Money{Cents: divRound(m.Cents*int64(minutes), 60), Currency: m.currencyOr("")}
+}
+
+// Percent retourne le pourcentage donné du montant, arrondi au centime le plus
+// proche (demi-centime arrondi en s'éloignant de zéro)
+func (m Money) Percent(percent float64) Money {
+	return Money{Cents: int64(math.Round(float64(m.Cents) * percent / 100)), Currency: m.currencyOr("")}
+}
+
+// Allocate répartit le montant proportionnellement aux poids donnés, au centime
+// près : chaque part est arrondie par défaut et les centimes restants sont
+// attribués aux premières parts, de sorte que la somme des parts égale le montant
+func (m Money) Allocate(weights []int64) []Money {
+	if m.IsNegative() {
+		parts := m.Neg().Allocate(weights)
+		for i := range parts {
+			parts[i] = parts[i].Neg()
+		}
+		return parts
+	}
+	parts := make([]Money, len(weights))
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		for i := range parts {
+			parts[i] = NewMoney(0, m.currencyOr(""))
+		}
+		return parts
+	}
+	remaining := m.Cents
+	for i, weight := range weights {
+		parts[i] = NewMoney(m.Cents*weight/total, m.currencyOr(""))
+		remaining -= parts[i].Cents
+	}
+	for i := 0; remaining > 0 && len(parts) > 0; i = (i + 1) % len(parts) {
+		if weights[i] > 0 {
+			parts[i].Cents++
+			remaining--
+		}
+	}
+	return parts
+}
+
+// Decimal retourne le montant en unités (ex. 45.5 pour 4550 centimes), pour les
+// échanges avec des systèmes externes uniquement
+func (m Money) Decimal() float64 {
+	return float64(m.Cents) / 100
+}
+
+// String formate le montant à la française : 1 234,50 € (ou le code ISO pour les autres devises)
+func (m Money) String() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	units := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteRune('\u202f')
+		}
+		grouped.WriteRune(digit)
+	}
+	symbol := string(m.currencyOr(""))
+	if symbol == string(CurrencyEUR) {
+		symbol = "€"
+	}
+	return fmt.Sprintf("%s%s,%02d %s", sign, grouped.String(), cents%100, symbol)
+}
+
+// UnmarshalJSON lit un montant {"cents": 4550, "currency": "EUR"} ; la devise par
+// défaut est appliquée lorsqu'elle est omise
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Cents    *int64   `json:"cents"`
+		Currency Currency `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("montant invalide : attendu {\"cents\": entier, \"currency\": code ISO 4217}")
+	}
+	if raw.Cents == nil {
+		return fmt.Errorf("montant invalide : le champ cents est obligatoire")
+	}
+	if raw.Currency == "" {
+		raw.Currency = DefaultCurrency
+	}
+	raw.Currency = Currency(strings.ToUpper(string(raw.Currency)))
+	if !currencyPattern.MatchString(string(raw.Currency)) {
+		return fmt.Errorf("devise invalide : %q n'est pas un code ISO 4217", raw.Currency)
+	}
+	m.Cents, m.Currency = *raw.Cents, raw.Currency
+	return nil
+}
+
+// SumMoney additionne des montants de même devise
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	if total.Currency == "" {
+		total.Currency = DefaultCurrency
+	}
+	return total
+}
+
+// divRound divise n par d (d > 0) en arrondissant la moitié en s'éloignant de zéro
+func divRound(n, d int64) int64 {
+	q, r := n/d, n%d
+	if 2*r >= d {
+		q++
+	} else if 2*r <= -d {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoneyProrateMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    Money
+		minutes int
+		want    int64
+	}{
+		{"heure pleine", EUR(3000), 60, 3000},
+		{"demi-heure", EUR(3000), 30, 1500},
+		{"arrondi au centime inférieur", EUR(2999), 50, 2499},
+		{"demi-centime arrondi au supérieur", EUR(1), 30, 1},
+		{"durée nulle", EUR(3000), 0, 0},
+		{"taux négatif", EUR(-1), 30, -1},
+		{"taux horaire non divisible", EUR(2500), 45, 1875},
+		{"une minute", EUR(2000), 1, 33},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rate.ProrateMinutes(tt.minutes)
+			if got.Cents != tt.want || got.Currency != CurrencyEUR {
+				t.Errorf("ProrateMinutes(%d) = %v, want %d cents EUR", tt.minutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyProrateMinutesDefaultCurrency(t *testing.T) {
+	got := Money{Cents: 6000}.ProrateMinutes(90)
+	if got.Cents != 9000 || got.Currency != DefaultCurrency {
+		t.Errorf("ProrateMinutes = %+v, want 9000 cents %s", got, DefaultCurrency)
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		percent float64
+		want    int64
+	}{
+		{"dix pour cent", EUR(4550), 10, 455},
+		{"demi-centime arrondi au supérieur", EUR(5), 10, 1},
+		{"arrondi au centime inférieur", EUR(1234), 12.5, 154},
+		{"pourcentage décimal", EUR(10000), 20.6, 2060},
+		{"cent pour cent", EUR(999), 100, 999},
+		{"zéro pour cent", EUR(999), 0, 0},
+		{"montant négatif", EUR(-5), 10, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Percent(tt.percent)
+			if got.Cents != tt.want || got.Currency != CurrencyEUR {
+				t.Errorf("Percent(%v) = %v, want %d cents EUR", tt.percent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []int64
+		want    []int64
+	}{
+		{"parts égales", EUR(900), []int64{1, 1, 1}, []int64{300, 300, 300}},
+		{"centimes restants aux premières parts", EUR(1000), []int64{1, 1, 1}, []int64{334, 333, 333}},
+		{"poids proportionnels", EUR(1000), []int64{1, 3}, []int64{250, 750}},
+		{"poids nul ignoré pour le reste", EUR(100), []int64{0, 1, 1, 1}, []int64{0, 34, 33, 33}},
+		{"poids tous nuls", EUR(100), []int64{0, 0}, []int64{0, 0}},
+		{"montant négatif", EUR(-1000), []int64{1, 1, 1}, []int64{-334, -333, -333}},
+		{"aucune part", EUR(100), nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := tt.amount.Allocate(tt.weights)
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Allocate(%v) returned %d parts, want %d", tt.weights, len(parts), len(tt.want))
+			}
+			var sum int64
+			for i, part := range parts {
+				if part.Cents != tt.want[i] || part.Currency != CurrencyEUR {
+					t.Errorf("part %d = %v, want %d cents EUR", i, part, tt.want[i])
+				}
+				sum += part.Cents
+			}
+			if len(parts) > 0 && sumWeights(tt.weights) > 0 && sum != tt.amount.Cents {
+				t.Errorf("sum of parts = %d, want %d", sum, tt.amount.Cents)
+			}
+		})
+	}
+}
+
+func sumWeights(weights []int64) int64 {
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	return total
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	usd := NewMoney(500, "USD")
+	tests := []struct {
+		name      string
+		a, b      Money
+		wantAdd   Money
+		wantSub   Money
+		wantPanic bool
+	}{
+		{"même devise", EUR(1500), EUR(500), EUR(2000), EUR(1000), false},
+		{"montant sans devise à gauche", Money{}, EUR(500), EUR(500), EUR(-500), false},
+		{"montant sans devise à droite", EUR(1500), Money{Cents: 500}, EUR(2000), EUR(1000), false},
+		{"devises différentes", EUR(1500), usd, Money{}, Money{}, true},
+		{"devises différentes, montant nul", EUR(0), usd, Money{}, Money{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, op := range []struct {
+				name string
+				fn   func(Money) Money
+				want Money
+			}{{"Add", tt.a.Add, tt.wantAdd}, {"Sub", tt.a.Sub, tt.wantSub}} {
+				got, err := recoverMoney(func() Money { return op.fn(tt.b) })
+				if tt.wantPanic {
+					if !errors.Is(err, ErrCurrencyMismatch) {
+						t.Errorf("%s(%v, %v) panic = %v, want %v", op.name, tt.a, tt.b, err, ErrCurrencyMismatch)
+					}
+					continue
+				}
+				if err != nil || got != op.want {
+					t.Errorf("%s(%v, %v) = %v (panic %v), want %v", op.name, tt.a, tt.b, got, err, op.want)
+				}
+			}
+		})
+	}
+}
+
+// recoverMoney exécute l'opération et retourne l'erreur avec laquelle elle a paniqué
+func recoverMoney(fn func() Money) (got Money, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, _ = r.(error)
+		}
+	}()
+	return fn(), nil
+}
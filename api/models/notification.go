@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationType represents the kind of event a notification refers to
+type NotificationType string
+
+const (
+	NotificationRescheduleRequested     NotificationType = "reschedule_requested"
+	NotificationRescheduleAccepted      NotificationType = "reschedule_accepted"
+	NotificationRescheduleRejected      NotificationType = "reschedule_rejected"
+	NotificationRescheduleCountered     NotificationType = "reschedule_countered"
+	NotificationRescheduleExpired       NotificationType = "reschedule_expired"
+	NotificationRescheduleCancelled     NotificationType = "reschedule_cancelled"
+	NotificationCourseCancelled         NotificationType = "course_cancelled"
+	NotificationAttendanceFlagged       NotificationType = "attendance_flagged"
+	NotificationReportSubmitted         NotificationType = "report_submitted"
+	NotificationReportValidated         NotificationType = "report_validated"
+	NotificationReportRejected          NotificationType = "report_rejected"
+	NotificationReportDueSoon           NotificationType = "report_due_soon"
+	NotificationReportOverdue           NotificationType = "report_overdue"
+	NotificationPaymentCompleted        NotificationType = "payment_completed"
+	NotificationPaymentFailed           NotificationType = "payment_failed"
+	NotificationPaymentRefunded         NotificationType = "payment_refunded"
+	NotificationPayoutLocked            NotificationType = "payout_locked"
+	NotificationPayoutPaid              NotificationType = "payout_paid"
+	NotificationAdvanceRecorded         NotificationType = "advance_recorded"
+	NotificationDebitScheduled          NotificationType = "debit_scheduled"
+	NotificationTaxCertificatePublished NotificationType = "tax_certificate_published"
+	NotificationWalletLowBalance        NotificationType = "wallet_low_balance"
+	NotificationWalletHoursExpiring     NotificationType = "wallet_hours_expiring"
+	NotificationWalletHoursExpired      NotificationType = "wallet_hours_expired"
+	NotificationReferralRewarded        NotificationType = "referral_rewarded"
+	NotificationApplicationReceived     NotificationType = "offer_application_received"
+	NotificationApplicationWithdrawn    NotificationType = "offer_application_withdrawn"
+	NotificationApplicationShortlisted  NotificationType = "offer_application_shortlisted"
+	NotificationApplicationRejected     NotificationType = "offer_application_rejected"
+	NotificationApplicationAccepted     NotificationType = "offer_application_accepted"
+	NotificationMissionCreated          NotificationType = "mission_created"
+	NotificationOfferPublished          NotificationType = "offer_published"
+	NotificationOfferClosed             NotificationType = "offer_closed"
+)
+
+// Notification model - represents an in-app notification sent to a user
+type Notification struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	Type      NotificationType `json:"type" gorm:"not null"`
+	Title     string           `json:"title" gorm:"not null"`
+	Message   string           `json:"message" gorm:"type:text"`
+	Link      string           `json:"link,omitempty"` // API path of the related resource
+	ReadAt    *time.Time       `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	DeletedAt gorm.DeletedAt   `json:"-" gorm:"index"`
+
+	// Foreign Key
+	UserID uint `json:"user_id" gorm:"index;not null"`
+}
+
+// Notification methods
+func (n *Notification) MarkAsRead() {
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,81 +17,339 @@ const (
 	OfferStatusDraft  OfferStatus = "draft"
 )
 
+// OfferApplicationStatus represents the progress of a teacher's application to an offer
+type OfferApplicationStatus string
+
+const (
+	OfferApplicationPending     OfferApplicationStatus = "pending"
+	OfferApplicationShortlisted OfferApplicationStatus = "shortlisted"
+	OfferApplicationRejected    OfferApplicationStatus = "rejected"
+	OfferApplicationAccepted    OfferApplicationStatus = "accepted"
+	OfferApplicationWithdrawn   OfferApplicationStatus = "withdrawn"
+)
+
+var (
+	ErrOfferNotOpen          = errors.New("cette offre n'accepte pas de candidatures")
+	ErrAlreadyApplied        = errors.New("l'enseignant a déjà postulé à cette offre")
+	ErrApplicationNotPending = errors.New("cette candidature a déjà été retirée ou traitée")
+
+	ErrOfferNotDraft         = errors.New("seule une offre en brouillon peut être publiée")
+	ErrOfferNotPublished     = errors.New("seule une offre publiée ou programmée peut être retirée")
+	ErrOfferPublishInPast    = errors.New("la date de publication programmée doit être dans le futur")
+	ErrOfferDeadlinePassed   = errors.New("la date limite de candidature est déjà passée ou précède la publication")
+	ErrOfferStatusTransition = errors.New("le statut d'une offre se modifie par les actions de publication, de retrait et de clôture")
+)
+
+// OfferRevisionAction represents a step of the offer lifecycle
+type OfferRevisionAction string
+
+const (
+	OfferActionPublished   OfferRevisionAction = "published"
+	OfferActionScheduled   OfferRevisionAction = "scheduled"
+	OfferActionUpdated     OfferRevisionAction = "updated"
+	OfferActionUnpublished OfferRevisionAction = "unpublished"
+	OfferActionClosed      OfferRevisionAction = "closed"
+	OfferActionFilled      OfferRevisionAction = "filled"
+)
+
 // Offer model - represents job offers for teachers
 type Offer struct {
-	ID              uint           `json:"id" gorm:"primaryKey"`
-	Title           string         `json:"title" gorm:"not null"`
-	Description     string         `json:"description" gorm:"type:text"`
-	HourlyRate      float64        `json:"hourly_rate"`
-	PublicationDate time.Time      `json:"publication_date"`
-	Status          OfferStatus    `json:"status" gorm:"default:'draft'"`
-	Requirements    string         `json:"requirements" gorm:"type:text"`
-	Subject         string         `json:"subject"`
-	Level           string         `json:"level"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                  uint           `json:"id" gorm:"primaryKey"`
+	Title               string         `json:"title" gorm:"not null"`
+	Description         string         `json:"description" gorm:"type:text"`
+	HourlyRate          Money          `json:"hourly_rate" gorm:"embedded;embeddedPrefix:hourly_rate_"`
+	PublicationDate     time.Time      `json:"publication_date"`
+	Status              OfferStatus    `json:"status" gorm:"default:'draft'"`
+	Requirements        string         `json:"requirements" gorm:"type:text"`
+	Subject             string         `json:"subject"`
+	Level               string         `json:"level"`
+	ReportFrequencyDays int            `json:"report_frequency_days,omitempty"` // Days between two teacher reports, 0 when none are expected
+	PublishAt           *time.Time     `json:"publish_at" gorm:"index"`         // Scheduled publication of a draft
+	Deadline            *time.Time     `json:"deadline" gorm:"index"`           // Applications close after this date
+	ClosedAt            *time.Time     `json:"closed_at"`
+	Revision            int            `json:"revision"` // Incremented at each change of the published offer
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Foreign Keys
 	CreatedByID uint `json:"created_by_id"`
 
 	// Relationships
-	CreatedBy   Administrator `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
-	Enseignants []Enseignant  `json:"enseignants,omitempty" gorm:"many2many:enseignant_offers;"`
-	Options     []Option      `json:"options,omitempty" gorm:"foreignKey:OfferID"`
+	CreatedBy   *Administrator  `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+	Enseignants []Enseignant    `json:"enseignants,omitempty" gorm:"many2many:enseignant_offers;"`
+	Options     []Option        `json:"options,omitempty" gorm:"foreignKey:OfferID"`
+	Revisions   []OfferRevision `json:"revisions,omitempty" gorm:"foreignKey:OfferID"`
+}
+
+// OfferRevision model - represents a snapshot of a published offer at a lifecycle
+// step, so that teachers who applied can be shown the version they answered
+type OfferRevision struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	Revision     int                 `json:"revision"`
+	Action       OfferRevisionAction `json:"action" gorm:"not null"`
+	Status       OfferStatus         `json:"status"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description" gorm:"type:text"`
+	Requirements string              `json:"requirements" gorm:"type:text"`
+	Subject      string              `json:"subject"`
+	Level        string              `json:"level"`
+	HourlyRate   Money               `json:"hourly_rate" gorm:"embedded;embeddedPrefix:hourly_rate_"`
+	Deadline     *time.Time          `json:"deadline"`
+	CreatedAt    time.Time           `json:"created_at"`
+
+	// Foreign Keys
+	OfferID uint `json:"offer_id" gorm:"index;not null"`
+	ActorID uint `json:"actor_id"` // 0 for the scheduler
+}
+
+// OfferApplication model - represents a teacher's application to an offer. It is
+// stored in the enseignant_offers join table, one row per teacher and offer.
+type OfferApplication struct {
+	OfferID      uint                   `json:"offer_id" gorm:"primaryKey"`
+	EnseignantID uint                   `json:"enseignant_id" gorm:"primaryKey;column:enseignant_user_id"`
+	Status       OfferApplicationStatus `json:"status" gorm:"not null;default:'pending';index"`
+	CoverLetter  string                 `json:"cover_letter" gorm:"type:text"`
+	Availability string                 `json:"availability" gorm:"type:text"`
+	ReviewNote   string                 `json:"review_note,omitempty" gorm:"type:text"` // Message from the administrator
+	AppliedAt    time.Time              `json:"applied_at"`
+	ReviewedAt   *time.Time             `json:"reviewed_at"`
+	WithdrawnAt  *time.Time             `json:"withdrawn_at"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+
+	// Foreign Keys
+	ReviewedByID *uint `json:"reviewed_by_id,omitempty"`
+	MissionID    *uint `json:"mission_id,omitempty"` // Mission created when the application is accepted
+
+	// Relationships
+	Offer      *Offer      `json:"offer,omitempty" gorm:"foreignKey:OfferID"`
+	Enseignant *Enseignant `json:"enseignant,omitempty" gorm:"foreignKey:EnseignantID"`
+}
+
+// TableName conserve la table de liaison existante entre enseignants et offres
+func (OfferApplication) TableName() string {
+	return "enseignant_offers"
 }
 
 // Offer methods
-func (o *Offer) CreateOffer() error {
-	// Logic to create offer
+
+// CreateOffer prépare une nouvelle offre en brouillon, rattachée à l'administrateur qui la rédige
+func (o *Offer) CreateOffer(adminID uint) error {
+	o.Status = OfferStatusDraft
+	o.CreatedByID = adminID
+	return nil
+}
+
+// Publish ouvre l'offre aux candidatures
+func (o *Offer) Publish(at time.Time) error {
+	if o.Status != OfferStatusDraft {
+		return ErrOfferNotDraft
+	}
+	if o.Deadline != nil && !o.Deadline.After(at) {
+		return ErrOfferDeadlinePassed
+	}
 	o.Status = OfferStatusOpen
-	o.PublicationDate = time.Now()
+	o.PublicationDate = at
+	o.PublishAt = nil
+	o.ClosedAt = nil
+	return nil
+}
+
+// SchedulePublication programme la publication d'un brouillon
+func (o *Offer) SchedulePublication(publishAt, now time.Time) error {
+	if o.Status != OfferStatusDraft {
+		return ErrOfferNotDraft
+	}
+	if !publishAt.After(now) {
+		return ErrOfferPublishInPast
+	}
+	if o.Deadline != nil && !o.Deadline.After(publishAt) {
+		return ErrOfferDeadlinePassed
+	}
+	o.PublishAt = &publishAt
 	return nil
 }
 
-func (o *Offer) ApplyForOffer() error {
-	// Logic for teachers to apply for offer
+// Unpublish repasse une offre ouverte en brouillon, ou annule une publication programmée
+func (o *Offer) Unpublish() error {
+	switch {
+	case o.Status == OfferStatusOpen:
+		o.Status = OfferStatusDraft
+	case o.Status == OfferStatusDraft && o.PublishAt != nil:
+		o.PublishAt = nil
+	default:
+		return ErrOfferNotPublished
+	}
 	return nil
 }
 
-func (o *Offer) CloseOffer() error {
-	// Logic to close offer
-	o.Status = OfferStatusClosed
+// IsPastDeadline indique si la date limite de candidature est dépassée
+func (o *Offer) IsPastDeadline(at time.Time) bool {
+	return o.Deadline != nil && at.After(*o.Deadline)
+}
+
+// ApplyForOffer prépare la candidature d'un enseignant à une offre ouverte
+func (o *Offer) ApplyForOffer(enseignantID uint, req OfferApplicationRequest, at time.Time) (*OfferApplication, error) {
+	if o.Status != OfferStatusOpen || o.IsPastDeadline(at) {
+		return nil, ErrOfferNotOpen
+	}
+	return &OfferApplication{
+		OfferID:      o.ID,
+		EnseignantID: enseignantID,
+		Status:       OfferApplicationPending,
+		CoverLetter:  req.CoverLetter,
+		Availability: req.Availability,
+		AppliedAt:    at,
+	}, nil
+}
+
+// CloseOffer clôt l'offre, fermée (status closed) ou pourvue (status filled)
+func (o *Offer) CloseOffer(status OfferStatus, at time.Time) error {
+	o.Status = status
+	o.PublishAt = nil
+	o.ClosedAt = &at
+	return nil
+}
+
+// OfferApplication methods
+
+// IsOpen indique si la candidature attend encore une décision
+func (a *OfferApplication) IsOpen() bool {
+	return a.Status == OfferApplicationPending || a.Status == OfferApplicationShortlisted
+}
+
+// Withdraw retire la candidature à la demande de l'enseignant
+func (a *OfferApplication) Withdraw(at time.Time) error {
+	if !a.IsOpen() {
+		return ErrApplicationNotPending
+	}
+	a.Status = OfferApplicationWithdrawn
+	a.WithdrawnAt = &at
+	return nil
+}
+
+// Review enregistre la décision d'un administrateur : présélection, refus ou acceptation
+func (a *OfferApplication) Review(status OfferApplicationStatus, note string, adminID uint, at time.Time) error {
+	if !a.IsOpen() || (status == OfferApplicationShortlisted && a.Status == OfferApplicationShortlisted) {
+		return ErrApplicationNotPending
+	}
+	a.Status = status
+	a.ReviewNote = note
+	a.ReviewedAt = &at
+	if adminID != 0 {
+		a.ReviewedByID = &adminID
+	}
 	return nil
 }
 
 // Request/Response structures
 type OfferCreateRequest struct {
-	Title        string  `json:"title" binding:"required"`
-	Description  string  `json:"description" binding:"required"`
-	HourlyRate   float64 `json:"hourly_rate" binding:"required,min=0"`
-	Requirements string  `json:"requirements"`
-	Subject      string  `json:"subject" binding:"required"`
-	Level        string  `json:"level" binding:"required"`
+	Title        string `json:"title" binding:"required"`
+	Description  string `json:"description" binding:"required"`
+	HourlyRate   Money  `json:"hourly_rate"`
+	Requirements string `json:"requirements"`
+	Subject      string `json:"subject" binding:"required"`
+	Level        string `json:"level" binding:"required"`
+
+	ReportFrequencyDays int `json:"report_frequency_days,omitempty" binding:"omitempty,min=0"`
+
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"` // Schedules the publication of the draft
 }
 
 type OfferUpdateRequest struct {
 	Title        string      `json:"title,omitempty"`
 	Description  string      `json:"description,omitempty"`
-	HourlyRate   float64     `json:"hourly_rate,omitempty"`
+	HourlyRate   *Money      `json:"hourly_rate,omitempty"`
 	Status       OfferStatus `json:"status,omitempty"`
 	Requirements string      `json:"requirements,omitempty"`
 	Subject      string      `json:"subject,omitempty"`
 	Level        string      `json:"level,omitempty"`
+
+	ReportFrequencyDays *int `json:"report_frequency_days,omitempty" binding:"omitempty,min=0"`
+
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+type OfferScheduleRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
 }
 
 type OfferFilterRequest struct {
 	Status   OfferStatus `json:"status,omitempty"`
 	Subject  string      `json:"subject,omitempty"`
 	Level    string      `json:"level,omitempty"`
-	MinRate  *float64    `json:"min_rate,omitempty"`
-	MaxRate  *float64    `json:"max_rate,omitempty"`
+	MinRate  *int64      `json:"min_rate_cents,omitempty"`
+	MaxRate  *int64      `json:"max_rate_cents,omitempty"`
 	DateFrom *time.Time  `json:"date_from,omitempty"`
 	DateTo   *time.Time  `json:"date_to,omitempty"`
 }
 
+// OfferSearchRequest combines a full-text query with the offer filters. Without a
+// status, only open offers are searched.
+type OfferSearchRequest struct {
+	OfferFilterRequest
+	Query  string `json:"query,omitempty"`
+	Limit  int    `json:"limit,omitempty"` // 20 by default, 100 at most
+	Offset int    `json:"offset,omitempty"`
+}
+
+type OfferSearchResult struct {
+	Offer
+	Rank float64 `json:"rank"` // Relevance to the query, higher is better; 0 without query
+}
+
+type OfferFacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type OfferRateRangeFacet struct {
+	Label    string `json:"label"`
+	MinCents *int64 `json:"min_cents,omitempty"` // Inclusive
+	MaxCents *int64 `json:"max_cents,omitempty"` // Exclusive
+	Count    int64  `json:"count"`
+}
+
+// OfferSearchFacets counts the matching offers per value of each facet, ignoring the
+// filter on the facet itself so that the other values stay selectable
+type OfferSearchFacets struct {
+	Subjects   []OfferFacetCount     `json:"subjects"`
+	Levels     []OfferFacetCount     `json:"levels"`
+	RateRanges []OfferRateRangeFacet `json:"rate_ranges"`
+}
+
+type OfferSearchResponse struct {
+	Total   int64               `json:"total"`
+	Results []OfferSearchResult `json:"results"`
+	Facets  OfferSearchFacets   `json:"facets"`
+}
+
 type OfferApplicationRequest struct {
-	OfferID      uint   `json:"offer_id" binding:"required"`
-	CoverLetter  string `json:"cover_letter"`
-	Availability string `json:"availability"`
+	OfferID      uint   `json:"offer_id,omitempty"` // Taken from the URL
+	CoverLetter  string `json:"cover_letter" binding:"max=5000"`
+	Availability string `json:"availability" binding:"max=1000"`
+}
+
+type OfferApplicationReviewRequest struct {
+	Note string `json:"note,omitempty" binding:"max=2000"`
+}
+
+// OfferApplicationAcceptRequest accepts an application and optionally creates the
+// mission of the selected teacher for the family
+type OfferApplicationAcceptRequest struct {
+	Note          string     `json:"note,omitempty" binding:"max=2000"`
+	CreateMission bool       `json:"create_mission"`
+	FamilleID     uint       `json:"famille_id,omitempty"` // Required to create the mission
+	StartDate     *time.Time `json:"start_date,omitempty"` // Now by default
+	EndDate       *time.Time `json:"end_date,omitempty"`
+	HourlyRate    *Money     `json:"hourly_rate,omitempty"` // Negotiated rate, the offer rate by default
+	Description   string     `json:"description,omitempty"`
+}
+
+type OfferApplicationAcceptResponse struct {
+	Application OfferApplication `json:"application"`
+	Offer       Offer            `json:"offer"`
+	Mission     *Mission         `json:"mission,omitempty"`
 }
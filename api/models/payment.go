@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,10 +12,12 @@ import (
 type PaymentStatus string
 
 const (
-	PaymentStatusPending   PaymentStatus = "pending"
-	PaymentStatusCompleted PaymentStatus = "completed"
-	PaymentStatusFailed    PaymentStatus = "failed"
-	PaymentStatusRefunded  PaymentStatus = "refunded"
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusProcessing PaymentStatus = "processing" // Claimed while the provider captures it
+	PaymentStatusCompleted  PaymentStatus = "completed"
+	PaymentStatusFailed     PaymentStatus = "failed"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusCancelled  PaymentStatus = "cancelled" // Never collected, e.g. a course payment taken over by an invoice
 )
 
 // PaymentType represents the type of payment
@@ -24,41 +28,184 @@ const (
 	PaymentTypeMission PaymentType = "mission"
 	PaymentTypeAdvance PaymentType = "advance"
 	PaymentTypeRefund  PaymentType = "refund"
+	PaymentTypeInvoice PaymentType = "invoice" // Settlement of an issued invoice, e.g. by SEPA direct debit
+
+	PaymentTypeCancellationFee PaymentType = "cancellation_fee"
+	PaymentTypeCredit          PaymentType = "credit"
+	PaymentTypePenalty         PaymentType = "penalty"
+	PaymentTypeHourPackage     PaymentType = "hour_package" // Purchase of prepaid hours
+)
+
+var (
+	ErrPaymentNotProcessable = errors.New("seul un paiement en attente ou échoué peut être traité")
+	ErrPaymentNotPayable     = errors.New("ce type de paiement ne peut pas être réglé auprès du prestataire")
+	ErrPaymentNotRefundable  = errors.New("seul un paiement encaissé peut être remboursé")
+	ErrInvalidRefundAmount   = errors.New("le montant à rembourser doit être strictement positif et dans la devise du paiement")
+	ErrRefundExceedsPayment  = errors.New("le montant à rembourser dépasse le montant encaissé restant")
 )
 
 // Payment model - represents payments in the system
 type Payment struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Amount      float64        `json:"amount" gorm:"not null"`
-	PaymentDate time.Time      `json:"payment_date"`
-	Status      PaymentStatus  `json:"status" gorm:"default:'pending'"`
-	Type        PaymentType    `json:"type" gorm:"not null"`
-	Description string         `json:"description"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	Amount      Money         `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	PaymentDate time.Time     `json:"payment_date"`
+	Status      PaymentStatus `json:"status" gorm:"default:'pending'"`
+	Type        PaymentType   `json:"type" gorm:"not null"`
+	Description string        `json:"description"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// Payment provider tracking
+	Provider         string     `json:"provider,omitempty"`
+	ProviderIntentID string     `json:"provider_intent_id,omitempty" gorm:"index"`
+	ProviderRefundID string     `json:"provider_refund_id,omitempty"`
+	FailureReason    string     `json:"failure_reason,omitempty"`
+	RefundedAt       *time.Time `json:"refunded_at"`
+
+	// Refunds: the amount already refunded on a captured payment, and on refund
+	// payments the reason given by the admin
+	RefundedAmount Money  `json:"refunded_amount" gorm:"embedded;embeddedPrefix:refunded_amount_"`
+	RefundReason   string `json:"refund_reason,omitempty"`
+
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Foreign Keys
-	UserID   uint `json:"user_id"`
-	CourseID uint `json:"course_id,omitempty"`
+	UserID    uint  `json:"user_id"`
+	CourseID  *uint `json:"course_id,omitempty"`
+	MissionID *uint `json:"mission_id,omitempty" gorm:"index"` // Mission an advance is reserved for
+	InvoiceID *uint `json:"invoice_id,omitempty" gorm:"index"` // Invoice settled by this payment, or credit note of a refund
+
+	// Refund payments point to the captured payment they refund
+	RefundedPaymentID *uint `json:"refunded_payment_id,omitempty" gorm:"index"`
+
+	// SEPA direct debit tracking
+	MandateID    *uint `json:"mandate_id,omitempty" gorm:"index"`
+	DebitBatchID *uint `json:"debit_batch_id,omitempty" gorm:"index"`
 
 	// Relationships
-	User   User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Course Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+	User   User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Course *Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
 }
 
 // Payment methods
+
+// CanProcess indique si le paiement peut être (de nouveau) présenté au prestataire
+func (p *Payment) CanProcess() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusFailed
+}
+
+// awaitsCapture indique si le paiement attend encore la réponse du prestataire
+func (p *Payment) awaitsCapture() bool {
+	return p.CanProcess() || p.Status == PaymentStatusProcessing
+}
+
+// Claim réserve le paiement avant de le présenter au prestataire : tant qu'il est
+// en cours de traitement, aucune autre demande ne peut le présenter une seconde fois
+func (p *Payment) Claim() error {
+	if !p.CanProcess() {
+		return ErrPaymentNotProcessable
+	}
+	p.Status = PaymentStatusProcessing
+	return nil
+}
+
+// IsPayable indique si le paiement peut être réglé auprès du prestataire : cours,
+// facture, acompte, forfait d'heures ou frais d'annulation. Les pénalités, déduites
+// des relevés des enseignants, et les autres écritures internes ne sont jamais
+// présentées au prestataire.
+func (p *Payment) IsPayable() bool {
+	switch p.Type {
+	case PaymentTypeCourse, PaymentTypeInvoice, PaymentTypeAdvance, PaymentTypeHourPackage, PaymentTypeCancellationFee:
+		return true
+	}
+	return false
+}
+
+// ProcessPayment marque le paiement comme encaissé une fois la capture confirmée par le prestataire
 func (p *Payment) ProcessPayment() error {
-	// Logic to process payment will be implemented in controllers
+	if !p.awaitsCapture() {
+		return ErrPaymentNotProcessable
+	}
 	p.Status = PaymentStatusCompleted
 	p.PaymentDate = time.Now()
+	p.FailureReason = ""
 	return nil
 }
 
+// FailPayment enregistre le refus du prestataire ; le paiement pourra être représenté
+func (p *Payment) FailPayment(reason string) error {
+	if !p.awaitsCapture() {
+		return ErrPaymentNotProcessable
+	}
+	p.Status = PaymentStatusFailed
+	p.FailureReason = reason
+	return nil
+}
+
+// Cancel annule un paiement en attente ou échoué qui ne sera jamais encaissé, par
+// exemple le paiement d'un cours repris sur une facture émise
+func (p *Payment) Cancel(reason string) error {
+	if !p.CanProcess() {
+		return ErrPaymentNotProcessable
+	}
+	p.Status = PaymentStatusCancelled
+	p.FailureReason = reason
+	return nil
+}
+
+// ReturnDebit enregistre le rejet d'un prélèvement par la banque du débiteur, ou
+// son retour après encaissement (jusqu'à treize mois pour un remboursement contesté)
+func (p *Payment) ReturnDebit(reason string) error {
+	if p.Status != PaymentStatusPending && p.Status != PaymentStatusCompleted {
+		return ErrPaymentNotProcessable
+	}
+	p.Status = PaymentStatusFailed
+	p.FailureReason = reason
+	return nil
+}
+
+// RefundableAmount retourne la part du montant encaissé qui n'a pas encore été remboursée
+func (p *Payment) RefundableAmount() Money {
+	if p.Status != PaymentStatusCompleted {
+		return NewMoney(0, p.Amount.Currency)
+	}
+	return p.Amount.Sub(p.RefundedAmount)
+}
+
+// CheckRefund vérifie qu'un remboursement du montant indiqué est possible
+func (p *Payment) CheckRefund(amount Money) error {
+	if p.Status != PaymentStatusCompleted {
+		return ErrPaymentNotRefundable
+	}
+	if !amount.IsPositive() || amount.Currency != p.Amount.Currency {
+		return ErrInvalidRefundAmount
+	}
+	if amount.Cents > p.RefundableAmount().Cents {
+		return ErrRefundExceedsPayment
+	}
+	return nil
+}
+
+// RecordRefund enregistre un remboursement total ou partiel ; le paiement passe à
+// l'état remboursé lorsque la totalité du montant encaissé a été remboursée
+func (p *Payment) RecordRefund(amount Money, refundID string, refundedAt time.Time) error {
+	if err := p.CheckRefund(amount); err != nil {
+		return err
+	}
+	p.RefundedAmount = p.RefundedAmount.Add(amount)
+	p.ProviderRefundID = refundID
+	p.RefundedAt = &refundedAt
+	if p.RefundedAmount.Cents == p.Amount.Cents {
+		p.Status = PaymentStatusRefunded
+	}
+	return nil
+}
+
+// GenerateInvoice retourne un reçu de paiement. Les factures légales, numérotées
+// sans rupture par série et par année, sont gérées par le modèle Invoice.
 func (p *Payment) GenerateInvoice() (map[string]interface{}, error) {
-	// Logic to generate invoice
 	return map[string]interface{}{
-		"invoice_number": "INV-" + time.Now().Format("20060102") + "-" + string(rune(p.ID)),
+		"invoice_number": fmt.Sprintf("REC-%s-%06d", p.PaymentDate.Format("20060102"), p.ID),
 		"amount":         p.Amount,
 		"date":           p.PaymentDate,
 		"description":    p.Description,
@@ -72,10 +219,38 @@ func (p *Payment) ViewHistory() ([]Payment, error) {
 
 // Request/Response structures
 type PaymentCreateRequest struct {
-	Amount      float64     `json:"amount" binding:"required,min=0"`
+	Amount      Money       `json:"amount"`
 	Type        PaymentType `json:"type" binding:"required"`
 	Description string      `json:"description"`
-	CourseID    uint        `json:"course_id,omitempty"`
+	CourseID    *uint       `json:"course_id,omitempty"`
+	MissionID   *uint       `json:"mission_id,omitempty"`
+	UserID      uint        `json:"user_id,omitempty"` // Admin only, defaults to the current user
+}
+
+type PaymentRefundRequest struct {
+	Amount *Money `json:"amount,omitempty"` // Remaining refundable amount when omitted
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type PaymentProcessRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required"` // Provider token of the card or account to charge
+}
+
+// PaymentWebhookEventType represents the kind of event notified by the payment provider
+type PaymentWebhookEventType string
+
+const (
+	PaymentEventSucceeded PaymentWebhookEventType = "payment.succeeded"
+	PaymentEventFailed    PaymentWebhookEventType = "payment.failed"
+	PaymentEventRefunded  PaymentWebhookEventType = "payment.refunded"
+)
+
+type PaymentWebhookEvent struct {
+	Type          PaymentWebhookEventType `json:"type" binding:"required"`
+	IntentID      string                  `json:"intent_id" binding:"required"`
+	RefundID      string                  `json:"refund_id,omitempty"`
+	Amount        *Money                  `json:"amount,omitempty"` // Refunded amount, the remaining captured amount when omitted
+	FailureReason string                  `json:"failure_reason,omitempty"`
 }
 
 type PaymentUpdateRequest struct {
@@ -88,15 +263,15 @@ type PaymentFilterRequest struct {
 	Type      PaymentType   `json:"type,omitempty"`
 	DateFrom  *time.Time    `json:"date_from,omitempty"`
 	DateTo    *time.Time    `json:"date_to,omitempty"`
-	MinAmount *float64      `json:"min_amount,omitempty"`
-	MaxAmount *float64      `json:"max_amount,omitempty"`
+	MinAmount *int64        `json:"min_amount_cents,omitempty"`
+	MaxAmount *int64        `json:"max_amount_cents,omitempty"`
 }
 
 type PaymentStatsResponse struct {
-	TotalAmount     float64 `json:"total_amount"`
-	CompletedAmount float64 `json:"completed_amount"`
-	PendingAmount   float64 `json:"pending_amount"`
-	TotalCount      int64   `json:"total_count"`
-	CompletedCount  int64   `json:"completed_count"`
-	PendingCount    int64   `json:"pending_count"`
+	TotalAmount     Money `json:"total_amount"`
+	CompletedAmount Money `json:"completed_amount"`
+	PendingAmount   Money `json:"pending_amount"`
+	TotalCount      int64 `json:"total_count"`
+	CompletedCount  int64 `json:"completed_count"`
+	PendingCount    int64 `json:"pending_count"`
 }
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PayoutStatus represents the status of a teacher payout statement
+type PayoutStatus string
+
+const (
+	PayoutStatusLocked PayoutStatus = "locked"
+	PayoutStatusPaid   PayoutStatus = "paid"
+)
+
+// PayoutLineKind distinguishes earned courses from deducted advances and penalties
+type PayoutLineKind string
+
+const (
+	PayoutLineCourse  PayoutLineKind = "course"
+	PayoutLineAdvance PayoutLineKind = "advance"
+	PayoutLinePenalty PayoutLineKind = "penalty" // Cancellation penalty charged to the teacher
+)
+
+var (
+	ErrPayoutAlreadyPaid = errors.New("ce relevé a déjà été payé")
+)
+
+// Payout model - represents the statement of what is owed to a teacher for a
+// period: approved courses at the mission rate, minus the advances already paid
+// and the pending cancellation penalties.
+// Statements are created locked; their content no longer changes afterwards.
+type Payout struct {
+	ID               uint         `json:"id" gorm:"primaryKey"`
+	Status           PayoutStatus `json:"status" gorm:"not null;default:'locked';index"`
+	PeriodStart      time.Time    `json:"period_start" gorm:"not null;index"`
+	PeriodEnd        time.Time    `json:"period_end" gorm:"not null"` // Exclusive
+	Currency         Currency     `json:"currency" gorm:"not null;default:'EUR'"`
+	CourseMinutes    int          `json:"course_minutes"`
+	GrossAmount      Money        `json:"gross_amount" gorm:"embedded;embeddedPrefix:gross_amount_"`
+	AdvancesAmount   Money        `json:"advances_amount" gorm:"embedded;embeddedPrefix:advances_amount_"`
+	PenaltiesAmount  Money        `json:"penalties_amount" gorm:"embedded;embeddedPrefix:penalties_amount_"`
+	NetAmount        Money        `json:"net_amount" gorm:"embedded;embeddedPrefix:net_amount_"`
+	LockedAt         *time.Time   `json:"locked_at"`
+	PaidAt           *time.Time   `json:"paid_at"`
+	PaymentReference string       `json:"payment_reference,omitempty"`              // Bank transfer reference
+	TransferBatchID  *uint        `json:"transfer_batch_id,omitempty" gorm:"index"` // SEPA export covering this payout
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+
+	// Foreign Keys
+	EnseignantID uint  `json:"enseignant_id" gorm:"index;not null"`
+	LockedByID   *uint `json:"locked_by_id,omitempty"`
+	PaidByID     *uint `json:"paid_by_id,omitempty"`
+
+	// Relationships
+	Lines []PayoutLine `json:"lines,omitempty" gorm:"foreignKey:PayoutID"`
+}
+
+// PayoutLine model - represents a course earned, or an advance or a penalty
+// deducted, on a payout statement. A course appears on at most one statement; an advance may
+// be deducted in several parts, each recorded as an AdvanceAllocation.
+type PayoutLine struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Position    int            `json:"position"`
+	Kind        PayoutLineKind `json:"kind" gorm:"not null"`
+	Description string         `json:"description" gorm:"not null"`
+	Minutes     int            `json:"minutes,omitempty"` // Approved duration for course lines
+	HourlyRate  Money          `json:"hourly_rate" gorm:"embedded;embeddedPrefix:hourly_rate_"`
+	Amount      Money          `json:"amount" gorm:"embedded;embeddedPrefix:amount_"` // Negative for advances and penalties
+
+	// Foreign Keys
+	PayoutID  uint  `json:"payout_id" gorm:"index;not null"`
+	CourseID  *uint `json:"course_id,omitempty" gorm:"uniqueIndex"`
+	MissionID *uint `json:"mission_id,omitempty" gorm:"index"`
+	PaymentID *uint `json:"payment_id,omitempty" gorm:"index"` // Advance deducted, possibly in part, or penalty
+}
+
+// Payout methods
+
+// IsPaid indique si le relevé a été payé
+func (p *Payout) IsPaid() bool {
+	return p.Status == PayoutStatusPaid
+}
+
+// ComputeTotals recalcule les montants du relevé à partir de ses lignes
+func (p *Payout) ComputeTotals() {
+	p.CourseMinutes = 0
+	p.GrossAmount, p.AdvancesAmount, p.PenaltiesAmount = NewMoney(0, p.Currency), NewMoney(0, p.Currency), NewMoney(0, p.Currency)
+	for _, line := range p.Lines {
+		switch line.Kind {
+		case PayoutLineCourse:
+			p.CourseMinutes += line.Minutes
+			p.GrossAmount = p.GrossAmount.Add(line.Amount)
+		case PayoutLineAdvance:
+			p.AdvancesAmount = p.AdvancesAmount.Sub(line.Amount)
+		case PayoutLinePenalty:
+			p.PenaltiesAmount = p.PenaltiesAmount.Sub(line.Amount)
+		}
+	}
+	p.NetAmount = p.GrossAmount.Sub(p.AdvancesAmount).Sub(p.PenaltiesAmount)
+}
+
+// MarkPaid enregistre le virement du relevé
+func (p *Payout) MarkPaid(reference string, paidAt time.Time, adminID uint) error {
+	if p.IsPaid() {
+		return ErrPayoutAlreadyPaid
+	}
+	p.Status = PayoutStatusPaid
+	p.PaymentReference = reference
+	p.PaidAt = &paidAt
+	p.PaidByID = &adminID
+	return nil
+}
+
+// Request/Response structures
+type PayoutPeriodRequest struct {
+	Month        string `json:"month,omitempty"`         // YYYY-MM, or period_start/period_end
+	PeriodStart  string `json:"period_start,omitempty"`  // YYYY-MM-DD
+	PeriodEnd    string `json:"period_end,omitempty"`    // YYYY-MM-DD, inclusive
+	EnseignantID *uint  `json:"enseignant_id,omitempty"` // All teachers when omitted
+}
+
+type PayoutPayRequest struct {
+	PaymentReference string `json:"payment_reference,omitempty"`
+}
+
+// PayoutSkippedCourse explains why an approved course was left out of a statement
+type PayoutSkippedCourse struct {
+	CourseID     uint   `json:"course_id"`
+	MissionID    uint   `json:"mission_id,omitempty"`
+	EnseignantID uint   `json:"enseignant_id"`
+	Reason       string `json:"reason"`
+}
+
+type PayoutPreviewResponse struct {
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	Payouts     []Payout              `json:"payouts"`
+	Skipped     []PayoutSkippedCourse `json:"skipped"`
+}
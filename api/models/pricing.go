@@ -0,0 +1,182 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PricingRuleKind represents the effect of a pricing rule on the hourly rate
+type PricingRuleKind string
+
+const (
+	PricingRuleBaseRate  PricingRuleKind = "base_rate" // Sets the base hourly rate
+	PricingRuleSurcharge PricingRuleKind = "surcharge" // Increases the rate
+	PricingRuleDiscount  PricingRuleKind = "discount"  // Decreases the rate
+)
+
+// PricingCondition represents the circumstance in which a pricing rule applies
+type PricingCondition string
+
+const (
+	PricingConditionAlways      PricingCondition = ""
+	PricingConditionEvening     PricingCondition = "evening"      // Course starting at or after Threshold o'clock
+	PricingConditionWeekend     PricingCondition = "weekend"      // Course on Saturday or Sunday
+	PricingConditionDistance    PricingCondition = "distance"     // Teacher travelling more than Threshold km
+	PricingConditionLongMission PricingCondition = "long_mission" // Mission lasting at least Threshold weeks
+	PricingConditionSiblings    PricingCondition = "siblings"     // Family with at least Threshold other active missions
+)
+
+var ErrInvalidPricingRule = errors.New("règle de tarification invalide : un taux de base exige un taux horaire, une majoration ou une remise un montant horaire ou un pourcentage")
+
+// PricingRule model - represents a rule of the pricing engine. The base rate is
+// given by the first matching base rate rule, by increasing priority; matching
+// surcharges and discounts are then applied in the same order to the current rate.
+type PricingRule struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	Name           string           `json:"name" gorm:"not null"`
+	Kind           PricingRuleKind  `json:"kind" gorm:"not null"`
+	Priority       int              `json:"priority" gorm:"index"` // Lower priorities are evaluated first
+	Subject        string           `json:"subject,omitempty"`     // Empty = any subject
+	Level          string           `json:"level,omitempty"`       // Empty = any level
+	Condition      PricingCondition `json:"condition,omitempty"`
+	Threshold      float64          `json:"threshold"`                                 // Hour, km, weeks or missions depending on the condition
+	Rate           Money            `json:"rate" gorm:"embedded;embeddedPrefix:rate_"` // Base rate, or fixed amount per hour
+	Percent        float64          `json:"percent"`                                   // Surcharge or discount in % of the current rate, instead of Rate
+	StopProcessing bool             `json:"stop_processing"`                           // No further surcharge or discount once applied
+	IsActive       bool             `json:"is_active" gorm:"default:true"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+// PricingContext describes the course or mission being priced
+type PricingContext struct {
+	Subject         string
+	Level           string
+	ScheduledTime   *time.Time // Local time of the course
+	DistanceKm      *float64   // Between the teacher and the course address
+	MissionWeeks    *float64   // Nil for open-ended missions
+	SiblingMissions int        // Other active missions of the family
+}
+
+// PricingRule methods
+
+// Matches indique si la règle s'applique au cours ou à la mission décrits
+func (r *PricingRule) Matches(ctx PricingContext) bool {
+	if !r.IsActive {
+		return false
+	}
+	if r.Subject != "" && !strings.EqualFold(r.Subject, ctx.Subject) {
+		return false
+	}
+	if r.Level != "" && !strings.EqualFold(r.Level, ctx.Level) {
+		return false
+	}
+	switch r.Condition {
+	case PricingConditionEvening:
+		if ctx.ScheduledTime == nil {
+			return false
+		}
+		return float64(ctx.ScheduledTime.Hour())+float64(ctx.ScheduledTime.Minute())/60 >= r.Threshold
+	case PricingConditionWeekend:
+		if ctx.ScheduledTime == nil {
+			return false
+		}
+		day := ctx.ScheduledTime.Weekday()
+		return day == time.Saturday || day == time.Sunday
+	case PricingConditionDistance:
+		return ctx.DistanceKm != nil && *ctx.DistanceKm > r.Threshold
+	case PricingConditionLongMission:
+		return ctx.MissionWeeks != nil && *ctx.MissionWeeks >= r.Threshold
+	case PricingConditionSiblings:
+		return ctx.SiblingMissions > 0 && float64(ctx.SiblingMissions) >= r.Threshold
+	}
+	return true
+}
+
+// Adjustment retourne la variation du taux horaire produite par une majoration
+// (positive) ou une remise (négative)
+func (r *PricingRule) Adjustment(rate Money) Money {
+	delta := NewMoney(r.Rate.Cents, rate.Currency)
+	if r.Percent > 0 {
+		delta = rate.Percent(r.Percent)
+	}
+	if r.Kind == PricingRuleDiscount {
+		return delta.Neg()
+	}
+	return delta
+}
+
+// Request/Response structures
+type PricingRuleRequest struct {
+	Name           string           `json:"name" binding:"required,max=100"`
+	Kind           PricingRuleKind  `json:"kind" binding:"required,oneof=base_rate surcharge discount"`
+	Priority       int              `json:"priority"`
+	Subject        string           `json:"subject,omitempty"`
+	Level          string           `json:"level,omitempty"`
+	Condition      PricingCondition `json:"condition,omitempty" binding:"omitempty,oneof=evening weekend distance long_mission siblings"`
+	Threshold      float64          `json:"threshold" binding:"min=0"`
+	Rate           Money            `json:"rate"`
+	Percent        float64          `json:"percent" binding:"min=0,max=100"`
+	StopProcessing bool             `json:"stop_processing"`
+	IsActive       *bool            `json:"is_active,omitempty"`
+}
+
+// Validate vérifie la cohérence du montant avec le type de règle
+func (r *PricingRuleRequest) Validate() error {
+	if r.Rate.IsNegative() {
+		return ErrInvalidPricingRule
+	}
+	if r.Kind == PricingRuleBaseRate {
+		if !r.Rate.IsPositive() || r.Percent > 0 {
+			return ErrInvalidPricingRule
+		}
+		return nil
+	}
+	if r.Rate.IsPositive() == (r.Percent > 0) {
+		return ErrInvalidPricingRule
+	}
+	return nil
+}
+
+// PricingQuoteRequest describes a mission or course to price before creating it.
+// Missing values are taken from the mission, then from the offer.
+type PricingQuoteRequest struct {
+	MissionID     *uint      `json:"mission_id,omitempty"` // Course of an existing mission
+	OfferID       *uint      `json:"offer_id,omitempty"`
+	FamilleID     uint       `json:"famille_id,omitempty"` // Admin only, defaults to the current family
+	EnseignantID  *uint      `json:"enseignant_id,omitempty"`
+	Subject       string     `json:"subject,omitempty"`
+	Level         string     `json:"level,omitempty"`
+	HourlyRate    *Money     `json:"hourly_rate,omitempty"` // Rate negotiated for the mission
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
+	ScheduledTime *time.Time `json:"scheduled_time,omitempty"`
+	Duration      int        `json:"duration,omitempty" binding:"omitempty,min=30,max=480"` // Minutes, 1 hour by default
+	AddressID     *uint      `json:"address_id,omitempty"`
+}
+
+// PricingQuoteLine is a step of the rate computation
+type PricingQuoteLine struct {
+	RuleID *uint           `json:"rule_id,omitempty"` // Nil for the offer or negotiated rate
+	Label  string          `json:"label"`
+	Kind   PricingRuleKind `json:"kind"`
+	Amount Money           `json:"amount"` // Change of the hourly rate
+	Rate   Money           `json:"rate"`   // Hourly rate after this step
+}
+
+type PricingQuoteResponse struct {
+	Subject         string             `json:"subject,omitempty"`
+	Level           string             `json:"level,omitempty"`
+	DistanceKm      *float64           `json:"distance_km,omitempty"`
+	MissionWeeks    *float64           `json:"mission_weeks,omitempty"`
+	SiblingMissions int                `json:"sibling_missions"`
+	BaseRate        Money              `json:"base_rate"`
+	HourlyRate      Money              `json:"hourly_rate"`
+	Duration        int                `json:"duration"`
+	Total           Money              `json:"total"` // Price of a course of this duration
+	Lines           []PricingQuoteLine `json:"lines"`
+}
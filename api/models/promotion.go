@@ -0,0 +1,288 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromoCodeKind represents how a promo code reduces the price
+type PromoCodeKind string
+
+const (
+	PromoCodePercentage PromoCodeKind = "percentage" // Percent off every course billed
+	PromoCodeFixed      PromoCodeKind = "fixed"      // Amount off, consumed course after course
+)
+
+// PromoRedemptionStatus represents the status of a promo code applied to a mission or an invoice
+type PromoRedemptionStatus string
+
+const (
+	PromoRedemptionActive    PromoRedemptionStatus = "active"
+	PromoRedemptionExhausted PromoRedemptionStatus = "exhausted" // Fixed amount fully used
+	PromoRedemptionCancelled PromoRedemptionStatus = "cancelled" // Invoice draft deleted
+)
+
+// ReferralStatus represents the progress of a referral
+type ReferralStatus string
+
+const (
+	ReferralStatusPending    ReferralStatus = "pending"    // Invitation sent
+	ReferralStatusRegistered ReferralStatus = "registered" // Referee signed up
+	ReferralStatusRewarded   ReferralStatus = "rewarded"   // Referee's first course paid, credits granted
+	ReferralStatusCancelled  ReferralStatus = "cancelled"
+)
+
+var (
+	ErrInvalidPromoCode          = errors.New("code promo invalide : un pourcentage entre 0 et 100 ou un montant fixe strictement positif est requis")
+	ErrPromoCodeNotFound         = errors.New("code promo inconnu")
+	ErrPromoCodeNotValid         = errors.New("ce code promo n'est pas valable à cette date")
+	ErrPromoCodeExhausted        = errors.New("ce code promo a atteint sa limite d'utilisation")
+	ErrPromoCodeAlreadyUsed      = errors.New("la famille a déjà utilisé ce code promo")
+	ErrPromoCodeFirstMissionOnly = errors.New("ce code promo est réservé à la première mission de la famille")
+	ErrPromoCodeInvalidPeriod    = errors.New("la fin de validité du code promo doit être postérieure à son début")
+	ErrReferralEmailTaken        = errors.New("cette adresse e-mail est déjà inscrite ou a déjà été invitée")
+	ErrReferralNotFound          = errors.New("code de parrainage inconnu ou déjà utilisé")
+)
+
+// PromoCode model - represents a promotional code of an acquisition campaign
+type PromoCode struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	Code              string         `json:"code" gorm:"uniqueIndex;not null"` // Stored in upper case
+	Description       string         `json:"description"`
+	Kind              PromoCodeKind  `json:"kind" gorm:"not null"`
+	Percent           float64        `json:"percent,omitempty"`
+	Amount            Money          `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	MaxUses           int            `json:"max_uses"`             // 0 = unlimited
+	MaxUsesPerFamille int            `json:"max_uses_per_famille"` // 0 = unlimited
+	UsedCount         int            `json:"used_count"`
+	ValidFrom         *time.Time     `json:"valid_from"`
+	ValidUntil        *time.Time     `json:"valid_until"`
+	FirstMissionOnly  bool           `json:"first_mission_only"`
+	IsActive          bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// PromoRedemption model - represents a promo code applied by a family to a mission
+// (every course of the mission) or to an invoice (its courses only)
+type PromoRedemption struct {
+	ID              uint                  `json:"id" gorm:"primaryKey"`
+	Code            string                `json:"code" gorm:"not null"`
+	Kind            PromoCodeKind         `json:"kind" gorm:"not null"`
+	Percent         float64               `json:"percent,omitempty"`
+	RemainingAmount Money                 `json:"remaining_amount" gorm:"embedded;embeddedPrefix:remaining_amount_"` // Fixed codes only
+	DiscountTotal   Money                 `json:"discount_total" gorm:"embedded;embeddedPrefix:discount_total_"`
+	Status          PromoRedemptionStatus `json:"status" gorm:"not null;index"`
+	CreatedAt       time.Time             `json:"created_at" gorm:"index"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+
+	// Foreign Keys
+	PromoCodeID uint  `json:"promo_code_id" gorm:"index;not null"`
+	FamilleID   uint  `json:"famille_id" gorm:"index;not null"`
+	MissionID   *uint `json:"mission_id,omitempty" gorm:"index"`
+	InvoiceID   *uint `json:"invoice_id,omitempty" gorm:"index"`
+	ActorID     uint  `json:"actor_id,omitempty"`
+
+	// Relationships
+	Discounts []PromoDiscount `json:"discounts,omitempty" gorm:"foreignKey:RedemptionID"`
+}
+
+// PromoDiscount model - represents the discount granted on a course by a promo
+// code, deducted from its payment or from its invoice
+type PromoDiscount struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Amount    Money     `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Foreign Keys
+	RedemptionID uint  `json:"redemption_id" gorm:"index;not null"`
+	CourseID     uint  `json:"course_id" gorm:"index"`
+	PaymentID    *uint `json:"payment_id,omitempty" gorm:"index"`
+	InvoiceID    *uint `json:"invoice_id,omitempty" gorm:"index"`
+}
+
+// Referral model - represents a family inviting another one. Both receive free
+// hours in their wallet once the referee's first course has been paid.
+type Referral struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Code          string         `json:"code" gorm:"uniqueIndex;not null"`
+	Email         string         `json:"email" gorm:"index;not null"`
+	Status        ReferralStatus `json:"status" gorm:"not null;index"`
+	RegisteredAt  *time.Time     `json:"registered_at"`
+	RewardedAt    *time.Time     `json:"rewarded_at"`
+	RewardMinutes int            `json:"reward_minutes,omitempty"` // Granted to each family
+	CreatedAt     time.Time      `json:"created_at" gorm:"index"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// Foreign Keys
+	ReferrerID      uint  `json:"referrer_id" gorm:"index;not null"`
+	RefereeID       *uint `json:"referee_id,omitempty" gorm:"index"`
+	RewardPaymentID *uint `json:"reward_payment_id,omitempty"` // Payment that triggered the reward
+}
+
+// PromoCode methods
+
+// NormalizePromoCode met un code saisi sous la forme stockée
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CheckValidity vérifie que le code est actif, dans sa période de validité et
+// sous sa limite d'utilisation
+func (p *PromoCode) CheckValidity(at time.Time) error {
+	if !p.IsActive || (p.ValidFrom != nil && at.Before(*p.ValidFrom)) || (p.ValidUntil != nil && !at.Before(*p.ValidUntil)) {
+		return ErrPromoCodeNotValid
+	}
+	if p.MaxUses > 0 && p.UsedCount >= p.MaxUses {
+		return ErrPromoCodeExhausted
+	}
+	return nil
+}
+
+// NewRedemption prépare l'application du code par une famille
+func (p *PromoCode) NewRedemption(familleID uint) PromoRedemption {
+	redemption := PromoRedemption{
+		Code:            p.Code,
+		Kind:            p.Kind,
+		Percent:         p.Percent,
+		RemainingAmount: NewMoney(0, p.Amount.Currency),
+		DiscountTotal:   NewMoney(0, p.Amount.Currency),
+		Status:          PromoRedemptionActive,
+		PromoCodeID:     p.ID,
+		FamilleID:       familleID,
+	}
+	if p.Kind == PromoCodeFixed {
+		redemption.RemainingAmount = p.Amount
+	}
+	return redemption
+}
+
+// PromoRedemption methods
+
+// Discount calcule la remise accordée sur un montant et la déduit du reliquat
+// d'un code à montant fixe
+func (r *PromoRedemption) Discount(amount Money) Money {
+	discount := NewMoney(0, amount.Currency)
+	if r.Status != PromoRedemptionActive || !amount.IsPositive() {
+		return discount
+	}
+	switch r.Kind {
+	case PromoCodePercentage:
+		discount = amount.Percent(r.Percent)
+	case PromoCodeFixed:
+		discount = r.RemainingAmount
+		if discount.Cents > amount.Cents {
+			discount = amount
+		}
+		r.RemainingAmount = r.RemainingAmount.Sub(discount)
+		if !r.RemainingAmount.IsPositive() {
+			r.Status = PromoRedemptionExhausted
+		}
+	}
+	r.DiscountTotal = r.DiscountTotal.Add(discount)
+	return discount
+}
+
+// Restore rend une remise annulée, avec la facture brouillon qui la portait
+func (r *PromoRedemption) Restore(discount Money) {
+	r.DiscountTotal = r.DiscountTotal.Sub(discount)
+	if r.Kind == PromoCodeFixed {
+		r.RemainingAmount = r.RemainingAmount.Add(discount)
+		if r.Status == PromoRedemptionExhausted && r.RemainingAmount.IsPositive() {
+			r.Status = PromoRedemptionActive
+		}
+	}
+}
+
+// Label retourne le libellé de la remise sur les paiements et les factures
+func (r *PromoRedemption) Label() string {
+	if r.Kind == PromoCodePercentage {
+		return fmt.Sprintf("Code promo %s (-%g %%)", r.Code, r.Percent)
+	}
+	return fmt.Sprintf("Code promo %s", r.Code)
+}
+
+// Request/Response structures
+type PromoCodeRequest struct {
+	Code              string        `json:"code" binding:"required,alphanum,max=30"`
+	Description       string        `json:"description"`
+	Kind              PromoCodeKind `json:"kind" binding:"required,oneof=percentage fixed"`
+	Percent           float64       `json:"percent"`
+	Amount            Money         `json:"amount"`
+	MaxUses           int           `json:"max_uses" binding:"min=0"`
+	MaxUsesPerFamille *int          `json:"max_uses_per_famille,omitempty" binding:"omitempty,min=0"` // 1 by default
+	ValidFrom         *time.Time    `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time    `json:"valid_until,omitempty"`
+	FirstMissionOnly  bool          `json:"first_mission_only"`
+	IsActive          *bool         `json:"is_active,omitempty"`
+}
+
+// Validate vérifie la cohérence de la remise avec le type de code et la période de validité
+func (r *PromoCodeRequest) Validate() error {
+	switch r.Kind {
+	case PromoCodePercentage:
+		if r.Percent <= 0 || r.Percent > 100 || r.Amount.Cents != 0 {
+			return ErrInvalidPromoCode
+		}
+	case PromoCodeFixed:
+		if !r.Amount.IsPositive() || r.Percent != 0 {
+			return ErrInvalidPromoCode
+		}
+	}
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidUntil.After(*r.ValidFrom) {
+		return ErrPromoCodeInvalidPeriod
+	}
+	return nil
+}
+
+type PromoCodeCheckRequest struct {
+	Code      string `json:"code" binding:"required"`
+	FamilleID uint   `json:"famille_id,omitempty"` // Admin only, defaults to the current family
+	MissionID *uint  `json:"mission_id,omitempty"` // Existing mission the code would apply to
+}
+
+type PromoCodeCheckResponse struct {
+	Code             string        `json:"code"`
+	Kind             PromoCodeKind `json:"kind"`
+	Percent          float64       `json:"percent,omitempty"`
+	Amount           Money         `json:"amount"`
+	FirstMissionOnly bool          `json:"first_mission_only"`
+	ValidUntil       *time.Time    `json:"valid_until,omitempty"`
+	Valid            bool          `json:"valid"`
+	Reason           string        `json:"reason,omitempty"` // Why the code cannot be used
+}
+
+type ReferralInviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PromoCodeReport summarises the use of a promo code over a period
+type PromoCodeReport struct {
+	PromoCodeID   uint   `json:"promo_code_id"`
+	Code          string `json:"code"`
+	Redemptions   int    `json:"redemptions"`
+	Cancelled     int    `json:"cancelled"`
+	Familles      int    `json:"familles"`
+	DiscountTotal Money  `json:"discount_total"` // Discounts granted during the period
+}
+
+// ReferralReport summarises the referral programme over a period
+type ReferralReport struct {
+	Invited        int `json:"invited"`
+	Registered     int `json:"registered"`
+	Rewarded       int `json:"rewarded"`
+	MinutesGranted int `json:"minutes_granted"` // Referrers and referees together
+}
+
+type PromotionReportResponse struct {
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"` // Exclusive
+	PromoCodes    []PromoCodeReport `json:"promo_codes"`
+	DiscountTotal Money             `json:"discount_total"`
+	Referrals     ReferralReport    `json:"referrals"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +15,12 @@ const (
 	ReportStatusValidated ReportStatus = "validated"
 	ReportStatusRejected  ReportStatus = "rejected"
 	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusDraft     ReportStatus = "draft"
+)
+
+var (
+	ErrReportNotEditable  = errors.New("seul un rapport en brouillon ou rejeté peut être modifié")
+	ErrReportNotSubmitted = errors.New("seul un rapport soumis peut être validé ou rejeté")
 )
 
 // Report model - represents reports submitted by teachers
@@ -24,37 +31,113 @@ type Report struct {
 	Status         ReportStatus   `json:"status" gorm:"default:'pending'"`
 	ValidationDate *time.Time     `json:"validation_date"`
 	Comments       string         `json:"comments"`
+	Revision       int            `json:"revision"` // Incremented at each submission
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Foreign Keys
-	EnseignantID  uint `json:"enseignant_id"`
-	MissionID     uint `json:"mission_id"`
-	ValidatedByID uint `json:"validated_by_id,omitempty"`
+	EnseignantID  uint  `json:"enseignant_id"`
+	MissionID     uint  `json:"mission_id"`
+	ValidatedByID *uint `json:"validated_by_id,omitempty"` // Reviewer of the last decision
+	TemplateID    *uint `json:"template_id,omitempty"`
 
 	// Relationships
-	Enseignant  Enseignant    `json:"enseignant,omitempty" gorm:"foreignKey:EnseignantID"`
-	Mission     Mission       `json:"mission,omitempty" gorm:"foreignKey:MissionID"`
-	ValidatedBy Administrator `json:"validated_by,omitempty" gorm:"foreignKey:ValidatedByID"`
+	Enseignant  Enseignant       `json:"enseignant,omitempty" gorm:"foreignKey:EnseignantID"`
+	Mission     Mission          `json:"mission,omitempty" gorm:"foreignKey:MissionID"`
+	ValidatedBy Administrator    `json:"validated_by,omitempty" gorm:"foreignKey:ValidatedByID"`
+	Template    *ReportTemplate  `json:"template,omitempty" gorm:"foreignKey:TemplateID"`
+	Answers     []ReportAnswer   `json:"answers,omitempty" gorm:"foreignKey:ReportID"`
+	Revisions   []ReportRevision `json:"revisions,omitempty" gorm:"foreignKey:ReportID"`
+}
+
+// ReportRevisionAction represents a step of the report workflow
+type ReportRevisionAction string
+
+const (
+	ReportActionCreated   ReportRevisionAction = "created"
+	ReportActionUpdated   ReportRevisionAction = "updated"
+	ReportActionSubmitted ReportRevisionAction = "submitted"
+	ReportActionValidated ReportRevisionAction = "validated"
+	ReportActionRejected  ReportRevisionAction = "rejected"
+)
+
+// ReportRevision model - represents a snapshot of a report at a workflow step
+type ReportRevision struct {
+	ID        uint                 `json:"id" gorm:"primaryKey"`
+	Revision  int                  `json:"revision"`
+	Action    ReportRevisionAction `json:"action" gorm:"not null"`
+	Status    ReportStatus         `json:"status"`
+	Content   string               `json:"content" gorm:"type:text"`
+	Answers   string               `json:"answers,omitempty" gorm:"type:text"` // JSON snapshot of the structured answers
+	Comments  string               `json:"comments,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+
+	// Foreign Keys
+	ReportID uint `json:"report_id" gorm:"index;not null"`
+	ActorID  uint `json:"actor_id"`
+}
+
+// ReportReminderKind represents the moment a report reminder was sent
+type ReportReminderKind string
+
+const (
+	ReportReminderDueSoon ReportReminderKind = "due_soon"
+	ReportReminderOverdue ReportReminderKind = "overdue"
+)
+
+// ReportReminder model - records a reminder sent for a report deadline, so that
+// each deadline triggers at most one reminder of each kind
+type ReportReminder struct {
+	ID        uint               `json:"id" gorm:"primaryKey"`
+	Kind      ReportReminderKind `json:"kind" gorm:"not null;uniqueIndex:idx_report_reminder"`
+	DueAt     time.Time          `json:"due_at" gorm:"not null;uniqueIndex:idx_report_reminder"`
+	CreatedAt time.Time          `json:"created_at"`
+
+	// Foreign Keys
+	MissionID uint `json:"mission_id" gorm:"not null;uniqueIndex:idx_report_reminder"`
 }
 
 // Report methods
 func (r *Report) SubmitReport() error {
-	// Logic to submit report
+	if !r.CanEdit() {
+		return ErrReportNotEditable
+	}
 	r.Status = ReportStatusSubmitted
 	r.SubmissionDate = time.Now()
+	r.Revision++
 	return nil
 }
 
-func (r *Report) ValidateReport() error {
-	// Logic to validate report
+func (r *Report) ValidateReport(adminID uint, comments string) error {
+	if r.Status != ReportStatusSubmitted {
+		return ErrReportNotSubmitted
+	}
 	r.Status = ReportStatusValidated
 	validationTime := time.Now()
 	r.ValidationDate = &validationTime
+	r.ValidatedByID = &adminID
+	r.Comments = comments
+	return nil
+}
+
+func (r *Report) RejectReport(adminID uint, comments string) error {
+	if r.Status != ReportStatusSubmitted {
+		return ErrReportNotSubmitted
+	}
+	r.Status = ReportStatusRejected
+	r.ValidationDate = nil
+	r.ValidatedByID = &adminID
+	r.Comments = comments
 	return nil
 }
 
+// CanEdit indique si l'enseignant peut encore modifier le rapport
+// (brouillon, ancien statut en attente ou rejeté pour révision)
+func (r *Report) CanEdit() bool {
+	return r.Status == ReportStatusDraft || r.Status == ReportStatusPending || r.Status == ReportStatusRejected
+}
+
 func (r *Report) ViewReport() (Report, error) {
 	// Logic to view report will be implemented in controllers
 	return *r, nil
@@ -62,14 +145,20 @@ func (r *Report) ViewReport() (Report, error) {
 
 // Request/Response structures
 type ReportCreateRequest struct {
-	Content   string `json:"content" binding:"required"`
-	MissionID uint   `json:"mission_id" binding:"required"`
+	Content    string                `json:"content" binding:"required"`
+	MissionID  uint                  `json:"mission_id" binding:"required"`
+	TemplateID *uint                 `json:"template_id,omitempty"`
+	Answers    []ReportAnswerRequest `json:"answers,omitempty" binding:"dive"`
+	Submit     bool                  `json:"submit,omitempty"` // Submit immediately instead of keeping a draft
 }
 
 type ReportUpdateRequest struct {
-	Content  string       `json:"content,omitempty"`
-	Status   ReportStatus `json:"status,omitempty"`
-	Comments string       `json:"comments,omitempty"`
+	Content string                `json:"content" binding:"required"`
+	Answers []ReportAnswerRequest `json:"answers,omitempty" binding:"dive"` // Replaces the structured answers when provided
+}
+
+type ReportReviewRequest struct {
+	Comments string `json:"comments"`
 }
 
 type ReportFilterRequest struct {
@@ -79,3 +168,14 @@ type ReportFilterRequest struct {
 	DateFrom     *time.Time   `json:"date_from,omitempty"`
 	DateTo       *time.Time   `json:"date_to,omitempty"`
 }
+
+type OverdueReportResponse struct {
+	MissionID        uint       `json:"mission_id"`
+	EnseignantID     uint       `json:"enseignant_id"`
+	FamilleID        uint       `json:"famille_id"`
+	FrequencyDays    int        `json:"frequency_days"`
+	DueAt            time.Time  `json:"due_at"`
+	DaysOverdue      int        `json:"days_overdue"`
+	LastSubmissionAt *time.Time `json:"last_submission_at"`
+	PayoutBlocked    bool       `json:"payout_blocked"`
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReportItemKind represents the kind of answer expected for a template item
+type ReportItemKind string
+
+const (
+	ReportItemRating ReportItemKind = "rating"
+	ReportItemText   ReportItemKind = "text"
+)
+
+// Skill model - represents a skill assessed for a subject and level
+type Skill struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Subject     string         `json:"subject" gorm:"not null;index"`
+	Level       string         `json:"level" gorm:"index"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ReportTemplate model - represents an admin-defined structure for reports
+type ReportTemplate struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Subject     string         `json:"subject"`
+	Level       string         `json:"level"`
+	Description string         `json:"description" gorm:"type:text"`
+	ScaleMin    int            `json:"scale_min"`
+	ScaleMax    int            `json:"scale_max"`
+	ScaleLabels string         `json:"scale_labels,omitempty"` // Comma separated, from ScaleMin to ScaleMax
+	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationships
+	Sections []ReportTemplateSection `json:"sections,omitempty" gorm:"foreignKey:TemplateID"`
+}
+
+// ReportTemplateSection model - represents a section of a report template
+type ReportTemplateSection struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	Title    string `json:"title" gorm:"not null"`
+	Position int    `json:"position"`
+
+	// Foreign Key
+	TemplateID uint `json:"template_id" gorm:"index;not null"`
+
+	// Relationships
+	Items []ReportTemplateItem `json:"items,omitempty" gorm:"foreignKey:SectionID"`
+}
+
+// ReportTemplateItem model - represents a question of a template section
+type ReportTemplateItem struct {
+	ID       uint           `json:"id" gorm:"primaryKey"`
+	Label    string         `json:"label" gorm:"not null"`
+	Kind     ReportItemKind `json:"kind" gorm:"not null"`
+	Position int            `json:"position"`
+
+	// Foreign Keys
+	SectionID uint  `json:"section_id" gorm:"index;not null"`
+	SkillID   *uint `json:"skill_id,omitempty"` // Skill assessed by a rating item
+
+	// Relationships
+	Skill *Skill `json:"skill,omitempty" gorm:"foreignKey:SkillID"`
+}
+
+// ReportAnswer model - represents a structured answer of a report
+type ReportAnswer struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	Rating *int   `json:"rating,omitempty"`
+	Text   string `json:"text,omitempty" gorm:"type:text"`
+
+	// Foreign Keys
+	ReportID uint  `json:"report_id" gorm:"index;not null"`
+	ItemID   uint  `json:"item_id" gorm:"not null"`
+	SkillID  *uint `json:"skill_id,omitempty" gorm:"index"` // Copied from the item for aggregation
+}
+
+// ReportTemplate methods
+func (t *ReportTemplate) Item(itemID uint) *ReportTemplateItem {
+	for i := range t.Sections {
+		for j := range t.Sections[i].Items {
+			if t.Sections[i].Items[j].ID == itemID {
+				return &t.Sections[i].Items[j]
+			}
+		}
+	}
+	return nil
+}
+
+// Request/Response structures
+type SkillRequest struct {
+	Subject     string `json:"subject" binding:"required"`
+	Level       string `json:"level"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type ReportTemplateItemRequest struct {
+	Label   string         `json:"label" binding:"required"`
+	Kind    ReportItemKind `json:"kind" binding:"required,oneof=rating text"`
+	SkillID *uint          `json:"skill_id,omitempty"`
+}
+
+type ReportTemplateSectionRequest struct {
+	Title string                      `json:"title" binding:"required"`
+	Items []ReportTemplateItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type ReportTemplateRequest struct {
+	Name        string                         `json:"name" binding:"required"`
+	Subject     string                         `json:"subject"`
+	Level       string                         `json:"level"`
+	Description string                         `json:"description"`
+	ScaleMin    int                            `json:"scale_min" binding:"min=0"`
+	ScaleMax    int                            `json:"scale_max" binding:"required,gtfield=ScaleMin"`
+	ScaleLabels string                         `json:"scale_labels,omitempty"`
+	IsActive    *bool                          `json:"is_active,omitempty"`
+	Sections    []ReportTemplateSectionRequest `json:"sections" binding:"required,min=1,dive"`
+}
+
+type ReportAnswerRequest struct {
+	ItemID uint   `json:"item_id" binding:"required"`
+	Rating *int   `json:"rating,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+type SkillProgressionPoint struct {
+	ReportID uint      `json:"report_id"`
+	Date     time.Time `json:"date"`
+	Rating   int       `json:"rating"`
+	ScaleMin int       `json:"scale_min"`
+	ScaleMax int       `json:"scale_max"`
+	Percent  float64   `json:"percent"` // Rating normalised on a 0-100 scale
+}
+
+type SkillProgressionResponse struct {
+	Skill  Skill                   `json:"skill"`
+	Points []SkillProgressionPoint `json:"points"`
+	First  float64                 `json:"first_percent"`
+	Last   float64                 `json:"last_percent"`
+	Delta  float64                 `json:"delta_percent"`
+}
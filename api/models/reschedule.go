@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RescheduleStatus represents the status of a reschedule request
+type RescheduleStatus string
+
+const (
+	RescheduleStatusPending   RescheduleStatus = "pending"
+	RescheduleStatusAccepted  RescheduleStatus = "accepted"
+	RescheduleStatusRejected  RescheduleStatus = "rejected"
+	RescheduleStatusCountered RescheduleStatus = "countered"
+	RescheduleStatusExpired   RescheduleStatus = "expired"
+	RescheduleStatusCancelled RescheduleStatus = "cancelled"
+)
+
+// RescheduleRequest model - represents a proposal to move a course to another time
+type RescheduleRequest struct {
+	ID               uint             `json:"id" gorm:"primaryKey"`
+	ProposedTime     time.Time        `json:"proposed_time" gorm:"not null"`
+	ProposedDuration int              `json:"proposed_duration"` // Duration in minutes
+	Reason           string           `json:"reason"`
+	Status           RescheduleStatus `json:"status" gorm:"default:'pending'"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	RespondedAt      *time.Time       `json:"responded_at"`
+	ResponseNote     string           `json:"response_note,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt   `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	CourseID      uint  `json:"course_id" gorm:"index;not null"`
+	RequestedByID uint  `json:"requested_by_id" gorm:"not null"`
+	RespondentID  uint  `json:"respondent_id" gorm:"not null"`
+	ParentID      *uint `json:"parent_id,omitempty"` // Request this one counter-proposes
+
+	// Relationships
+	Course *Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+}
+
+// RescheduleRequest methods
+func (r *RescheduleRequest) IsOpen(now time.Time) bool {
+	return r.Status == RescheduleStatusPending && now.Before(r.ExpiresAt)
+}
+
+func (r *RescheduleRequest) Respond(status RescheduleStatus, note string) {
+	now := time.Now()
+	r.Status = status
+	r.RespondedAt = &now
+	r.ResponseNote = note
+}
+
+// Request/Response structures
+type RescheduleCreateRequest struct {
+	ProposedTime     time.Time `json:"proposed_time" binding:"required"`
+	ProposedDuration int       `json:"proposed_duration,omitempty" binding:"omitempty,min=30,max=480"` // Keeps the current duration when omitted
+	Reason           string    `json:"reason"`
+}
+
+type RescheduleResponseRequest struct {
+	Note string `json:"note"`
+}
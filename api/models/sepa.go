@@ -0,0 +1,127 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MandateStatus represents the status of a SEPA direct debit mandate
+type MandateStatus string
+
+const (
+	MandateStatusActive  MandateStatus = "active"
+	MandateStatusRevoked MandateStatus = "revoked"
+)
+
+var (
+	ErrMandateRevoked = errors.New("ce mandat a déjà été révoqué")
+)
+
+// SEPATransferBatch model - represents a SEPA credit transfer file (ISO 20022
+// pain.001) exported for a set of locked payouts. The generated XML is kept as
+// issued; each payout references the batch that covered it so it cannot be
+// exported twice.
+type SEPATransferBatch struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	MessageID     string    `json:"message_id" gorm:"uniqueIndex;not null"` // e.g. "PAYOUT-20261031-000004"
+	ExecutionDate time.Time `json:"execution_date" gorm:"not null"`
+	PaymentCount  int       `json:"payment_count"`
+	Total         Money     `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	DebtorName    string    `json:"debtor_name"`
+	DebtorIBAN    string    `json:"debtor_iban"` // Masked in API responses
+	Content       string    `json:"-" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Foreign Keys
+	CreatedByID uint `json:"created_by_id"`
+
+	// Relationships
+	Payouts []Payout `json:"payouts,omitempty" gorm:"foreignKey:TransferBatchID"`
+}
+
+// SEPAMandate model - represents a SEPA Core direct debit mandate signed by a
+// family. Its unique mandate reference (RUM) and signature date are quoted on
+// every collection. A family has at most one active mandate: signing a new one
+// revokes the previous one.
+type SEPAMandate struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	Reference  string        `json:"reference" gorm:"uniqueIndex;not null"` // RUM, e.g. "MDT-000042"
+	Status     MandateStatus `json:"status" gorm:"not null;default:'active';index"`
+	DebtorName string        `json:"debtor_name" gorm:"not null"`
+	IBAN       string        `json:"-" gorm:"not null"` // Normalized, checksum validated
+	BIC        string        `json:"bic,omitempty"`
+	SignedAt   time.Time     `json:"signed_at" gorm:"not null"`
+	RevokedAt  *time.Time    `json:"revoked_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+
+	// Foreign Keys
+	FamilleID uint `json:"famille_id" gorm:"index;not null"`
+
+	// Computed, not stored
+	MaskedIBAN string `json:"iban" gorm:"-"`
+}
+
+// SEPADebitBatch model - represents a SEPA direct debit file (ISO 20022
+// pain.008) collecting open invoices. Each collection is a pending payment
+// linked to the batch, settled when the bank notification is imported.
+type SEPADebitBatch struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	MessageID      string    `json:"message_id" gorm:"uniqueIndex;not null"` // e.g. "DEBIT-20261031-000004"
+	CollectionDate time.Time `json:"collection_date" gorm:"not null"`
+	PrenotifiedAt  time.Time `json:"prenotified_at"` // Families were notified of amount and date
+	PaymentCount   int       `json:"payment_count"`
+	Total          Money     `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	Content        string    `json:"-" gorm:"type:text"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Foreign Keys
+	CreatedByID uint `json:"created_by_id"`
+
+	// Relationships
+	Payments []Payment `json:"payments,omitempty" gorm:"foreignKey:DebitBatchID"`
+}
+
+// SEPAMandate methods
+
+// IsActive indique si le mandat autorise encore des prélèvements
+func (m *SEPAMandate) IsActive() bool {
+	return m.Status == MandateStatusActive
+}
+
+// Revoke révoque le mandat ; aucun nouveau prélèvement ne peut plus être émis
+func (m *SEPAMandate) Revoke(at time.Time) error {
+	if !m.IsActive() {
+		return ErrMandateRevoked
+	}
+	m.Status = MandateStatusRevoked
+	m.RevokedAt = &at
+	return nil
+}
+
+// Request/Response structures
+type SEPAExportRequest struct {
+	PayoutIDs     []uint `json:"payout_ids,omitempty"`     // All locked payouts not yet exported when omitted
+	ExecutionDate string `json:"execution_date,omitempty"` // YYYY-MM-DD, defaults to the next business day
+}
+
+type SEPAMandateCreateRequest struct {
+	Reference  string    `json:"reference,omitempty"` // Existing RUM of a paper mandate, generated when omitted
+	DebtorName string    `json:"debtor_name" binding:"required,max=70"`
+	IBAN       string    `json:"iban" binding:"required"`
+	BIC        string    `json:"bic,omitempty"`
+	SignedAt   time.Time `json:"signed_at" binding:"required"`
+}
+
+type SEPADebitExportRequest struct {
+	InvoiceIDs     []uint `json:"invoice_ids,omitempty"`     // All open invoices of families with an active mandate when omitted
+	CollectionDate string `json:"collection_date,omitempty"` // YYYY-MM-DD, defaults to the end of the pre-notification period
+}
+
+// SEPAReturnImportResponse summarizes the import of a bank notification (camt.054)
+type SEPAReturnImportResponse struct {
+	Completed []uint   `json:"completed"` // Payments marked as completed
+	Failed    []uint   `json:"failed"`    // Payments rejected or returned
+	Ignored   []string `json:"ignored"`   // Already applied, not booked yet, or unrelated entries
+	Unmatched []string `json:"unmatched"` // End-to-end references matching no collection
+}
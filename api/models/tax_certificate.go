@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// TaxCertificateStatus represents the status of an annual tax certificate
+type TaxCertificateStatus string
+
+const (
+	TaxCertificateStatusDraft     TaxCertificateStatus = "draft"
+	TaxCertificateStatusPublished TaxCertificateStatus = "published"
+)
+
+var (
+	ErrTaxCertificatePublished = errors.New("cette attestation a déjà été publiée et ne peut plus être modifiée")
+)
+
+// TaxCertificate model - represents the yearly "attestation fiscale" of a
+// family: the amounts actually paid during the calendar year for tutoring
+// courses, with the hours given by each teacher, which entitles the family to
+// the tax credit for services à la personne. Drafts are recomputed on each
+// generation; published certificates are visible to the family and frozen.
+type TaxCertificate struct {
+	ID           uint                 `json:"id" gorm:"primaryKey"`
+	Year         int                  `json:"year" gorm:"not null;uniqueIndex:idx_tax_certificate"`
+	Status       TaxCertificateStatus `json:"status" gorm:"not null;default:'draft';index"`
+	Number       string               `json:"number" gorm:"uniqueIndex;not null"` // e.g. ATT-2026-000042
+	TotalAmount  Money                `json:"total_amount" gorm:"embedded;embeddedPrefix:total_amount_"`
+	TotalMinutes int                  `json:"total_minutes"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+	PublishedAt  *time.Time           `json:"published_at"`
+
+	// Legal mentions, frozen on publication
+	SellerName        string    `json:"seller_name,omitempty"`
+	SellerAddress     string    `json:"seller_address,omitempty"`
+	SellerSIRET       string    `json:"seller_siret,omitempty"`
+	DeclarationNumber string    `json:"declaration_number,omitempty"` // Services à la personne declaration
+	CustomerName      string    `json:"customer_name,omitempty"`
+	CustomerAddress   string    `json:"customer_address,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	// Foreign Keys
+	FamilleID     uint  `json:"famille_id" gorm:"not null;uniqueIndex:idx_tax_certificate"`
+	PublishedByID *uint `json:"published_by_id,omitempty"`
+
+	// Relationships
+	Lines []TaxCertificateLine `json:"lines,omitempty" gorm:"foreignKey:TaxCertificateID"`
+}
+
+// TaxCertificateLine model - represents the hours given by one teacher and the
+// amount paid for them during the year
+type TaxCertificateLine struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Position    int    `json:"position"`
+	TeacherName string `json:"teacher_name"`
+	Minutes     int    `json:"minutes"`
+	Amount      Money  `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+
+	// Foreign Keys
+	TaxCertificateID uint `json:"tax_certificate_id" gorm:"index;not null"`
+	EnseignantID     uint `json:"enseignant_id" gorm:"index"`
+}
+
+// TaxCertificate methods
+
+// IsPublished indique si l'attestation est visible de la famille
+func (t *TaxCertificate) IsPublished() bool {
+	return t.Status == TaxCertificateStatusPublished
+}
+
+// ComputeTotals recalcule le montant et la durée totale à partir des lignes
+func (t *TaxCertificate) ComputeTotals() {
+	t.TotalMinutes = 0
+	t.TotalAmount = NewMoney(0, t.TotalAmount.Currency)
+	for _, line := range t.Lines {
+		t.TotalMinutes += line.Minutes
+		t.TotalAmount = t.TotalAmount.Add(line.Amount)
+	}
+}
+
+// Request/Response structures
+type TaxCertificateRequest struct {
+	Year      int   `json:"year" binding:"required,min=2000,max=2100"`
+	FamilleID *uint `json:"famille_id,omitempty"` // All families when omitted
+}
+
+// TaxCertificateBatchResponse summarizes a batch generation or publication
+type TaxCertificateBatchResponse struct {
+	Year         int              `json:"year"`
+	Certificates []TaxCertificate `json:"certificates"`
+	Skipped      []uint           `json:"skipped"` // Families whose certificate is already published
+}
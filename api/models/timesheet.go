@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TimesheetStatus represents the status of a timesheet entry
+type TimesheetStatus string
+
+const (
+	TimesheetStatusPending  TimesheetStatus = "pending"
+	TimesheetStatusApproved TimesheetStatus = "approved"
+	TimesheetStatusDisputed TimesheetStatus = "disputed"
+	TimesheetStatusVoided   TimesheetStatus = "voided" // Course cancelled before the hours were approved
+)
+
+var (
+	ErrTimesheetNotPending      = errors.New("cette déclaration n'est plus en attente d'approbation")
+	ErrTimesheetCourseCancelled = errors.New("le cours a été annulé : ses heures déclarées ne peuvent plus être approuvées")
+)
+
+// TimesheetEntry model - represents the hours declared by a teacher for a course
+type TimesheetEntry struct {
+	ID               uint            `json:"id" gorm:"primaryKey"`
+	DeclaredStart    time.Time       `json:"declared_start" gorm:"not null"`
+	DeclaredEnd      time.Time       `json:"declared_end" gorm:"not null"`
+	DurationMinutes  int             `json:"duration_minutes"` // Actual duration in minutes
+	Notes            string          `json:"notes" gorm:"type:text"`
+	Status           TimesheetStatus `json:"status" gorm:"default:'pending'"`
+	ApprovalDeadline time.Time       `json:"approval_deadline"`
+	ReviewedAt       *time.Time      `json:"reviewed_at"`
+	AutoApproved     bool            `json:"auto_approved"`
+	DisputeReason    string          `json:"dispute_reason,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt  `json:"-" gorm:"index"`
+
+	// Foreign Keys
+	CourseID     uint `json:"course_id" gorm:"uniqueIndex;not null"`
+	EnseignantID uint `json:"enseignant_id" gorm:"index"`
+	FamilleID    uint `json:"famille_id" gorm:"index"`
+	ReviewedByID uint `json:"reviewed_by_id,omitempty"`
+
+	// Relationships
+	Course *Course `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+}
+
+// TimesheetEntry methods
+func (t *TimesheetEntry) Declare(start, end time.Time, notes string, deadline time.Time) {
+	t.DeclaredStart = start
+	t.DeclaredEnd = end
+	t.DurationMinutes = int(end.Sub(start).Minutes())
+	t.Notes = notes
+	t.Status = TimesheetStatusPending
+	t.ApprovalDeadline = deadline
+	t.ReviewedAt = nil
+	t.ReviewedByID = 0
+	t.AutoApproved = false
+	t.DisputeReason = ""
+}
+
+func (t *TimesheetEntry) Approve(reviewerID uint) {
+	now := time.Now()
+	t.Status = TimesheetStatusApproved
+	t.ReviewedAt = &now
+	t.ReviewedByID = reviewerID
+}
+
+func (t *TimesheetEntry) AutoApprove() {
+	t.Approve(0)
+	t.AutoApproved = true
+}
+
+func (t *TimesheetEntry) Dispute(reviewerID uint, reason string) {
+	now := time.Now()
+	t.Status = TimesheetStatusDisputed
+	t.ReviewedAt = &now
+	t.ReviewedByID = reviewerID
+	t.DisputeReason = reason
+}
+
+// AdjustDuration corrige la durée retenue ; l'heure de fin déclarée est recalculée
+func (t *TimesheetEntry) AdjustDuration(minutes int) {
+	t.DurationMinutes = minutes
+	t.DeclaredEnd = t.DeclaredStart.Add(time.Duration(minutes) * time.Minute)
+}
+
+// Void annule la déclaration d'un cours annulé : elle ne sera ni approuvée ni facturée
+func (t *TimesheetEntry) Void() {
+	t.Status = TimesheetStatusVoided
+}
+
+// CanBeReviewed indique si la famille peut encore approuver ou contester l'entrée
+func (t *TimesheetEntry) CanBeReviewed(now time.Time) bool {
+	return t.Status == TimesheetStatusPending && now.Before(t.ApprovalDeadline)
+}
+
+// Hours retourne la durée réelle en heures
+func (t *TimesheetEntry) Hours() float64 {
+	return float64(t.DurationMinutes) / 60
+}
+
+// Request/Response structures
+type TimesheetDeclareRequest struct {
+	DeclaredStart *time.Time `json:"declared_start,omitempty"`
+	DeclaredEnd   *time.Time `json:"declared_end,omitempty"`
+	Hours         float64    `json:"hours,omitempty" binding:"omitempty,gt=0,max=12"` // Used when start/end are omitted
+	Notes         string     `json:"notes,omitempty"`
+}
+
+type TimesheetDisputeRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type TimesheetSummaryResponse struct {
+	EnseignantID    uint             `json:"enseignant_id"`
+	Month           string           `json:"month"` // YYYY-MM
+	EntryCount      int              `json:"entry_count"`
+	TotalMinutes    int              `json:"total_minutes"`
+	ApprovedMinutes int              `json:"approved_minutes"`
+	PendingMinutes  int              `json:"pending_minutes"`
+	DisputedMinutes int              `json:"disputed_minutes"`
+	ApprovedHours   float64          `json:"approved_hours"`
+	Entries         []TimesheetEntry `json:"entries"`
+}
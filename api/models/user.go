@@ -53,6 +53,11 @@ type Enseignant struct {
 	Specialization string `json:"specialization"`
 	Qualifications string `json:"qualifications"`
 
+	// Bank details used for payouts, only exposed through the bank account endpoint
+	AccountHolder string `json:"-"`
+	IBAN          string `json:"-"` // Normalized, checksum validated
+	BIC           string `json:"-"`
+
 	// Relationships
 	User     User      `json:"user" gorm:"foreignKey:UserID"`
 	Missions []Mission `json:"missions,omitempty" gorm:"foreignKey:EnseignantID"`
@@ -130,16 +135,6 @@ func (f *Famille) SelectTeacher(teacherID uint) error {
 	return nil
 }
 
-func (f *Famille) ValidateSession(courseID uint) error {
-	// Logic to validate a session
-	return nil
-}
-
-func (f *Famille) DeclineSession(courseID uint) error {
-	// Logic to decline a session
-	return nil
-}
-
 // Enseignant methods
 func (e *Enseignant) CompleteProfile() error {
 	// Logic to complete profile
@@ -176,11 +171,6 @@ func (e *Enseignant) StopMission(missionID uint) error {
 	return nil
 }
 
-func (e *Enseignant) DeclareHours(courseID uint, hours float64) error {
-	// Logic to declare hours worked
-	return nil
-}
-
 func (e *Enseignant) SelectStudent(familleID uint) error {
 	// Logic to select a student/family
 	return nil
@@ -213,7 +203,7 @@ func (a *Administrator) ConsultReports() ([]Report, error) {
 }
 
 func (a *Administrator) ValidateReports(reportID uint) error {
-	// Logic to validate reports
+	// La validation (statut, historique, notifications) est réalisée par services.ValidateReport
 	return nil
 }
 
@@ -253,6 +243,18 @@ type UserUpdateRequest struct {
 	Qualifications string `json:"qualifications,omitempty"`
 }
 
+type BankAccountRequest struct {
+	AccountHolder string `json:"account_holder" binding:"required,max=70"`
+	IBAN          string `json:"iban" binding:"required"`
+	BIC           string `json:"bic,omitempty"`
+}
+
+type BankAccountResponse struct {
+	AccountHolder string `json:"account_holder"`
+	IBAN          string `json:"iban"` // Grouped by four characters
+	BIC           string `json:"bic,omitempty"`
+}
+
 // BeforeCreate hash le mot de passe avant de créer l'utilisateur
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.Password != "" {
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HourPackagePurchaseStatus represents the status of hours credited to a family wallet
+type HourPackagePurchaseStatus string
+
+const (
+	HourPackagePurchasePending   HourPackagePurchaseStatus = "pending"   // Waiting for the payment
+	HourPackagePurchaseActive    HourPackagePurchaseStatus = "active"    // Hours available
+	HourPackagePurchaseExhausted HourPackagePurchaseStatus = "exhausted" // All hours used
+	HourPackagePurchaseExpired   HourPackagePurchaseStatus = "expired"   // Unused hours lost
+	HourPackagePurchaseCancelled HourPackagePurchaseStatus = "cancelled" // Payment refunded
+)
+
+// WalletTransactionType represents the kind of movement of a family wallet
+type WalletTransactionType string
+
+const (
+	WalletTransactionPurchase     WalletTransactionType = "purchase"
+	WalletTransactionCourse       WalletTransactionType = "course"
+	WalletTransactionExpiry       WalletTransactionType = "expiry"
+	WalletTransactionAdjustment   WalletTransactionType = "adjustment"
+	WalletTransactionCancellation WalletTransactionType = "cancellation"
+)
+
+var (
+	ErrHourPackageInactive     = errors.New("cette formule d'heures n'est plus proposée")
+	ErrHourPackageUsed         = errors.New("les heures de cette formule ont déjà été utilisées ou ont expiré")
+	ErrInsufficientWalletHours = errors.New("le solde d'heures de la famille est insuffisant")
+	ErrWalletExpiryInPast      = errors.New("la date d'expiration des heures doit être dans le futur")
+)
+
+// HourPackage model - represents a package of prepaid hours offered to families
+type HourPackage struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Name         string         `json:"name" gorm:"not null"`
+	Description  string         `json:"description"`
+	Minutes      int            `json:"minutes" gorm:"not null"` // Hours sold, in minutes
+	Price        Money          `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	ValidityDays int            `json:"validity_days"` // Unused hours expire after this delay; 0 = never
+	IsActive     bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// HourPackagePurchase model - represents hours credited to a family wallet, either
+// bought as a package or granted by an administrator. Courses consume the hours
+// that expire first; the value of the hours used is prorated from the price paid.
+type HourPackagePurchase struct {
+	ID               uint                      `json:"id" gorm:"primaryKey"`
+	Name             string                    `json:"name" gorm:"not null"`
+	Minutes          int                       `json:"minutes" gorm:"not null"`
+	RemainingMinutes int                       `json:"remaining_minutes"`
+	Price            Money                     `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	RemainingValue   Money                     `json:"remaining_value" gorm:"embedded;embeddedPrefix:remaining_value_"`
+	Status           HourPackagePurchaseStatus `json:"status" gorm:"not null;index"`
+	ActivatedAt      *time.Time                `json:"activated_at"`
+	ExpiresAt        *time.Time                `json:"expires_at" gorm:"index"`
+	ExpiryNotifiedAt *time.Time                `json:"-"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+
+	// Foreign Keys
+	FamilleID     uint  `json:"famille_id" gorm:"index;not null"`
+	HourPackageID *uint `json:"hour_package_id,omitempty"` // Nil for hours granted by an administrator
+	PaymentID     *uint `json:"payment_id,omitempty" gorm:"index"`
+
+	// Relationships
+	Payment *Payment `json:"payment,omitempty" gorm:"foreignKey:PaymentID"`
+}
+
+// WalletTransaction model - represents a movement of hours in a family wallet,
+// with the value of these hours
+type WalletTransaction struct {
+	ID          uint                  `json:"id" gorm:"primaryKey"`
+	Type        WalletTransactionType `json:"type" gorm:"not null"`
+	Minutes     int                   `json:"minutes"` // Positive when hours are credited
+	Amount      Money                 `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Description string                `json:"description"`
+	CreatedAt   time.Time             `json:"created_at" gorm:"index"`
+
+	// Foreign Keys
+	FamilleID  uint  `json:"famille_id" gorm:"index;not null"`
+	PurchaseID uint  `json:"purchase_id" gorm:"index;not null"`
+	CourseID   *uint `json:"course_id,omitempty" gorm:"index"`
+	ActorID    uint  `json:"actor_id,omitempty"` // 0 for automatic actions
+}
+
+// HourPackage methods
+
+// NewPurchase prépare l'achat de la formule par une famille, en attente de paiement
+func (p *HourPackage) NewPurchase(familleID uint) HourPackagePurchase {
+	return HourPackagePurchase{
+		Name:           p.Name,
+		Minutes:        p.Minutes,
+		Price:          p.Price,
+		RemainingValue: NewMoney(0, p.Price.Currency),
+		Status:         HourPackagePurchasePending,
+		FamilleID:      familleID,
+		HourPackageID:  &p.ID,
+	}
+}
+
+// HourPackagePurchase methods
+
+// Activate crédite les heures achetées ; elles expirent après validityDays jours (jamais si 0)
+func (p *HourPackagePurchase) Activate(at time.Time, validityDays int) {
+	p.Status = HourPackagePurchaseActive
+	p.RemainingMinutes = p.Minutes
+	p.RemainingValue = p.Price
+	p.ActivatedAt = &at
+	p.ExpiresAt = nil
+	if validityDays > 0 {
+		expiresAt := at.AddDate(0, 0, validityDays)
+		p.ExpiresAt = &expiresAt
+	}
+}
+
+// IsUsable indique si des heures restent disponibles à la date donnée
+func (p *HourPackagePurchase) IsUsable(at time.Time) bool {
+	return p.Status == HourPackagePurchaseActive && p.RemainingMinutes > 0 &&
+		(p.ExpiresAt == nil || p.ExpiresAt.After(at))
+}
+
+// Consume retire des heures disponibles et retourne leur valeur, au prorata du prix ;
+// les dernières minutes emportent le reliquat pour que la valeur totale soit exacte
+func (p *HourPackagePurchase) Consume(minutes int) Money {
+	if minutes > p.RemainingMinutes {
+		minutes = p.RemainingMinutes
+	}
+	value := p.RemainingValue
+	if minutes < p.RemainingMinutes && p.Minutes > 0 {
+		value = NewMoney(p.Price.Cents*int64(minutes)/int64(p.Minutes), p.Price.Currency)
+	}
+	p.RemainingMinutes -= minutes
+	p.RemainingValue = p.RemainingValue.Sub(value)
+	if p.RemainingMinutes == 0 {
+		p.Status = HourPackagePurchaseExhausted
+	}
+	return value
+}
+
+// Close retire toutes les heures restantes (expiration ou annulation) et retourne
+// les minutes et la valeur perdues
+func (p *HourPackagePurchase) Close(status HourPackagePurchaseStatus) (int, Money) {
+	minutes, value := p.RemainingMinutes, p.RemainingValue
+	p.RemainingMinutes = 0
+	p.RemainingValue = NewMoney(0, p.RemainingValue.Currency)
+	p.Status = status
+	return minutes, value
+}
+
+// Request/Response structures
+type HourPackageRequest struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description"`
+	Minutes      int    `json:"minutes" binding:"required,min=30"`
+	Price        Money  `json:"price"`
+	ValidityDays int    `json:"validity_days" binding:"min=0"`
+	IsActive     *bool  `json:"is_active,omitempty"`
+}
+
+type HourPackagePurchaseRequest struct {
+	HourPackageID uint `json:"hour_package_id" binding:"required"`
+}
+
+// WalletAdjustmentRequest grants (positive minutes) or removes (negative minutes) hours
+type WalletAdjustmentRequest struct {
+	Minutes   int        `json:"minutes" binding:"required"`
+	Reason    string     `json:"reason" binding:"required,max=255"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Granted hours only; never expire when omitted
+}
+
+type WalletBalanceResponse struct {
+	FamilleID     uint                  `json:"famille_id"`
+	Minutes       int                   `json:"minutes"`        // Hours available
+	Value         Money                 `json:"value"`          // Prepaid value of the hours available
+	AdvanceCredit Money                 `json:"advance_credit"` // Advances not yet allocated to an invoice
+	LowBalance    bool                  `json:"low_balance"`
+	NextExpiry    *time.Time            `json:"next_expiry,omitempty"`
+	Purchases     []HourPackagePurchase `json:"purchases"` // Active and pending purchases
+}
+
+// WalletStatementLine is a movement of the wallet with the running balance
+type WalletStatementLine struct {
+	WalletTransaction
+	BalanceMinutes int   `json:"balance_minutes"`
+	BalanceValue   Money `json:"balance_value"`
+}
+
+type WalletStatementResponse struct {
+	FamilleID      uint                  `json:"famille_id"`
+	From           time.Time             `json:"from"`
+	To             time.Time             `json:"to"` // Exclusive
+	OpeningMinutes int                   `json:"opening_minutes"`
+	OpeningValue   Money                 `json:"opening_value"`
+	Lines          []WalletStatementLine `json:"lines"`
+	ClosingMinutes int                   `json:"closing_minutes"`
+	ClosingValue   Money                 `json:"closing_value"`
+}
@@ -38,6 +38,12 @@ func SetupRoutes(router *gin.Engine) {
 			auth.POST("/refresh", controllers.RefreshToken)
 		}
 
+		// Flux iCalendar (public, protégé par le jeton secret)
+		v1.GET("/calendar/feeds/:token", controllers.GetCalendarFeedICS)
+
+		// Webhooks du prestataire de paiement (public, protégé par la signature)
+		v1.POST("/payments/webhook", controllers.PaymentWebhook)
+
 		// Routes protégées (nécessitent une authentification)
 		protected := v1.Group("/")
 		protected.Use(middleware.AuthMiddleware())
@@ -46,6 +52,10 @@ func SetupRoutes(router *gin.Engine) {
 			protected.PUT("/profile", controllers.UpdateProfile)
 			protected.POST("/auth/logout", controllers.Logout)
 
+			// Abonnement calendrier de l'utilisateur connecté
+			protected.GET("/calendar/feed", controllers.GetCalendarFeed)
+			protected.POST("/calendar/feed/regenerate", controllers.RegenerateCalendarFeed)
+
 			// Routes utilisateurs (accessibles à tous les utilisateurs authentifiés pour leur propre profil)
 			users := protected.Group("/users")
 			{
@@ -107,6 +117,16 @@ func SetupRoutes(router *gin.Engine) {
 				familles.GET("/:id/payments", controllers.GetFamillePayments)
 				familles.POST("/:id/reviews", controllers.PostFamilleReview)
 				familles.GET("/:id/options", controllers.GetFamilleOptions)
+				familles.GET("/:id/skill-progression", controllers.GetFamilleSkillProgression)
+				familles.GET("/:id/mandates", controllers.ListFamilleMandates)
+				familles.POST("/:id/mandates", controllers.CreateFamilleMandate)
+				familles.POST("/:id/mandates/:mandate_id/revoke", controllers.RevokeFamilleMandate)
+				familles.GET("/:id/wallet", controllers.GetFamilleWallet)
+				familles.GET("/:id/wallet/statement", controllers.GetFamilleWalletStatement)
+				familles.POST("/:id/wallet/purchases", controllers.PurchaseFamilleHourPackage)
+				familles.POST("/:id/wallet/adjustments", middleware.RequireAdmin(), controllers.AdjustFamilleWallet)
+				familles.GET("/:id/referrals", controllers.ListFamilleReferrals)
+				familles.POST("/:id/referrals", controllers.InviteFamilleReferral)
 			}
 
 			// Missions routes
@@ -120,6 +140,7 @@ func SetupRoutes(router *gin.Engine) {
 
 				missions.GET("/:id/courses", controllers.GetMissionCourses)
 				missions.GET("/:id/reports", controllers.GetMissionReports)
+				missions.GET("/:id/skill-progression", controllers.GetMissionSkillProgression)
 				missions.GET("/:id/payments", controllers.GetMissionPayments)
 
 				missions.PUT("/:id/stop", controllers.StopMission)
@@ -138,8 +159,203 @@ func SetupRoutes(router *gin.Engine) {
 				courses.PUT("/:id/schedule", controllers.ScheduleCourse)
 				courses.PUT("/:id/cancel", controllers.CancelCourse)
 				courses.PUT("/:id/complete", controllers.CompleteCourse)
+				courses.PUT("/:id/decline", controllers.DeclineCourse)
 				courses.POST("/:id/declare", controllers.DeclareCourse)
 				courses.GET("/:id/payments", controllers.GetCoursePayments)
+				courses.GET("/:id/ics", controllers.ExportCourseICS)
+				courses.GET("/:id/timesheet", controllers.GetCourseTimesheet)
+				courses.GET("/:id/reschedule-requests", controllers.GetCourseRescheduleRequests)
+				courses.POST("/:id/reschedule-requests", controllers.CreateRescheduleRequest)
+				courses.GET("/:id/cancellation-preview", controllers.GetCancellationPreview)
+				courses.POST("/:id/check-in", controllers.CheckInCourse)
+				courses.POST("/:id/check-out", controllers.CheckOutCourse)
+				courses.GET("/:id/attendance", controllers.GetCourseAttendance)
+			}
+
+			// Timesheets routes
+			timesheets := protected.Group("/timesheets")
+			{
+				timesheets.GET("", controllers.ListTimesheets)
+				timesheets.PUT("/:id/approve", controllers.ApproveTimesheet)
+				timesheets.PUT("/:id/dispute", controllers.DisputeTimesheet)
+			}
+
+			// Payments routes
+			payments := protected.Group("/payments")
+			{
+				payments.GET("", controllers.ListPayments)
+				payments.POST("", controllers.CreatePayment)
+				payments.GET("/stats", controllers.GetPaymentStats)
+				payments.GET("/:id", controllers.GetPaymentByID)
+				payments.POST("/:id/process", controllers.ProcessPayment)
+				payments.POST("/:id/refund", middleware.RequireAdmin(), controllers.RefundPayment)
+			}
+
+			// Advances routes (acomptes des familles et avances aux enseignants)
+			advances := protected.Group("/advances")
+			{
+				advances.GET("", controllers.ListAdvances)
+				advances.POST("", middleware.RequireAdmin(), controllers.CreateAdvance)
+				advances.GET("/:id", controllers.GetAdvanceByID)
+			}
+
+			// Invoices routes
+			invoices := protected.Group("/invoices")
+			{
+				invoices.GET("", controllers.ListInvoices)
+				invoices.POST("", middleware.RequireAdmin(), controllers.CreateInvoice)
+				invoices.POST("/sepa-debit-export", middleware.RequireAdmin(), controllers.ExportInvoiceDebits)
+				invoices.GET("/:id", controllers.GetInvoiceByID)
+				invoices.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateInvoice)
+				invoices.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteInvoice)
+				invoices.GET("/:id/pdf", controllers.GetInvoicePDF)
+				invoices.POST("/:id/issue", middleware.RequireAdmin(), controllers.IssueInvoice)
+				invoices.POST("/:id/credit-note", middleware.RequireAdmin(), controllers.CreateCreditNote)
+			}
+
+			// SEPA direct debit files routes (prélèvements des factures et avis de la banque)
+			sepaDebits := protected.Group("/sepa-debits")
+			{
+				sepaDebits.GET("", middleware.RequireAdmin(), controllers.ListSEPADebits)
+				sepaDebits.POST("/returns", middleware.RequireAdmin(), controllers.ImportSEPADebitReturns)
+				sepaDebits.GET("/:id/xml", middleware.RequireAdmin(), controllers.GetSEPADebitXML)
+			}
+
+			// Tax certificates routes (attestations fiscales annuelles des familles)
+			taxCertificates := protected.Group("/tax-certificates")
+			{
+				taxCertificates.GET("", controllers.ListTaxCertificates)
+				taxCertificates.POST("/generate", middleware.RequireAdmin(), controllers.GenerateTaxCertificates)
+				taxCertificates.POST("/publish", middleware.RequireAdmin(), controllers.PublishTaxCertificates)
+				taxCertificates.GET("/:id", controllers.GetTaxCertificateByID)
+				taxCertificates.GET("/:id/pdf", controllers.GetTaxCertificatePDF)
+			}
+
+			// Ledger routes (comptabilité en partie double, administrateurs uniquement)
+			ledger := protected.Group("/ledger")
+			ledger.Use(middleware.RequireAdmin())
+			{
+				ledger.GET("/accounts", controllers.ListLedgerAccounts)
+				ledger.PUT("/accounts/:id", controllers.UpdateLedgerAccount)
+				ledger.GET("/entries", controllers.ListLedgerEntries)
+				ledger.GET("/trial-balance", controllers.GetLedgerTrialBalance)
+				ledger.GET("/statement", controllers.GetLedgerAccountStatement)
+				ledger.GET("/fec", controllers.ExportLedgerFEC)
+			}
+
+			// Reports routes
+			reports := protected.Group("/reports")
+			{
+				reports.GET("", controllers.ListReports)
+				reports.POST("", controllers.CreateReport)
+				reports.GET("/overdue", middleware.RequireAdmin(), controllers.ListOverdueReports)
+				reports.GET("/:id", controllers.GetReportByID)
+				reports.PUT("/:id", controllers.UpdateReport)
+				reports.DELETE("/:id", controllers.DeleteReport)
+
+				reports.PUT("/:id/submit", controllers.SubmitReport)
+				reports.PUT("/:id/validate", middleware.RequireAdmin(), controllers.ValidateReport)
+				reports.PUT("/:id/reject", middleware.RequireAdmin(), controllers.RejectReport)
+				reports.GET("/:id/revisions", controllers.GetReportRevisions)
+			}
+
+			// Report templates routes
+			templates := protected.Group("/report-templates")
+			{
+				templates.GET("", controllers.ListReportTemplates)
+				templates.GET("/:id", controllers.GetReportTemplateByID)
+				templates.POST("", middleware.RequireAdmin(), controllers.CreateReportTemplate)
+				templates.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateReportTemplate)
+				templates.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteReportTemplate)
+			}
+
+			// Skills routes
+			skills := protected.Group("/skills")
+			{
+				skills.GET("", controllers.ListSkills)
+				skills.POST("", middleware.RequireAdmin(), controllers.CreateSkill)
+				skills.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateSkill)
+				skills.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteSkill)
+			}
+
+			// Attendances routes
+			attendances := protected.Group("/attendances")
+			{
+				attendances.GET("", middleware.RequireAdmin(), controllers.ListAttendances)
+				attendances.GET("/:id", controllers.GetAttendanceByID)
+				attendances.PUT("/:id/review", middleware.RequireAdmin(), controllers.ReviewAttendance)
+			}
+
+			// Reschedule requests routes
+			reschedules := protected.Group("/reschedule-requests")
+			{
+				reschedules.GET("/:id", controllers.GetRescheduleRequestByID)
+				reschedules.PUT("/:id/accept", controllers.AcceptRescheduleRequest)
+				reschedules.PUT("/:id/reject", controllers.RejectRescheduleRequest)
+				reschedules.PUT("/:id/counter", controllers.CounterRescheduleRequest)
+				reschedules.PUT("/:id/cancel", controllers.CancelRescheduleRequest)
+			}
+
+			// Cancellation policies routes
+			policies := protected.Group("/cancellation-policies")
+			{
+				policies.GET("", controllers.ListCancellationPolicies)
+				policies.POST("", middleware.RequireAdmin(), controllers.CreateCancellationPolicy)
+				policies.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateCancellationPolicy)
+				policies.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteCancellationPolicy)
+			}
+
+			// Hour packages routes (forfaits d'heures prépayées)
+			hourPackages := protected.Group("/hour-packages")
+			{
+				hourPackages.GET("", controllers.ListHourPackages)
+				hourPackages.POST("", middleware.RequireAdmin(), controllers.CreateHourPackage)
+				hourPackages.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateHourPackage)
+				hourPackages.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteHourPackage)
+			}
+
+			// Pricing routes (règles de tarification et devis)
+			pricing := protected.Group("/pricing")
+			{
+				pricing.POST("/quote", controllers.QuotePrice)
+				pricing.GET("/rules", middleware.RequireAdmin(), controllers.ListPricingRules)
+				pricing.POST("/rules", middleware.RequireAdmin(), controllers.CreatePricingRule)
+				pricing.PUT("/rules/:id", middleware.RequireAdmin(), controllers.UpdatePricingRule)
+				pricing.DELETE("/rules/:id", middleware.RequireAdmin(), controllers.DeletePricingRule)
+			}
+
+			// Promo codes routes (campagnes d'acquisition)
+			promoCodes := protected.Group("/promo-codes")
+			{
+				promoCodes.POST("/check", controllers.CheckPromoCode)
+				promoCodes.GET("", middleware.RequireAdmin(), controllers.ListPromoCodes)
+				promoCodes.POST("", middleware.RequireAdmin(), controllers.CreatePromoCode)
+				promoCodes.GET("/report", middleware.RequireAdmin(), controllers.GetPromotionReport)
+				promoCodes.PUT("/:id", middleware.RequireAdmin(), controllers.UpdatePromoCode)
+				promoCodes.DELETE("/:id", middleware.RequireAdmin(), controllers.DeletePromoCode)
+				promoCodes.GET("/:id/redemptions", middleware.RequireAdmin(), controllers.GetPromoCodeRedemptions)
+			}
+
+			// Referrals routes (parrainage entre familles)
+			referrals := protected.Group("/referrals")
+			{
+				referrals.GET("", middleware.RequireAdmin(), controllers.ListReferrals)
+			}
+
+			// Notifications routes
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", controllers.ListNotifications)
+				notifications.PUT("/read-all", controllers.MarkAllNotificationsRead)
+				notifications.PUT("/:id/read", controllers.MarkNotificationRead)
+			}
+
+			// Disputes routes
+			disputes := protected.Group("/disputes")
+			{
+				disputes.GET("", middleware.RequireAdmin(), controllers.ListDisputes)
+				disputes.GET("/:id", controllers.GetDisputeByID)
+				disputes.PUT("/:id/resolve", middleware.RequireAdmin(), controllers.ResolveDispute)
 			}
 
 			// Enseignants routes
@@ -158,23 +374,57 @@ func SetupRoutes(router *gin.Engine) {
 				enseignants.GET("/:id/payments", controllers.GetEnseignantPayments)
 				enseignants.GET("/:id/reports", controllers.GetEnseignantReports)
 				enseignants.GET("/:id/options", controllers.GetEnseignantOptions)
+				enseignants.GET("/:id/timesheets", controllers.GetEnseignantTimesheets)
+				enseignants.GET("/:id/applications", controllers.GetEnseignantApplications)
+				enseignants.GET("/:id/bank-account", controllers.GetEnseignantBankAccount)
+				enseignants.PUT("/:id/bank-account", controllers.UpdateEnseignantBankAccount)
 
 				enseignants.GET("/nearby", controllers.GetEnseignantsNearby)
 			}
 
+			// Payouts routes (relevés de paiement des enseignants)
+			payouts := protected.Group("/payouts")
+			{
+				payouts.GET("", middleware.RequireAdmin(), controllers.ListPayouts)
+				payouts.POST("", middleware.RequireAdmin(), controllers.LockPayouts)
+				payouts.POST("/preview", middleware.RequireAdmin(), controllers.PreviewPayouts)
+				payouts.POST("/sepa-export", middleware.RequireAdmin(), controllers.ExportPayoutTransfers)
+				payouts.GET("/:id", controllers.GetPayoutByID)
+				payouts.POST("/:id/pay", middleware.RequireAdmin(), controllers.MarkPayoutPaid)
+			}
+
+			// SEPA transfer files routes (fichiers de virements des relevés)
+			sepaTransfers := protected.Group("/sepa-transfers")
+			{
+				sepaTransfers.GET("", middleware.RequireAdmin(), controllers.ListSEPATransfers)
+				sepaTransfers.GET("/:id/xml", middleware.RequireAdmin(), controllers.GetSEPATransferXML)
+			}
+
 			// Offers routes
 			offers := protected.Group("/offers")
 			{
 				offers.GET("", controllers.ListOffers)
-				offers.POST("", controllers.CreateOffer)
+				offers.POST("", middleware.RequireAdmin(), controllers.CreateOffer)
 				offers.GET("/:id", controllers.GetOfferByID)
-				offers.PUT("/:id", controllers.UpdateOffer)
-				offers.DELETE("/:id", controllers.DeleteOffer)
+				offers.PUT("/:id", middleware.RequireAdmin(), controllers.UpdateOffer)
+				offers.DELETE("/:id", middleware.RequireAdmin(), controllers.DeleteOffer)
 
 				offers.GET("/:id/options", controllers.GetOfferOptions)
-				offers.PUT("/:id/close", controllers.CloseOffer)
+				offers.PUT("/:id/close", middleware.RequireAdmin(), controllers.CloseOffer)
+				offers.PUT("/:id/publish", middleware.RequireAdmin(), controllers.PublishOffer)
+				offers.PUT("/:id/unpublish", middleware.RequireAdmin(), controllers.UnpublishOffer)
+				offers.PUT("/:id/schedule", middleware.RequireAdmin(), controllers.ScheduleOfferPublication)
+				offers.GET("/:id/revisions", middleware.RequireAdmin(), controllers.GetOfferRevisions)
 				offers.GET("/active", controllers.ListActiveOffers)
 				offers.GET("/search", controllers.SearchOffers)
+
+				// Candidatures des enseignants
+				offers.POST("/:id/applications", controllers.ApplyToOffer)
+				offers.PUT("/:id/applications/withdraw", controllers.WithdrawOfferApplication)
+				offers.GET("/:id/applications", middleware.RequireAdmin(), controllers.ListOfferApplications)
+				offers.PUT("/:id/applications/:enseignant_id/shortlist", middleware.RequireAdmin(), controllers.ShortlistOfferApplication)
+				offers.PUT("/:id/applications/:enseignant_id/reject", middleware.RequireAdmin(), controllers.RejectOfferApplication)
+				offers.PUT("/:id/applications/:enseignant_id/accept", middleware.RequireAdmin(), controllers.AcceptOfferApplication)
 			}
 
 			// Options routes
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdvanceRecipient = errors.New("une avance ne peut concerner qu'une famille ou un enseignant")
+	ErrAdvanceMission   = errors.New("la mission ne concerne pas le bénéficiaire de l'avance")
+	ErrAdvanceAllocated = errors.New("cette avance a déjà été imputée et ne peut plus être remboursée")
+)
+
+// openAdvance est une avance dont une partie reste à imputer
+type openAdvance struct {
+	payment   models.Payment
+	remaining models.Money
+}
+
+// RecordAdvance enregistre une avance réglée hors prestataire : acompte versé par
+// une famille ou avance consentie à un enseignant, éventuellement réservée à une mission
+func RecordAdvance(db *gorm.DB, req models.AdvanceCreateRequest) (*models.Payment, error) {
+	var user models.User
+	if err := db.First(&user, req.UserID).Error; err != nil {
+		return nil, ErrAdvanceRecipient
+	}
+	if user.Role != models.RoleFamille && user.Role != models.RoleEnseignant {
+		return nil, ErrAdvanceRecipient
+	}
+	if req.MissionID != nil {
+		var mission models.Mission
+		if err := db.First(&mission, *req.MissionID).Error; err != nil {
+			return nil, ErrAdvanceMission
+		}
+		if mission.FamilleID != user.ID && mission.EnseignantID != user.ID {
+			return nil, ErrAdvanceMission
+		}
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != nil {
+		paidAt = *req.PaidAt
+	}
+	description := req.Description
+	if description == "" {
+		description = "Avance du " + paidAt.In(utils.CalendarLocation()).Format("02/01/2006")
+	}
+	payment := models.Payment{
+		Amount:      req.Amount,
+		PaymentDate: paidAt,
+		Status:      models.PaymentStatusCompleted,
+		Type:        models.PaymentTypeAdvance,
+		Description: description,
+		Provider:    "manual",
+		UserID:      user.ID,
+		MissionID:   req.MissionID,
+	}
+	message := fmt.Sprintf("Votre acompte de %s a été enregistré", payment.Amount)
+	if user.Role == models.RoleEnseignant {
+		message = fmt.Sprintf("Une avance de %s vous a été versée", payment.Amount)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Course").Create(&payment).Error; err != nil {
+			return err
+		}
+		if err := postPaymentEntry(tx, &payment); err != nil {
+			return err
+		}
+		return Notify(tx, user.ID, models.NotificationAdvanceRecorded, "Avance enregistrée", message, advanceLink(payment.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// allocatedAdvances retourne, par avance, le montant déjà imputé (hors imputations libérées)
+func allocatedAdvances(tx *gorm.DB, paymentIDs []uint) (map[uint]int64, error) {
+	allocated := make(map[uint]int64, len(paymentIDs))
+	if len(paymentIDs) == 0 {
+		return allocated, nil
+	}
+	var rows []struct {
+		PaymentID uint
+		Cents     int64
+	}
+	err := tx.Model(&models.AdvanceAllocation{}).
+		Select("payment_id, SUM(amount_cents) AS cents").
+		Where("payment_id IN ? AND released_at IS NULL", paymentIDs).
+		Group("payment_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		allocated[row.PaymentID] = row.Cents
+	}
+	return allocated, nil
+}
+
+// openAdvances retourne, de la plus ancienne à la plus récente, les avances
+// encaissées d'un utilisateur réglées avant la date donnée dont il reste un solde.
+// Les avances réservées à une mission ne sont retenues que si elle figure dans missionIDs.
+func openAdvances(tx *gorm.DB, userID uint, currency models.Currency, before time.Time, missionIDs map[uint]bool) ([]openAdvance, error) {
+	var payments []models.Payment
+	if err := tx.Where("user_id = ? AND type = ? AND status = ? AND amount_currency = ? AND payment_date <= ?",
+		userID, models.PaymentTypeAdvance, models.PaymentStatusCompleted, currency, before).
+		Order("payment_date, id").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(payments))
+	for i, payment := range payments {
+		ids[i] = payment.ID
+	}
+	allocated, err := allocatedAdvances(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var open []openAdvance
+	for _, payment := range payments {
+		if payment.MissionID != nil && !missionIDs[*payment.MissionID] {
+			continue
+		}
+		remaining := payment.Amount.Sub(models.NewMoney(allocated[payment.ID], currency))
+		if remaining.IsPositive() {
+			open = append(open, openAdvance{payment: payment, remaining: remaining})
+		}
+	}
+	return open, nil
+}
+
+// splitAdvances répartit un montant dû sur les avances ouvertes, dans l'ordre, et
+// appelle apply pour chaque part imputée
+func splitAdvances(advances []openAdvance, due models.Money, apply func(advance openAdvance, amount models.Money) error) error {
+	for _, advance := range advances {
+		if !due.IsPositive() {
+			break
+		}
+		amount := advance.remaining
+		if amount.Cents > due.Cents {
+			amount = due
+		}
+		if err := apply(advance, amount); err != nil {
+			return err
+		}
+		due = due.Sub(amount)
+	}
+	return nil
+}
+
+// allocateInvoiceAdvances impute les acomptes de la famille sur une facture en cours
+// d'émission et en reporte le total sur la facture
+func allocateInvoiceAdvances(tx *gorm.DB, invoice *models.Invoice) error {
+	invoice.AdvanceAmount = models.NewMoney(0, invoice.Currency)
+	if invoice.Kind != models.InvoiceKindInvoice || !invoice.TotalTTC.IsPositive() {
+		return nil
+	}
+	missionIDs := map[uint]bool{}
+	if invoice.MissionID != nil {
+		missionIDs[*invoice.MissionID] = true
+	}
+	var courseIDs []uint
+	for _, line := range invoice.Lines {
+		if line.CourseID != nil {
+			courseIDs = append(courseIDs, *line.CourseID)
+		}
+	}
+	if len(courseIDs) > 0 {
+		var ids []uint
+		if err := tx.Model(&models.Course{}).Where("id IN ?", courseIDs).Distinct().Pluck("mission_id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			missionIDs[id] = true
+		}
+	}
+
+	advances, err := openAdvances(tx, invoice.FamilleID, invoice.Currency, *invoice.IssueDate, missionIDs)
+	if err != nil {
+		return err
+	}
+	return splitAdvances(advances, invoice.TotalTTC, func(advance openAdvance, amount models.Money) error {
+		invoiceID := invoice.ID
+		allocation := models.AdvanceAllocation{
+			PaymentID: advance.payment.ID,
+			Amount:    amount,
+			Reference: "Facture " + *invoice.Number,
+			InvoiceID: &invoiceID,
+		}
+		if err := tx.Create(&allocation).Error; err != nil {
+			return err
+		}
+		invoice.AdvanceAmount = invoice.AdvanceAmount.Add(amount)
+		return nil
+	})
+}
+
+// releaseInvoiceAdvances libère les acomptes imputés sur une facture annulée par un avoir
+func releaseInvoiceAdvances(tx *gorm.DB, invoiceID uint, at time.Time) error {
+	return tx.Model(&models.AdvanceAllocation{}).
+		Where("invoice_id = ? AND released_at IS NULL", invoiceID).
+		Update("released_at", at).Error
+}
+
+// deductAdvances ajoute au relevé les avances versées à l'enseignant qui restent à
+// imputer, de la plus ancienne à la plus récente, dans la limite du montant brut.
+// Une avance réservée à une mission n'est déduite que des cours de cette mission.
+func deductAdvances(db *gorm.DB, payout *models.Payout) error {
+	missionIDs := map[uint]bool{}
+	for _, line := range payout.Lines {
+		if line.MissionID != nil {
+			missionIDs[*line.MissionID] = true
+		}
+	}
+	advances, err := openAdvances(db, payout.EnseignantID, payout.Currency, payout.PeriodEnd, missionIDs)
+	if err != nil {
+		return err
+	}
+	return splitAdvances(advances, payout.GrossAmount, func(advance openAdvance, amount models.Money) error {
+		paymentID := advance.payment.ID
+		description := fmt.Sprintf("Avance du %s", advance.payment.PaymentDate.In(utils.CalendarLocation()).Format("02/01/2006"))
+		if amount.Cents < advance.remaining.Cents {
+			description += fmt.Sprintf(" (part déduite, reste %s)", advance.remaining.Sub(amount))
+		}
+		payout.Lines = append(payout.Lines, models.PayoutLine{
+			Kind:        models.PayoutLineAdvance,
+			Description: description,
+			Amount:      amount.Neg(),
+			PaymentID:   &paymentID,
+		})
+		return nil
+	})
+}
+
+// recordPayoutAllocations enregistre l'imputation des avances déduites d'un relevé verrouillé
+func recordPayoutAllocations(tx *gorm.DB, payout *models.Payout) error {
+	for _, line := range payout.Lines {
+		if line.Kind != models.PayoutLineAdvance || line.PaymentID == nil {
+			continue
+		}
+		payoutID := payout.ID
+		if err := tx.Create(&models.AdvanceAllocation{
+			PaymentID: *line.PaymentID,
+			Amount:    line.Amount.Neg(),
+			Reference: fmt.Sprintf("Relevé n° %d %s", payout.ID, payoutPeriodLabel(payout)),
+			PayoutID:  &payoutID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAdvanceRefundable refuse le remboursement d'une avance déjà imputée
+func checkAdvanceRefundable(db *gorm.DB, payment *models.Payment) error {
+	if payment.Type != models.PaymentTypeAdvance {
+		return nil
+	}
+	var count int64
+	if err := db.Model(&models.AdvanceAllocation{}).
+		Where("payment_id = ? AND released_at IS NULL", payment.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAdvanceAllocated
+	}
+	return nil
+}
+
+// AdvanceReports construit le rapprochement des avances sélectionnées : montant
+// imputé, solde restant et détail des imputations (factures ou relevés)
+func AdvanceReports(db *gorm.DB, query *gorm.DB) ([]models.AdvanceReport, error) {
+	var advances []models.Payment
+	if err := query.Where("type = ?", models.PaymentTypeAdvance).
+		Order("payment_date DESC, id DESC").Find(&advances).Error; err != nil {
+		return nil, err
+	}
+	reports := make([]models.AdvanceReport, len(advances))
+	if len(advances) == 0 {
+		return reports, nil
+	}
+	ids := make([]uint, len(advances))
+	for i, advance := range advances {
+		ids[i] = advance.ID
+	}
+	var allocations []models.AdvanceAllocation
+	if err := db.Where("payment_id IN ?", ids).Order("created_at, id").Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+	byAdvance := map[uint][]models.AdvanceAllocation{}
+	for _, allocation := range allocations {
+		byAdvance[allocation.PaymentID] = append(byAdvance[allocation.PaymentID], allocation)
+	}
+	for i, advance := range advances {
+		report := models.AdvanceReport{
+			Advance:     advance,
+			Allocated:   models.NewMoney(0, advance.Amount.Currency),
+			Allocations: []models.AdvanceAllocation{},
+		}
+		for _, allocation := range byAdvance[advance.ID] {
+			report.Allocations = append(report.Allocations, allocation)
+			if allocation.IsActive() {
+				report.Allocated = report.Allocated.Add(allocation.Amount)
+			}
+		}
+		report.Remaining = advance.Amount.Sub(report.Allocated)
+		if advance.Status != models.PaymentStatusCompleted {
+			report.Remaining = models.NewMoney(0, advance.Amount.Currency)
+		}
+		reports[i] = report
+	}
+	return reports, nil
+}
+
+func advanceLink(id uint) string {
+	return fmt.Sprintf("/api/v1/advances/%d", id)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+// checkInEarliest est l'avance maximale autorisée pour pointer avant le début du cours
+const checkInEarliest = time.Hour
+
+var (
+	ErrCheckInNotAllowed = errors.New("seul un cours planifié peut faire l'objet d'un pointage d'arrivée")
+	ErrCheckInTooEarly   = errors.New("le pointage d'arrivée n'est possible qu'une heure avant le début du cours")
+	ErrAlreadyCheckedIn  = errors.New("le pointage d'arrivée a déjà été enregistré")
+	ErrNotCheckedIn      = errors.New("aucun pointage d'arrivée en cours pour ce cours")
+)
+
+// GeofenceRadius retourne le rayon (en mètres) autour de l'adresse du cours dans lequel
+// le pointage est considéré comme sur place (ATTENDANCE_GEOFENCE_RADIUS_METERS, 200 par défaut)
+func GeofenceRadius() float64 {
+	radius := 200.0
+	if env := os.Getenv("ATTENDANCE_GEOFENCE_RADIUS_METERS"); env != "" {
+		if r, err := strconv.ParseFloat(env, 64); err == nil && r > 0 {
+			radius = r
+		}
+	}
+	return radius
+}
+
+// LateTolerance retourne le retard toléré avant signalement (ATTENDANCE_LATE_TOLERANCE_MINUTES, 10 par défaut)
+func LateTolerance() time.Duration {
+	minutes := 10
+	if env := os.Getenv("ATTENDANCE_LATE_TOLERANCE_MINUTES"); env != "" {
+		if m, err := strconv.Atoi(env); err == nil && m >= 0 {
+			minutes = m
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// distanceToCourse calcule la distance entre une position et l'adresse du cours.
+// Retourne nil si la position ou les coordonnées de l'adresse sont inconnues.
+func distanceToCourse(tx *gorm.DB, course *models.Course, latitude, longitude *float64) *float64 {
+	if latitude == nil || longitude == nil || course.AddressID == 0 {
+		return nil
+	}
+	var address models.Address
+	if err := tx.First(&address, course.AddressID).Error; err != nil {
+		return nil
+	}
+	if address.Latitude == 0 && address.Longitude == 0 {
+		return nil
+	}
+	distance := math.Round(utils.DistanceMeters(*latitude, *longitude, address.Latitude, address.Longitude))
+	return &distance
+}
+
+// CheckIn enregistre l'arrivée de l'enseignant : le cours passe en cours et les
+// arrivées tardives ou hors zone sont signalées aux administrateurs
+func CheckIn(db *gorm.DB, course *models.Course, actorID uint, req models.AttendanceCheckRequest, now time.Time) (*models.CourseAttendance, error) {
+	var attendance models.CourseAttendance
+	if err := db.Where("course_id = ?", course.ID).First(&attendance).Error; err == nil && attendance.CheckInAt != nil {
+		return nil, ErrAlreadyCheckedIn
+	}
+	if course.Status != models.CourseStatusScheduled {
+		return nil, ErrCheckInNotAllowed
+	}
+	if now.Before(course.ScheduledTime.Add(-checkInEarliest)) {
+		return nil, ErrCheckInTooEarly
+	}
+
+	attendance.CourseID = course.ID
+	attendance.EnseignantID = course.EnseignantID
+	attendance.CheckInAt = &now
+	attendance.CheckInLatitude = req.Latitude
+	attendance.CheckInLongitude = req.Longitude
+	attendance.CheckInDistance = distanceToCourse(db, course, req.Latitude, req.Longitude)
+	if late := now.Sub(course.ScheduledTime); late > LateTolerance() {
+		attendance.LateMinutes = int(late.Minutes())
+		attendance.Flag(fmt.Sprintf("arrivée en retard de %d min", attendance.LateMinutes))
+	}
+	if d := attendance.CheckInDistance; d != nil && *d > GeofenceRadius() {
+		attendance.Flag(fmt.Sprintf("arrivée hors zone (%.0f m de l'adresse)", *d))
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&attendance).Error; err != nil {
+			return err
+		}
+		course.Status = models.CourseStatusInProgress
+		if err := tx.Save(course).Error; err != nil {
+			return err
+		}
+		if err := RecordCourseEvent(tx, course.ID, actorID, models.CourseEventCheckedIn, "Arrivée de l'enseignant pointée"); err != nil {
+			return err
+		}
+		return notifyAttendanceFlagged(tx, &attendance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
+
+// CheckOut enregistre le départ de l'enseignant : la durée mesurée pré-remplit la
+// feuille de temps soumise à l'approbation de la famille. Le cours reste en cours
+// jusqu'à cette approbation, qui le fait passer à l'état terminé.
+func CheckOut(db *gorm.DB, course *models.Course, actorID uint, req models.AttendanceCheckRequest, now time.Time) (*models.CourseAttendance, *models.TimesheetEntry, error) {
+	var attendance models.CourseAttendance
+	if err := db.Where("course_id = ?", course.ID).First(&attendance).Error; err != nil ||
+		attendance.CheckInAt == nil || attendance.CheckOutAt != nil || course.Status != models.CourseStatusInProgress {
+		return nil, nil, ErrNotCheckedIn
+	}
+
+	previousReasons := attendance.FlagReasons
+	attendance.CheckOut(now, req.Latitude, req.Longitude, distanceToCourse(db, course, req.Latitude, req.Longitude))
+	if d := attendance.CheckOutDistance; d != nil && *d > GeofenceRadius() {
+		attendance.Flag(fmt.Sprintf("départ hors zone (%.0f m de l'adresse)", *d))
+	}
+
+	var entry models.TimesheetEntry
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&attendance).Error; err != nil {
+			return err
+		}
+		if err := RecordCourseEvent(tx, course.ID, actorID, models.CourseEventCheckedOut,
+			fmt.Sprintf("Départ de l'enseignant pointé : %s mesurées", formatMinutes(attendance.MeasuredMinutes))); err != nil {
+			return err
+		}
+
+		if attendance.FlagReasons != previousReasons {
+			if err := notifyAttendanceFlagged(tx, &attendance); err != nil {
+				return err
+			}
+		}
+
+		// Les heures déjà approuvées ou contestées ne sont pas écrasées
+		err := tx.Where("course_id = ?", course.ID).First(&entry).Error
+		if err == nil && entry.Status != models.TimesheetStatusPending {
+			entry = models.TimesheetEntry{}
+			return nil
+		}
+		if attendance.MeasuredMinutes > 0 {
+			entry.CourseID = course.ID
+			entry.EnseignantID = course.EnseignantID
+			entry.FamilleID = course.FamilleID
+			entry.Declare(*attendance.CheckInAt, now, "Pré-rempli depuis le pointage", now.Add(TimesheetApprovalDelay()))
+			return tx.Save(&entry).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if entry.ID == 0 {
+		return &attendance, nil, nil
+	}
+	return &attendance, &entry, nil
+}
+
+// notifyAttendanceFlagged prévient les administrateurs d'un pointage à vérifier
+func notifyAttendanceFlagged(tx *gorm.DB, attendance *models.CourseAttendance) error {
+	if !attendance.Flagged {
+		return nil
+	}
+	return NotifyAdmins(tx, models.NotificationAttendanceFlagged, "Pointage à vérifier",
+		fmt.Sprintf("Cours %d : %s", attendance.CourseID, attendance.FlagReasons),
+		fmt.Sprintf("/api/v1/attendances/%d", attendance.ID))
+}
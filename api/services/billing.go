@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+// CourseHourlyRate retourne le taux horaire applicable à un cours : taux de sa
+// mission ou de son offre, ajusté par les règles de tarification applicables au
+// créneau, au trajet de l'enseignant et à la famille
+func CourseHourlyRate(tx *gorm.DB, course *models.Course) (models.Money, error) {
+	input, err := coursePricingInput(tx, course)
+	if err != nil {
+		return models.Money{}, err
+	}
+	rate, _, err := priceHourlyRate(tx, input)
+	if err != nil {
+		return models.Money{}, err
+	}
+	return rate, nil
+}
+
+// BillTimesheetEntry débite la durée du cours des heures prépayées de la famille
+// puis crée le paiement en attente correspondant aux heures approuvées qu'elles ne
+// couvrent pas, déduction faite des codes promo de la mission. Aucun doublon n'est
+// créé si le cours a déjà été facturé ou débité.
+func BillTimesheetEntry(tx *gorm.DB, entry *models.TimesheetEntry) (*models.Payment, error) {
+	if entry.Status != models.TimesheetStatusApproved {
+		return nil, nil
+	}
+
+	var existing models.Payment
+	err := tx.Where("course_id = ? AND type = ? AND status <> ?", entry.CourseID, models.PaymentTypeCourse, models.PaymentStatusRefunded).
+		First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+
+	var course models.Course
+	if err := tx.First(&course, entry.CourseID).Error; err != nil {
+		return nil, err
+	}
+
+	covered, err := debitCourseHours(tx, &course, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	minutes := entry.DurationMinutes - covered
+	if minutes <= 0 {
+		return nil, nil
+	}
+	description := fmt.Sprintf("Cours du %s - %s", entry.DeclaredStart.In(utils.CalendarLocation()).Format("02/01/2006"), formatMinutes(minutes))
+	if covered > 0 {
+		description += fmt.Sprintf(" (hors %s d'heures prépayées)", formatMinutes(covered))
+	}
+
+	rate, err := CourseHourlyRate(tx, &course)
+	if err != nil {
+		return nil, err
+	}
+	amount := rate.ProrateMinutes(minutes)
+	discounts, discount, err := discountCoursePayment(tx, &course, amount)
+	if err != nil {
+		return nil, err
+	}
+	if discount.IsPositive() {
+		amount = amount.Sub(discount)
+		description += fmt.Sprintf(" (remise code promo : %s)", discount)
+	}
+	if !amount.IsPositive() {
+		return nil, savePromoDiscounts(tx, discounts, nil)
+	}
+	payment := models.Payment{
+		Amount:      amount,
+		Status:      models.PaymentStatusPending,
+		Type:        models.PaymentTypeCourse,
+		Description: description,
+		UserID:      course.FamilleID,
+		CourseID:    &course.ID,
+		PaymentDate: time.Now(),
+	}
+	if err := tx.Create(&payment).Error; err != nil {
+		return nil, err
+	}
+	if err := savePromoDiscounts(tx, discounts, &payment.ID); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// formatMinutes formate une durée en minutes sous la forme 1h30
+func formatMinutes(minutes int) string {
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%02d", minutes/60, minutes%60)
+}
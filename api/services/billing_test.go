@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"api/models"
+)
+
+func TestApproveTimesheetPricingError(t *testing.T) {
+	db := newTestDB(t)
+	course, entry := newDeclaredCourse(t, db)
+	if err := db.Unscoped().Delete(&models.Mission{}, course.MissionID).Error; err != nil {
+		t.Fatalf("delete mission: %v", err)
+	}
+	if err := ApproveTimesheetEntry(db, entry, course.FamilleID); err == nil {
+		t.Fatal("approve timesheet without a course rate: want an error")
+	}
+	if err := db.First(entry, entry.ID).Error; err != nil {
+		t.Fatalf("reload timesheet entry: %v", err)
+	}
+	if err := db.First(course, course.ID).Error; err != nil {
+		t.Fatalf("reload course: %v", err)
+	}
+	if entry.Status != models.TimesheetStatusPending || course.Status != models.CourseStatusInProgress {
+		t.Errorf("after a failed approval: entry %s, course %s, want both unchanged", entry.Status, course.Status)
+	}
+	var payments int64
+	if err := db.Model(&models.Payment{}).Where("course_id = ?", course.ID).Count(&payments).Error; err != nil {
+		t.Fatalf("count payments: %v", err)
+	}
+	if payments != 0 {
+		t.Errorf("course payments = %d, want none", payments)
+	}
+}
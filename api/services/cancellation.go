@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCourseNotCancellable = errors.New("seul un cours planifié ou en cours peut être annulé")
+
+// FindCancellationPolicy retourne la règle applicable à une annulation : la plus
+// spécifique (motif, puis partie) et, à égalité, celle dont la fenêtre de préavis est la plus courte
+func FindCancellationPolicy(db *gorm.DB, party models.CancellationParty, reason models.CancellationReason, noticeHours float64) (*models.CancellationPolicy, error) {
+	var policies []models.CancellationPolicy
+	if err := db.Where("is_active = ?", true).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	var matching []models.CancellationPolicy
+	for _, p := range policies {
+		if p.Matches(party, reason, noticeHours) {
+			matching = append(matching, p)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		a, b := matching[i], matching[j]
+		if (a.ReasonCode != "") != (b.ReasonCode != "") {
+			return a.ReasonCode != ""
+		}
+		if (a.CancelledBy != "") != (b.CancelledBy != "") {
+			return a.CancelledBy != ""
+		}
+		return windowRank(a.NoticeWindowHours) < windowRank(b.NoticeWindowHours)
+	})
+	return &matching[0], nil
+}
+
+func windowRank(hours int) int {
+	if hours == 0 {
+		return math.MaxInt32
+	}
+	return hours
+}
+
+// PreviewCancellation calcule les montants qu'entraînerait l'annulation d'un cours
+func PreviewCancellation(db *gorm.DB, course *models.Course, party models.CancellationParty, reason models.CancellationReason, at time.Time) (*models.CancellationPreviewResponse, error) {
+	notice := course.ScheduledTime.Sub(at).Hours()
+	if notice < 0 {
+		notice = 0
+	}
+	notice = math.Round(notice*100) / 100
+
+	rate, err := CourseHourlyRate(db, course)
+	if err != nil {
+		return nil, err
+	}
+	price := rate.ProrateMinutes(course.Duration)
+	preview := &models.CancellationPreviewResponse{
+		CancelledBy: party,
+		ReasonCode:  reason,
+		NoticeHours: notice,
+		CoursePrice: price,
+	}
+	policy, err := FindCancellationPolicy(db, party, reason, notice)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		preview.Policy = policy
+		preview.FeeAmount = price.Percent(policy.FeePercent)
+		preview.CreditAmount = price.Percent(policy.CreditPercent)
+		preview.CreditMinutes = int(math.Round(float64(course.Duration) * policy.CreditPercent / 100))
+		preview.PenaltyAmount = price.Percent(policy.PenaltyPercent)
+	} else {
+		zero := models.NewMoney(0, price.Currency)
+		preview.FeeAmount, preview.CreditAmount, preview.PenaltyAmount = zero, zero, zero
+	}
+	return preview, nil
+}
+
+// CancelCourse annule un cours en appliquant la politique d'annulation : enregistrement
+// de l'auteur et du motif, frais à régler par la famille ou avoir crédité en heures
+// sur son porte-monnaie, pénalité déduite du prochain relevé de l'enseignant. Les
+// heures déclarées et pas encore approuvées sont annulées avec le cours.
+func CancelCourse(db *gorm.DB, course *models.Course, actorID uint, party models.CancellationParty, req models.CourseCancelRequest) (*models.CourseCancellation, error) {
+	if course.Status != models.CourseStatusScheduled && course.Status != models.CourseStatusInProgress {
+		return nil, ErrCourseNotCancellable
+	}
+	preview, err := PreviewCancellation(db, course, party, req.ReasonCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation := models.CourseCancellation{
+		CourseID:      course.ID,
+		CancelledByID: actorID,
+		CancelledBy:   party,
+		ReasonCode:    req.ReasonCode,
+		Note:          req.Note,
+		NoticeHours:   preview.NoticeHours,
+		FeeAmount:     preview.FeeAmount,
+		CreditAmount:  preview.CreditAmount,
+		CreditMinutes: preview.CreditMinutes,
+		PenaltyAmount: preview.PenaltyAmount,
+	}
+	if preview.Policy != nil {
+		cancellation.PolicyID = &preview.Policy.ID
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(course, course.ID).Error; err != nil {
+			return err
+		}
+		if course.Status == models.CourseStatusCancelled || course.Cancel() != nil {
+			return ErrCourseNotCancellable
+		}
+		if err := tx.Model(course).Update("status", course.Status).Error; err != nil {
+			return err
+		}
+		if err := voidCourseTimesheet(tx, course.ID); err != nil {
+			return err
+		}
+		if err := tx.Create(&cancellation).Error; err != nil {
+			return err
+		}
+
+		label := fmt.Sprintf("cours du %s", formatSlot(course.ScheduledTime))
+		entries := []struct {
+			amount      models.Money
+			paymentType models.PaymentType
+			userID      uint
+			description string
+		}{
+			{cancellation.FeeAmount, models.PaymentTypeCancellationFee, course.FamilleID, "Frais d'annulation - " + label},
+			{cancellation.PenaltyAmount, models.PaymentTypePenalty, course.EnseignantID, "Pénalité d'annulation - " + label},
+		}
+		for _, e := range entries {
+			if !e.amount.IsPositive() {
+				continue
+			}
+			if err := tx.Create(&models.Payment{
+				Amount:      e.amount,
+				Status:      models.PaymentStatusPending,
+				Type:        e.paymentType,
+				Description: e.description,
+				UserID:      e.userID,
+				CourseID:    &course.ID,
+				PaymentDate: time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if cancellation.CreditMinutes > 0 {
+			if _, err := grantWalletHours(tx, course.FamilleID, cancellation.CreditMinutes,
+				"Avoir suite à l'annulation - "+label, nil, actorID, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		message := fmt.Sprintf("Cours annulé (%s, motif : %s)", party, req.ReasonCode)
+		if req.Note != "" {
+			message += " - " + req.Note
+		}
+		if err := RecordCourseEvent(tx, course.ID, actorID, models.CourseEventCancelled, message); err != nil {
+			return err
+		}
+		return NotifyAll(tx, []uint{course.FamilleID, course.EnseignantID}, models.NotificationCourseCancelled,
+			"Cours annulé", fmt.Sprintf("Le %s a été annulé", label), fmt.Sprintf("/api/v1/courses/%d", course.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cancellation, nil
+}
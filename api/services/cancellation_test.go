@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"api/models"
+)
+
+func TestCancelCourseSettlements(t *testing.T) {
+	db := newTestDB(t)
+	completed, entry := newDeclaredCourse(t, db)
+	if err := ApproveTimesheetEntry(db, entry, completed.FamilleID); err != nil {
+		t.Fatalf("approve timesheet: %v", err)
+	}
+	policy := models.CancellationPolicy{Name: "Annulation tardive", FeePercent: 50, CreditPercent: 50, PenaltyPercent: 25, IsActive: true}
+	if err := db.Create(&policy).Error; err != nil {
+		t.Fatalf("create policy: %v", err)
+	}
+	course := models.Course{ScheduledTime: completed.ScheduledTime.AddDate(0, 0, 7), Duration: 60,
+		Status: models.CourseStatusScheduled, FamilleID: completed.FamilleID, EnseignantID: 2, MissionID: completed.MissionID}
+	if err := db.Create(&course).Error; err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	req := models.CourseCancelRequest{ReasonCode: models.CancellationReasonEmergency}
+	cancellation, err := CancelCourse(db, &course, course.FamilleID, models.CancellationByFamille, req)
+	if err != nil {
+		t.Fatalf("cancel course: %v", err)
+	}
+	if cancellation.CreditMinutes != 30 {
+		t.Errorf("credit = %d min, want 30", cancellation.CreditMinutes)
+	}
+
+	var fee models.Payment
+	if err := db.Where("course_id = ? AND type = ?", course.ID, models.PaymentTypeCancellationFee).First(&fee).Error; err != nil {
+		t.Fatalf("cancellation fee: %v", err)
+	}
+	if fee.Amount != models.EUR(2000) || !fee.IsPayable() || !fee.CanProcess() {
+		t.Errorf("cancellation fee = %s, payable %v, want 20,00 € to pay", fee.Amount, fee.IsPayable())
+	}
+	var credits int64
+	if err := db.Model(&models.Payment{}).Where("type = ?", models.PaymentTypeCredit).Count(&credits).Error; err != nil {
+		t.Fatalf("count credits: %v", err)
+	}
+	if credits != 0 {
+		t.Errorf("credit payments = %d, want the credit in the wallet", credits)
+	}
+	minutes, err := walletMinutes(db, course.FamilleID, time.Now())
+	if err != nil {
+		t.Fatalf("wallet minutes: %v", err)
+	}
+	if minutes != 30 {
+		t.Errorf("wallet = %d min, want 30", minutes)
+	}
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	preview, err := PreviewPayouts(db, start, time.Now().AddDate(0, 0, 1), nil, time.Now())
+	if err != nil {
+		t.Fatalf("preview payouts: %v", err)
+	}
+	if len(preview.Payouts) != 1 {
+		t.Fatalf("payouts = %d, want 1", len(preview.Payouts))
+	}
+	payout := preview.Payouts[0]
+	if payout.GrossAmount != models.EUR(4000) || payout.PenaltiesAmount != models.EUR(1000) || payout.NetAmount != models.EUR(3000) {
+		t.Errorf("payout = %s gross, %s penalties, %s net, want 40,00 €, 10,00 €, 30,00 €",
+			payout.GrossAmount, payout.PenaltiesAmount, payout.NetAmount)
+	}
+
+	if _, err := LockPayouts(db, start, time.Now().AddDate(0, 0, 1), nil, 1); err != nil {
+		t.Fatalf("lock payouts: %v", err)
+	}
+	var penalty models.Payment
+	if err := db.Where("course_id = ? AND type = ?", course.ID, models.PaymentTypePenalty).First(&penalty).Error; err != nil {
+		t.Fatalf("penalty: %v", err)
+	}
+	if penalty.Status != models.PaymentStatusCompleted {
+		t.Errorf("penalty after lock = %s, want %s", penalty.Status, models.PaymentStatusCompleted)
+	}
+}
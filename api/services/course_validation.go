@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordCourseEvent ajoute une étape à l'historique d'un cours
+func RecordCourseEvent(tx *gorm.DB, courseID, actorID uint, eventType models.CourseEventType, message string) error {
+	return tx.Create(&models.CourseEvent{
+		CourseID: courseID,
+		ActorID:  actorID,
+		Type:     eventType,
+		Message:  message,
+	}).Error
+}
+
+// DeclineCourse enregistre le refus de la famille et ouvre un litige pour les administrateurs
+func DeclineCourse(db *gorm.DB, entry *models.TimesheetEntry, actorID uint, reason string) (*models.CourseDispute, error) {
+	dispute := models.CourseDispute{
+		CourseID:         entry.CourseID,
+		TimesheetEntryID: entry.ID,
+		OpenedByID:       actorID,
+		Reason:           reason,
+		Status:           models.DisputeStatusOpen,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		entry.Dispute(actorID, reason)
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&dispute).Error; err != nil {
+			return err
+		}
+		return RecordCourseEvent(tx, entry.CourseID, actorID, models.CourseEventDeclined, "Cours refusé par la famille : "+reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// ResolveDispute applique la décision d'un administrateur sur un litige ouvert.
+// En cas d'approbation, la durée peut être corrigée avant facturation ;
+// en cas de rejet, le cours est annulé et n'est pas facturé.
+func ResolveDispute(db *gorm.DB, dispute *models.CourseDispute, adminID uint, req models.DisputeResolveRequest) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var course models.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, dispute.CourseID).Error; err != nil {
+			return err
+		}
+		var entry models.TimesheetEntry
+		if err := tx.First(&entry, dispute.TimesheetEntryID).Error; err != nil {
+			return err
+		}
+
+		dispute.Resolve(adminID, req.Resolution, req.Note)
+		if err := tx.Save(dispute).Error; err != nil {
+			return err
+		}
+
+		message := "Litige résolu : cours non facturé"
+		switch req.Resolution {
+		case models.DisputeResolutionApproved:
+			if course.Status == models.CourseStatusCancelled {
+				return models.ErrTimesheetCourseCancelled
+			}
+			if err := course.Validate(); err != nil {
+				return err
+			}
+			if req.DurationMinutes != nil {
+				entry.AdjustDuration(*req.DurationMinutes)
+			}
+			entry.Approve(adminID)
+			if err := tx.Save(&entry).Error; err != nil {
+				return err
+			}
+			if _, err := BillTimesheetEntry(tx, &entry); err != nil {
+				return err
+			}
+			message = fmt.Sprintf("Litige résolu : %s facturées", formatMinutes(entry.DurationMinutes))
+		case models.DisputeResolutionRejected:
+			if err := course.Cancel(); err != nil {
+				return err
+			}
+			entry.Void()
+			if err := tx.Save(&entry).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&course).Update("status", course.Status).Error; err != nil {
+			return err
+		}
+		if req.Note != "" {
+			message += " (" + req.Note + ")"
+		}
+		return RecordCourseEvent(tx, dispute.CourseID, adminID, models.CourseEventDisputeResolved, message)
+	})
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"api/models"
+)
+
+func TestResolveDispute(t *testing.T) {
+	minutes := 45
+	tests := []struct {
+		name        string
+		req         models.DisputeResolveRequest
+		wantEntry   models.TimesheetStatus
+		wantCourse  models.CourseStatus
+		wantMinutes int
+		wantPayment models.Money
+	}{
+		{"approbation avec durée corrigée", models.DisputeResolveRequest{Resolution: models.DisputeResolutionApproved, DurationMinutes: &minutes},
+			models.TimesheetStatusApproved, models.CourseStatusCompleted, 45, models.EUR(3000)},
+		{"approbation sans correction", models.DisputeResolveRequest{Resolution: models.DisputeResolutionApproved},
+			models.TimesheetStatusApproved, models.CourseStatusCompleted, 60, models.EUR(4000)},
+		{"rejet", models.DisputeResolveRequest{Resolution: models.DisputeResolutionRejected},
+			models.TimesheetStatusVoided, models.CourseStatusCancelled, 60, models.Money{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			course, entry := newDeclaredCourse(t, db)
+			dispute, err := DeclineCourse(db, entry, course.FamilleID, "Le cours a fini plus tôt")
+			if err != nil {
+				t.Fatalf("decline course: %v", err)
+			}
+			if err := ResolveDispute(db, dispute, 1, tt.req); err != nil {
+				t.Fatalf("resolve dispute: %v", err)
+			}
+			if err := db.First(entry, entry.ID).Error; err != nil {
+				t.Fatalf("reload timesheet entry: %v", err)
+			}
+			if err := db.First(course, course.ID).Error; err != nil {
+				t.Fatalf("reload course: %v", err)
+			}
+			if entry.Status != tt.wantEntry || course.Status != tt.wantCourse {
+				t.Errorf("entry %s, course %s, want %s, %s", entry.Status, course.Status, tt.wantEntry, tt.wantCourse)
+			}
+			wantEnd := entry.DeclaredStart.Add(time.Duration(tt.wantMinutes) * time.Minute)
+			if entry.DurationMinutes != tt.wantMinutes || !entry.DeclaredEnd.Equal(wantEnd) {
+				t.Errorf("entry = %d min ending %s, want %d min ending %s", entry.DurationMinutes, entry.DeclaredEnd, tt.wantMinutes, wantEnd)
+			}
+			var payment models.Payment
+			err = db.Where("course_id = ? AND type = ?", course.ID, models.PaymentTypeCourse).First(&payment).Error
+			if tt.wantPayment.IsZero() {
+				if err == nil {
+					t.Errorf("course payment = %s, want none", payment.Amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("course payment: %v", err)
+			}
+			if payment.Amount != tt.wantPayment {
+				t.Errorf("course payment = %s, want %s", payment.Amount, tt.wantPayment)
+			}
+		})
+	}
+}
@@ -0,0 +1,515 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCourseAlreadyInvoiced  = errors.New("ce cours figure déjà sur une facture")
+	ErrCourseNotInvoiceable   = errors.New("seuls les cours terminés de la famille, dont la feuille de temps est approuvée, peuvent être facturés")
+	ErrCourseAlreadyPaid      = errors.New("ce cours a déjà donné lieu à un paiement")
+	ErrCoursePrepaid          = errors.New("ce cours est entièrement couvert par des heures prépayées")
+	ErrInvoiceAlreadyCredited = errors.New("cette facture a déjà fait l'objet d'un avoir")
+	ErrNotAnInvoice           = errors.New("un avoir ne peut pas lui-même être annulé par un avoir")
+)
+
+// InvoiceSettings regroupe les mentions légales de l'émetteur et les règles de TVA
+type InvoiceSettings struct {
+	Series           string
+	CreditNoteSeries string
+	VATRate          float64
+	VATMention       string
+	PaymentTermsDays int
+	SellerName       string
+	SellerAddress    string
+	SellerSIRET      string
+	SellerVATNumber  string
+}
+
+// CurrentInvoiceSettings lit la configuration de facturation (variables INVOICE_*).
+// Sans taux de TVA, la mention d'exonération est portée sur chaque facture.
+func CurrentInvoiceSettings() InvoiceSettings {
+	settings := InvoiceSettings{
+		Series:           envOr("INVOICE_SERIES", "FA"),
+		CreditNoteSeries: envOr("INVOICE_CREDIT_NOTE_SERIES", "AV"),
+		PaymentTermsDays: 30,
+		SellerName:       envOr("INVOICE_SELLER_NAME", "Help Us"),
+		SellerAddress:    os.Getenv("INVOICE_SELLER_ADDRESS"),
+		SellerSIRET:      os.Getenv("INVOICE_SELLER_SIRET"),
+		SellerVATNumber:  os.Getenv("INVOICE_SELLER_VAT_NUMBER"),
+	}
+	if env := os.Getenv("INVOICE_VAT_RATE"); env != "" {
+		if rate, err := strconv.ParseFloat(env, 64); err == nil && rate > 0 {
+			settings.VATRate = rate
+		}
+	}
+	if env := os.Getenv("INVOICE_PAYMENT_TERMS_DAYS"); env != "" {
+		if days, err := strconv.Atoi(env); err == nil && days > 0 {
+			settings.PaymentTermsDays = days
+		}
+	}
+	if settings.VATRate == 0 {
+		settings.VATMention = envOr("INVOICE_VAT_EXEMPTION_MENTION", "TVA non applicable, article 293 B du CGI")
+	}
+	return settings
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// includedVAT extrait la TVA comprise dans un montant TTC
+func includedVAT(amount models.Money, rate float64) models.Money {
+	if rate <= 0 {
+		return models.NewMoney(0, amount.Currency)
+	}
+	return models.NewMoney(amount.Cents-int64(math.Round(float64(amount.Cents)*100/(100+rate))), amount.Currency)
+}
+
+// invoicedHours retourne, par cours, le volume horaire net déjà facturé
+// (les lignes d'avoir, négatives, annulent les lignes de facture)
+func invoicedHours(tx *gorm.DB, courseIDs []uint) (map[uint]float64, error) {
+	type row struct {
+		CourseID uint
+		Hours    float64
+	}
+	var rows []row
+	err := tx.Model(&models.InvoiceLine{}).
+		Select("invoice_lines.course_id AS course_id, SUM(invoice_lines.quantity) AS hours").
+		Joins("JOIN invoices ON invoices.id = invoice_lines.invoice_id AND invoices.deleted_at IS NULL").
+		Where("invoice_lines.course_id IN ?", courseIDs).
+		Group("invoice_lines.course_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	hours := make(map[uint]float64, len(rows))
+	for _, r := range rows {
+		hours[r.CourseID] = r.Hours
+	}
+	return hours, nil
+}
+
+// courseInvoiceLine construit la ligne d'un cours terminé sur la base des heures
+// approuvées de sa feuille de temps, hors heures prépayées débitées du
+// porte-monnaie de la famille. Elle est nulle si le cours est entièrement prépayé.
+func courseInvoiceLine(tx *gorm.DB, course *models.Course, entry *models.TimesheetEntry, vatRate float64) (*models.InvoiceLine, error) {
+	covered, err := courseWalletMinutes(tx, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	minutes := entry.DurationMinutes - covered
+	if minutes <= 0 {
+		return nil, nil
+	}
+	description := fmt.Sprintf("Cours du %s - %s", course.ScheduledTime.In(utils.CalendarLocation()).Format("02/01/2006"), formatMinutes(minutes))
+	if covered > 0 {
+		description += fmt.Sprintf(" (hors %s d'heures prépayées)", formatMinutes(covered))
+	}
+	rate, err := CourseHourlyRate(tx, course)
+	if err != nil {
+		return nil, err
+	}
+	courseID := course.ID
+	return &models.InvoiceLine{
+		Description: description,
+		Quantity:    math.Round(float64(minutes)/60*100) / 100,
+		UnitPrice:   rate,
+		VATRate:     vatRate,
+		TotalHT:     rate.ProrateMinutes(minutes),
+		CourseID:    &courseID,
+	}, nil
+}
+
+// approvedTimesheets retourne, par cours, la feuille de temps approuvée
+func approvedTimesheets(tx *gorm.DB, courseIDs []uint) (map[uint]*models.TimesheetEntry, error) {
+	var entries []models.TimesheetEntry
+	if err := tx.Where("course_id IN ? AND status = ?", courseIDs, models.TimesheetStatusApproved).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	byCourse := make(map[uint]*models.TimesheetEntry, len(entries))
+	for i := range entries {
+		byCourse[entries[i].CourseID] = &entries[i]
+	}
+	return byCourse, nil
+}
+
+// paidCourses retourne les cours déjà réglés par un paiement de cours encaissé :
+// ils ne sont pas facturés une seconde fois. Les paiements encore en attente, créés
+// à l'approbation des feuilles de temps, sont annulés à l'émission de la facture.
+func paidCourses(tx *gorm.DB, courseIDs []uint) (map[uint]bool, error) {
+	var ids []uint
+	if err := tx.Model(&models.Payment{}).
+		Where("course_id IN ? AND type = ? AND status = ?", courseIDs, models.PaymentTypeCourse, models.PaymentStatusCompleted).
+		Pluck("course_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	paid := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		paid[id] = true
+	}
+	return paid, nil
+}
+
+// CreateInvoiceDraft prépare une facture en brouillon pour les cours terminés
+// désignés, ou à défaut pour tous les cours terminés non facturés de la mission
+// (ou de la famille). Seuls les cours dont la feuille de temps est approuvée sont
+// facturés ; ceux déjà réglés par un paiement de cours ou entièrement couverts par
+// des heures prépayées sont écartés. Les codes promo des missions et celui saisi à
+// la création sont déduits sur des lignes de remise.
+func CreateInvoiceDraft(db *gorm.DB, req models.InvoiceCreateRequest, adminID uint) (*models.Invoice, error) {
+	settings := CurrentInvoiceSettings()
+	var famille models.Famille
+	if err := db.First(&famille, req.FamilleID).Error; err != nil {
+		return nil, err
+	}
+	query := db.Where("famille_id = ?", req.FamilleID)
+	if req.MissionID != nil {
+		query = query.Where("mission_id = ?", *req.MissionID)
+	}
+	if len(req.CourseIDs) > 0 {
+		query = query.Where("id IN ?", req.CourseIDs)
+	} else {
+		query = query.Where("status = ? AND id IN (?)", models.CourseStatusCompleted,
+			db.Model(&models.TimesheetEntry{}).Select("course_id").Where("status = ?", models.TimesheetStatusApproved))
+	}
+	var courses []models.Course
+	if err := query.Order("scheduled_time ASC").Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	if len(req.CourseIDs) > 0 && len(courses) != len(req.CourseIDs) {
+		return nil, ErrCourseNotInvoiceable
+	}
+
+	courseIDs := make([]uint, len(courses))
+	for i, course := range courses {
+		courseIDs[i] = course.ID
+	}
+	alreadyInvoiced, err := invoicedHours(db, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	timesheets, err := approvedTimesheets(db, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := paidCourses(db, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := models.Invoice{
+		Kind:      models.InvoiceKindInvoice,
+		Status:    models.InvoiceStatusDraft,
+		Series:    settings.Series,
+		Currency:  "EUR",
+		Notes:     req.Notes,
+		FamilleID: req.FamilleID,
+		MissionID: req.MissionID,
+	}
+	if req.Series != "" {
+		invoice.Series = strings.ToUpper(req.Series)
+	}
+	for i := range courses {
+		course := &courses[i]
+		if alreadyInvoiced[course.ID] > 0 {
+			if len(req.CourseIDs) > 0 {
+				return nil, fmt.Errorf("%w (cours %d)", ErrCourseAlreadyInvoiced, course.ID)
+			}
+			continue
+		}
+		entry := timesheets[course.ID]
+		if course.Status != models.CourseStatusCompleted || entry == nil {
+			return nil, fmt.Errorf("%w (cours %d)", ErrCourseNotInvoiceable, course.ID)
+		}
+		if paid[course.ID] {
+			if len(req.CourseIDs) > 0 {
+				return nil, fmt.Errorf("%w (cours %d)", ErrCourseAlreadyPaid, course.ID)
+			}
+			continue
+		}
+		line, err := courseInvoiceLine(db, course, entry, settings.VATRate)
+		if err != nil {
+			return nil, err
+		}
+		if line == nil {
+			if len(req.CourseIDs) > 0 {
+				return nil, fmt.Errorf("%w (cours %d)", ErrCoursePrepaid, course.ID)
+			}
+			continue
+		}
+		line.Position = len(invoice.Lines) + 1
+		invoice.Lines = append(invoice.Lines, *line)
+	}
+	if len(invoice.Lines) == 0 {
+		return nil, models.ErrInvoiceEmpty
+	}
+	invoice.ComputeTotals()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&invoice).Error; err != nil {
+			return err
+		}
+		return applyInvoicePromotions(tx, &invoice, courses, req.PromoCode, settings.VATRate, adminID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// UpdateInvoiceDraft modifie les notes d'une facture encore en brouillon
+func UpdateInvoiceDraft(db *gorm.DB, invoice *models.Invoice, req models.InvoiceUpdateRequest) error {
+	if invoice.IsIssued() {
+		return models.ErrInvoiceIssued
+	}
+	invoice.Notes = req.Notes
+	return db.Model(invoice).Update("notes", req.Notes).Error
+}
+
+// DeleteInvoiceDraft supprime un brouillon ; aucun numéro ne lui ayant été
+// attribué, la numérotation reste continue. Ses remises sont rendues aux codes promo.
+func DeleteInvoiceDraft(db *gorm.DB, invoice *models.Invoice) error {
+	if invoice.IsIssued() {
+		return models.ErrInvoiceIssued
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := releaseInvoicePromotions(tx, invoice); err != nil {
+			return err
+		}
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(invoice).Error
+	})
+}
+
+// nextInvoiceNumber réserve le numéro suivant de la série pour l'année, sous
+// verrou, afin de garantir une numérotation continue et sans doublon
+func nextInvoiceNumber(tx *gorm.DB, series string, year int) (string, int, error) {
+	var sequence models.InvoiceSequence
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("series = ? AND year = ?", series, year).First(&sequence).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		sequence = models.InvoiceSequence{Series: series, Year: year}
+		err = tx.Create(&sequence).Error
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	sequence.LastNumber++
+	if err := tx.Save(&sequence).Error; err != nil {
+		return "", 0, err
+	}
+	return fmt.Sprintf("%s-%d-%06d", series, year, sequence.LastNumber), sequence.LastNumber, nil
+}
+
+// issueInvoice numérote la facture, fige les mentions légales, l'enregistre et la
+// comptabilise
+func issueInvoice(tx *gorm.DB, invoice *models.Invoice, adminID uint, now time.Time) error {
+	settings := CurrentInvoiceSettings()
+	year := now.In(utils.CalendarLocation()).Year()
+	number, sequence, err := nextInvoiceNumber(tx, invoice.Series, year)
+	if err != nil {
+		return err
+	}
+	if err := invoice.Issue(number, year, sequence, now, adminID); err != nil {
+		return err
+	}
+	if invoice.Kind == models.InvoiceKindInvoice {
+		dueDate := now.AddDate(0, 0, settings.PaymentTermsDays)
+		invoice.DueDate = &dueDate
+	}
+	invoice.VATMention = settings.VATMention
+	invoice.SellerName = settings.SellerName
+	invoice.SellerAddress = settings.SellerAddress
+	invoice.SellerSIRET = settings.SellerSIRET
+	invoice.SellerVATNumber = settings.SellerVATNumber
+	invoice.CustomerName, invoice.CustomerAddress = invoiceCustomer(tx, invoice.FamilleID)
+	if err := cancelInvoicedCoursePayments(tx, invoice); err != nil {
+		return err
+	}
+	if err := allocateInvoiceAdvances(tx, invoice); err != nil {
+		return err
+	}
+	if err := tx.Omit(clause.Associations).Save(invoice).Error; err != nil {
+		return err
+	}
+	return postInvoiceEntries(tx, invoice)
+}
+
+// cancelInvoicedCoursePayments annule les paiements de cours en attente ou échoués
+// des cours repris sur la facture, afin que la famille ne les règle pas deux fois,
+// et rend aux codes promo les remises qui leur avaient été accordées. L'émission est
+// refusée si l'un de ces cours a été réglé, ou est en cours de règlement, depuis la
+// préparation du brouillon.
+func cancelInvoicedCoursePayments(tx *gorm.DB, invoice *models.Invoice) error {
+	if invoice.Kind != models.InvoiceKindInvoice {
+		return nil
+	}
+	var courseIDs []uint
+	if err := tx.Model(&models.InvoiceLine{}).Where("invoice_id = ? AND course_id IS NOT NULL", invoice.ID).
+		Pluck("course_id", &courseIDs).Error; err != nil {
+		return err
+	}
+	if len(courseIDs) == 0 {
+		return nil
+	}
+	var payments []models.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("course_id IN ? AND type = ? AND status IN ?", courseIDs, models.PaymentTypeCourse,
+			[]models.PaymentStatus{models.PaymentStatusPending, models.PaymentStatusFailed,
+				models.PaymentStatusProcessing, models.PaymentStatusCompleted}).
+		Order("id").Find(&payments).Error; err != nil {
+		return err
+	}
+	for i := range payments {
+		payment := &payments[i]
+		if payment.Status == models.PaymentStatusCompleted || payment.Status == models.PaymentStatusProcessing {
+			return fmt.Errorf("%w (cours %d)", ErrCourseAlreadyPaid, *payment.CourseID)
+		}
+		if err := payment.Cancel("Cours repris sur la facture " + *invoice.Number); err != nil {
+			return err
+		}
+		payment.InvoiceID = &invoice.ID
+		if err := tx.Omit(clause.Associations).Save(payment).Error; err != nil {
+			return err
+		}
+		if err := releasePaymentPromotions(tx, payment.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invoiceCustomer retourne le nom et l'adresse de facturation de la famille
+func invoiceCustomer(tx *gorm.DB, familleID uint) (string, string) {
+	var famille models.Famille
+	if err := tx.Preload("User").First(&famille, familleID).Error; err != nil {
+		return "", ""
+	}
+	name := famille.FamilyName
+	if name == "" {
+		name = famille.User.Username
+	}
+	var address models.Address
+	if err := tx.Where("user_id = ?", familleID).Order("id ASC").First(&address).Error; err != nil {
+		return name, ""
+	}
+	return name, fmt.Sprintf("%s, %s %s, %s", address.Street, address.PostalCode, address.City, address.Country)
+}
+
+// IssueInvoice émet un brouillon : il reçoit son numéro définitif et n'est plus modifiable
+func IssueInvoice(db *gorm.DB, invoice *models.Invoice, adminID uint) error {
+	if invoice.IsIssued() {
+		return models.ErrInvoiceIssued
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return issueInvoice(tx, invoice, adminID, time.Now())
+	})
+}
+
+// CreateCreditNote émet un avoir annulant intégralement une facture émise ; les
+// cours concernés redeviennent facturables et les acomptes imputés sont libérés
+func CreateCreditNote(db *gorm.DB, invoice *models.Invoice, adminID uint, reason string) (*models.Invoice, error) {
+	if invoice.Kind != models.InvoiceKindInvoice {
+		return nil, ErrNotAnInvoice
+	}
+	if !invoice.IsIssued() {
+		return nil, models.ErrInvoiceNotIssued
+	}
+	var count int64
+	if err := db.Model(&models.Invoice{}).Where("credited_invoice_id = ?", invoice.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrInvoiceAlreadyCredited
+	}
+
+	creditNote := models.Invoice{
+		Kind:              models.InvoiceKindCreditNote,
+		Status:            models.InvoiceStatusDraft,
+		Series:            CurrentInvoiceSettings().CreditNoteSeries,
+		Currency:          invoice.Currency,
+		Reason:            reason,
+		FamilleID:         invoice.FamilleID,
+		MissionID:         invoice.MissionID,
+		CreditedInvoiceID: &invoice.ID,
+	}
+	for _, line := range invoice.Lines {
+		creditNote.Lines = append(creditNote.Lines, models.InvoiceLine{
+			Position:    line.Position,
+			Description: line.Description,
+			Quantity:    -line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			VATRate:     line.VATRate,
+			TotalHT:     line.TotalHT.Neg(),
+			CourseID:    line.CourseID,
+		})
+	}
+	creditNote.ComputeTotals()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&creditNote).Error; err != nil {
+			return err
+		}
+		if err := releaseInvoiceAdvances(tx, invoice.ID, time.Now()); err != nil {
+			return err
+		}
+		if err := postInvoiceAdvancesRelease(tx, invoice, time.Now()); err != nil {
+			return err
+		}
+		return issueInvoice(tx, &creditNote, adminID, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &creditNote, nil
+}
+
+// LoadInvoice charge une facture avec ses lignes ordonnées
+func LoadInvoice(db *gorm.DB, id uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position ASC") }).
+		First(&invoice, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// InvoicePDF rend le PDF d'une facture. Les mentions d'un brouillon ne sont pas
+// encore figées : elles sont reprises de la configuration courante.
+func InvoicePDF(db *gorm.DB, invoice *models.Invoice) []byte {
+	document := *invoice
+	if !document.IsIssued() {
+		settings := CurrentInvoiceSettings()
+		document.VATMention = settings.VATMention
+		document.SellerName = settings.SellerName
+		document.SellerAddress = settings.SellerAddress
+		document.SellerSIRET = settings.SellerSIRET
+		document.SellerVATNumber = settings.SellerVATNumber
+		document.CustomerName, document.CustomerAddress = invoiceCustomer(db, document.FamilleID)
+	}
+	creditedNumber := ""
+	if document.CreditedInvoiceID != nil {
+		var credited models.Invoice
+		if err := db.Select("number").First(&credited, *document.CreditedInvoiceID).Error; err == nil && credited.Number != nil {
+			creditedNumber = *credited.Number
+		}
+	}
+	return RenderInvoicePDF(&document, creditedNumber)
+}
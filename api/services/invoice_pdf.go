@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"api/models"
+	"api/utils"
+)
+
+const (
+	pdfMargin     = 50.0
+	pdfLineHeight = 16.0
+	pdfBottom     = utils.PDFPageHeight - 90
+)
+
+// RenderInvoicePDF produit le document PDF d'une facture ou d'un avoir. Les
+// brouillons sont rendus avec la mention « BROUILLON » et sans numéro.
+func RenderInvoicePDF(invoice *models.Invoice, creditedNumber string) []byte {
+	title := "Facture"
+	if invoice.Kind == models.InvoiceKindCreditNote {
+		title = "Avoir"
+	}
+	heading := strings.ToUpper(title) + " - BROUILLON"
+	if invoice.Number != nil {
+		heading = fmt.Sprintf("%s N° %s", strings.ToUpper(title), *invoice.Number)
+	}
+	doc := &utils.PDFDocument{Title: heading}
+	doc.AddPage()
+
+	right := utils.PDFPageWidth - pdfMargin
+	y := 60.0
+	doc.Text(pdfMargin, y, 16, true, invoice.SellerName)
+	doc.TextRight(right, y, 16, true, heading)
+	y += pdfLineHeight + 4
+	for _, line := range []string{invoice.SellerAddress, prefixed("SIRET : ", invoice.SellerSIRET), prefixed("TVA intracommunautaire : ", invoice.SellerVATNumber)} {
+		if line != "" {
+			doc.Text(pdfMargin, y, 9, false, line)
+			y += 12
+		}
+	}
+
+	y = math.Max(y, 110) + 10
+	loc := utils.CalendarLocation()
+	if invoice.IssueDate != nil {
+		doc.TextRight(right, y, 10, false, "Date d'émission : "+invoice.IssueDate.In(loc).Format("02/01/2006"))
+	}
+	if invoice.DueDate != nil {
+		doc.TextRight(right, y+14, 10, false, "Date d'échéance : "+invoice.DueDate.In(loc).Format("02/01/2006"))
+	}
+	doc.Text(pdfMargin, y, 10, true, "Client")
+	doc.Text(pdfMargin, y+14, 10, false, invoice.CustomerName)
+	doc.Text(pdfMargin, y+28, 9, false, invoice.CustomerAddress)
+	y += 56
+	if creditedNumber != "" {
+		doc.Text(pdfMargin, y, 10, false, "Avoir sur la facture n° "+creditedNumber)
+		y += pdfLineHeight
+	}
+	if invoice.Reason != "" {
+		doc.Text(pdfMargin, y, 10, false, "Motif : "+invoice.Reason)
+		y += pdfLineHeight
+	}
+
+	columns := []float64{pdfMargin, 330, 400, 470, right}
+	tableHeader := func() {
+		y += 8
+		doc.Text(columns[0], y, 9, true, "Désignation")
+		doc.TextRight(columns[2], y, 9, true, "Qté (h)")
+		doc.TextRight(columns[3], y, 9, true, "PU HT")
+		doc.TextRight(columns[4], y, 9, true, "Total HT")
+		y += 6
+		doc.Line(pdfMargin, y, right, y, 0.8)
+		y += pdfLineHeight
+	}
+	tableHeader()
+	for _, line := range invoice.Lines {
+		if y > pdfBottom {
+			doc.AddPage()
+			y = 60
+			tableHeader()
+		}
+		doc.Text(columns[0], y, 9, false, line.Description)
+		doc.TextRight(columns[2], y, 9, false, formatQuantity(line.Quantity))
+		doc.TextRight(columns[3], y, 9, false, line.UnitPrice.String())
+		doc.TextRight(columns[4], y, 9, false, line.TotalHT.String())
+		y += pdfLineHeight
+	}
+	doc.Line(pdfMargin, y-10, right, y-10, 0.5)
+
+	if y > pdfBottom-90 {
+		doc.AddPage()
+		y = 60
+	}
+	y += 6
+	type totalRow struct {
+		label  string
+		amount models.Money
+		bold   bool
+	}
+	totals := []totalRow{
+		{"Total HT", invoice.TotalHT, false},
+		{"TVA", invoice.VATAmount, false},
+		{"Total TTC", invoice.TotalTTC, true},
+	}
+	if invoice.AdvanceAmount.IsPositive() {
+		totals = append(totals,
+			totalRow{"Acomptes déduits", invoice.AdvanceAmount.Neg(), false},
+			totalRow{"Net à payer", invoice.AmountDue(), true})
+	}
+	for _, total := range totals {
+		doc.TextRight(columns[3], y, 10, total.bold, total.label)
+		doc.TextRight(columns[4], y, 10, total.bold, total.amount.String())
+		y += pdfLineHeight
+	}
+
+	y += 10
+	footer := []string{invoice.VATMention}
+	if invoice.Kind == models.InvoiceKindInvoice && invoice.DueDate != nil {
+		footer = append(footer,
+			"Paiement à réception, au plus tard à la date d'échéance. Pas d'escompte pour paiement anticipé.")
+	}
+	if invoice.Notes != "" {
+		footer = append(footer, invoice.Notes)
+	}
+	for _, line := range footer {
+		if line != "" {
+			doc.Text(pdfMargin, y, 9, false, line)
+			y += 12
+		}
+	}
+	return doc.Bytes()
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
+
+func formatQuantity(quantity float64) string {
+	return strings.Replace(strconv.FormatFloat(quantity, 'f', 2, 64), ".", ",", 1)
+}
+
+// InvoiceFileName retourne le nom du fichier PDF d'une facture
+func InvoiceFileName(invoice *models.Invoice) string {
+	if invoice.Number != nil {
+		return *invoice.Number + ".pdf"
+	}
+	return fmt.Sprintf("brouillon-%d.pdf", invoice.ID)
+}
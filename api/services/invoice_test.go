@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"api/database"
+	"api/models"
+	"api/utils"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB ouvre une base SQLite en mémoire propre au test et y crée le schéma
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db
+}
+
+// newDraftInvoice enregistre un brouillon d'une ligne de la série donnée
+func newDraftInvoice(t *testing.T, db *gorm.DB, series string, cents int64) *models.Invoice {
+	t.Helper()
+	invoice := models.Invoice{
+		Kind:      models.InvoiceKindInvoice,
+		Status:    models.InvoiceStatusDraft,
+		Series:    series,
+		Currency:  models.CurrencyEUR,
+		FamilleID: 1,
+		Lines: []models.InvoiceLine{
+			{Position: 1, Description: "Cours", Quantity: 1, UnitPrice: models.EUR(cents), TotalHT: models.EUR(cents)},
+		},
+	}
+	invoice.ComputeTotals()
+	if err := db.Create(&invoice).Error; err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	return &invoice
+}
+
+// newDeclaredCourse enregistre une famille, une mission à 40 € de l'heure et un
+// cours d'une heure dont la feuille de temps attend l'approbation de la famille
+func newDeclaredCourse(t *testing.T, db *gorm.DB) (*models.Course, *models.TimesheetEntry) {
+	t.Helper()
+	user := models.User{Username: "famille", Password: "x", Email: "famille@example.com", Role: models.RoleFamille}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&models.Famille{UserID: user.ID, FamilyName: "Martin"}).Error; err != nil {
+		t.Fatalf("create famille: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 17, 0, 0, 0, utils.CalendarLocation())
+	mission := models.Mission{StartDate: start, HourlyRate: models.EUR(4000), FamilleID: user.ID, EnseignantID: 2}
+	if err := db.Create(&mission).Error; err != nil {
+		t.Fatalf("create mission: %v", err)
+	}
+	course := models.Course{ScheduledTime: start, Duration: 60, Status: models.CourseStatusInProgress,
+		FamilleID: user.ID, EnseignantID: 2, MissionID: mission.ID}
+	if err := db.Create(&course).Error; err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	entry := models.TimesheetEntry{CourseID: course.ID, EnseignantID: 2, FamilleID: user.ID}
+	entry.Declare(start, start.Add(time.Hour), "", start.Add(TimesheetApprovalDelay()))
+	if err := db.Create(&entry).Error; err != nil {
+		t.Fatalf("create timesheet entry: %v", err)
+	}
+	return &course, &entry
+}
+
+func TestInvoiceApprovedTimesheet(t *testing.T) {
+	db := newTestDB(t)
+	course, entry := newDeclaredCourse(t, db)
+	if err := ApproveTimesheetEntry(db, entry, course.FamilleID); err != nil {
+		t.Fatalf("approve timesheet: %v", err)
+	}
+	var payment models.Payment
+	if err := db.Where("course_id = ? AND type = ?", course.ID, models.PaymentTypeCourse).First(&payment).Error; err != nil {
+		t.Fatalf("course payment: %v", err)
+	}
+	if payment.Status != models.PaymentStatusPending || payment.Amount != models.EUR(4000) {
+		t.Fatalf("course payment = %s %s, want pending 40,00 €", payment.Status, payment.Amount)
+	}
+
+	invoice, err := CreateInvoiceDraft(db, models.InvoiceCreateRequest{FamilleID: course.FamilleID, CourseIDs: []uint{course.ID}}, 1)
+	if err != nil {
+		t.Fatalf("draft invoice for an approved course with a pending payment: %v", err)
+	}
+	if len(invoice.Lines) != 1 || invoice.TotalHT != models.EUR(4000) {
+		t.Fatalf("draft = %d line(s), %s HT, want 1 line, 40,00 € HT", len(invoice.Lines), invoice.TotalHT)
+	}
+	if err := IssueInvoice(db, invoice, 1); err != nil {
+		t.Fatalf("issue invoice: %v", err)
+	}
+	if err := db.First(&payment, payment.ID).Error; err != nil {
+		t.Fatalf("reload payment: %v", err)
+	}
+	if payment.Status != models.PaymentStatusCancelled || payment.InvoiceID == nil || *payment.InvoiceID != invoice.ID {
+		t.Errorf("course payment after issue = %s (invoice %v), want cancelled on invoice %d", payment.Status, payment.InvoiceID, invoice.ID)
+	}
+	if payment.CanProcess() {
+		t.Errorf("cancelled course payment can still be processed")
+	}
+}
+
+func TestInvoicePaidCourse(t *testing.T) {
+	db := newTestDB(t)
+	course, entry := newDeclaredCourse(t, db)
+	if err := ApproveTimesheetEntry(db, entry, course.FamilleID); err != nil {
+		t.Fatalf("approve timesheet: %v", err)
+	}
+	invoice, err := CreateInvoiceDraft(db, models.InvoiceCreateRequest{FamilleID: course.FamilleID, CourseIDs: []uint{course.ID}}, 1)
+	if err != nil {
+		t.Fatalf("draft invoice: %v", err)
+	}
+	if err := db.Model(&models.Payment{}).Where("course_id = ?", course.ID).
+		Update("status", models.PaymentStatusCompleted).Error; err != nil {
+		t.Fatalf("complete payment: %v", err)
+	}
+	if err := IssueInvoice(db, invoice, 1); !errors.Is(err, ErrCourseAlreadyPaid) {
+		t.Errorf("issue invoice of a course paid meanwhile = %v, want %v", err, ErrCourseAlreadyPaid)
+	}
+	draft, err := LoadInvoice(db, invoice.ID)
+	if err != nil {
+		t.Fatalf("reload draft: %v", err)
+	}
+	if err := DeleteInvoiceDraft(db, draft); err != nil {
+		t.Fatalf("delete draft: %v", err)
+	}
+	if _, err := CreateInvoiceDraft(db, models.InvoiceCreateRequest{FamilleID: course.FamilleID, CourseIDs: []uint{course.ID}}, 1); !errors.Is(err, ErrCourseAlreadyPaid) {
+		t.Errorf("draft invoice of a paid course = %v, want %v", err, ErrCourseAlreadyPaid)
+	}
+}
+
+func TestNextInvoiceNumber(t *testing.T) {
+	db := newTestDB(t)
+	tests := []struct {
+		series   string
+		year     int
+		want     string
+		sequence int
+	}{
+		{"FA", 2026, "FA-2026-000001", 1},
+		{"FA", 2026, "FA-2026-000002", 2},
+		{"AV", 2026, "AV-2026-000001", 1},
+		{"FA", 2027, "FA-2027-000001", 1},
+		{"FA", 2026, "FA-2026-000003", 3},
+	}
+	for _, tt := range tests {
+		var number string
+		var sequence int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			number, sequence, err = nextInvoiceNumber(tx, tt.series, tt.year)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("nextInvoiceNumber(%s, %d) returned error: %v", tt.series, tt.year, err)
+		}
+		if number != tt.want || sequence != tt.sequence {
+			t.Errorf("nextInvoiceNumber(%s, %d) = %s, %d, want %s, %d", tt.series, tt.year, number, sequence, tt.want, tt.sequence)
+		}
+	}
+}
+
+func TestNextInvoiceNumberRollback(t *testing.T) {
+	db := newTestDB(t)
+	failure := errors.New("échec de l'émission")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if _, _, err := nextInvoiceNumber(tx, "FA", 2026); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("transaction error = %v, want %v", err, failure)
+	}
+	// Le numéro réservé par une émission annulée est rendu
+	var number string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		number, _, err = nextInvoiceNumber(tx, "FA", 2026)
+		return err
+	})
+	if err != nil || number != "FA-2026-000001" {
+		t.Errorf("nextInvoiceNumber after rollback = %q, %v, want FA-2026-000001", number, err)
+	}
+}
+
+func TestIssueInvoiceGaplessNumbering(t *testing.T) {
+	db := newTestDB(t)
+	first := newDraftInvoice(t, db, "FA", 3000)
+	deleted := newDraftInvoice(t, db, "FA", 4500)
+	second := newDraftInvoice(t, db, "FA", 1500)
+
+	// Un brouillon supprimé n'a jamais reçu de numéro
+	if err := DeleteInvoiceDraft(db, deleted); err != nil {
+		t.Fatalf("DeleteInvoiceDraft returned error: %v", err)
+	}
+	// Les brouillons sont numérotés dans l'ordre de leur émission
+	for _, invoice := range []*models.Invoice{second, first} {
+		if err := IssueInvoice(db, invoice, 1); err != nil {
+			t.Fatalf("IssueInvoice(%d) returned error: %v", invoice.ID, err)
+		}
+	}
+	// Une facture déjà émise ne consomme pas de nouveau numéro
+	if err := IssueInvoice(db, first, 1); !errors.Is(err, models.ErrInvoiceIssued) {
+		t.Errorf("IssueInvoice of an issued invoice error = %v, want ErrInvoiceIssued", err)
+	}
+	third := newDraftInvoice(t, db, "FA", 2000)
+	if err := IssueInvoice(db, third, 1); err != nil {
+		t.Fatalf("IssueInvoice(%d) returned error: %v", third.ID, err)
+	}
+
+	year := time.Now().In(utils.CalendarLocation()).Year()
+	var issued []models.Invoice
+	if err := db.Where("number IS NOT NULL").Order("sequence").Find(&issued).Error; err != nil {
+		t.Fatalf("load issued invoices: %v", err)
+	}
+	wantIDs := []uint{second.ID, first.ID, third.ID}
+	if len(issued) != len(wantIDs) {
+		t.Fatalf("got %d issued invoices, want %d", len(issued), len(wantIDs))
+	}
+	for i, invoice := range issued {
+		want := fmt.Sprintf("FA-%d-%06d", year, i+1)
+		if invoice.ID != wantIDs[i] || *invoice.Number != want || invoice.Year != year || invoice.Sequence != i+1 ||
+			invoice.Status != models.InvoiceStatusIssued || invoice.IssueDate == nil {
+			t.Errorf("issued invoice %d = id %d, number %s, sequence %d, status %s; want id %d, number %s",
+				i, invoice.ID, *invoice.Number, invoice.Sequence, invoice.Status, wantIDs[i], want)
+		}
+	}
+}
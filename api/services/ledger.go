@@ -0,0 +1,375 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ledgerEntrySeries est la série des numéros d'écriture, continus par année
+const ledgerEntrySeries = "EC"
+
+// ledgerPosting prépare les lignes d'une écriture à partir du plan comptable
+type ledgerPosting struct {
+	accounts map[models.LedgerAccountRole]models.LedgerAccount
+	lines    []models.LedgerLine
+}
+
+// ledgerAux identifie le compte auxiliaire d'une famille ou d'un enseignant
+type ledgerAux struct {
+	number string
+	label  string
+}
+
+// LedgerAccounts retourne le plan comptable, complété des comptes par défaut manquants
+func LedgerAccounts(db *gorm.DB) ([]models.LedgerAccount, error) {
+	var accounts []models.LedgerAccount
+	if err := db.Order("number, id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	known := map[models.LedgerAccountRole]bool{}
+	for _, account := range accounts {
+		known[account.Role] = true
+	}
+	for _, account := range models.DefaultLedgerAccounts {
+		if known[account.Role] {
+			continue
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	sort.SliceStable(accounts, func(i, j int) bool { return accounts[i].Number < accounts[j].Number })
+	return accounts, nil
+}
+
+// UpdateLedgerAccount modifie le numéro ou le libellé d'un compte ; les écritures
+// déjà passées conservent le numéro et le libellé en vigueur lors de leur saisie
+func UpdateLedgerAccount(db *gorm.DB, account *models.LedgerAccount, req models.LedgerAccountUpdateRequest) error {
+	account.Number = strings.TrimSpace(req.Number)
+	account.Label = strings.TrimSpace(req.Label)
+	return db.Model(account).Select("Number", "Label").Updates(account).Error
+}
+
+func newLedgerPosting(tx *gorm.DB) (*ledgerPosting, error) {
+	accounts, err := LedgerAccounts(tx)
+	if err != nil {
+		return nil, err
+	}
+	posting := &ledgerPosting{accounts: map[models.LedgerAccountRole]models.LedgerAccount{}}
+	for _, account := range accounts {
+		posting.accounts[account.Role] = account
+	}
+	return posting, nil
+}
+
+// debit ajoute un débit ; un montant négatif est porté au crédit et un montant nul ignoré
+func (p *ledgerPosting) debit(role models.LedgerAccountRole, aux *ledgerAux, amount models.Money) {
+	if amount.Cents == 0 {
+		return
+	}
+	account := p.accounts[role]
+	line := models.LedgerLine{
+		Position:      len(p.lines) + 1,
+		AccountNumber: account.Number,
+		AccountLabel:  account.Label,
+		Debit:         models.NewMoney(0, amount.Currency),
+		Credit:        models.NewMoney(0, amount.Currency),
+	}
+	if aux != nil {
+		line.AuxNumber, line.AuxLabel = aux.number, aux.label
+	}
+	if amount.Cents > 0 {
+		line.Debit = amount
+	} else {
+		line.Credit = amount.Neg()
+	}
+	p.lines = append(p.lines, line)
+}
+
+// credit ajoute un crédit ; un montant négatif est porté au débit
+func (p *ledgerPosting) credit(role models.LedgerAccountRole, aux *ledgerAux, amount models.Money) {
+	p.debit(role, aux, amount.Neg())
+}
+
+// customerAux retourne le compte auxiliaire client d'une famille
+func customerAux(tx *gorm.DB, familleID uint) *ledgerAux {
+	name, _ := invoiceCustomer(tx, familleID)
+	return &ledgerAux{number: fmt.Sprintf("FAM%06d", familleID), label: name}
+}
+
+// teacherAux retourne le compte auxiliaire fournisseur d'un enseignant
+func teacherAux(tx *gorm.DB, enseignantID uint) *ledgerAux {
+	var user models.User
+	tx.Unscoped().Select("id", "username").First(&user, enseignantID)
+	return &ledgerAux{number: fmt.Sprintf("ENS%06d", enseignantID), label: user.Username}
+}
+
+// postLedgerEntry numérote et enregistre une écriture. Une écriture déjà passée
+// pour le même événement du même document n'est pas dupliquée ; une écriture sans
+// ligne (montants nuls) n'est pas enregistrée.
+func postLedgerEntry(tx *gorm.DB, entry *models.LedgerEntry, posting *ledgerPosting) error {
+	if len(posting.lines) == 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.LedgerEntry{}).
+		Where("source_type = ? AND source_id = ? AND event = ?", entry.SourceType, entry.SourceID, entry.Event).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	entry.Lines = posting.lines
+	if !entry.IsBalanced() {
+		return models.ErrLedgerUnbalanced
+	}
+	if entry.PieceDate.IsZero() {
+		entry.PieceDate = entry.EntryDate
+	}
+	number, _, err := nextInvoiceNumber(tx, ledgerEntrySeries, entry.EntryDate.In(utils.CalendarLocation()).Year())
+	if err != nil {
+		return err
+	}
+	entry.Number = number
+	return tx.Create(entry).Error
+}
+
+// reverseLedgerEntry passe l'écriture de contrepassation d'un événement déjà
+// comptabilisé ; rien n'est passé si l'événement d'origine n'a pas été comptabilisé
+func reverseLedgerEntry(tx *gorm.DB, sourceType models.LedgerSourceType, sourceID uint, original, event models.LedgerEvent,
+	journal models.LedgerJournal, label string, at time.Time) error {
+	var entry models.LedgerEntry
+	err := tx.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		Where("source_type = ? AND source_id = ? AND event = ?", sourceType, sourceID, original).
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	posting := &ledgerPosting{}
+	for _, line := range entry.Lines {
+		posting.lines = append(posting.lines, models.LedgerLine{
+			Position:      line.Position,
+			AccountNumber: line.AccountNumber,
+			AccountLabel:  line.AccountLabel,
+			AuxNumber:     line.AuxNumber,
+			AuxLabel:      line.AuxLabel,
+			Debit:         line.Credit,
+			Credit:        line.Debit,
+		})
+	}
+	return postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:         journal,
+		EntryDate:       at,
+		Label:           label,
+		PieceRef:        entry.PieceRef,
+		PieceDate:       entry.PieceDate,
+		SourceType:      sourceType,
+		SourceID:        sourceID,
+		Event:           event,
+		ReversedEntryID: &entry.ID,
+	}, posting)
+}
+
+// postInvoiceEntries comptabilise une facture ou un avoir émis : la créance TTC
+// sur la famille, le chiffre d'affaires HT et la TVA collectée (en sens inverse
+// pour un avoir), puis l'imputation des acomptes de la famille
+func postInvoiceEntries(tx *gorm.DB, invoice *models.Invoice) error {
+	posting, err := newLedgerPosting(tx)
+	if err != nil {
+		return err
+	}
+	customer := customerAux(tx, invoice.FamilleID)
+	label := "Facture " + *invoice.Number
+	if invoice.Kind == models.InvoiceKindCreditNote {
+		label = "Avoir " + *invoice.Number
+	}
+	posting.debit(models.LedgerAccountCustomers, customer, invoice.TotalTTC)
+	posting.credit(models.LedgerAccountSales, nil, invoice.TotalHT)
+	posting.credit(models.LedgerAccountVATCollected, nil, invoice.VATAmount)
+	if err := postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:    models.LedgerJournalSales,
+		EntryDate:  *invoice.IssueDate,
+		Label:      label + " - " + customer.label,
+		PieceRef:   *invoice.Number,
+		SourceType: models.LedgerSourceInvoice,
+		SourceID:   invoice.ID,
+		Event:      models.LedgerEventIssued,
+	}, posting); err != nil {
+		return err
+	}
+
+	advances := &ledgerPosting{accounts: posting.accounts}
+	advances.debit(models.LedgerAccountCustomerAdvances, customer, invoice.AdvanceAmount)
+	advances.credit(models.LedgerAccountCustomers, customer, invoice.AdvanceAmount)
+	return postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:    models.LedgerJournalMisc,
+		EntryDate:  *invoice.IssueDate,
+		Label:      "Imputation des acomptes - " + label,
+		PieceRef:   *invoice.Number,
+		SourceType: models.LedgerSourceInvoice,
+		SourceID:   invoice.ID,
+		Event:      models.LedgerEventAdvances,
+	}, advances)
+}
+
+// postInvoiceAdvancesRelease contrepasse l'imputation des acomptes d'une facture annulée par un avoir
+func postInvoiceAdvancesRelease(tx *gorm.DB, invoice *models.Invoice, at time.Time) error {
+	return reverseLedgerEntry(tx, models.LedgerSourceInvoice, invoice.ID, models.LedgerEventAdvances,
+		models.LedgerEventAdvancesReleased, models.LedgerJournalMisc, "Libération des acomptes - Facture "+*invoice.Number, at)
+}
+
+// postPaymentEntry comptabilise un paiement encaissé ou versé, selon son objet :
+// remboursement, règlement de facture, acompte ou avance, pénalité, avoir, ou
+// vente au comptant pour les cours et frais réglés sans facture
+func postPaymentEntry(tx *gorm.DB, payment *models.Payment) error {
+	posting, err := newLedgerPosting(tx)
+	if err != nil {
+		return err
+	}
+	var user models.User
+	if err := tx.Unscoped().Select("id", "username", "role").First(&user, payment.UserID).Error; err != nil {
+		return err
+	}
+	amount := payment.Amount
+	switch {
+	case payment.Type == models.PaymentTypeRefund:
+		role, aux, err := refundLedgerAccount(tx, payment)
+		if err != nil {
+			return err
+		}
+		posting.debit(role, aux, amount)
+		posting.credit(models.LedgerAccountBank, nil, amount)
+	case payment.InvoiceID != nil:
+		posting.debit(models.LedgerAccountBank, nil, amount)
+		posting.credit(models.LedgerAccountCustomers, customerAux(tx, user.ID), amount)
+	case payment.Type == models.PaymentTypeAdvance && user.Role == models.RoleEnseignant:
+		posting.debit(models.LedgerAccountTeacherAdvances, teacherAux(tx, user.ID), amount)
+		posting.credit(models.LedgerAccountBank, nil, amount)
+	case payment.Type == models.PaymentTypeAdvance:
+		posting.debit(models.LedgerAccountBank, nil, amount)
+		posting.credit(models.LedgerAccountCustomerAdvances, customerAux(tx, user.ID), amount)
+	case payment.Type == models.PaymentTypePenalty:
+		posting.debit(models.LedgerAccountBank, nil, amount)
+		posting.credit(models.LedgerAccountOtherIncome, nil, amount)
+	case payment.Type == models.PaymentTypeCredit:
+		posting.debit(models.LedgerAccountSalesDiscounts, nil, amount)
+		posting.credit(models.LedgerAccountBank, nil, amount)
+	default:
+		vat := includedVAT(amount, CurrentInvoiceSettings().VATRate)
+		posting.debit(models.LedgerAccountBank, nil, amount)
+		posting.credit(models.LedgerAccountSales, nil, amount.Sub(vat))
+		posting.credit(models.LedgerAccountVATCollected, nil, vat)
+	}
+	label := payment.Description
+	if label == "" {
+		label = fmt.Sprintf("Paiement n° %d", payment.ID)
+	}
+	return postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:    models.LedgerJournalBank,
+		EntryDate:  payment.PaymentDate,
+		Label:      label + " - " + user.Username,
+		PieceRef:   ledgerPaymentRef(payment),
+		SourceType: models.LedgerSourcePayment,
+		SourceID:   payment.ID,
+		Event:      models.LedgerEventCompleted,
+	}, posting)
+}
+
+// refundLedgerAccount retourne le compte débité par un remboursement : le compte
+// client, soldé par l'avoir émis, ou le compte de l'acompte ou de la pénalité remboursés
+func refundLedgerAccount(tx *gorm.DB, refund *models.Payment) (models.LedgerAccountRole, *ledgerAux, error) {
+	var original models.Payment
+	if refund.RefundedPaymentID != nil {
+		if err := tx.Unscoped().First(&original, *refund.RefundedPaymentID).Error; err != nil {
+			return "", nil, err
+		}
+	}
+	switch {
+	case refund.InvoiceID == nil && original.Type == models.PaymentTypeAdvance:
+		return models.LedgerAccountCustomerAdvances, customerAux(tx, refund.UserID), nil
+	case refund.InvoiceID == nil && original.Type == models.PaymentTypePenalty:
+		return models.LedgerAccountOtherIncome, nil, nil
+	}
+	return models.LedgerAccountCustomers, customerAux(tx, refund.UserID), nil
+}
+
+// postDebitReturn contrepasse l'encaissement d'un prélèvement rejeté après coup
+func postDebitReturn(tx *gorm.DB, payment *models.Payment, at time.Time) error {
+	return reverseLedgerEntry(tx, models.LedgerSourcePayment, payment.ID, models.LedgerEventCompleted,
+		models.LedgerEventReturned, models.LedgerJournalBank, fmt.Sprintf("Rejet du prélèvement %s", ledgerPaymentRef(payment)), at)
+}
+
+// postPayoutEntry comptabilise un relevé verrouillé : la charge brute, les avances
+// et les pénalités déduites et le net dû à l'enseignant
+func postPayoutEntry(tx *gorm.DB, payout *models.Payout) error {
+	posting, err := newLedgerPosting(tx)
+	if err != nil {
+		return err
+	}
+	teacher := teacherAux(tx, payout.EnseignantID)
+	posting.debit(models.LedgerAccountTeacherFees, nil, payout.GrossAmount)
+	posting.credit(models.LedgerAccountTeacherAdvances, teacher, payout.AdvancesAmount)
+	posting.credit(models.LedgerAccountOtherIncome, nil, payout.PenaltiesAmount)
+	posting.credit(models.LedgerAccountTeachers, teacher, payout.NetAmount)
+	return postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:    models.LedgerJournalPurchases,
+		EntryDate:  *payout.LockedAt,
+		Label:      fmt.Sprintf("Relevé %s - %s", payoutPeriodLabel(payout), teacher.label),
+		PieceRef:   ledgerPayoutRef(payout),
+		SourceType: models.LedgerSourcePayout,
+		SourceID:   payout.ID,
+		Event:      models.LedgerEventLocked,
+	}, posting)
+}
+
+// postPayoutPaid comptabilise le virement du net d'un relevé
+func postPayoutPaid(tx *gorm.DB, payout *models.Payout) error {
+	posting, err := newLedgerPosting(tx)
+	if err != nil {
+		return err
+	}
+	teacher := teacherAux(tx, payout.EnseignantID)
+	posting.debit(models.LedgerAccountTeachers, teacher, payout.NetAmount)
+	posting.credit(models.LedgerAccountBank, nil, payout.NetAmount)
+	return postLedgerEntry(tx, &models.LedgerEntry{
+		Journal:    models.LedgerJournalBank,
+		EntryDate:  *payout.PaidAt,
+		Label:      fmt.Sprintf("Virement relevé %s - %s", payoutPeriodLabel(payout), teacher.label),
+		PieceRef:   ledgerPayoutRef(payout),
+		PieceDate:  *payout.LockedAt,
+		SourceType: models.LedgerSourcePayout,
+		SourceID:   payout.ID,
+		Event:      models.LedgerEventPaid,
+	}, posting)
+}
+
+func ledgerPaymentRef(payment *models.Payment) string {
+	if payment.Type == models.PaymentTypeRefund && payment.ProviderRefundID != "" {
+		return payment.ProviderRefundID
+	}
+	if payment.ProviderIntentID != "" {
+		return payment.ProviderIntentID
+	}
+	return fmt.Sprintf("PAY-%06d", payment.ID)
+}
+
+func ledgerPayoutRef(payout *models.Payout) string {
+	if payout.PaymentReference != "" {
+		return payout.PaymentReference
+	}
+	return fmt.Sprintf("REL-%06d", payout.ID)
+}
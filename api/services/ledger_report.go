@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidLedgerPeriod = errors.New("période invalide : indiquez from et to au format YYYY-MM-DD, to étant inclus")
+
+var ledgerJournalLabels = map[models.LedgerJournal]string{
+	models.LedgerJournalSales:     "Journal des ventes",
+	models.LedgerJournalPurchases: "Journal des achats",
+	models.LedgerJournalBank:      "Journal de banque",
+	models.LedgerJournalMisc:      "Opérations diverses",
+}
+
+var nonDigits = regexp.MustCompile(`\D`)
+
+// ParseLedgerPeriod retourne les bornes [début, fin[ de la période demandée, en
+// heure locale ; l'année civile en cours par défaut
+func ParseLedgerPeriod(from, to string, now time.Time) (time.Time, time.Time, error) {
+	loc := utils.CalendarLocation()
+	year := now.In(loc).Year()
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", from, loc); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidLedgerPeriod
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, loc); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidLedgerPeriod
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, ErrInvalidLedgerPeriod
+	}
+	return start, end, nil
+}
+
+// ledgerLines sélectionne les lignes des écritures de la période
+func ledgerLines(db *gorm.DB, start, end time.Time) *gorm.DB {
+	return db.Model(&models.LedgerLine{}).
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id").
+		Where("ledger_entries.entry_date >= ? AND ledger_entries.entry_date < ?", start, end)
+}
+
+// ExportFEC produit le fichier des écritures comptables de la période et son nom
+// réglementaire (SIREN de l'émetteur et date de clôture)
+func ExportFEC(db *gorm.DB, start, end time.Time) ([]byte, string, error) {
+	var entries []models.LedgerEntry
+	if err := db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		Where("entry_date >= ? AND entry_date < ?", start, end).
+		Order("number").Find(&entries).Error; err != nil {
+		return nil, "", err
+	}
+	var lines []utils.FECLine
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			lines = append(lines, utils.FECLine{
+				JournalCode:  string(entry.Journal),
+				JournalLib:   ledgerJournalLabels[entry.Journal],
+				EcritureNum:  entry.Number,
+				EcritureDate: entry.EntryDate,
+				CompteNum:    line.AccountNumber,
+				CompteLib:    line.AccountLabel,
+				CompAuxNum:   line.AuxNumber,
+				CompAuxLib:   line.AuxLabel,
+				PieceRef:     entry.PieceRef,
+				PieceDate:    entry.PieceDate,
+				EcritureLib:  entry.Label,
+				DebitCents:   line.Debit.Cents,
+				CreditCents:  line.Credit.Cents,
+				ValidDate:    entry.CreatedAt,
+				Currency:     string(line.Debit.Currency),
+			})
+		}
+	}
+	siren := nonDigits.ReplaceAllString(CurrentInvoiceSettings().SellerSIRET, "")
+	if len(siren) > 9 {
+		siren = siren[:9]
+	}
+	return utils.FECFile(lines), utils.FECFileName(siren, end.AddDate(0, 0, -1)), nil
+}
+
+// LedgerTrialBalance calcule la balance générale : total des débits et des
+// crédits de chaque compte sur la période, et solde
+func LedgerTrialBalance(db *gorm.DB, start, end time.Time) (*models.LedgerTrialBalanceResponse, error) {
+	var rows []struct {
+		AccountNumber string
+		AccountLabel  string
+		Debit         int64
+		Credit        int64
+	}
+	err := ledgerLines(db, start, end).
+		Select("ledger_lines.account_number, MAX(ledger_lines.account_label) AS account_label, " +
+			"SUM(ledger_lines.debit_cents) AS debit, SUM(ledger_lines.credit_cents) AS credit").
+		Group("ledger_lines.account_number").Order("ledger_lines.account_number").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	balance := &models.LedgerTrialBalanceResponse{
+		From:        start,
+		To:          end,
+		Accounts:    []models.LedgerTrialBalanceAccount{},
+		TotalDebit:  models.EUR(0),
+		TotalCredit: models.EUR(0),
+	}
+	for _, row := range rows {
+		balance.Accounts = append(balance.Accounts, models.LedgerTrialBalanceAccount{
+			AccountNumber: row.AccountNumber,
+			AccountLabel:  row.AccountLabel,
+			Debit:         models.EUR(row.Debit),
+			Credit:        models.EUR(row.Credit),
+			Balance:       models.EUR(row.Debit - row.Credit),
+		})
+		balance.TotalDebit = balance.TotalDebit.Add(models.EUR(row.Debit))
+		balance.TotalCredit = balance.TotalCredit.Add(models.EUR(row.Credit))
+	}
+	return balance, nil
+}
+
+// LedgerAccountStatement construit le relevé d'un compte, éventuellement limité à
+// un compte auxiliaire : solde d'ouverture, mouvements de la période avec le solde
+// progressif, et solde de clôture
+func LedgerAccountStatement(db *gorm.DB, accountNumber, auxNumber string, start, end time.Time) (*models.LedgerStatementResponse, error) {
+	filter := func(query *gorm.DB) *gorm.DB {
+		query = query.Where("ledger_lines.account_number = ?", accountNumber)
+		if auxNumber != "" {
+			query = query.Where("ledger_lines.aux_number = ?", auxNumber)
+		}
+		return query
+	}
+	var opening struct {
+		Debit  int64
+		Credit int64
+	}
+	err := filter(db.Model(&models.LedgerLine{}).
+		Joins("JOIN ledger_entries ON ledger_entries.id = ledger_lines.entry_id").
+		Where("ledger_entries.entry_date < ?", start)).
+		Select("COALESCE(SUM(ledger_lines.debit_cents), 0) AS debit, COALESCE(SUM(ledger_lines.credit_cents), 0) AS credit").
+		Scan(&opening).Error
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		EntryID     uint
+		EntryNumber string
+		Journal     models.LedgerJournal
+		EntryDate   time.Time
+		Label       string
+		PieceRef    string
+		AuxNumber   string
+		Debit       int64
+		Credit      int64
+	}
+	err = filter(ledgerLines(db, start, end)).
+		Select("ledger_entries.id AS entry_id, ledger_entries.number AS entry_number, ledger_entries.journal, " +
+			"ledger_entries.entry_date, ledger_entries.label, ledger_entries.piece_ref, ledger_lines.aux_number, " +
+			"ledger_lines.debit_cents AS debit, ledger_lines.credit_cents AS credit").
+		Order("ledger_entries.entry_date, ledger_entries.number, ledger_lines.position").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.LedgerStatementResponse{
+		AccountNumber:  accountNumber,
+		AuxNumber:      auxNumber,
+		From:           start,
+		To:             end,
+		OpeningBalance: models.EUR(opening.Debit - opening.Credit),
+		Lines:          []models.LedgerStatementLine{},
+		TotalDebit:     models.EUR(0),
+		TotalCredit:    models.EUR(0),
+	}
+	balance := statement.OpeningBalance
+	for _, row := range rows {
+		balance = balance.Add(models.EUR(row.Debit - row.Credit))
+		statement.Lines = append(statement.Lines, models.LedgerStatementLine{
+			EntryID:     row.EntryID,
+			EntryNumber: row.EntryNumber,
+			Journal:     row.Journal,
+			EntryDate:   row.EntryDate,
+			Label:       row.Label,
+			PieceRef:    row.PieceRef,
+			AuxNumber:   row.AuxNumber,
+			Debit:       models.EUR(row.Debit),
+			Credit:      models.EUR(row.Credit),
+			Balance:     balance,
+		})
+		statement.TotalDebit = statement.TotalDebit.Add(models.EUR(row.Debit))
+		statement.TotalCredit = statement.TotalCredit.Add(models.EUR(row.Credit))
+	}
+	statement.ClosingBalance = balance
+	return statement, nil
+}
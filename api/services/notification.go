@@ -0,0 +1,41 @@
+package services
+
+import (
+	"api/models"
+
+	"gorm.io/gorm"
+)
+
+// Notify enregistre une notification pour un utilisateur. Les identifiants nuls
+// (partie inconnue) sont ignorés.
+func Notify(tx *gorm.DB, userID uint, notifType models.NotificationType, title, message, link string) error {
+	if userID == 0 {
+		return nil
+	}
+	return tx.Create(&models.Notification{
+		UserID:  userID,
+		Type:    notifType,
+		Title:   title,
+		Message: message,
+		Link:    link,
+	}).Error
+}
+
+// NotifyAll envoie la même notification à plusieurs utilisateurs
+func NotifyAll(tx *gorm.DB, userIDs []uint, notifType models.NotificationType, title, message, link string) error {
+	for _, id := range userIDs {
+		if err := Notify(tx, id, notifType, title, message, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyAdmins envoie une notification à tous les administrateurs
+func NotifyAdmins(tx *gorm.DB, notifType models.NotificationType, title, message, link string) error {
+	var adminIDs []uint
+	if err := tx.Model(&models.User{}).Where("role = ?", models.RoleAdministrator).Pluck("id", &adminIDs).Error; err != nil {
+		return err
+	}
+	return NotifyAll(tx, adminIDs, notifType, title, message, link)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func offerLink(offerID uint) string {
+	return fmt.Sprintf("/api/v1/offers/%d", offerID)
+}
+
+// RecordOfferRevision conserve l'état de l'offre à une étape de sa vie publique
+func RecordOfferRevision(tx *gorm.DB, offer *models.Offer, action models.OfferRevisionAction, actorID uint) error {
+	offer.Revision++
+	if err := tx.Model(offer).UpdateColumn("revision", offer.Revision).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.OfferRevision{
+		OfferID:      offer.ID,
+		Revision:     offer.Revision,
+		Action:       action,
+		Status:       offer.Status,
+		Title:        offer.Title,
+		Description:  offer.Description,
+		Requirements: offer.Requirements,
+		Subject:      offer.Subject,
+		Level:        offer.Level,
+		HourlyRate:   offer.HourlyRate,
+		Deadline:     offer.Deadline,
+		ActorID:      actorID,
+	}).Error
+}
+
+// saveOfferLifecycle enregistre le statut et les dates de publication de l'offre
+func saveOfferLifecycle(tx *gorm.DB, offer *models.Offer) error {
+	return tx.Model(offer).Select("status", "publication_date", "publish_at", "closed_at").Updates(offer).Error
+}
+
+// changeOffer verrouille l'offre, lui applique une étape du cycle de publication et
+// l'historise
+func changeOffer(db *gorm.DB, offerID uint, action models.OfferRevisionAction, actorID uint, change func(*models.Offer) error) (*models.Offer, error) {
+	var offer models.Offer
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, offerID).Error; err != nil {
+			return err
+		}
+		if err := change(&offer); err != nil {
+			return err
+		}
+		if err := saveOfferLifecycle(tx, &offer); err != nil {
+			return err
+		}
+		return RecordOfferRevision(tx, &offer, action, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// PublishOffer ouvre immédiatement une offre en brouillon aux candidatures
+func PublishOffer(db *gorm.DB, offerID, adminID uint) (*models.Offer, error) {
+	return changeOffer(db, offerID, models.OfferActionPublished, adminID, func(offer *models.Offer) error {
+		return offer.Publish(time.Now())
+	})
+}
+
+// ScheduleOfferPublication programme la publication d'une offre en brouillon
+func ScheduleOfferPublication(db *gorm.DB, offerID uint, publishAt time.Time, adminID uint) (*models.Offer, error) {
+	return changeOffer(db, offerID, models.OfferActionScheduled, adminID, func(offer *models.Offer) error {
+		return offer.SchedulePublication(publishAt, time.Now())
+	})
+}
+
+// UnpublishOffer retire une offre publiée, qui repasse en brouillon, ou annule sa
+// publication programmée. Les candidatures déjà reçues sont conservées.
+func UnpublishOffer(db *gorm.DB, offerID, adminID uint) (*models.Offer, error) {
+	return changeOffer(db, offerID, models.OfferActionUnpublished, adminID, func(offer *models.Offer) error {
+		return offer.Unpublish()
+	})
+}
+
+// CloseOffer clôt manuellement une offre : elle n'accepte plus de candidatures
+func CloseOffer(db *gorm.DB, offerID, adminID uint) (*models.Offer, error) {
+	return changeOffer(db, offerID, models.OfferActionClosed, adminID, func(offer *models.Offer) error {
+		if offer.Status == models.OfferStatusClosed || offer.Status == models.OfferStatusFilled {
+			return models.ErrOfferNotOpen
+		}
+		return offer.CloseOffer(models.OfferStatusClosed, time.Now())
+	})
+}
+
+// PublishScheduledOffers publie les brouillons dont la date de publication programmée
+// est atteinte et en informe leur auteur
+func PublishScheduledOffers(db *gorm.DB, now time.Time) (int, error) {
+	var ids []uint
+	if err := db.Model(&models.Offer{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.OfferStatusDraft, now).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var offer models.Offer
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, id).Error; err != nil {
+				return err
+			}
+			// Publication annulée ou déjà faite entre-temps
+			if offer.Status != models.OfferStatusDraft || offer.PublishAt == nil || offer.PublishAt.After(now) {
+				return nil
+			}
+			if err := offer.Publish(now); err != nil {
+				// Date limite dépassée avant la publication : le brouillon est conservé
+				if errors.Is(err, models.ErrOfferDeadlinePassed) {
+					return tx.Model(&offer).Update("publish_at", nil).Error
+				}
+				return err
+			}
+			if err := saveOfferLifecycle(tx, &offer); err != nil {
+				return err
+			}
+			if err := RecordOfferRevision(tx, &offer, models.OfferActionPublished, 0); err != nil {
+				return err
+			}
+			published++
+			return Notify(tx, offer.CreatedByID, models.NotificationOfferPublished, "Offre publiée",
+				fmt.Sprintf("L'offre « %s » a été publiée comme programmé", offer.Title), offerLink(offer.ID))
+		})
+		if err != nil {
+			return published, err
+		}
+	}
+	return published, nil
+}
+
+// CloseExpiredOffers clôt les offres ouvertes dont la date limite de candidature est
+// passée, et marque pourvues celles qui ont déjà un enseignant retenu ou une mission
+// en cours
+func CloseExpiredOffers(db *gorm.DB, now time.Time) (int, error) {
+	filled := db.Model(&models.OfferApplication{}).Select("1").
+		Where("enseignant_offers.offer_id = offers.id AND enseignant_offers.status = ?", models.OfferApplicationAccepted)
+	missions := db.Model(&models.Mission{}).Select("1").
+		Where("missions.offer_id = offers.id AND missions.status = ?", models.MissionStatusActive)
+
+	var offers []models.Offer
+	if err := db.Where("status = ?", models.OfferStatusOpen).
+		Where("(deadline IS NOT NULL AND deadline < ?) OR EXISTS (?) OR EXISTS (?)", now, filled, missions).
+		Find(&offers).Error; err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, candidate := range offers {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var offer models.Offer
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, candidate.ID).Error; err != nil {
+				return err
+			}
+			if offer.Status != models.OfferStatusOpen {
+				return nil
+			}
+			var teachers int64
+			if err := tx.Model(&models.OfferApplication{}).
+				Where("offer_id = ? AND status = ?", offer.ID, models.OfferApplicationAccepted).
+				Count(&teachers).Error; err != nil {
+				return err
+			}
+			if teachers == 0 {
+				if err := tx.Model(&models.Mission{}).
+					Where("offer_id = ? AND status = ?", offer.ID, models.MissionStatusActive).
+					Count(&teachers).Error; err != nil {
+					return err
+				}
+			}
+
+			status, action, title := models.OfferStatusFilled, models.OfferActionFilled, "Offre pourvue"
+			message := fmt.Sprintf("L'offre « %s » a été marquée pourvue", offer.Title)
+			if teachers == 0 {
+				if !offer.IsPastDeadline(now) {
+					return nil
+				}
+				status, action, title = models.OfferStatusClosed, models.OfferActionClosed, "Offre close"
+				message = fmt.Sprintf("L'offre « %s » a été close : la date limite de candidature est passée", offer.Title)
+			}
+			offer.CloseOffer(status, now)
+			if err := saveOfferLifecycle(tx, &offer); err != nil {
+				return err
+			}
+			if err := RecordOfferRevision(tx, &offer, action, 0); err != nil {
+				return err
+			}
+			closed++
+			return Notify(tx, offer.CreatedByID, models.NotificationOfferClosed, title, message, offerLink(offer.ID))
+		})
+		if err != nil {
+			return closed, err
+		}
+	}
+	return closed, nil
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMissionFamilleRequired = errors.New("la famille est requise pour créer la mission")
+	ErrMissionFamilleNotFound = errors.New("famille de la mission introuvable")
+)
+
+func offerApplicationsLink(offerID uint) string {
+	return fmt.Sprintf("/api/v1/offers/%d/applications", offerID)
+}
+
+func enseignantApplicationsLink(enseignantID uint) string {
+	return fmt.Sprintf("/api/v1/enseignants/%d/applications", enseignantID)
+}
+
+// lockOfferApplication charge et verrouille la candidature d'un enseignant à une offre
+func lockOfferApplication(tx *gorm.DB, offerID, enseignantID uint) (*models.OfferApplication, error) {
+	var application models.OfferApplication
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Offer").
+		Where("offer_id = ? AND enseignant_user_id = ?", offerID, enseignantID).
+		First(&application).Error; err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// ApplyForOffer enregistre la candidature d'un enseignant à une offre ouverte et
+// prévient l'auteur de l'offre. Un enseignant qui a retiré sa candidature peut
+// postuler à nouveau.
+func ApplyForOffer(db *gorm.DB, offerID, enseignantID uint, req models.OfferApplicationRequest) (*models.OfferApplication, error) {
+	var application *models.OfferApplication
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var offer models.Offer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, offerID).Error; err != nil {
+			return err
+		}
+		var err error
+		application, err = offer.ApplyForOffer(enseignantID, req, time.Now())
+		if err != nil {
+			return err
+		}
+
+		var existing models.OfferApplication
+		err = tx.Where("offer_id = ? AND enseignant_user_id = ?", offerID, enseignantID).First(&existing).Error
+		switch {
+		case err == nil:
+			if existing.Status != models.OfferApplicationWithdrawn {
+				return models.ErrAlreadyApplied
+			}
+			application.CreatedAt = existing.CreatedAt
+			if err := tx.Omit(clause.Associations).Save(application).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(application).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		var teacher models.User
+		if err := tx.First(&teacher, enseignantID).Error; err != nil {
+			return err
+		}
+		title := "Nouvelle candidature"
+		message := fmt.Sprintf("%s a postulé à l'offre « %s »", teacher.Username, offer.Title)
+		if offer.CreatedByID != 0 {
+			return Notify(tx, offer.CreatedByID, models.NotificationApplicationReceived, title, message, offerApplicationsLink(offerID))
+		}
+		return NotifyAdmins(tx, models.NotificationApplicationReceived, title, message, offerApplicationsLink(offerID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	application.Offer = nil
+	return application, nil
+}
+
+// WithdrawApplication retire la candidature d'un enseignant tant qu'aucune décision
+// n'a été prise
+func WithdrawApplication(db *gorm.DB, offerID, enseignantID uint) (*models.OfferApplication, error) {
+	var application *models.OfferApplication
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		application, err = lockOfferApplication(tx, offerID, enseignantID)
+		if err != nil {
+			return err
+		}
+		if err := application.Withdraw(time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(application).Error; err != nil {
+			return err
+		}
+
+		var teacher models.User
+		if err := tx.First(&teacher, enseignantID).Error; err != nil {
+			return err
+		}
+		title := "Candidature retirée"
+		message := fmt.Sprintf("%s a retiré sa candidature à l'offre « %s »", teacher.Username, application.Offer.Title)
+		if application.Offer.CreatedByID != 0 {
+			return Notify(tx, application.Offer.CreatedByID, models.NotificationApplicationWithdrawn, title, message, offerApplicationsLink(offerID))
+		}
+		return NotifyAdmins(tx, models.NotificationApplicationWithdrawn, title, message, offerApplicationsLink(offerID))
+	})
+	return application, err
+}
+
+// ReviewApplication présélectionne ou refuse une candidature et en informe l'enseignant
+func ReviewApplication(db *gorm.DB, offerID, enseignantID uint, status models.OfferApplicationStatus, note string, adminID uint) (*models.OfferApplication, error) {
+	var application *models.OfferApplication
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		application, err = lockOfferApplication(tx, offerID, enseignantID)
+		if err != nil {
+			return err
+		}
+		if err := application.Review(status, note, adminID, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(application).Error; err != nil {
+			return err
+		}
+		return notifyApplicationReviewed(tx, application)
+	})
+	return application, err
+}
+
+// notifyApplicationReviewed informe l'enseignant de la décision prise sur sa candidature
+func notifyApplicationReviewed(tx *gorm.DB, application *models.OfferApplication) error {
+	var notifType models.NotificationType
+	var title, message string
+	offerTitle := application.Offer.Title
+	switch application.Status {
+	case models.OfferApplicationShortlisted:
+		notifType = models.NotificationApplicationShortlisted
+		title = "Candidature présélectionnée"
+		message = fmt.Sprintf("Votre candidature à l'offre « %s » a été présélectionnée", offerTitle)
+	case models.OfferApplicationAccepted:
+		notifType = models.NotificationApplicationAccepted
+		title = "Candidature retenue"
+		message = fmt.Sprintf("Votre candidature à l'offre « %s » a été retenue", offerTitle)
+	default:
+		notifType = models.NotificationApplicationRejected
+		title = "Candidature non retenue"
+		message = fmt.Sprintf("Votre candidature à l'offre « %s » n'a pas été retenue", offerTitle)
+	}
+	if application.ReviewNote != "" {
+		message += " : " + application.ReviewNote
+	}
+	return Notify(tx, application.EnseignantID, notifType, title, message, enseignantApplicationsLink(application.EnseignantID))
+}
+
+// AcceptApplication retient la candidature d'un enseignant, y compris après la date
+// limite de candidature : l'offre est pourvue, les autres candidatures en cours sont
+// refusées et, sur demande, la mission de l'enseignant auprès de la famille est créée.
+func AcceptApplication(db *gorm.DB, offerID, enseignantID uint, req models.OfferApplicationAcceptRequest, adminID uint) (*models.OfferApplicationAcceptResponse, error) {
+	if req.CreateMission && req.FamilleID == 0 {
+		return nil, ErrMissionFamilleRequired
+	}
+
+	var response models.OfferApplicationAcceptResponse
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var offer models.Offer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, offerID).Error; err != nil {
+			return err
+		}
+		// Une offre close à sa date limite reste à pourvoir parmi les candidats reçus
+		if offer.Status != models.OfferStatusOpen && offer.Status != models.OfferStatusClosed {
+			return models.ErrOfferNotOpen
+		}
+		application, err := lockOfferApplication(tx, offerID, enseignantID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := application.Review(models.OfferApplicationAccepted, req.Note, adminID, now); err != nil {
+			return err
+		}
+
+		if req.CreateMission {
+			mission, err := createOfferMission(tx, &offer, enseignantID, req)
+			if err != nil {
+				return err
+			}
+			application.MissionID = &mission.ID
+			response.Mission = mission
+		}
+		if err := tx.Omit(clause.Associations).Save(application).Error; err != nil {
+			return err
+		}
+		offer.CloseOffer(models.OfferStatusFilled, now)
+		if err := saveOfferLifecycle(tx, &offer); err != nil {
+			return err
+		}
+		if err := RecordOfferRevision(tx, &offer, models.OfferActionFilled, adminID); err != nil {
+			return err
+		}
+
+		var others []models.OfferApplication
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Offer").
+			Where("offer_id = ? AND enseignant_user_id <> ? AND status IN ?", offerID, enseignantID,
+				[]models.OfferApplicationStatus{models.OfferApplicationPending, models.OfferApplicationShortlisted}).
+			Find(&others).Error; err != nil {
+			return err
+		}
+		for i := range others {
+			other := &others[i]
+			if err := other.Review(models.OfferApplicationRejected, "L'offre a été pourvue", adminID, now); err != nil {
+				return err
+			}
+			if err := tx.Omit(clause.Associations).Save(other).Error; err != nil {
+				return err
+			}
+			if err := notifyApplicationReviewed(tx, other); err != nil {
+				return err
+			}
+		}
+
+		if err := notifyApplicationReviewed(tx, application); err != nil {
+			return err
+		}
+		application.Offer = nil
+		response.Application = *application
+		response.Offer = offer
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// createOfferMission crée la mission de l'enseignant retenu et prévient la famille
+func createOfferMission(tx *gorm.DB, offer *models.Offer, enseignantID uint, req models.OfferApplicationAcceptRequest) (*models.Mission, error) {
+	var famille models.Famille
+	if err := tx.First(&famille, req.FamilleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMissionFamilleNotFound
+		}
+		return nil, err
+	}
+
+	mission := models.Mission{
+		StartDate:    time.Now(),
+		EndDate:      req.EndDate,
+		Description:  req.Description,
+		FamilleID:    famille.UserID,
+		EnseignantID: enseignantID,
+		OfferID:      &offer.ID,
+	}
+	if req.StartDate != nil {
+		mission.StartDate = *req.StartDate
+	}
+	if mission.Description == "" {
+		mission.Description = offer.Title
+	}
+	if req.HourlyRate != nil {
+		mission.HourlyRate = *req.HourlyRate
+	}
+	mission.CreateMission()
+	if err := tx.Create(&mission).Error; err != nil {
+		return nil, err
+	}
+	due, err := RefreshNextReportDue(tx, mission.ID)
+	if err != nil {
+		return nil, err
+	}
+	mission.NextReportDueAt = due
+
+	message := fmt.Sprintf("Une mission « %s » a été créée avec l'enseignant retenu", mission.Description)
+	if err := Notify(tx, famille.UserID, models.NotificationMissionCreated, "Nouvelle mission", message,
+		fmt.Sprintf("/api/v1/missions/%d", mission.ID)); err != nil {
+		return nil, err
+	}
+	return &mission, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"api/database"
+	"api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	offerSearchDefaultLimit = 20
+	offerSearchMaxLimit     = 100
+)
+
+// offerRateRanges découpe les taux horaires (en centimes) pour la facette de prix ;
+// une borne nulle est ouverte
+var offerRateRanges = []struct {
+	label    string
+	min, max int64
+}{
+	{"Moins de 20 €", 0, 2000},
+	{"De 20 à 30 €", 2000, 3000},
+	{"De 30 à 40 €", 3000, 4000},
+	{"De 40 à 50 €", 4000, 5000},
+	{"50 € et plus", 5000, 0},
+}
+
+// offerSearchMode désigne le moteur utilisé pour la recherche textuelle
+type offerSearchMode int
+
+const (
+	offerSearchNone offerSearchMode = iota
+	offerSearchPostgres
+	offerSearchFTS5
+	offerSearchLike
+)
+
+// offerSearch porte une recherche d'offres et le moteur choisi selon la base
+type offerSearch struct {
+	db    *gorm.DB
+	req   models.OfferSearchRequest
+	terms []string
+	mode  offerSearchMode
+}
+
+// offerSearchTerms découpe la recherche en mots, en minuscules et sans ponctuation
+func offerSearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func newOfferSearch(db *gorm.DB, req models.OfferSearchRequest) *offerSearch {
+	s := &offerSearch{db: db, req: req, terms: offerSearchTerms(req.Query)}
+	switch {
+	case len(s.terms) == 0:
+		s.mode = offerSearchNone
+	case db.Dialector.Name() == "postgres":
+		s.mode = offerSearchPostgres
+	case db.Migrator().HasTable(database.OfferSearchTable):
+		s.mode = offerSearchFTS5
+	default:
+		s.mode = offerSearchLike
+	}
+	return s
+}
+
+// ftsQuery traduit les mots recherchés en requête FTS5 : tous les mots, en préfixe
+func (s *offerSearch) ftsQuery() string {
+	parts := make([]string, len(s.terms))
+	for i, term := range s.terms {
+		parts[i] = `"` + term + `"*`
+	}
+	return strings.Join(parts, " ")
+}
+
+// query sélectionne les offres qui correspondent à la recherche et aux filtres, à
+// l'exception du filtre de la facette indiquée
+func (s *offerSearch) query(skip string) *gorm.DB {
+	q := s.db.Model(&models.Offer{})
+	switch s.mode {
+	case offerSearchPostgres:
+		q = q.Where("offers.search_vector @@ websearch_to_tsquery('french', ?)", s.req.Query)
+	case offerSearchFTS5:
+		q = q.Joins("JOIN offers_fts ON offers_fts.rowid = offers.id").Where("offers_fts MATCH ?", s.ftsQuery())
+	case offerSearchLike:
+		for _, term := range s.terms {
+			like := "%" + term + "%"
+			q = q.Where("(LOWER(offers.title) LIKE ? OR LOWER(offers.description) LIKE ? OR LOWER(offers.requirements) LIKE ?)",
+				like, like, like)
+		}
+	}
+
+	status := s.req.Status
+	if status == "" {
+		status = models.OfferStatusOpen
+	}
+	q = q.Where("offers.status = ?", status)
+	if s.req.Subject != "" && skip != "subject" {
+		q = q.Where("offers.subject = ?", s.req.Subject)
+	}
+	if s.req.Level != "" && skip != "level" {
+		q = q.Where("offers.level = ?", s.req.Level)
+	}
+	if skip != "rate" {
+		if s.req.MinRate != nil {
+			q = q.Where("offers.hourly_rate_cents >= ?", *s.req.MinRate)
+		}
+		if s.req.MaxRate != nil {
+			q = q.Where("offers.hourly_rate_cents <= ?", *s.req.MaxRate)
+		}
+	}
+	if s.req.DateFrom != nil {
+		q = q.Where("offers.publication_date >= ?", *s.req.DateFrom)
+	}
+	if s.req.DateTo != nil {
+		q = q.Where("offers.publication_date < ?", s.req.DateTo.AddDate(0, 0, 1))
+	}
+	return q
+}
+
+// relevance retourne l'expression SQL de pertinence d'une offre : titre, puis
+// description, puis prérequis
+func (s *offerSearch) relevance() (string, []interface{}) {
+	switch s.mode {
+	case offerSearchPostgres:
+		return "ts_rank(offers.search_vector, websearch_to_tsquery('french', ?))", []interface{}{s.req.Query}
+	case offerSearchFTS5:
+		// bm25 est d'autant plus faible que l'offre est pertinente
+		return "-bm25(offers_fts, 10.0, 4.0, 1.0)", nil
+	case offerSearchLike:
+		var parts []string
+		var args []interface{}
+		for _, term := range s.terms {
+			like := "%" + term + "%"
+			parts = append(parts, "(CASE WHEN LOWER(offers.title) LIKE ? THEN 3 ELSE 0 END"+
+				" + CASE WHEN LOWER(offers.description) LIKE ? THEN 2 ELSE 0 END"+
+				" + CASE WHEN LOWER(offers.requirements) LIKE ? THEN 1 ELSE 0 END)")
+			args = append(args, like, like, like)
+		}
+		return strings.Join(parts, " + "), args
+	}
+	return "0", nil
+}
+
+// facet compte les offres par valeur d'une colonne, les plus fréquentes en premier
+func (s *offerSearch) facet(column string) ([]models.OfferFacetCount, error) {
+	facets := []models.OfferFacetCount{}
+	err := s.query(column).
+		Select("offers." + column + " AS value, COUNT(*) AS count").
+		Where("offers." + column + " <> ''").
+		Group("offers." + column).
+		Order("count DESC, value").
+		Scan(&facets).Error
+	return facets, err
+}
+
+// rateFacet compte les offres par tranche de taux horaire
+func (s *offerSearch) rateFacet() ([]models.OfferRateRangeFacet, error) {
+	facets := make([]models.OfferRateRangeFacet, 0, len(offerRateRanges))
+	for _, r := range offerRateRanges {
+		facet := models.OfferRateRangeFacet{Label: r.label}
+		q := s.query("rate")
+		if r.min > 0 {
+			from := r.min
+			facet.MinCents = &from
+			q = q.Where("offers.hourly_rate_cents >= ?", from)
+		}
+		if r.max > 0 {
+			to := r.max
+			facet.MaxCents = &to
+			q = q.Where("offers.hourly_rate_cents < ?", to)
+		}
+		if err := q.Count(&facet.Count).Error; err != nil {
+			return nil, err
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+// SearchOffers recherche les offres par texte libre sur le titre, la description et
+// les prérequis, classées par pertinence, avec les facettes matière, niveau et taux
+// horaire. PostgreSQL utilise un vecteur tsvector (dictionnaire français), SQLite
+// l'index FTS5 ou, à défaut, des filtres LIKE.
+func SearchOffers(db *gorm.DB, req models.OfferSearchRequest) (*models.OfferSearchResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = offerSearchDefaultLimit
+	}
+	if req.Limit > offerSearchMaxLimit {
+		req.Limit = offerSearchMaxLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+	s := newOfferSearch(db, req)
+
+	response := models.OfferSearchResponse{Results: []models.OfferSearchResult{}}
+	if err := s.query("").Count(&response.Total).Error; err != nil {
+		return nil, err
+	}
+
+	var hits []struct {
+		ID        uint
+		Relevance float64
+	}
+	relevance, args := s.relevance()
+	order := "relevance DESC, offers.publication_date DESC, offers.id DESC"
+	if s.mode == offerSearchNone {
+		order = "offers.publication_date DESC, offers.id DESC"
+	}
+	if err := s.query("").
+		Select("offers.id, "+relevance+" AS relevance", args...).
+		Order(order).Limit(req.Limit).Offset(req.Offset).
+		Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	if len(hits) > 0 {
+		ids := make([]uint, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.ID
+		}
+		var offers []models.Offer
+		if err := db.Preload("Options").Where("id IN ?", ids).Find(&offers).Error; err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.Offer, len(offers))
+		for _, offer := range offers {
+			byID[offer.ID] = offer
+		}
+		for _, hit := range hits {
+			response.Results = append(response.Results, models.OfferSearchResult{Offer: byID[hit.ID], Rank: hit.Relevance})
+		}
+	}
+
+	var err error
+	if response.Facets.Subjects, err = s.facet("subject"); err != nil {
+		return nil, err
+	}
+	if response.Facets.Levels, err = s.facet("level"); err != nil {
+		return nil, err
+	}
+	if response.Facets.RateRanges, err = s.rateFacet(); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPaymentOutsideProvider = errors.New("ce paiement n'a pas été encaissé via le prestataire de paiement")
+
+// CreatePayment enregistre un paiement en attente et ouvre l'intention correspondante chez le prestataire
+func CreatePayment(db *gorm.DB, req models.PaymentCreateRequest, userID uint) (*models.Payment, error) {
+	provider, err := CurrentPaymentProvider()
+	if err != nil {
+		return nil, err
+	}
+	payment := models.Payment{
+		Amount:      req.Amount,
+		Type:        req.Type,
+		Description: req.Description,
+		Status:      models.PaymentStatusPending,
+		UserID:      userID,
+		CourseID:    req.CourseID,
+		MissionID:   req.MissionID,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Course").Create(&payment).Error; err != nil {
+			return err
+		}
+		return openPaymentIntent(tx, provider, &payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// providerError signale une erreur renvoyée par le prestataire de paiement
+func providerError(err error) error {
+	return fmt.Errorf("%w : %v", ErrPaymentProvider, err)
+}
+
+func openPaymentIntent(tx *gorm.DB, provider PaymentProvider, payment *models.Payment) error {
+	intent, err := provider.CreateIntent(payment)
+	if err != nil {
+		return providerError(err)
+	}
+	payment.Provider = provider.Name()
+	payment.ProviderIntentID = intent.ID
+	return tx.Model(payment).Select("provider", "provider_intent_id").Updates(payment).Error
+}
+
+// ProcessPayment présente le paiement au prestataire avec le moyen de paiement fourni.
+// Un refus du prestataire fait passer le paiement à l'état échoué sans renvoyer d'erreur.
+// Le paiement est d'abord réservé sous verrou (état en cours de traitement), de sorte
+// que deux demandes simultanées ne puissent pas le capturer deux fois ; le prestataire
+// est ensuite appelé hors transaction et sa réponse enregistrée sous verrou, un
+// webhook ayant pu la devancer. Une erreur du prestataire libère la réservation.
+func ProcessPayment(db *gorm.DB, payment *models.Payment, paymentMethod string) error {
+	provider, err := CurrentPaymentProvider()
+	if err != nil {
+		return err
+	}
+	var previous models.PaymentStatus
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(payment, payment.ID).Error; err != nil {
+			return err
+		}
+		if !payment.IsPayable() {
+			return models.ErrPaymentNotPayable
+		}
+		previous = payment.Status
+		if err := payment.Claim(); err != nil {
+			return err
+		}
+		return tx.Model(payment).Update("status", payment.Status).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// Les paiements générés automatiquement (facturation, annulations) n'ont pas encore d'intention
+	if payment.ProviderIntentID == "" {
+		if err := openPaymentIntent(db, provider, payment); err != nil {
+			return releasePaymentClaim(db, payment, previous, err)
+		}
+	}
+	result, err := provider.Capture(payment.ProviderIntentID, paymentMethod, payment.Amount)
+	if err != nil {
+		return releasePaymentClaim(db, payment, previous, providerError(err))
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(payment, payment.ID).Error; err != nil {
+			return err
+		}
+		if result.Succeeded {
+			if payment.Status == models.PaymentStatusCompleted {
+				return nil
+			}
+			return completePayment(tx, payment)
+		}
+		if payment.Status == models.PaymentStatusCompleted {
+			return nil
+		}
+		return failPayment(tx, payment, result.FailureReason)
+	})
+}
+
+// releasePaymentClaim rend au paiement réservé son statut précédent lorsque le
+// prestataire n'a pas pu le traiter, puis retourne l'erreur d'origine
+func releasePaymentClaim(db *gorm.DB, payment *models.Payment, previous models.PaymentStatus, cause error) error {
+	if err := db.Model(&models.Payment{}).Where("id = ? AND status = ?", payment.ID, models.PaymentStatusProcessing).
+		Update("status", previous).Error; err != nil {
+		return err
+	}
+	payment.Status = previous
+	return cause
+}
+
+// RefundPayment rembourse tout ou partie d'un paiement encaissé via le prestataire,
+// sans jamais dépasser le montant encaissé restant. Sans montant, le solde non encore
+// remboursé est restitué. Le remboursement est enregistré comme un paiement lié au
+// paiement d'origine, accompagné d'un avoir lorsqu'il porte sur une prestation.
+// Le prestataire est appelé hors transaction ; le montant est revérifié sous verrou
+// lors de l'enregistrement du remboursement.
+func RefundPayment(db *gorm.DB, payment *models.Payment, amount *models.Money, reason string, adminID uint) (*models.Payment, error) {
+	provider, err := CurrentPaymentProvider()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.First(payment, payment.ID).Error; err != nil {
+		return nil, err
+	}
+	refundAmount := payment.RefundableAmount()
+	if amount != nil {
+		refundAmount = *amount
+	}
+	if err := payment.CheckRefund(refundAmount); err != nil {
+		return nil, err
+	}
+	if err := checkAdvanceRefundable(db, payment); err != nil {
+		return nil, err
+	}
+	if err := checkHourPackageRefundable(db, payment, refundAmount); err != nil {
+		return nil, err
+	}
+	if payment.ProviderIntentID == "" {
+		return nil, ErrPaymentOutsideProvider
+	}
+
+	sequence, err := refundSequence(db, payment)
+	if err != nil {
+		return nil, err
+	}
+	result, err := provider.Refund(payment.ProviderIntentID, sequence, refundAmount)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	if !result.Succeeded {
+		return nil, fmt.Errorf("%w : remboursement refusé (%s)", ErrPaymentProvider, result.FailureReason)
+	}
+
+	var refund *models.Payment
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Verrou sur le paiement : deux remboursements simultanés ne peuvent pas dépasser le montant encaissé
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(payment, payment.ID).Error; err != nil {
+			return err
+		}
+		refund, err = recordRefund(tx, payment, refundAmount, result.Reference, reason, adminID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// refundSequence retourne le rang du prochain remboursement d'un paiement, d'après
+// les remboursements déjà enregistrés
+func refundSequence(db *gorm.DB, payment *models.Payment) (int, error) {
+	var count int64
+	err := db.Model(&models.Payment{}).Where("type = ? AND refunded_payment_id = ?", models.PaymentTypeRefund, payment.ID).
+		Count(&count).Error
+	return int(count) + 1, err
+}
+
+// HandlePaymentWebhook applique une notification authentifiée du prestataire.
+// Les notifications déjà prises en compte sont ignorées.
+func HandlePaymentWebhook(db *gorm.DB, event models.PaymentWebhookEvent) (*models.Payment, error) {
+	var payment models.Payment
+	if err := db.Where("provider_intent_id = ?", event.IntentID).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		switch event.Type {
+		case models.PaymentEventSucceeded:
+			if payment.Status == models.PaymentStatusCompleted {
+				return nil
+			}
+			return completePayment(tx, &payment)
+		case models.PaymentEventFailed:
+			if payment.Status == models.PaymentStatusFailed {
+				return nil
+			}
+			return failPayment(tx, &payment, event.FailureReason)
+		case models.PaymentEventRefunded:
+			// Les remboursements demandés depuis l'API sont déjà enregistrés
+			var count int64
+			if err := tx.Model(&models.Payment{}).Where("refunded_payment_id = ? AND provider_refund_id = ?", payment.ID, event.RefundID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if payment.Status == models.PaymentStatusRefunded || count > 0 {
+				return nil
+			}
+			amount := payment.RefundableAmount()
+			if event.Amount != nil {
+				amount = *event.Amount
+			}
+			_, err := recordRefund(tx, &payment, amount, event.RefundID, "Remboursement effectué depuis le prestataire de paiement", 0)
+			return err
+		}
+		return fmt.Errorf("type d'événement inconnu : %s", event.Type)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func completePayment(tx *gorm.DB, payment *models.Payment) error {
+	if err := payment.ProcessPayment(); err != nil {
+		return err
+	}
+	if err := savePayment(tx, payment); err != nil {
+		return err
+	}
+	if err := postPaymentEntry(tx, payment); err != nil {
+		return err
+	}
+	if payment.Type == models.PaymentTypeHourPackage {
+		if err := activateHourPackage(tx, payment); err != nil {
+			return err
+		}
+	}
+	if err := rewardReferral(tx, payment); err != nil {
+		return err
+	}
+	return Notify(tx, payment.UserID, models.NotificationPaymentCompleted, "Paiement confirmé",
+		fmt.Sprintf("Votre paiement de %s a été encaissé", payment.Amount), paymentLink(payment.ID))
+}
+
+func failPayment(tx *gorm.DB, payment *models.Payment, reason string) error {
+	if err := payment.FailPayment(reason); err != nil {
+		return err
+	}
+	if err := savePayment(tx, payment); err != nil {
+		return err
+	}
+	return Notify(tx, payment.UserID, models.NotificationPaymentFailed, "Paiement refusé",
+		fmt.Sprintf("Votre paiement de %s a été refusé : %s", payment.Amount, reason), paymentLink(payment.ID))
+}
+
+func savePayment(tx *gorm.DB, payment *models.Payment) error {
+	return tx.Omit("User", "Course").Save(payment).Error
+}
+
+func paymentLink(id uint) string {
+	return fmt.Sprintf("/api/v1/payments/%d", id)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"api/models"
+)
+
+var (
+	ErrInvalidWebhookSignature = errors.New("signature du webhook invalide")
+	ErrUnknownPaymentProvider  = errors.New("prestataire de paiement inconnu")
+	ErrNoPaymentProvider       = errors.New("aucun prestataire de paiement configuré : renseignez PAYMENT_PROVIDER (fake pour le développement local)")
+	ErrPaymentProvider         = errors.New("erreur du prestataire de paiement")
+)
+
+// PaymentIntent représente l'intention de paiement ouverte chez le prestataire
+type PaymentIntent struct {
+	ID     string
+	Amount models.Money
+}
+
+// PaymentResult représente la réponse du prestataire à une capture ou un remboursement
+type PaymentResult struct {
+	Reference     string
+	Succeeded     bool
+	FailureReason string
+}
+
+// PaymentProvider abstrait le prestataire chargé d'encaisser et de rembourser les paiements
+type PaymentProvider interface {
+	Name() string
+	// CreateIntent ouvre une intention de paiement pour le montant du paiement
+	CreateIntent(payment *models.Payment) (PaymentIntent, error)
+	// Capture débite le moyen de paiement fourni ; un refus n'est pas une erreur
+	Capture(intentID, paymentMethod string, amount models.Money) (PaymentResult, error)
+	// Refund rembourse tout ou partie d'une intention déjà capturée ; sequence est le
+	// rang du remboursement sur le paiement (1 pour le premier), qui sert de clé
+	// d'idempotence auprès du prestataire
+	Refund(intentID string, sequence int, amount models.Money) (PaymentResult, error)
+	// VerifyWebhook authentifie et décode une notification du prestataire
+	VerifyWebhook(payload []byte, signature string) (models.PaymentWebhookEvent, error)
+}
+
+var (
+	paymentProviderMu sync.RWMutex
+	paymentProvider   PaymentProvider
+)
+
+// CurrentPaymentProvider retourne le prestataire configuré par PAYMENT_PROVIDER.
+// Il doit être choisi explicitement : le faux prestataire (« fake ») n'est jamais
+// retenu par défaut, afin qu'une production mal configurée n'encaisse pas à vide.
+func CurrentPaymentProvider() (PaymentProvider, error) {
+	paymentProviderMu.RLock()
+	provider := paymentProvider
+	paymentProviderMu.RUnlock()
+	if provider != nil {
+		return provider, nil
+	}
+	name := strings.ToLower(os.Getenv("PAYMENT_PROVIDER"))
+	switch name {
+	case "":
+		return nil, ErrNoPaymentProvider
+	case "fake":
+		provider = NewFakePaymentProvider(os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	default:
+		return nil, fmt.Errorf("%w : %s", ErrUnknownPaymentProvider, name)
+	}
+	SetPaymentProvider(provider)
+	return provider, nil
+}
+
+// SetPaymentProvider remplace le prestataire utilisé (tests, intégrations)
+func SetPaymentProvider(provider PaymentProvider) {
+	paymentProviderMu.Lock()
+	paymentProvider = provider
+	paymentProviderMu.Unlock()
+}
+
+// FakePaymentDeclinedMethod est le moyen de paiement systématiquement refusé par le faux prestataire
+const FakePaymentDeclinedMethod = "fake_card_declined"
+
+// FakePaymentProvider est un prestataire déterministe pour le développement local :
+// les références dérivent de l'identifiant du paiement (et du rang du remboursement
+// au-delà du premier), toute capture réussit sauf avec FakePaymentDeclinedMethod et
+// les webhooks sont signés en HMAC-SHA256.
+type FakePaymentProvider struct {
+	secret []byte
+}
+
+// NewFakePaymentProvider crée un faux prestataire signant ses webhooks avec secret
+func NewFakePaymentProvider(secret string) *FakePaymentProvider {
+	return &FakePaymentProvider{secret: []byte(secret)}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePaymentProvider) CreateIntent(payment *models.Payment) (PaymentIntent, error) {
+	if !payment.Amount.IsPositive() {
+		return PaymentIntent{}, errors.New("le montant doit être strictement positif")
+	}
+	return PaymentIntent{ID: fmt.Sprintf("fake_pi_%06d", payment.ID), Amount: payment.Amount}, nil
+}
+
+func (p *FakePaymentProvider) Capture(intentID, paymentMethod string, amount models.Money) (PaymentResult, error) {
+	if !strings.HasPrefix(intentID, "fake_pi_") {
+		return PaymentResult{}, fmt.Errorf("intention de paiement inconnue : %s", intentID)
+	}
+	if paymentMethod == FakePaymentDeclinedMethod {
+		return PaymentResult{Reference: intentID, FailureReason: "carte refusée"}, nil
+	}
+	return PaymentResult{Reference: intentID, Succeeded: true}, nil
+}
+
+func (p *FakePaymentProvider) Refund(intentID string, sequence int, amount models.Money) (PaymentResult, error) {
+	if !strings.HasPrefix(intentID, "fake_pi_") {
+		return PaymentResult{}, fmt.Errorf("intention de paiement inconnue : %s", intentID)
+	}
+	reference := "fake_re_" + strings.TrimPrefix(intentID, "fake_pi_")
+	if sequence > 1 {
+		reference = fmt.Sprintf("%s_%d", reference, sequence)
+	}
+	return PaymentResult{Reference: reference, Succeeded: true}, nil
+}
+
+func (p *FakePaymentProvider) VerifyWebhook(payload []byte, signature string) (models.PaymentWebhookEvent, error) {
+	var event models.PaymentWebhookEvent
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(p.secret) == 0 || !hmac.Equal(expected, p.sign(payload)) {
+		return event, ErrInvalidWebhookSignature
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// SignWebhook calcule la signature attendue pour un webhook du faux prestataire
+func (p *FakePaymentProvider) SignWebhook(payload []byte) string {
+	return hex.EncodeToString(p.sign(payload))
+}
+
+func (p *FakePaymentProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"api/models"
+)
+
+func TestFakePaymentProviderCapture(t *testing.T) {
+	provider := NewFakePaymentProvider("secret")
+	intent, err := provider.CreateIntent(&models.Payment{ID: 42, Amount: models.EUR(4000)})
+	if err != nil {
+		t.Fatalf("create intent: %v", err)
+	}
+	if intent.ID != "fake_pi_000042" {
+		t.Errorf("intent = %s, want fake_pi_000042", intent.ID)
+	}
+	if _, err := provider.CreateIntent(&models.Payment{ID: 43}); err == nil {
+		t.Error("create intent without amount: want an error")
+	}
+
+	tests := []struct {
+		name          string
+		intentID      string
+		paymentMethod string
+		wantSucceeded bool
+		wantErr       bool
+	}{
+		{"capture acceptée", intent.ID, "fake_card", true, false},
+		{"carte refusée", intent.ID, FakePaymentDeclinedMethod, false, false},
+		{"intention inconnue", "pi_000042", "fake_card", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := provider.Capture(tt.intentID, tt.paymentMethod, models.EUR(4000))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("capture error = %v, want error %v", err, tt.wantErr)
+			}
+			if result.Succeeded != tt.wantSucceeded {
+				t.Errorf("succeeded = %v, want %v", result.Succeeded, tt.wantSucceeded)
+			}
+			if !tt.wantErr && !tt.wantSucceeded && result.FailureReason == "" {
+				t.Error("declined capture without a failure reason")
+			}
+		})
+	}
+}
+
+func TestFakePaymentProviderRefund(t *testing.T) {
+	tests := []struct {
+		name          string
+		intentID      string
+		sequence      int
+		wantReference string
+		wantErr       bool
+	}{
+		{"premier remboursement", "fake_pi_000042", 1, "fake_re_000042", false},
+		{"deuxième remboursement", "fake_pi_000042", 2, "fake_re_000042_2", false},
+		{"intention inconnue", "pi_000042", 1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Un nouveau prestataire à chaque appel : la référence ne dépend que du rang fourni
+			for range 2 {
+				result, err := NewFakePaymentProvider("secret").Refund(tt.intentID, tt.sequence, models.EUR(1000))
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("refund error = %v, want error %v", err, tt.wantErr)
+				}
+				if result.Reference != tt.wantReference {
+					t.Errorf("reference = %q, want %q", result.Reference, tt.wantReference)
+				}
+			}
+		})
+	}
+}
+
+func TestFakePaymentProviderVerifyWebhook(t *testing.T) {
+	payload := []byte(`{"type":"payment.succeeded","intent_id":"fake_pi_000042"}`)
+	provider := NewFakePaymentProvider("secret")
+	signature := provider.SignWebhook(payload)
+
+	tests := []struct {
+		name      string
+		provider  *FakePaymentProvider
+		payload   []byte
+		signature string
+		wantErr   error
+	}{
+		{"signature valide", provider, payload, signature, nil},
+		{"contenu modifié", provider, []byte(`{"type":"payment.succeeded","intent_id":"fake_pi_000043"}`), signature, ErrInvalidWebhookSignature},
+		{"autre secret", NewFakePaymentProvider("other"), payload, signature, ErrInvalidWebhookSignature},
+		{"signature non hexadécimale", provider, payload, "zz", ErrInvalidWebhookSignature},
+		{"secret vide", NewFakePaymentProvider(""), payload, NewFakePaymentProvider("").SignWebhook(payload), ErrInvalidWebhookSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := tt.provider.VerifyWebhook(tt.payload, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify webhook = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (event.Type != models.PaymentEventSucceeded || event.IntentID != "fake_pi_000042") {
+				t.Errorf("event = %+v, want payment.succeeded on fake_pi_000042", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"api/models"
+
+	"gorm.io/gorm"
+)
+
+// newProviderPayment active le faux prestataire et enregistre un paiement en attente
+// du type donné, avec son intention de paiement
+func newProviderPayment(t *testing.T, db *gorm.DB, paymentType models.PaymentType) *models.Payment {
+	t.Helper()
+	SetPaymentProvider(NewFakePaymentProvider("secret"))
+	t.Cleanup(func() { SetPaymentProvider(nil) })
+	user := models.User{Username: "famille", Password: "x", Email: "famille@example.com", Role: models.RoleFamille}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	req := models.PaymentCreateRequest{Amount: models.EUR(4000), Type: paymentType, Description: "Cours"}
+	payment, err := CreatePayment(db, req, user.ID)
+	if err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	return payment
+}
+
+func TestProcessPayment(t *testing.T) {
+	tests := []struct {
+		name          string
+		paymentType   models.PaymentType
+		status        models.PaymentStatus
+		paymentMethod string
+		wantErr       error
+		wantStatus    models.PaymentStatus
+	}{
+		{"paiement accepté", models.PaymentTypeCourse, models.PaymentStatusPending, "fake_card", nil, models.PaymentStatusCompleted},
+		{"carte refusée", models.PaymentTypeCourse, models.PaymentStatusPending, FakePaymentDeclinedMethod, nil, models.PaymentStatusFailed},
+		{"nouvel essai après un refus", models.PaymentTypeCourse, models.PaymentStatusFailed, "fake_card", nil, models.PaymentStatusCompleted},
+		{"type non réglable", models.PaymentTypePenalty, models.PaymentStatusPending, "fake_card", models.ErrPaymentNotPayable, models.PaymentStatusPending},
+		{"capture déjà en cours", models.PaymentTypeCourse, models.PaymentStatusProcessing, "fake_card", models.ErrPaymentNotProcessable, models.PaymentStatusProcessing},
+		{"paiement déjà encaissé", models.PaymentTypeCourse, models.PaymentStatusCompleted, "fake_card", models.ErrPaymentNotProcessable, models.PaymentStatusCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			payment := newProviderPayment(t, db, tt.paymentType)
+			if err := db.Model(payment).Update("status", tt.status).Error; err != nil {
+				t.Fatalf("set payment status: %v", err)
+			}
+			if err := ProcessPayment(db, payment, tt.paymentMethod); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ProcessPayment = %v, want %v", err, tt.wantErr)
+			}
+			if err := db.First(payment, payment.ID).Error; err != nil {
+				t.Fatalf("reload payment: %v", err)
+			}
+			if payment.Status != tt.wantStatus {
+				t.Errorf("payment = %s, want %s", payment.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRefundPayment(t *testing.T) {
+	db := newTestDB(t)
+	payment := newProviderPayment(t, db, models.PaymentTypeCourse)
+	if err := ProcessPayment(db, payment, "fake_card"); err != nil {
+		t.Fatalf("process payment: %v", err)
+	}
+	unpaid, err := CreatePayment(db, models.PaymentCreateRequest{Amount: models.EUR(4000), Type: models.PaymentTypeCourse}, payment.UserID)
+	if err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	outside := models.Payment{Amount: models.EUR(4000), Type: models.PaymentTypeCourse, Status: models.PaymentStatusCompleted, UserID: payment.UserID}
+	if err := db.Create(&outside).Error; err != nil {
+		t.Fatalf("create payment outside the provider: %v", err)
+	}
+
+	partial, excess := models.EUR(1500), models.EUR(3000)
+	tests := []struct {
+		name          string
+		payment       *models.Payment
+		amount        *models.Money
+		wantErr       error
+		wantReference string
+		wantRefunded  models.Money
+	}{
+		{"remboursement partiel", payment, &partial, nil, "fake_re_000001", models.EUR(1500)},
+		{"au-delà du montant restant", payment, &excess, models.ErrRefundExceedsPayment, "", models.EUR(1500)},
+		{"solde restant", payment, nil, nil, "fake_re_000001_2", models.EUR(4000)},
+		{"paiement déjà remboursé", payment, nil, models.ErrPaymentNotRefundable, "", models.EUR(4000)},
+		{"paiement non encaissé", unpaid, nil, models.ErrPaymentNotRefundable, "", models.Money{}},
+		{"paiement hors prestataire", &outside, nil, ErrPaymentOutsideProvider, "", models.Money{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, err := RefundPayment(db, tt.payment, tt.amount, "Cours non effectué", 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefundPayment = %v, want %v", err, tt.wantErr)
+			}
+			if refund != nil && refund.ProviderRefundID != tt.wantReference {
+				t.Errorf("refund reference = %q, want %q", refund.ProviderRefundID, tt.wantReference)
+			}
+			if err := db.First(tt.payment, tt.payment.ID).Error; err != nil {
+				t.Fatalf("reload payment: %v", err)
+			}
+			if tt.payment.RefundedAmount.Cents != tt.wantRefunded.Cents {
+				t.Errorf("refunded amount = %s, want %s", tt.payment.RefundedAmount, tt.wantRefunded)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidPayoutPeriod = errors.New("période invalide : indiquez month (YYYY-MM) ou period_start et period_end (YYYY-MM-DD)")
+	ErrNothingToPay        = errors.New("aucun cours approuvé à verser sur cette période")
+)
+
+// ParsePayoutPeriod retourne les bornes [début, fin[ de la période demandée, en
+// heure locale : un mois entier ou un intervalle de dates dont la fin est incluse
+func ParsePayoutPeriod(req models.PayoutPeriodRequest) (time.Time, time.Time, error) {
+	loc := utils.CalendarLocation()
+	if req.Month != "" {
+		month, err := time.ParseInLocation("2006-01", req.Month, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidPayoutPeriod
+		}
+		return month, month.AddDate(0, 1, 0), nil
+	}
+	start, err := time.ParseInLocation("2006-01-02", req.PeriodStart, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidPayoutPeriod
+	}
+	end, err := time.ParseInLocation("2006-01-02", req.PeriodEnd, loc)
+	if err != nil || end.Before(start) {
+		return time.Time{}, time.Time{}, ErrInvalidPayoutPeriod
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// PreviewPayouts calcule, sans rien enregistrer, les relevés de la période : pour
+// chaque enseignant, les cours terminés dont les heures ont été approuvées et qui
+// n'ont pas encore été versés, au taux de la mission (ou à défaut de l'offre),
+// diminués des avances déjà réglées et des pénalités d'annulation en attente
+func PreviewPayouts(db *gorm.DB, start, end time.Time, enseignantID *uint, now time.Time) (*models.PayoutPreviewResponse, error) {
+	preview := &models.PayoutPreviewResponse{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Payouts:     []models.Payout{},
+		Skipped:     []models.PayoutSkippedCourse{},
+	}
+
+	query := db.Joins("JOIN courses ON courses.id = timesheet_entries.course_id AND courses.deleted_at IS NULL").
+		Where("timesheet_entries.status = ? AND courses.status = ?", models.TimesheetStatusApproved, models.CourseStatusCompleted).
+		Where("courses.scheduled_time >= ? AND courses.scheduled_time < ?", start, end).
+		Where("NOT EXISTS (SELECT 1 FROM payout_lines WHERE payout_lines.course_id = courses.id)").
+		Preload("Course").
+		Order("courses.enseignant_id, courses.scheduled_time")
+	if enseignantID != nil {
+		query = query.Where("courses.enseignant_id = ?", *enseignantID)
+	}
+	var entries []models.TimesheetEntry
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	missions, err := payoutMissions(db, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	type payoutKey struct {
+		enseignantID uint
+		currency     models.Currency
+	}
+	var keys []payoutKey
+	payouts := map[payoutKey]*models.Payout{}
+	for _, entry := range entries {
+		course := entry.Course
+		skip := func(reason string) {
+			preview.Skipped = append(preview.Skipped, models.PayoutSkippedCourse{
+				CourseID: course.ID, MissionID: course.MissionID, EnseignantID: course.EnseignantID, Reason: reason,
+			})
+		}
+		mission, ok := missions[course.MissionID]
+		if !ok {
+			skip("cours rattaché à aucune mission")
+			continue
+		}
+		if err := CheckMissionPayout(mission, now); err != nil {
+			skip(err.Error())
+			continue
+		}
+		rate := mission.EffectiveHourlyRate()
+		if !rate.IsPositive() {
+			skip("aucun taux horaire défini pour la mission ni pour l'offre")
+			continue
+		}
+
+		key := payoutKey{course.EnseignantID, rate.Currency}
+		payout, ok := payouts[key]
+		if !ok {
+			payout = &models.Payout{
+				Status:       models.PayoutStatusLocked,
+				PeriodStart:  start,
+				PeriodEnd:    end,
+				Currency:     rate.Currency,
+				EnseignantID: course.EnseignantID,
+			}
+			payouts[key] = payout
+			keys = append(keys, key)
+		}
+		courseID, missionID := course.ID, mission.ID
+		payout.Lines = append(payout.Lines, models.PayoutLine{
+			Kind: models.PayoutLineCourse,
+			Description: fmt.Sprintf("Cours du %s - %s",
+				course.ScheduledTime.In(utils.CalendarLocation()).Format("02/01/2006"), formatMinutes(entry.DurationMinutes)),
+			Minutes:    entry.DurationMinutes,
+			HourlyRate: rate,
+			Amount:     rate.ProrateMinutes(entry.DurationMinutes),
+			CourseID:   &courseID,
+			MissionID:  &missionID,
+		})
+	}
+
+	for _, key := range keys {
+		payout := payouts[key]
+		payout.ComputeTotals()
+		if err := deductAdvances(db, payout); err != nil {
+			return nil, err
+		}
+		payout.ComputeTotals()
+		if err := deductPenalties(db, payout); err != nil {
+			return nil, err
+		}
+		for i := range payout.Lines {
+			payout.Lines[i].Position = i + 1
+		}
+		payout.ComputeTotals()
+		preview.Payouts = append(preview.Payouts, *payout)
+	}
+	return preview, nil
+}
+
+// deductPenalties ajoute au relevé les pénalités d'annulation de l'enseignant en
+// attente, de la plus ancienne à la plus récente, tant qu'elles tiennent en entier
+// dans le net restant ; les autres attendent un relevé suivant
+func deductPenalties(db *gorm.DB, payout *models.Payout) error {
+	var penalties []models.Payment
+	if err := db.Where("user_id = ? AND type = ? AND status = ? AND amount_currency = ? AND payment_date < ?",
+		payout.EnseignantID, models.PaymentTypePenalty, models.PaymentStatusPending, payout.Currency, payout.PeriodEnd).
+		Order("payment_date, id").Find(&penalties).Error; err != nil {
+		return err
+	}
+	remaining := payout.NetAmount
+	for _, penalty := range penalties {
+		if penalty.Amount.Cents > remaining.Cents {
+			continue
+		}
+		remaining = remaining.Sub(penalty.Amount)
+		paymentID := penalty.ID
+		payout.Lines = append(payout.Lines, models.PayoutLine{
+			Kind:        models.PayoutLinePenalty,
+			Description: penalty.Description,
+			Amount:      penalty.Amount.Neg(),
+			PaymentID:   &paymentID,
+		})
+	}
+	return nil
+}
+
+// settlePayoutPenalties marque comme réglées les pénalités déduites d'un relevé verrouillé
+func settlePayoutPenalties(tx *gorm.DB, payout *models.Payout) error {
+	for _, line := range payout.Lines {
+		if line.Kind != models.PayoutLinePenalty || line.PaymentID == nil {
+			continue
+		}
+		var penalty models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&penalty, *line.PaymentID).Error; err != nil {
+			return err
+		}
+		if err := penalty.ProcessPayment(); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(&penalty).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// payoutMissions charge, avec leur offre, les missions des cours à verser
+func payoutMissions(db *gorm.DB, entries []models.TimesheetEntry) (map[uint]*models.Mission, error) {
+	var ids []uint
+	for _, entry := range entries {
+		if entry.Course != nil && entry.Course.MissionID != 0 {
+			ids = append(ids, entry.Course.MissionID)
+		}
+	}
+	missions := map[uint]*models.Mission{}
+	if len(ids) == 0 {
+		return missions, nil
+	}
+	var list []models.Mission
+	if err := db.Preload("Offer").Find(&list, ids).Error; err != nil {
+		return nil, err
+	}
+	for i := range list {
+		missions[list[i].ID] = &list[i]
+	}
+	return missions, nil
+}
+
+// LockPayouts enregistre les relevés de la période tels que calculés par
+// PreviewPayouts ; les cours, avances et pénalités qu'ils contiennent ne peuvent
+// plus figurer sur un autre relevé
+func LockPayouts(db *gorm.DB, start, end time.Time, enseignantID *uint, adminID uint) ([]models.Payout, error) {
+	var locked []models.Payout
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		preview, err := PreviewPayouts(tx, start, end, enseignantID, now)
+		if err != nil {
+			return err
+		}
+		if len(preview.Payouts) == 0 {
+			return ErrNothingToPay
+		}
+		for _, payout := range preview.Payouts {
+			payout.LockedAt = &now
+			payout.LockedByID = &adminID
+			if err := tx.Create(&payout).Error; err != nil {
+				return err
+			}
+			if err := recordPayoutAllocations(tx, &payout); err != nil {
+				return err
+			}
+			if err := settlePayoutPenalties(tx, &payout); err != nil {
+				return err
+			}
+			if err := postPayoutEntry(tx, &payout); err != nil {
+				return err
+			}
+			if err := Notify(tx, payout.EnseignantID, models.NotificationPayoutLocked, "Relevé de paiement disponible",
+				fmt.Sprintf("Votre relevé %s est disponible : %s à percevoir", payoutPeriodLabel(&payout), payout.NetAmount),
+				payoutLink(payout.ID)); err != nil {
+				return err
+			}
+			locked = append(locked, payout)
+		}
+		return nil
+	})
+	return locked, err
+}
+
+// MarkPayoutPaid enregistre le virement d'un relevé verrouillé
+func MarkPayoutPaid(db *gorm.DB, payout *models.Payout, adminID uint, reference string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := payout.MarkPaid(reference, time.Now(), adminID); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(payout).Error; err != nil {
+			return err
+		}
+		if err := postPayoutPaid(tx, payout); err != nil {
+			return err
+		}
+		return Notify(tx, payout.EnseignantID, models.NotificationPayoutPaid, "Paiement effectué",
+			fmt.Sprintf("Votre relevé %s a été payé : %s", payoutPeriodLabel(payout), payout.NetAmount),
+			payoutLink(payout.ID))
+	})
+}
+
+// LoadPayout charge un relevé avec ses lignes
+func LoadPayout(db *gorm.DB, id uint) (*models.Payout, error) {
+	var payout models.Payout
+	err := db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		First(&payout, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+// payoutPeriodLabel formate la période d'un relevé : du 01/10/2026 au 31/10/2026
+func payoutPeriodLabel(payout *models.Payout) string {
+	loc := utils.CalendarLocation()
+	return fmt.Sprintf("du %s au %s", payout.PeriodStart.In(loc).Format("02/01/2006"),
+		payout.PeriodEnd.In(loc).AddDate(0, 0, -1).Format("02/01/2006"))
+}
+
+func payoutLink(id uint) string {
+	return fmt.Sprintf("/api/v1/payouts/%d", id)
+}
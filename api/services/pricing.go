@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+// pricingInput rassemble ce qui détermine le taux horaire d'un cours ou d'une mission
+type pricingInput struct {
+	context    models.PricingContext
+	negotiated models.Money // Taux négocié de la mission, prioritaire sur les taux de base
+	fallback   models.Money // Taux de l'offre, à défaut de règle de taux de base
+}
+
+// priceHourlyRate évalue les règles de tarification actives : le taux négocié ou,
+// à défaut, la première règle de taux de base applicable ou le taux de l'offre,
+// puis les majorations et remises applicables, par priorité croissante
+func priceHourlyRate(db *gorm.DB, input pricingInput) (models.Money, []models.PricingQuoteLine, error) {
+	var rules []models.PricingRule
+	if err := db.Where("is_active = ?", true).Order("priority, id").Find(&rules).Error; err != nil {
+		return models.Money{}, nil, err
+	}
+
+	rate := input.fallback
+	if rate.Currency == "" {
+		rate = models.NewMoney(rate.Cents, models.DefaultCurrency)
+	}
+	lines := []models.PricingQuoteLine{{Label: "Taux horaire de l'offre", Kind: models.PricingRuleBaseRate, Amount: rate, Rate: rate}}
+	if input.negotiated.IsPositive() {
+		rate = input.negotiated
+		lines[0] = models.PricingQuoteLine{Label: "Taux horaire négocié de la mission", Kind: models.PricingRuleBaseRate, Amount: rate, Rate: rate}
+	} else {
+		for i := range rules {
+			rule := &rules[i]
+			if rule.Kind == models.PricingRuleBaseRate && rule.Matches(input.context) {
+				rate = models.NewMoney(rule.Rate.Cents, rate.Currency)
+				lines[0] = models.PricingQuoteLine{RuleID: &rule.ID, Label: rule.Name, Kind: rule.Kind, Amount: rate, Rate: rate}
+				break
+			}
+		}
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Kind == models.PricingRuleBaseRate || !rule.Matches(input.context) {
+			continue
+		}
+		delta := rule.Adjustment(rate)
+		if rate.Add(delta).IsNegative() {
+			delta = rate.Neg()
+		}
+		rate = rate.Add(delta)
+		lines = append(lines, models.PricingQuoteLine{RuleID: &rule.ID, Label: rule.Name, Kind: rule.Kind, Amount: delta, Rate: rate})
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return rate, lines, nil
+}
+
+// QuotePrice calcule, avant leur création, le taux horaire d'une mission ou d'un
+// cours et le prix d'un cours de la durée demandée, avec le détail des règles appliquées
+func QuotePrice(db *gorm.DB, req models.PricingQuoteRequest, familleID uint) (*models.PricingQuoteResponse, error) {
+	var mission models.Mission
+	if req.MissionID != nil {
+		if err := db.Preload("Offer").First(&mission, *req.MissionID).Error; err != nil {
+			return nil, err
+		}
+		familleID = mission.FamilleID
+	}
+	offer := mission.Offer
+	if req.OfferID != nil {
+		offer = &models.Offer{}
+		if err := db.First(offer, *req.OfferID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	input := pricingInput{negotiated: mission.HourlyRate}
+	if req.HourlyRate != nil {
+		input.negotiated = *req.HourlyRate
+	}
+	input.context.Subject, input.context.Level = req.Subject, req.Level
+	if offer != nil {
+		input.fallback = offer.HourlyRate
+		if input.context.Subject == "" {
+			input.context.Subject = offer.Subject
+		}
+		if input.context.Level == "" {
+			input.context.Level = offer.Level
+		}
+	}
+	if req.ScheduledTime != nil {
+		local := req.ScheduledTime.In(utils.CalendarLocation())
+		input.context.ScheduledTime = &local
+	}
+
+	start, end := mission.StartDate, mission.EndDate
+	if req.StartDate != nil {
+		start = *req.StartDate
+	}
+	if req.EndDate != nil {
+		end = req.EndDate
+	}
+	input.context.MissionWeeks = missionWeeks(start, end)
+
+	siblings, err := siblingMissions(db, familleID, mission.ID)
+	if err != nil {
+		return nil, err
+	}
+	input.context.SiblingMissions = siblings
+
+	enseignantID := mission.EnseignantID
+	if req.EnseignantID != nil {
+		enseignantID = *req.EnseignantID
+	}
+	if req.AddressID != nil {
+		distance, err := teacherDistanceKm(db, enseignantID, *req.AddressID)
+		if err != nil {
+			return nil, err
+		}
+		input.context.DistanceKm = distance
+	}
+
+	rate, lines, err := priceHourlyRate(db, input)
+	if err != nil {
+		return nil, err
+	}
+	duration := req.Duration
+	if duration == 0 {
+		duration = 60
+	}
+	return &models.PricingQuoteResponse{
+		Subject:         input.context.Subject,
+		Level:           input.context.Level,
+		DistanceKm:      input.context.DistanceKm,
+		MissionWeeks:    input.context.MissionWeeks,
+		SiblingMissions: siblings,
+		BaseRate:        lines[0].Rate,
+		HourlyRate:      rate,
+		Duration:        duration,
+		Total:           rate.ProrateMinutes(duration),
+		Lines:           lines,
+	}, nil
+}
+
+// coursePricingInput décrit un cours existant pour le moteur de tarification
+func coursePricingInput(tx *gorm.DB, course *models.Course) (pricingInput, error) {
+	local := course.ScheduledTime.In(utils.CalendarLocation())
+	input := pricingInput{context: models.PricingContext{ScheduledTime: &local}}
+	if course.MissionID != 0 {
+		var mission models.Mission
+		if err := tx.Unscoped().Preload("Offer").First(&mission, course.MissionID).Error; err != nil {
+			return input, err
+		}
+		input.negotiated = mission.HourlyRate
+		if mission.Offer != nil {
+			input.fallback = mission.Offer.HourlyRate
+			input.context.Subject = mission.Offer.Subject
+			input.context.Level = mission.Offer.Level
+		}
+		input.context.MissionWeeks = missionWeeks(mission.StartDate, mission.EndDate)
+	}
+	siblings, err := siblingMissions(tx, course.FamilleID, course.MissionID)
+	if err != nil {
+		return input, err
+	}
+	input.context.SiblingMissions = siblings
+	if course.AddressID != 0 {
+		distance, err := teacherDistanceKm(tx, course.EnseignantID, course.AddressID)
+		if err != nil {
+			return input, err
+		}
+		input.context.DistanceKm = distance
+	}
+	return input, nil
+}
+
+// missionWeeks retourne la durée de la mission en semaines, ou nil si elle n'a pas de fin prévue
+func missionWeeks(start time.Time, end *time.Time) *float64 {
+	if end == nil || start.IsZero() || end.Before(start) {
+		return nil
+	}
+	weeks := math.Round(end.Sub(start).Hours()/24/7*10) / 10
+	return &weeks
+}
+
+// siblingMissions compte les autres missions actives de la famille
+func siblingMissions(db *gorm.DB, familleID, missionID uint) (int, error) {
+	if familleID == 0 {
+		return 0, nil
+	}
+	var count int64
+	err := db.Model(&models.Mission{}).Where("famille_id = ? AND status = ? AND id <> ?", familleID, models.MissionStatusActive, missionID).
+		Count(&count).Error
+	return int(count), err
+}
+
+// teacherDistanceKm retourne la distance entre l'adresse de l'enseignant et celle
+// du cours, ou nil si l'une des deux n'est pas géolocalisée
+func teacherDistanceKm(db *gorm.DB, enseignantID, addressID uint) (*float64, error) {
+	if enseignantID == 0 {
+		return nil, nil
+	}
+	var destination models.Address
+	if err := db.First(&destination, addressID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var origin models.Address
+	err := db.Where("user_id = ? AND (latitude <> 0 OR longitude <> 0)", enseignantID).Order("id").First(&origin).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if destination.Latitude == 0 && destination.Longitude == 0 {
+		return nil, nil
+	}
+	km := math.Round(utils.DistanceMeters(origin.Latitude, origin.Longitude, destination.Latitude, destination.Longitude)/100) / 10
+	return &km, nil
+}
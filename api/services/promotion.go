@@ -0,0 +1,580 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReferralRewardMinutes retourne les heures offertes à chacune des deux familles
+// lorsque le premier cours du filleul est payé (REFERRAL_REWARD_MINUTES, 1h par défaut)
+func ReferralRewardMinutes() int {
+	if value := os.Getenv("REFERRAL_REWARD_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes >= 0 {
+			return minutes
+		}
+	}
+	return 60
+}
+
+// ReferralRewardValidityDays retourne la durée de validité des heures offertes par
+// le parrainage (REFERRAL_REWARD_VALIDITY_DAYS, 365 jours par défaut, 0 : sans expiration)
+func ReferralRewardValidityDays() int {
+	if value := os.Getenv("REFERRAL_REWARD_VALIDITY_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			return days
+		}
+	}
+	return 365
+}
+
+// findPromoCode retourne le code promo saisi, verrouillé jusqu'à la fin de la transaction
+func findPromoCode(tx *gorm.DB, code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", models.NormalizePromoCode(code)).First(&promo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+// checkPromoCode vérifie qu'une famille peut utiliser le code, pour la mission
+// donnée le cas échéant
+func checkPromoCode(tx *gorm.DB, promo *models.PromoCode, familleID uint, missionID *uint, at time.Time) error {
+	if err := promo.CheckValidity(at); err != nil {
+		return err
+	}
+	if promo.MaxUsesPerFamille > 0 {
+		var used int64
+		if err := tx.Model(&models.PromoRedemption{}).
+			Where("promo_code_id = ? AND famille_id = ? AND status <> ?", promo.ID, familleID, models.PromoRedemptionCancelled).
+			Count(&used).Error; err != nil {
+			return err
+		}
+		if int(used) >= promo.MaxUsesPerFamille {
+			return models.ErrPromoCodeAlreadyUsed
+		}
+	}
+	if promo.FirstMissionOnly {
+		// La mission concernée doit être la seule de la famille ; sans mission
+		// désignée, la famille ne doit pas en avoir plus d'une
+		query := tx.Model(&models.Mission{}).Where("famille_id = ?", familleID)
+		allowed := int64(1)
+		if missionID != nil {
+			query = query.Where("id <> ?", *missionID)
+			allowed = 0
+		}
+		var missions int64
+		if err := query.Count(&missions).Error; err != nil {
+			return err
+		}
+		if missions > allowed {
+			return models.ErrPromoCodeFirstMissionOnly
+		}
+	}
+	return nil
+}
+
+// redeemPromoCode applique un code promo à une mission ou à une facture de la
+// famille et décompte son utilisation
+func redeemPromoCode(tx *gorm.DB, code string, familleID uint, missionID, invoiceID *uint, actorID uint) (*models.PromoRedemption, error) {
+	promo, err := findPromoCode(tx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPromoCode(tx, promo, familleID, missionID, time.Now()); err != nil {
+		return nil, err
+	}
+	redemption := promo.NewRedemption(familleID)
+	redemption.MissionID = missionID
+	redemption.InvoiceID = invoiceID
+	redemption.ActorID = actorID
+	if err := tx.Create(&redemption).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(promo).Update("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// RedeemMissionPromoCode applique un code promo à tous les cours d'une mission
+func RedeemMissionPromoCode(tx *gorm.DB, mission *models.Mission, code string, actorID uint) (*models.PromoRedemption, error) {
+	return redeemPromoCode(tx, code, mission.FamilleID, &mission.ID, nil, actorID)
+}
+
+// CheckPromoCode indique, sans l'appliquer, si une famille peut utiliser un code promo
+func CheckPromoCode(db *gorm.DB, req models.PromoCodeCheckRequest, familleID uint) (*models.PromoCodeCheckResponse, error) {
+	var response *models.PromoCodeCheckResponse
+	err := db.Transaction(func(tx *gorm.DB) error {
+		promo, err := findPromoCode(tx, req.Code)
+		if err != nil {
+			return err
+		}
+		response = &models.PromoCodeCheckResponse{
+			Code:             promo.Code,
+			Kind:             promo.Kind,
+			Percent:          promo.Percent,
+			Amount:           promo.Amount,
+			FirstMissionOnly: promo.FirstMissionOnly,
+			ValidUntil:       promo.ValidUntil,
+			Valid:            true,
+		}
+		if err := checkPromoCode(tx, promo, familleID, req.MissionID, time.Now()); err != nil {
+			if !isPromoRefusal(err) {
+				return err
+			}
+			response.Valid, response.Reason = false, err.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// isPromoRefusal distingue les refus d'un code promo des erreurs techniques
+func isPromoRefusal(err error) bool {
+	return errors.Is(err, models.ErrPromoCodeNotValid) || errors.Is(err, models.ErrPromoCodeExhausted) ||
+		errors.Is(err, models.ErrPromoCodeAlreadyUsed) || errors.Is(err, models.ErrPromoCodeFirstMissionOnly)
+}
+
+// coursePromoDiscounts applique les codes promo au montant facturé pour un cours,
+// chacun sur ce qui reste après les précédents. Un code n'accorde qu'une remise
+// par cours, qu'il soit réglé par paiement ou par facture ; la remise d'un paiement
+// pas encore encaissé est reportée sur la facture qui reprend le cours.
+func coursePromoDiscounts(tx *gorm.DB, redemptions []*models.PromoRedemption, courseID uint, amount models.Money) ([]models.PromoDiscount, models.Money, error) {
+	total := models.NewMoney(0, amount.Currency)
+	var discounts []models.PromoDiscount
+	for _, redemption := range redemptions {
+		var applied int64
+		if err := tx.Model(&models.PromoDiscount{}).Where("redemption_id = ? AND course_id = ?", redemption.ID, courseID).
+			Where("payment_id IS NULL OR payment_id NOT IN (?)", tx.Model(&models.Payment{}).Select("id").
+				Where("status IN ?", []models.PaymentStatus{models.PaymentStatusPending, models.PaymentStatusFailed})).
+			Count(&applied).Error; err != nil {
+			return nil, total, err
+		}
+		if applied > 0 {
+			continue
+		}
+		discount := redemption.Discount(amount.Sub(total))
+		if !discount.IsPositive() {
+			continue
+		}
+		total = total.Add(discount)
+		discounts = append(discounts, models.PromoDiscount{Amount: discount, RedemptionID: redemption.ID, CourseID: courseID})
+	}
+	return discounts, total, nil
+}
+
+// missionRedemptions retourne les codes promo actifs des missions, verrouillés
+func missionRedemptions(tx *gorm.DB, missionIDs []uint) ([]models.PromoRedemption, error) {
+	var redemptions []models.PromoRedemption
+	if len(missionIDs) == 0 {
+		return redemptions, nil
+	}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("mission_id IN ? AND status = ?", missionIDs, models.PromoRedemptionActive).
+		Order("id").Find(&redemptions).Error
+	return redemptions, err
+}
+
+// discountCoursePayment applique les codes promo de la mission au montant d'un
+// cours facturé par paiement et retourne les remises, à enregistrer avec le paiement
+func discountCoursePayment(tx *gorm.DB, course *models.Course, amount models.Money) ([]models.PromoDiscount, models.Money, error) {
+	if course.MissionID == 0 {
+		return nil, models.NewMoney(0, amount.Currency), nil
+	}
+	redemptions, err := missionRedemptions(tx, []uint{course.MissionID})
+	if err != nil {
+		return nil, models.Money{}, err
+	}
+	applicable := make([]*models.PromoRedemption, len(redemptions))
+	for i := range redemptions {
+		applicable[i] = &redemptions[i]
+	}
+	discounts, total, err := coursePromoDiscounts(tx, applicable, course.ID, amount)
+	if err != nil {
+		return nil, total, err
+	}
+	for i := range redemptions {
+		if err := tx.Save(&redemptions[i]).Error; err != nil {
+			return nil, total, err
+		}
+	}
+	return discounts, total, nil
+}
+
+// savePromoDiscounts enregistre les remises accordées sur le paiement d'un cours
+func savePromoDiscounts(tx *gorm.DB, discounts []models.PromoDiscount, paymentID *uint) error {
+	if len(discounts) == 0 {
+		return nil
+	}
+	for i := range discounts {
+		discounts[i].PaymentID = paymentID
+	}
+	return tx.Create(&discounts).Error
+}
+
+// releasePaymentPromotions rend aux codes promo les remises accordées sur le
+// paiement d'un cours annulé avant d'avoir été encaissé
+func releasePaymentPromotions(tx *gorm.DB, paymentID uint) error {
+	var discounts []models.PromoDiscount
+	if err := tx.Where("payment_id = ?", paymentID).Find(&discounts).Error; err != nil {
+		return err
+	}
+	for _, discount := range discounts {
+		var redemption models.PromoRedemption
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&redemption, discount.RedemptionID).Error; err != nil {
+			return err
+		}
+		redemption.Restore(discount.Amount)
+		if err := tx.Save(&redemption).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("payment_id = ?", paymentID).Delete(&models.PromoDiscount{}).Error
+}
+
+// applyInvoicePromotions ajoute à une facture brouillon une ligne de remise par
+// code promo applicable : ceux des missions de ses cours et, le cas échéant, le
+// code saisi à sa création
+func applyInvoicePromotions(tx *gorm.DB, invoice *models.Invoice, courses []models.Course, code string, vatRate float64, adminID uint) error {
+	courseMissions := make(map[uint]uint, len(courses))
+	var missionIDs []uint
+	for _, course := range courses {
+		courseMissions[course.ID] = course.MissionID
+		if course.MissionID != 0 {
+			missionIDs = append(missionIDs, course.MissionID)
+		}
+	}
+	redemptions, err := missionRedemptions(tx, uniqueIDs(missionIDs))
+	if err != nil {
+		return err
+	}
+	if code != "" {
+		redemption, err := redeemPromoCode(tx, code, invoice.FamilleID, invoice.MissionID, &invoice.ID, adminID)
+		if err != nil {
+			return err
+		}
+		redemptions = append(redemptions, *redemption)
+	}
+	if len(redemptions) == 0 {
+		return nil
+	}
+
+	totals := make(map[uint]models.Money, len(redemptions))
+	var discounts []models.PromoDiscount
+	for _, line := range invoice.Lines {
+		if line.CourseID == nil {
+			continue
+		}
+		var applicable []*models.PromoRedemption
+		for i := range redemptions {
+			redemption := &redemptions[i]
+			if redemption.InvoiceID != nil ||
+				(redemption.MissionID != nil && *redemption.MissionID == courseMissions[*line.CourseID]) {
+				applicable = append(applicable, redemption)
+			}
+		}
+		lineDiscounts, _, err := coursePromoDiscounts(tx, applicable, *line.CourseID, line.TotalHT)
+		if err != nil {
+			return err
+		}
+		for _, discount := range lineDiscounts {
+			discount.InvoiceID = &invoice.ID
+			totals[discount.RedemptionID] = totals[discount.RedemptionID].Add(discount.Amount)
+			discounts = append(discounts, discount)
+		}
+	}
+
+	for i := range redemptions {
+		redemption := &redemptions[i]
+		if err := tx.Save(redemption).Error; err != nil {
+			return err
+		}
+		total, ok := totals[redemption.ID]
+		if !ok {
+			continue
+		}
+		line := models.InvoiceLine{
+			Position:    len(invoice.Lines) + 1,
+			Description: redemption.Label(),
+			Quantity:    1,
+			UnitPrice:   total.Neg(),
+			VATRate:     vatRate,
+			TotalHT:     total.Neg(),
+			InvoiceID:   invoice.ID,
+		}
+		if err := tx.Create(&line).Error; err != nil {
+			return err
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	if len(discounts) > 0 {
+		if err := tx.Create(&discounts).Error; err != nil {
+			return err
+		}
+	}
+	invoice.ComputeTotals()
+	return tx.Omit("Lines", "CreditedInvoice").Save(invoice).Error
+}
+
+// releaseInvoicePromotions rend les remises d'une facture brouillon supprimée et
+// annule le code promo saisi à sa création
+func releaseInvoicePromotions(tx *gorm.DB, invoice *models.Invoice) error {
+	var discounts []models.PromoDiscount
+	if err := tx.Where("invoice_id = ?", invoice.ID).Find(&discounts).Error; err != nil {
+		return err
+	}
+	redemptions := map[uint]*models.PromoRedemption{}
+	for _, discount := range discounts {
+		redemption, ok := redemptions[discount.RedemptionID]
+		if !ok {
+			redemption = &models.PromoRedemption{}
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(redemption, discount.RedemptionID).Error; err != nil {
+				return err
+			}
+			redemptions[discount.RedemptionID] = redemption
+		}
+		redemption.Restore(discount.Amount)
+	}
+	if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.PromoDiscount{}).Error; err != nil {
+		return err
+	}
+
+	var own []models.PromoRedemption
+	if err := tx.Where("invoice_id = ? AND status <> ?", invoice.ID, models.PromoRedemptionCancelled).Find(&own).Error; err != nil {
+		return err
+	}
+	for i := range own {
+		redemption, ok := redemptions[own[i].ID]
+		if !ok {
+			redemption = &own[i]
+			redemptions[redemption.ID] = redemption
+		}
+		redemption.Status = models.PromoRedemptionCancelled
+		if err := tx.Model(&models.PromoCode{}).Where("id = ?", redemption.PromoCodeID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			return err
+		}
+	}
+	for _, redemption := range redemptions {
+		if err := tx.Save(redemption).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InviteFamille enregistre l'invitation d'une nouvelle famille par une famille
+// inscrite ; le code de parrainage est à saisir lors de l'inscription
+func InviteFamille(db *gorm.DB, referrerID uint, email string) (*models.Referral, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	var users int64
+	if err := db.Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&users).Error; err != nil {
+		return nil, err
+	}
+	var invitations int64
+	if err := db.Model(&models.Referral{}).Where("email = ? AND status <> ?", email, models.ReferralStatusCancelled).
+		Count(&invitations).Error; err != nil {
+		return nil, err
+	}
+	if users > 0 || invitations > 0 {
+		return nil, models.ErrReferralEmailTaken
+	}
+	token, err := utils.GenerateSecureToken(5)
+	if err != nil {
+		return nil, err
+	}
+	referral := models.Referral{
+		Code:       strings.ToUpper(token),
+		Email:      email,
+		Status:     models.ReferralStatusPending,
+		ReferrerID: referrerID,
+	}
+	if err := db.Create(&referral).Error; err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// FindPendingReferral retourne l'invitation correspondant au code de parrainage
+// saisi à l'inscription ou, sans code, à l'adresse e-mail invitée (nil si aucune)
+func FindPendingReferral(db *gorm.DB, code, email string) (*models.Referral, error) {
+	query := db.Where("status = ?", models.ReferralStatusPending)
+	if code != "" {
+		query = query.Where("code = ?", strings.ToUpper(strings.TrimSpace(code)))
+	} else {
+		query = query.Where("email = ?", strings.ToLower(strings.TrimSpace(email)))
+	}
+	var referral models.Referral
+	err := query.First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if code != "" {
+			return nil, models.ErrReferralNotFound
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// RegisterReferral rattache une famille qui vient de s'inscrire à son invitation
+func RegisterReferral(db *gorm.DB, referral *models.Referral, refereeID uint) error {
+	now := time.Now()
+	referral.Status = models.ReferralStatusRegistered
+	referral.RefereeID = &refereeID
+	referral.RegisteredAt = &now
+	return db.Save(referral).Error
+}
+
+// rewardReferral crédite le parrain et le filleul lorsque le premier cours du
+// filleul est payé
+func rewardReferral(tx *gorm.DB, payment *models.Payment) error {
+	if payment.Type != models.PaymentTypeCourse && payment.Type != models.PaymentTypeInvoice {
+		return nil
+	}
+	var referral models.Referral
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("referee_id = ? AND status = ?", payment.UserID, models.ReferralStatusRegistered).First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	minutes := ReferralRewardMinutes()
+	var expiresAt *time.Time
+	if days := ReferralRewardValidityDays(); days > 0 {
+		expiry := now.AddDate(0, 0, days)
+		expiresAt = &expiry
+	}
+	referral.Status = models.ReferralStatusRewarded
+	referral.RewardedAt = &now
+	referral.RewardMinutes = minutes
+	referral.RewardPaymentID = &payment.ID
+	if err := tx.Save(&referral).Error; err != nil {
+		return err
+	}
+	if minutes == 0 {
+		return nil
+	}
+	for _, familleID := range []uint{referral.ReferrerID, *referral.RefereeID} {
+		if _, err := grantWalletHours(tx, familleID, minutes, "Parrainage", expiresAt, 0, now); err != nil {
+			return err
+		}
+		if err := Notify(tx, familleID, models.NotificationReferralRewarded, "Parrainage récompensé",
+			fmt.Sprintf("Votre porte-monnaie a été crédité de %s de cours grâce au parrainage", formatMinutes(minutes)),
+			walletLink(familleID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PromotionReport synthétise les campagnes sur la période : utilisations et remises
+// accordées par code promo, invitations, inscriptions et récompenses de parrainage
+func PromotionReport(db *gorm.DB, start, end time.Time) (*models.PromotionReportResponse, error) {
+	report := &models.PromotionReportResponse{
+		From:          start,
+		To:            end,
+		PromoCodes:    []models.PromoCodeReport{},
+		DiscountTotal: models.NewMoney(0, models.DefaultCurrency),
+	}
+
+	var codes []models.PromoCode
+	if err := db.Unscoped().Order("code").Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	var redemptions []models.PromoRedemption
+	if err := db.Where("created_at >= ? AND created_at < ?", start, end).Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	type discountRow struct {
+		PromoCodeID uint
+		Cents       int64
+	}
+	var discounts []discountRow
+	if err := db.Model(&models.PromoDiscount{}).
+		Select("promo_redemptions.promo_code_id AS promo_code_id, promo_discounts.amount_cents AS cents").
+		Joins("JOIN promo_redemptions ON promo_redemptions.id = promo_discounts.redemption_id").
+		Where("promo_discounts.created_at >= ? AND promo_discounts.created_at < ?", start, end).
+		Scan(&discounts).Error; err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[uint]*models.PromoCodeReport, len(codes))
+	familles := map[uint]map[uint]bool{}
+	for _, code := range codes {
+		byCode[code.ID] = &models.PromoCodeReport{PromoCodeID: code.ID, Code: code.Code, DiscountTotal: models.NewMoney(0, models.DefaultCurrency)}
+		familles[code.ID] = map[uint]bool{}
+	}
+	for _, redemption := range redemptions {
+		line, ok := byCode[redemption.PromoCodeID]
+		if !ok {
+			continue
+		}
+		if redemption.Status == models.PromoRedemptionCancelled {
+			line.Cancelled++
+			continue
+		}
+		line.Redemptions++
+		familles[redemption.PromoCodeID][redemption.FamilleID] = true
+	}
+	for _, discount := range discounts {
+		if line, ok := byCode[discount.PromoCodeID]; ok {
+			amount := models.NewMoney(discount.Cents, models.DefaultCurrency)
+			line.DiscountTotal = line.DiscountTotal.Add(amount)
+			report.DiscountTotal = report.DiscountTotal.Add(amount)
+		}
+	}
+	for _, code := range codes {
+		line := byCode[code.ID]
+		line.Familles = len(familles[code.ID])
+		if line.Redemptions > 0 || line.Cancelled > 0 || line.DiscountTotal.Cents != 0 {
+			report.PromoCodes = append(report.PromoCodes, *line)
+		}
+	}
+
+	var referrals []models.Referral
+	if err := db.Where("(created_at >= ? AND created_at < ?) OR (registered_at >= ? AND registered_at < ?) OR (rewarded_at >= ? AND rewarded_at < ?)",
+		start, end, start, end, start, end).Find(&referrals).Error; err != nil {
+		return nil, err
+	}
+	within := func(at *time.Time) bool {
+		return at != nil && !at.Before(start) && at.Before(end)
+	}
+	for i := range referrals {
+		referral := &referrals[i]
+		if within(&referral.CreatedAt) {
+			report.Referrals.Invited++
+		}
+		if within(referral.RegisteredAt) {
+			report.Referrals.Registered++
+		}
+		if within(referral.RewardedAt) {
+			report.Referrals.Rewarded++
+			report.Referrals.MinutesGranted += 2 * referral.RewardMinutes
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+// recordRefund enregistre un remboursement accepté par le prestataire : le montant
+// remboursé est reporté sur le paiement d'origine, un paiement de remboursement lui
+// est rattaché et, lorsque le paiement rémunérait une prestation, un avoir du même
+// montant est émis
+func recordRefund(tx *gorm.DB, payment *models.Payment, amount models.Money, refundID, reason string, adminID uint) (*models.Payment, error) {
+	now := time.Now()
+	if err := payment.RecordRefund(amount, refundID, now); err != nil {
+		return nil, err
+	}
+	if err := savePayment(tx, payment); err != nil {
+		return nil, err
+	}
+	if err := cancelHourPackage(tx, payment, adminID); err != nil {
+		return nil, err
+	}
+	creditNote, err := refundCreditNote(tx, payment, amount, reason, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	refund := models.Payment{
+		Amount:            amount,
+		PaymentDate:       now,
+		Status:            models.PaymentStatusCompleted,
+		Type:              models.PaymentTypeRefund,
+		Description:       "Remboursement - " + refundedPaymentLabel(payment),
+		Provider:          payment.Provider,
+		ProviderRefundID:  refundID,
+		RefundReason:      reason,
+		UserID:            payment.UserID,
+		CourseID:          payment.CourseID,
+		MissionID:         payment.MissionID,
+		RefundedPaymentID: &payment.ID,
+	}
+	if creditNote != nil {
+		refund.InvoiceID = &creditNote.ID
+	}
+	if err := tx.Omit("User", "Course").Create(&refund).Error; err != nil {
+		return nil, err
+	}
+	if err := postPaymentEntry(tx, &refund); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Votre paiement de %s a été remboursé", payment.Amount)
+	if payment.Status != models.PaymentStatusRefunded {
+		message = fmt.Sprintf("%s de votre paiement de %s vous ont été remboursés", amount, payment.Amount)
+	}
+	if err := Notify(tx, payment.UserID, models.NotificationPaymentRefunded, "Paiement remboursé", message, paymentLink(refund.ID)); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// refundCreditNote émet l'avoir correspondant à un remboursement : sur la facture
+// réglée par le paiement, dans la limite de ce qui n'a pas déjà été crédité, ou
+// sans facture d'origine pour les cours, forfaits et frais réglés au comptant. Les acomptes
+// et pénalités remboursés ne donnent pas lieu à un avoir.
+func refundCreditNote(tx *gorm.DB, payment *models.Payment, amount models.Money, reason string, adminID uint) (*models.Invoice, error) {
+	settings := CurrentInvoiceSettings()
+	creditNote := models.Invoice{
+		Kind:      models.InvoiceKindCreditNote,
+		Status:    models.InvoiceStatusDraft,
+		Series:    settings.CreditNoteSeries,
+		Currency:  amount.Currency,
+		Reason:    reason,
+		FamilleID: payment.UserID,
+		MissionID: payment.MissionID,
+	}
+	rate := settings.VATRate
+	switch {
+	case payment.InvoiceID != nil:
+		invoice, err := LoadInvoice(tx, *payment.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		var credited int64
+		if err := tx.Model(&models.Invoice{}).Where("credited_invoice_id = ? AND status = ?", invoice.ID, models.InvoiceStatusIssued).
+			Select("COALESCE(SUM(total_ttc_cents), 0)").Scan(&credited).Error; err != nil {
+			return nil, err
+		}
+		if remaining := invoice.TotalTTC.Cents + credited; amount.Cents > remaining {
+			amount = models.NewMoney(remaining, amount.Currency)
+		}
+		if len(invoice.Lines) > 0 {
+			rate = invoice.Lines[0].VATRate
+		}
+		creditNote.FamilleID = invoice.FamilleID
+		creditNote.MissionID = invoice.MissionID
+		creditNote.CreditedInvoiceID = &invoice.ID
+	case payment.Type == models.PaymentTypeCourse, payment.Type == models.PaymentTypeMission,
+		payment.Type == models.PaymentTypeCancellationFee, payment.Type == models.PaymentTypeHourPackage:
+	default:
+		return nil, nil
+	}
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	ht := amount.Sub(includedVAT(amount, rate))
+	creditNote.Lines = []models.InvoiceLine{{
+		Position:    1,
+		Description: "Remboursement - " + refundedPaymentLabel(payment),
+		Quantity:    -1,
+		UnitPrice:   ht,
+		VATRate:     rate,
+		TotalHT:     ht.Neg(),
+	}}
+	creditNote.ComputeTotals()
+	if err := tx.Create(&creditNote).Error; err != nil {
+		return nil, err
+	}
+	if err := issueInvoice(tx, &creditNote, adminID, time.Now()); err != nil {
+		return nil, err
+	}
+	return &creditNote, nil
+}
+
+// refundedPaymentLabel décrit le paiement remboursé dans les libellés
+func refundedPaymentLabel(payment *models.Payment) string {
+	if payment.Description != "" {
+		return payment.Description
+	}
+	return fmt.Sprintf("paiement du %s", payment.PaymentDate.In(utils.CalendarLocation()).Format("02/01/2006"))
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recordReportRevision conserve l'état du rapport à une étape du circuit de validation
+func recordReportRevision(tx *gorm.DB, report *models.Report, action models.ReportRevisionAction, actorID uint) error {
+	revision := models.ReportRevision{
+		ReportID: report.ID,
+		Revision: report.Revision,
+		Action:   action,
+		Status:   report.Status,
+		Content:  report.Content,
+		Comments: report.Comments,
+		ActorID:  actorID,
+	}
+	if report.TemplateID != nil {
+		if report.Answers == nil {
+			if err := tx.Where("report_id = ?", report.ID).Find(&report.Answers).Error; err != nil {
+				return err
+			}
+		}
+		snapshot, err := json.Marshal(report.Answers)
+		if err != nil {
+			return err
+		}
+		revision.Answers = string(snapshot)
+	}
+	return tx.Create(&revision).Error
+}
+
+// reportAnswers valide les réponses structurées d'un rapport au regard de son modèle
+func reportAnswers(db *gorm.DB, templateID *uint, inputs []models.ReportAnswerRequest, creating bool) ([]models.ReportAnswer, error) {
+	if templateID == nil {
+		if len(inputs) > 0 {
+			return nil, fmt.Errorf("%w : aucun modèle n'est associé au rapport", ErrInvalidAnswer)
+		}
+		return nil, nil
+	}
+	template, err := LoadReportTemplate(db, *templateID)
+	if err != nil {
+		return nil, err
+	}
+	if creating && (!template.IsActive || template.DeletedAt.Valid) {
+		return nil, ErrTemplateInactive
+	}
+	return BuildReportAnswers(template, inputs)
+}
+
+// saveReportAnswers remplace les réponses structurées d'un rapport
+func saveReportAnswers(tx *gorm.DB, report *models.Report, answers []models.ReportAnswer) error {
+	if err := tx.Where("report_id = ?", report.ID).Delete(&models.ReportAnswer{}).Error; err != nil {
+		return err
+	}
+	for i := range answers {
+		answers[i].ReportID = report.ID
+	}
+	if len(answers) > 0 {
+		if err := tx.Create(&answers).Error; err != nil {
+			return err
+		}
+	}
+	report.Answers = answers
+	return nil
+}
+
+// CreateReport crée le rapport d'un enseignant pour sa mission, en brouillon ou directement soumis
+func CreateReport(db *gorm.DB, mission *models.Mission, req models.ReportCreateRequest) (*models.Report, error) {
+	answers, err := reportAnswers(db, req.TemplateID, req.Answers, true)
+	if err != nil {
+		return nil, err
+	}
+	report := models.Report{
+		Content:      req.Content,
+		Status:       models.ReportStatusDraft,
+		EnseignantID: mission.EnseignantID,
+		MissionID:    mission.ID,
+		TemplateID:   req.TemplateID,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&report).Error; err != nil {
+			return err
+		}
+		if report.TemplateID != nil {
+			if err := saveReportAnswers(tx, &report, answers); err != nil {
+				return err
+			}
+		}
+		if err := recordReportRevision(tx, &report, models.ReportActionCreated, mission.EnseignantID); err != nil {
+			return err
+		}
+		if req.Submit {
+			return submitReport(tx, &report, mission.EnseignantID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// UpdateReport modifie le texte, et le cas échéant les réponses structurées,
+// d'un rapport en brouillon ou rejeté
+func UpdateReport(db *gorm.DB, report *models.Report, actorID uint, req models.ReportUpdateRequest) error {
+	if !report.CanEdit() {
+		return models.ErrReportNotEditable
+	}
+	var answers []models.ReportAnswer
+	if req.Answers != nil {
+		var err error
+		if answers, err = reportAnswers(db, report.TemplateID, req.Answers, false); err != nil {
+			return err
+		}
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		report.Content = req.Content
+		if err := tx.Omit(clause.Associations).Save(report).Error; err != nil {
+			return err
+		}
+		if req.Answers != nil && report.TemplateID != nil {
+			if err := saveReportAnswers(tx, report, answers); err != nil {
+				return err
+			}
+		}
+		return recordReportRevision(tx, report, models.ReportActionUpdated, actorID)
+	})
+}
+
+// SubmitReport soumet le rapport à la validation des administrateurs
+func SubmitReport(db *gorm.DB, report *models.Report, actorID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return submitReport(tx, report, actorID)
+	})
+}
+
+func submitReport(tx *gorm.DB, report *models.Report, actorID uint) error {
+	if err := report.SubmitReport(); err != nil {
+		return err
+	}
+	if err := tx.Omit(clause.Associations).Save(report).Error; err != nil {
+		return err
+	}
+	if err := recordReportRevision(tx, report, models.ReportActionSubmitted, actorID); err != nil {
+		return err
+	}
+	if _, err := RefreshNextReportDue(tx, report.MissionID); err != nil {
+		return err
+	}
+	return NotifyAdmins(tx, models.NotificationReportSubmitted, "Rapport à valider",
+		fmt.Sprintf("Le rapport de la mission %d (révision %d) attend votre validation", report.MissionID, report.Revision),
+		reportLink(report.ID))
+}
+
+// ValidateReport valide un rapport soumis ; il devient visible pour la famille
+func ValidateReport(db *gorm.DB, report *models.Report, adminID uint, comments string) error {
+	if err := report.ValidateReport(adminID, comments); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(report).Error; err != nil {
+			return err
+		}
+		if err := recordReportRevision(tx, report, models.ReportActionValidated, adminID); err != nil {
+			return err
+		}
+		var mission models.Mission
+		if err := tx.First(&mission, report.MissionID).Error; err != nil {
+			return err
+		}
+		return NotifyAll(tx, []uint{report.EnseignantID, mission.FamilleID}, models.NotificationReportValidated,
+			"Rapport validé", fmt.Sprintf("Le rapport de la mission %d a été validé", report.MissionID), reportLink(report.ID))
+	})
+}
+
+// RejectReport renvoie un rapport soumis à l'enseignant pour révision
+func RejectReport(db *gorm.DB, report *models.Report, adminID uint, comments string) error {
+	if err := report.RejectReport(adminID, comments); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(report).Error; err != nil {
+			return err
+		}
+		if err := recordReportRevision(tx, report, models.ReportActionRejected, adminID); err != nil {
+			return err
+		}
+		// La soumission rejetée ne compte plus dans l'échéancier
+		if _, err := RefreshNextReportDue(tx, report.MissionID); err != nil {
+			return err
+		}
+		return Notify(tx, report.EnseignantID, models.NotificationReportRejected, "Rapport à réviser",
+			fmt.Sprintf("Le rapport de la mission %d a été rejeté : %s", report.MissionID, comments), reportLink(report.ID))
+	})
+}
+
+func reportLink(id uint) string {
+	return fmt.Sprintf("/api/v1/reports/%d", id)
+}
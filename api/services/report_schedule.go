@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"api/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrPayoutBlockedByReport = errors.New("le versement est bloqué tant que le rapport de la mission est en retard")
+
+// ReportReminderLead retourne le délai avant l'échéance à partir duquel
+// l'enseignant est relancé (REPORT_REMINDER_DAYS, 3 jours par défaut)
+func ReportReminderLead() time.Duration {
+	days := 3
+	if env := os.Getenv("REPORT_REMINDER_DAYS"); env != "" {
+		if d, err := strconv.Atoi(env); err == nil && d > 0 {
+			days = d
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// lastReportSubmission retourne la date de la dernière soumission prise en compte
+// pour l'échéancier : les rapports rejetés doivent être soumis à nouveau
+func lastReportSubmission(tx *gorm.DB, missionID uint) (*time.Time, error) {
+	var report models.Report
+	err := tx.Where("mission_id = ? AND status IN ?", missionID,
+		[]models.ReportStatus{models.ReportStatusSubmitted, models.ReportStatusValidated}).
+		Order("submission_date DESC").First(&report).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report.SubmissionDate, nil
+}
+
+// RefreshNextReportDue recalcule et enregistre l'échéance du prochain rapport d'une mission
+func RefreshNextReportDue(tx *gorm.DB, missionID uint) (*time.Time, error) {
+	var mission models.Mission
+	if err := tx.Preload("Offer").First(&mission, missionID).Error; err != nil {
+		return nil, err
+	}
+	last, err := lastReportSubmission(tx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	due := mission.ComputeNextReportDue(last)
+	if err := tx.Model(&models.Mission{}).Where("id = ?", missionID).
+		UpdateColumn("next_report_due_at", due).Error; err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// RefreshOfferReportSchedules recalcule l'échéance des missions rattachées à une offre
+func RefreshOfferReportSchedules(tx *gorm.DB, offerID uint) error {
+	var missionIDs []uint
+	if err := tx.Model(&models.Mission{}).Where("offer_id = ?", offerID).Pluck("id", &missionIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range missionIDs {
+		if _, err := RefreshNextReportDue(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendReportReminders relance les enseignants dont l'échéance approche ou est
+// dépassée. Chaque échéance donne lieu à au plus une relance de chaque type ;
+// les administrateurs sont également prévenus des retards.
+func SendReportReminders(db *gorm.DB, now time.Time) (int, error) {
+	var missions []models.Mission
+	if err := db.Where("status = ? AND next_report_due_at IS NOT NULL AND next_report_due_at <= ?",
+		models.MissionStatusActive, now.Add(ReportReminderLead())).Find(&missions).Error; err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range missions {
+		mission := &missions[i]
+		kind := models.ReportReminderDueSoon
+		if mission.IsReportOverdue(now) {
+			kind = models.ReportReminderOverdue
+		}
+		created := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&models.ReportReminder{}).
+				Where("mission_id = ? AND due_at = ? AND kind = ?", mission.ID, *mission.NextReportDueAt, kind).
+				Count(&count).Error; err != nil || count > 0 {
+				return err
+			}
+			if err := tx.Create(&models.ReportReminder{MissionID: mission.ID, DueAt: *mission.NextReportDueAt, Kind: kind}).Error; err != nil {
+				return err
+			}
+			created = true
+			return notifyReportReminder(tx, mission, kind)
+		})
+		if err != nil {
+			log.Printf("Relance du rapport de la mission %d impossible: %v", mission.ID, err)
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func notifyReportReminder(tx *gorm.DB, mission *models.Mission, kind models.ReportReminderKind) error {
+	due := formatSlot(*mission.NextReportDueAt)
+	link := missionLink(mission.ID)
+	if kind == models.ReportReminderDueSoon {
+		return Notify(tx, mission.EnseignantID, models.NotificationReportDueSoon, "Rapport à rendre",
+			fmt.Sprintf("Le rapport de la mission %d est attendu pour le %s", mission.ID, due), link)
+	}
+	message := fmt.Sprintf("Le rapport de la mission %d était attendu pour le %s", mission.ID, due)
+	if mission.BlockPayoutOnOverdueReport {
+		message += " ; les versements sont suspendus jusqu'à sa soumission"
+	}
+	if err := Notify(tx, mission.EnseignantID, models.NotificationReportOverdue, "Rapport en retard", message, link); err != nil {
+		return err
+	}
+	return NotifyAdmins(tx, models.NotificationReportOverdue, "Rapport en retard",
+		fmt.Sprintf("La mission %d n'a pas reçu de rapport depuis le %s", mission.ID, due), link)
+}
+
+// OverdueReports liste les missions actives dont le rapport est en retard,
+// du retard le plus ancien au plus récent
+func OverdueReports(db *gorm.DB, now time.Time) ([]models.OverdueReportResponse, error) {
+	var missions []models.Mission
+	if err := db.Preload("Offer").
+		Where("status = ? AND next_report_due_at IS NOT NULL AND next_report_due_at < ?", models.MissionStatusActive, now).
+		Order("next_report_due_at ASC").Find(&missions).Error; err != nil {
+		return nil, err
+	}
+	overdue := make([]models.OverdueReportResponse, 0, len(missions))
+	for _, mission := range missions {
+		last, err := lastReportSubmission(db, mission.ID)
+		if err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, models.OverdueReportResponse{
+			MissionID:        mission.ID,
+			EnseignantID:     mission.EnseignantID,
+			FamilleID:        mission.FamilleID,
+			FrequencyDays:    mission.EffectiveReportFrequencyDays(),
+			DueAt:            *mission.NextReportDueAt,
+			DaysOverdue:      int(now.Sub(*mission.NextReportDueAt).Hours() / 24),
+			LastSubmissionAt: last,
+			PayoutBlocked:    mission.BlockPayoutOnOverdueReport,
+		})
+	}
+	return overdue, nil
+}
+
+// CheckMissionPayout refuse un versement à l'enseignant lorsque la mission
+// bloque les versements et que son rapport est en retard
+func CheckMissionPayout(mission *models.Mission, now time.Time) error {
+	if mission.BlockPayoutOnOverdueReport && mission.IsReportOverdue(now) {
+		return ErrPayoutBlockedByReport
+	}
+	return nil
+}
+
+func missionLink(id uint) string {
+	return fmt.Sprintf("/api/v1/missions/%d", id)
+}
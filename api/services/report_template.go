@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"api/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTemplateInUse    = errors.New("ce modèle est déjà utilisé par des rapports : créez un nouveau modèle ou désactivez celui-ci")
+	ErrTemplateInactive = errors.New("ce modèle de rapport n'est plus actif")
+	ErrUnknownSkill     = errors.New("compétence inconnue")
+	ErrInvalidAnswer    = errors.New("réponse invalide")
+)
+
+// BuildReportTemplate construit un modèle (sections et questions) à partir de la requête
+func BuildReportTemplate(db *gorm.DB, req models.ReportTemplateRequest) (*models.ReportTemplate, error) {
+	template := models.ReportTemplate{
+		Name:        req.Name,
+		Subject:     req.Subject,
+		Level:       req.Level,
+		Description: req.Description,
+		ScaleMin:    req.ScaleMin,
+		ScaleMax:    req.ScaleMax,
+		ScaleLabels: req.ScaleLabels,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	for i, s := range req.Sections {
+		section := models.ReportTemplateSection{Title: s.Title, Position: i + 1}
+		for j, it := range s.Items {
+			if it.SkillID != nil {
+				var count int64
+				db.Model(&models.Skill{}).Where("id = ?", *it.SkillID).Count(&count)
+				if count == 0 {
+					return nil, fmt.Errorf("%w : %d", ErrUnknownSkill, *it.SkillID)
+				}
+			}
+			section.Items = append(section.Items, models.ReportTemplateItem{
+				Label:    it.Label,
+				Kind:     it.Kind,
+				Position: j + 1,
+				SkillID:  it.SkillID,
+			})
+		}
+		template.Sections = append(template.Sections, section)
+	}
+	return &template, nil
+}
+
+// ReportTemplateInUse indique si des rapports ont déjà été rédigés avec ce modèle
+func ReportTemplateInUse(db *gorm.DB, templateID uint) bool {
+	var count int64
+	db.Model(&models.Report{}).Where("template_id = ?", templateID).Count(&count)
+	return count > 0
+}
+
+// LoadReportTemplate charge un modèle avec ses sections et questions, y compris s'il a été supprimé
+func LoadReportTemplate(db *gorm.DB, templateID uint) (*models.ReportTemplate, error) {
+	var template models.ReportTemplate
+	err := db.Unscoped().
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Sections.Items", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Sections.Items.Skill").
+		First(&template, templateID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// BuildReportAnswers valide les réponses structurées au regard du modèle : chaque question
+// doit appartenir au modèle, une seule fois, et les notes doivent respecter l'échelle
+func BuildReportAnswers(template *models.ReportTemplate, inputs []models.ReportAnswerRequest) ([]models.ReportAnswer, error) {
+	seen := map[uint]bool{}
+	answers := make([]models.ReportAnswer, 0, len(inputs))
+	for _, in := range inputs {
+		item := template.Item(in.ItemID)
+		if item == nil {
+			return nil, fmt.Errorf("%w : la question %d n'appartient pas au modèle", ErrInvalidAnswer, in.ItemID)
+		}
+		if seen[in.ItemID] {
+			return nil, fmt.Errorf("%w : la question %d est renseignée plusieurs fois", ErrInvalidAnswer, in.ItemID)
+		}
+		seen[in.ItemID] = true
+
+		answer := models.ReportAnswer{ItemID: item.ID, SkillID: item.SkillID}
+		switch item.Kind {
+		case models.ReportItemRating:
+			if in.Rating == nil || *in.Rating < template.ScaleMin || *in.Rating > template.ScaleMax {
+				return nil, fmt.Errorf("%w : la question %d attend une note entre %d et %d", ErrInvalidAnswer, in.ItemID, template.ScaleMin, template.ScaleMax)
+			}
+			answer.Rating = in.Rating
+			answer.Text = in.Text
+		case models.ReportItemText:
+			if in.Text == "" {
+				return nil, fmt.Errorf("%w : la question %d attend une réponse écrite", ErrInvalidAnswer, in.ItemID)
+			}
+			answer.Text = in.Text
+		}
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+// SkillProgression retrace l'évolution des compétences notées dans les rapports validés
+// des missions données, dans l'ordre chronologique
+func SkillProgression(db *gorm.DB, missionIDs []uint, subject string) ([]models.SkillProgressionResponse, error) {
+	result := []models.SkillProgressionResponse{}
+	if len(missionIDs) == 0 {
+		return result, nil
+	}
+	var reports []models.Report
+	if err := db.Where("mission_id IN ? AND status = ? AND template_id IS NOT NULL", missionIDs, models.ReportStatusValidated).
+		Preload("Answers", "rating IS NOT NULL AND skill_id IS NOT NULL").
+		Order("submission_date, id").
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	templates := map[uint]*models.ReportTemplate{}
+	bySkill := map[uint]*models.SkillProgressionResponse{}
+	var order []uint
+	for _, report := range reports {
+		template, ok := templates[*report.TemplateID]
+		if !ok {
+			t, err := LoadReportTemplate(db, *report.TemplateID)
+			if err != nil {
+				continue
+			}
+			template, templates[*report.TemplateID] = t, t
+		}
+		date := report.SubmissionDate
+		if report.ValidationDate != nil {
+			date = *report.ValidationDate
+		}
+		for _, answer := range report.Answers {
+			progression, ok := bySkill[*answer.SkillID]
+			if !ok {
+				var skill models.Skill
+				if err := db.Unscoped().First(&skill, *answer.SkillID).Error; err != nil {
+					continue
+				}
+				if subject != "" && skill.Subject != subject {
+					continue
+				}
+				progression = &models.SkillProgressionResponse{Skill: skill}
+				bySkill[skill.ID] = progression
+				order = append(order, skill.ID)
+			}
+			progression.Points = append(progression.Points, models.SkillProgressionPoint{
+				ReportID: report.ID,
+				Date:     date,
+				Rating:   *answer.Rating,
+				ScaleMin: template.ScaleMin,
+				ScaleMax: template.ScaleMax,
+				Percent:  ratingPercent(*answer.Rating, template.ScaleMin, template.ScaleMax),
+			})
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := bySkill[order[i]].Skill, bySkill[order[j]].Skill
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		return a.Name < b.Name
+	})
+	for _, id := range order {
+		p := bySkill[id]
+		p.First = p.Points[0].Percent
+		p.Last = p.Points[len(p.Points)-1].Percent
+		p.Delta = math.Round((p.Last-p.First)*10) / 10
+		result = append(result, *p)
+	}
+	return result, nil
+}
+
+func ratingPercent(rating, min, max int) float64 {
+	if max <= min {
+		return 0
+	}
+	return math.Round(float64(rating-min)/float64(max-min)*1000) / 10
+}
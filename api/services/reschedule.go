@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSlotInPast         = errors.New("le créneau proposé est déjà passé")
+	ErrSlotOutsideMission = errors.New("le créneau proposé est en dehors des dates de la mission")
+	ErrTeacherUnavailable = errors.New("l'enseignant a déjà un cours sur ce créneau")
+	ErrFamilyUnavailable  = errors.New("la famille a déjà un cours sur ce créneau")
+	ErrCourseNotMovable   = errors.New("seul un cours planifié peut être déplacé")
+
+	ErrReschedulePending = errors.New("une demande de report est déjà en attente pour ce cours")
+)
+
+// RescheduleExpiryDelay retourne la durée de validité d'une demande de report
+// (RESCHEDULE_EXPIRY_HOURS, 48h par défaut)
+func RescheduleExpiryDelay() time.Duration {
+	hours := 48
+	if env := os.Getenv("RESCHEDULE_EXPIRY_HOURS"); env != "" {
+		if h, err := strconv.Atoi(env); err == nil && h > 0 {
+			hours = h
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// CheckCourseSlot vérifie qu'un cours peut être placé sur le créneau donné :
+// créneau futur, compris dans la mission et sans chevauchement avec un autre cours
+// de l'enseignant ou de la famille.
+func CheckCourseSlot(db *gorm.DB, course *models.Course, start time.Time, duration int) error {
+	if course.Status != models.CourseStatusScheduled {
+		return ErrCourseNotMovable
+	}
+	if !start.After(time.Now()) {
+		return ErrSlotInPast
+	}
+	end := start.Add(time.Duration(duration) * time.Minute)
+
+	if course.MissionID != 0 {
+		var mission models.Mission
+		if err := db.First(&mission, course.MissionID).Error; err == nil {
+			if start.Before(mission.StartDate) || (mission.EndDate != nil && end.After(*mission.EndDate)) {
+				return ErrSlotOutsideMission
+			}
+		}
+	}
+
+	var others []models.Course
+	if err := db.Where("id <> ? AND status <> ? AND (enseignant_id = ? OR famille_id = ?)",
+		course.ID, models.CourseStatusCancelled, course.EnseignantID, course.FamilleID).
+		Where("scheduled_time < ?", end).
+		Find(&others).Error; err != nil {
+		return err
+	}
+	for _, other := range others {
+		otherEnd := other.ScheduledTime.Add(time.Duration(other.Duration) * time.Minute)
+		if !otherEnd.After(start) {
+			continue
+		}
+		if other.EnseignantID == course.EnseignantID {
+			return ErrTeacherUnavailable
+		}
+		return ErrFamilyUnavailable
+	}
+	return nil
+}
+
+// IsSlotError indique si l'erreur provient d'un contrôle de disponibilité
+func IsSlotError(err error) bool {
+	return errors.Is(err, ErrSlotInPast) || errors.Is(err, ErrSlotOutsideMission) ||
+		errors.Is(err, ErrTeacherUnavailable) || errors.Is(err, ErrFamilyUnavailable) ||
+		errors.Is(err, ErrCourseNotMovable)
+}
+
+// CreateRescheduleRequest crée une proposition de report adressée à l'autre partie du cours
+func CreateRescheduleRequest(db *gorm.DB, course *models.Course, requesterID uint, req models.RescheduleCreateRequest, parentID *uint) (*models.RescheduleRequest, error) {
+	if parentID == nil {
+		var pending int64
+		db.Model(&models.RescheduleRequest{}).
+			Where("course_id = ? AND status = ? AND expires_at > ?", course.ID, models.RescheduleStatusPending, time.Now()).
+			Count(&pending)
+		if pending > 0 {
+			return nil, ErrReschedulePending
+		}
+	}
+
+	duration := req.ProposedDuration
+	if duration == 0 {
+		duration = course.Duration
+	}
+	if err := CheckCourseSlot(db, course, req.ProposedTime, duration); err != nil {
+		return nil, err
+	}
+
+	respondentID := course.EnseignantID
+	if requesterID == course.EnseignantID {
+		respondentID = course.FamilleID
+	}
+	request := models.RescheduleRequest{
+		CourseID:         course.ID,
+		RequestedByID:    requesterID,
+		RespondentID:     respondentID,
+		ProposedTime:     req.ProposedTime,
+		ProposedDuration: duration,
+		Reason:           req.Reason,
+		Status:           models.RescheduleStatusPending,
+		ExpiresAt:        time.Now().Add(RescheduleExpiryDelay()),
+		ParentID:         parentID,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&request).Error; err != nil {
+			return err
+		}
+		notifType, title := models.NotificationRescheduleRequested, "Demande de report de cours"
+		if parentID != nil {
+			notifType, title = models.NotificationRescheduleCountered, "Contre-proposition de report"
+		}
+		return NotifyAll(tx, []uint{respondentID, requesterID}, notifType, title,
+			fmt.Sprintf("Nouveau créneau proposé pour le cours du %s : %s", formatSlot(course.ScheduledTime), formatSlot(request.ProposedTime)),
+			rescheduleLink(request.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CounterRescheduleRequest remplace une demande en attente par une contre-proposition
+// adressée à son auteur
+func CounterRescheduleRequest(db *gorm.DB, request *models.RescheduleRequest, course *models.Course, actorID uint, req models.RescheduleCreateRequest) (*models.RescheduleRequest, error) {
+	var counter *models.RescheduleRequest
+	err := db.Transaction(func(tx *gorm.DB) error {
+		request.Respond(models.RescheduleStatusCountered, req.Reason)
+		if err := tx.Save(request).Error; err != nil {
+			return err
+		}
+		var err error
+		counter, err = CreateRescheduleRequest(tx, course, actorID, req, &request.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counter, nil
+}
+
+// AcceptRescheduleRequest applique le nouveau créneau au cours après un dernier contrôle
+func AcceptRescheduleRequest(db *gorm.DB, request *models.RescheduleRequest, actorID uint, note string) (*models.Course, error) {
+	var course models.Course
+	if err := db.First(&course, request.CourseID).Error; err != nil {
+		return nil, err
+	}
+	if err := CheckCourseSlot(db, &course, request.ProposedTime, request.ProposedDuration); err != nil {
+		return nil, err
+	}
+
+	previous := course.ScheduledTime
+	err := db.Transaction(func(tx *gorm.DB) error {
+		request.Respond(models.RescheduleStatusAccepted, note)
+		if err := tx.Save(request).Error; err != nil {
+			return err
+		}
+		course.ScheduledTime = request.ProposedTime
+		course.Duration = request.ProposedDuration
+		if err := tx.Save(&course).Error; err != nil {
+			return err
+		}
+		if err := RecordCourseEvent(tx, course.ID, actorID, models.CourseEventRescheduled,
+			fmt.Sprintf("Cours déplacé du %s au %s", formatSlot(previous), formatSlot(course.ScheduledTime))); err != nil {
+			return err
+		}
+		return NotifyAll(tx, []uint{course.FamilleID, course.EnseignantID}, models.NotificationRescheduleAccepted,
+			"Cours déplacé",
+			fmt.Sprintf("Le cours du %s a été déplacé au %s", formatSlot(previous), formatSlot(course.ScheduledTime)),
+			rescheduleLink(request.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// CloseRescheduleRequest clôt une demande (refus, contre-proposition, annulation ou expiration)
+// et prévient les deux parties
+func CloseRescheduleRequest(db *gorm.DB, request *models.RescheduleRequest, status models.RescheduleStatus, note string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		request.Respond(status, note)
+		if err := tx.Save(request).Error; err != nil {
+			return err
+		}
+		parties := []uint{request.RequestedByID, request.RespondentID}
+		switch status {
+		case models.RescheduleStatusRejected:
+			return NotifyAll(tx, parties, models.NotificationRescheduleRejected, "Demande de report refusée",
+				fmt.Sprintf("Le créneau du %s a été refusé. %s", formatSlot(request.ProposedTime), note), rescheduleLink(request.ID))
+		case models.RescheduleStatusExpired:
+			return NotifyAll(tx, parties, models.NotificationRescheduleExpired, "Demande de report expirée",
+				fmt.Sprintf("Sans réponse, la proposition du %s a expiré ; le cours garde son horaire initial", formatSlot(request.ProposedTime)), rescheduleLink(request.ID))
+		case models.RescheduleStatusCancelled:
+			return NotifyAll(tx, parties, models.NotificationRescheduleCancelled, "Demande de report retirée",
+				fmt.Sprintf("La proposition du %s a été retirée", formatSlot(request.ProposedTime)), rescheduleLink(request.ID))
+		}
+		// La contre-proposition notifie elle-même l'autre partie
+		return nil
+	})
+}
+
+// ExpireRescheduleRequests fait expirer les demandes restées sans réponse
+func ExpireRescheduleRequests(db *gorm.DB, now time.Time) (int, error) {
+	var requests []models.RescheduleRequest
+	if err := db.Where("status = ? AND expires_at <= ?", models.RescheduleStatusPending, now).
+		Find(&requests).Error; err != nil {
+		return 0, err
+	}
+	for i := range requests {
+		if err := CloseRescheduleRequest(db, &requests[i], models.RescheduleStatusExpired, ""); err != nil {
+			return i, err
+		}
+	}
+	return len(requests), nil
+}
+
+func formatSlot(t time.Time) string {
+	return t.In(utils.CalendarLocation()).Format("02/01/2006 à 15h04")
+}
+
+func rescheduleLink(id uint) string {
+	return fmt.Sprintf("/api/v1/reschedule-requests/%d", id)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"api/database"
+
+	"gorm.io/gorm"
+)
+
+// Job représente une tâche périodique exécutée en arrière-plan
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(db *gorm.DB, now time.Time) error
+}
+
+// DefaultJobs retourne les tâches périodiques de l'application
+func DefaultJobs() []Job {
+	return []Job{
+		{Name: "auto-approbation des feuilles de temps", Interval: 15 * time.Minute, Run: autoApproveTimesheetsJob},
+		{Name: "expiration des demandes de report", Interval: 15 * time.Minute, Run: expireRescheduleRequestsJob},
+		{Name: "relance des rapports de mission", Interval: time.Hour, Run: sendReportRemindersJob},
+		{Name: "expiration des heures prépayées", Interval: time.Hour, Run: expireHourPackagesJob},
+		{Name: "publication programmée des offres", Interval: 5 * time.Minute, Run: publishScheduledOffersJob},
+		{Name: "clôture des offres", Interval: 15 * time.Minute, Run: closeExpiredOffersJob},
+	}
+}
+
+// StartScheduler lance chaque tâche dans sa propre goroutine
+func StartScheduler(jobs ...Job) {
+	for _, job := range jobs {
+		go runJob(job)
+	}
+}
+
+func runJob(job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		if err := job.Run(database.DB, time.Now()); err != nil {
+			log.Printf("Erreur lors de la tâche %q: %v", job.Name, err)
+		}
+		<-ticker.C
+	}
+}
+
+func autoApproveTimesheetsJob(db *gorm.DB, now time.Time) error {
+	count, err := AutoApproveTimesheets(db, now)
+	if count > 0 {
+		log.Printf("%d feuille(s) de temps approuvée(s) automatiquement", count)
+	}
+	return err
+}
+
+func expireRescheduleRequestsJob(db *gorm.DB, now time.Time) error {
+	count, err := ExpireRescheduleRequests(db, now)
+	if count > 0 {
+		log.Printf("%d demande(s) de report expirée(s)", count)
+	}
+	return err
+}
+
+func sendReportRemindersJob(db *gorm.DB, now time.Time) error {
+	count, err := SendReportReminders(db, now)
+	if count > 0 {
+		log.Printf("%d relance(s) de rapport envoyée(s)", count)
+	}
+	return err
+}
+
+func expireHourPackagesJob(db *gorm.DB, now time.Time) error {
+	count, err := ExpireHourPackages(db, now)
+	if count > 0 {
+		log.Printf("%d forfait(s) d'heures expiré(s)", count)
+	}
+	return err
+}
+
+func publishScheduledOffersJob(db *gorm.DB, now time.Time) error {
+	count, err := PublishScheduledOffers(db, now)
+	if count > 0 {
+		log.Printf("%d offre(s) publiée(s) comme programmé", count)
+	}
+	return err
+}
+
+func closeExpiredOffersJob(db *gorm.DB, now time.Time) error {
+	count, err := CloseExpiredOffers(db, now)
+	if count > 0 {
+		log.Printf("%d offre(s) close(s) automatiquement", count)
+	}
+	return err
+}
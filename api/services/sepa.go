@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSEPAAccountNotConfigured = errors.New("compte bancaire de la société non configuré (SEPA_ACCOUNT_IBAN)")
+	ErrNoPayoutToExport         = errors.New("aucun relevé verrouillé à exporter")
+	ErrPayoutNotTransferable    = errors.New("seuls les relevés verrouillés, non payés, en euros et de montant positif peuvent être virés")
+	ErrPayoutAlreadyExported    = errors.New("un des relevés figure déjà dans un fichier de virements")
+	ErrMissingBankAccount       = errors.New("coordonnées bancaires manquantes ou invalides")
+	ErrInvalidExecutionDate     = errors.New("date d'exécution invalide : format YYYY-MM-DD, à partir d'aujourd'hui")
+)
+
+// SEPASettings décrit le compte bancaire de la société, débité pour payer les
+// enseignants et crédité des prélèvements sur les familles
+type SEPASettings struct {
+	AccountName         string
+	AccountIBAN         string
+	AccountBIC          string
+	CreditorID          string // Identifiant créancier SEPA (ICS), requis pour les prélèvements
+	PrenotificationDays int    // Délai minimal entre le préavis aux familles et le prélèvement
+}
+
+// CurrentSEPASettings lit le compte bancaire de la société (variables SEPA_*)
+func CurrentSEPASettings() (SEPASettings, error) {
+	settings := SEPASettings{
+		AccountName:         envOr("SEPA_ACCOUNT_NAME", CurrentInvoiceSettings().SellerName),
+		CreditorID:          strings.ToUpper(strings.TrimSpace(os.Getenv("SEPA_CREDITOR_ID"))),
+		PrenotificationDays: 14,
+	}
+	if env := os.Getenv("SEPA_PRENOTIFICATION_DAYS"); env != "" {
+		if days, err := strconv.Atoi(env); err == nil && days > 0 {
+			settings.PrenotificationDays = days
+		}
+	}
+	iban, err := utils.ValidateIBAN(os.Getenv("SEPA_ACCOUNT_IBAN"))
+	if err != nil {
+		return settings, ErrSEPAAccountNotConfigured
+	}
+	settings.AccountIBAN = iban
+	if env := os.Getenv("SEPA_ACCOUNT_BIC"); env != "" {
+		bic, err := utils.ValidateBIC(env)
+		if err != nil {
+			return settings, ErrSEPAAccountNotConfigured
+		}
+		settings.AccountBIC = bic
+	}
+	return settings, nil
+}
+
+// UpdateBankAccount valide et enregistre les coordonnées bancaires d'un enseignant
+func UpdateBankAccount(db *gorm.DB, enseignant *models.Enseignant, req models.BankAccountRequest) error {
+	iban, err := utils.ValidateIBAN(req.IBAN)
+	if err != nil {
+		return err
+	}
+	bic := ""
+	if strings.TrimSpace(req.BIC) != "" {
+		if bic, err = utils.ValidateBIC(req.BIC); err != nil {
+			return err
+		}
+	}
+	enseignant.AccountHolder = strings.TrimSpace(req.AccountHolder)
+	enseignant.IBAN = iban
+	enseignant.BIC = bic
+	return db.Model(enseignant).Select("AccountHolder", "IBAN", "BIC").Updates(enseignant).Error
+}
+
+// ParseExecutionDate retourne la date d'exécution demandée, ou à défaut le
+// prochain jour ouvré ; une date passée est refusée
+func ParseExecutionDate(value string, now time.Time) (time.Time, error) {
+	loc := utils.CalendarLocation()
+	today := time.Date(now.In(loc).Year(), now.In(loc).Month(), now.In(loc).Day(), 0, 0, 0, 0, loc)
+	if value == "" {
+		date := today.AddDate(0, 0, 1)
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil || date.Before(today) {
+		return time.Time{}, ErrInvalidExecutionDate
+	}
+	return date, nil
+}
+
+// ExportPayoutTransfers génère le fichier de virements SEPA (pain.001) des relevés
+// demandés, ou de tous les relevés verrouillés pas encore exportés, et rattache
+// chaque relevé au lot pour qu'il ne puisse pas être viré deux fois
+func ExportPayoutTransfers(db *gorm.DB, payoutIDs []uint, executionDate time.Time, adminID uint) (*models.SEPATransferBatch, error) {
+	settings, err := CurrentSEPASettings()
+	if err != nil {
+		return nil, err
+	}
+
+	var batch models.SEPATransferBatch
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var payouts []models.Payout
+		query := tx.Order("enseignant_id, id")
+		if len(payoutIDs) > 0 {
+			query = query.Where("id IN ?", payoutIDs)
+		} else {
+			query = query.Where("status = ? AND transfer_batch_id IS NULL AND net_amount_cents > 0 AND net_amount_currency = ?",
+				models.PayoutStatusLocked, models.CurrencyEUR)
+		}
+		if err := query.Find(&payouts).Error; err != nil {
+			return err
+		}
+		if len(payouts) == 0 {
+			return ErrNoPayoutToExport
+		}
+		if len(payoutIDs) > 0 && len(payouts) != len(uniqueIDs(payoutIDs)) {
+			return gorm.ErrRecordNotFound
+		}
+
+		enseignantIDs := make([]uint, 0, len(payouts))
+		ids := make([]uint, len(payouts))
+		for i, payout := range payouts {
+			if payout.TransferBatchID != nil {
+				return ErrPayoutAlreadyExported
+			}
+			if payout.IsPaid() || !payout.NetAmount.IsPositive() || payout.NetAmount.Currency != models.CurrencyEUR {
+				return ErrPayoutNotTransferable
+			}
+			ids[i] = payout.ID
+			enseignantIDs = append(enseignantIDs, payout.EnseignantID)
+		}
+		var enseignants []models.Enseignant
+		if err := tx.Preload("User").Where("user_id IN ?", enseignantIDs).Find(&enseignants).Error; err != nil {
+			return err
+		}
+		accounts := make(map[uint]models.Enseignant, len(enseignants))
+		for _, enseignant := range enseignants {
+			if _, err := utils.ValidateIBAN(enseignant.IBAN); err == nil {
+				accounts[enseignant.UserID] = enseignant
+			}
+		}
+		var missing []string
+		for _, payout := range payouts {
+			if _, ok := accounts[payout.EnseignantID]; !ok {
+				missing = append(missing, fmt.Sprint(payout.EnseignantID))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w (enseignants %s)", ErrMissingBankAccount, strings.Join(missing, ", "))
+		}
+
+		now := time.Now()
+		batch = models.SEPATransferBatch{
+			MessageID:     fmt.Sprintf("PAYOUT-%s-%d", now.In(utils.CalendarLocation()).Format("20060102"), now.UnixNano()),
+			ExecutionDate: executionDate,
+			PaymentCount:  len(payouts),
+			Total:         models.NewMoney(0, models.CurrencyEUR),
+			DebtorName:    settings.AccountName,
+			DebtorIBAN:    utils.MaskIBAN(settings.AccountIBAN),
+			CreatedByID:   adminID,
+		}
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+		batch.MessageID = fmt.Sprintf("PAYOUT-%s-%06d", now.In(utils.CalendarLocation()).Format("20060102"), batch.ID)
+
+		transfer := utils.SEPACreditTransfer{
+			MessageID:     batch.MessageID,
+			CreatedAt:     now.In(utils.CalendarLocation()),
+			ExecutionDate: executionDate,
+			DebtorName:    settings.AccountName,
+			DebtorIBAN:    settings.AccountIBAN,
+			DebtorBIC:     settings.AccountBIC,
+		}
+		for i := range payouts {
+			payout := &payouts[i]
+			account := accounts[payout.EnseignantID]
+			creditor := account.AccountHolder
+			if creditor == "" {
+				creditor = account.User.Username
+			}
+			transfer.Transactions = append(transfer.Transactions, utils.SEPATransaction{
+				EndToEndID:   fmt.Sprintf("PAYOUT-%d", payout.ID),
+				AmountCents:  payout.NetAmount.Cents,
+				CreditorName: creditor,
+				CreditorIBAN: account.IBAN,
+				CreditorBIC:  account.BIC,
+				Remittance:   fmt.Sprintf("Releve n° %d %s", payout.ID, payoutPeriodLabel(payout)),
+			})
+			batch.Total = batch.Total.Add(payout.NetAmount)
+		}
+		content, err := transfer.Pain001()
+		if err != nil {
+			return err
+		}
+		batch.Content = string(content)
+		if err := tx.Save(&batch).Error; err != nil {
+			return err
+		}
+
+		// Garde-fou contre deux exports simultanés des mêmes relevés
+		result := tx.Model(&models.Payout{}).
+			Where("id IN ? AND transfer_batch_id IS NULL", ids).
+			Update("transfer_batch_id", batch.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return ErrPayoutAlreadyExported
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// uniqueIDs retire les doublons d'une liste d'identifiants
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var unique []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+)
+
+// Prestataire enregistré sur les paiements encaissés par prélèvement SEPA
+const sepaDebitProvider = "sepa_direct_debit"
+
+var (
+	ErrSEPACreditorNotConfigured = errors.New("identifiant créancier SEPA non configuré ou invalide (SEPA_CREDITOR_ID)")
+	ErrMandateSignedInFuture     = errors.New("la date de signature du mandat ne peut pas être dans le futur")
+	ErrMandateReference          = errors.New("référence de mandat invalide : 35 caractères au plus, lettres, chiffres et / - ? : ( ) . , ' + espace")
+	ErrMandateReferenceTaken     = errors.New("cette référence de mandat est déjà utilisée")
+	ErrNoInvoiceToCollect        = errors.New("aucune facture à prélever")
+	ErrInvoiceNotCollectable     = errors.New("seules les factures émises, en euros, non soldées, sans avoir ni prélèvement en cours peuvent être prélevées")
+	ErrNoActiveMandate           = errors.New("aucun mandat de prélèvement actif")
+)
+
+// CreateMandate enregistre le mandat de prélèvement signé par une famille et révoque
+// le mandat actif précédent. Sans référence fournie, une RUM est attribuée.
+func CreateMandate(db *gorm.DB, familleID uint, req models.SEPAMandateCreateRequest) (*models.SEPAMandate, error) {
+	iban, err := utils.ValidateIBAN(req.IBAN)
+	if err != nil {
+		return nil, err
+	}
+	bic := ""
+	if strings.TrimSpace(req.BIC) != "" {
+		if bic, err = utils.ValidateBIC(req.BIC); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	if req.SignedAt.After(now) {
+		return nil, ErrMandateSignedInFuture
+	}
+	reference := strings.TrimSpace(req.Reference)
+	if reference != "" && utils.SEPAText(reference, 35) != reference {
+		return nil, ErrMandateReference
+	}
+
+	mandate := models.SEPAMandate{
+		Reference:  reference,
+		Status:     models.MandateStatusActive,
+		DebtorName: strings.TrimSpace(req.DebtorName),
+		IBAN:       iban,
+		BIC:        bic,
+		SignedAt:   req.SignedAt,
+		FamilleID:  familleID,
+	}
+	if reference == "" {
+		mandate.Reference = fmt.Sprintf("MDT-TMP-%d", now.UnixNano())
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if reference != "" {
+			var count int64
+			if err := tx.Model(&models.SEPAMandate{}).Where("reference = ?", reference).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrMandateReferenceTaken
+			}
+		}
+		if err := tx.Model(&models.SEPAMandate{}).
+			Where("famille_id = ? AND status = ?", familleID, models.MandateStatusActive).
+			Updates(map[string]interface{}{"status": models.MandateStatusRevoked, "revoked_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&mandate).Error; err != nil {
+			return err
+		}
+		if reference == "" {
+			mandate.Reference = fmt.Sprintf("MDT-%06d", mandate.ID)
+			return tx.Model(&mandate).Update("reference", mandate.Reference).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	maskMandate(&mandate)
+	return &mandate, nil
+}
+
+// RevokeMandate révoque un mandat ; les prélèvements déjà exportés ne sont pas annulés
+func RevokeMandate(db *gorm.DB, mandate *models.SEPAMandate) error {
+	if err := mandate.Revoke(time.Now()); err != nil {
+		return err
+	}
+	maskMandate(mandate)
+	return db.Model(mandate).Select("status", "revoked_at").Updates(mandate).Error
+}
+
+// FamilleMandates retourne les mandats d'une famille, du plus récent au plus ancien
+func FamilleMandates(db *gorm.DB, familleID uint) ([]models.SEPAMandate, error) {
+	var mandates []models.SEPAMandate
+	if err := db.Where("famille_id = ?", familleID).Order("signed_at DESC, id DESC").Find(&mandates).Error; err != nil {
+		return nil, err
+	}
+	for i := range mandates {
+		maskMandate(&mandates[i])
+	}
+	return mandates, nil
+}
+
+// maskMandate renseigne l'IBAN masqué exposé par l'API
+func maskMandate(mandate *models.SEPAMandate) {
+	mandate.MaskedIBAN = utils.MaskIBAN(mandate.IBAN)
+}
+
+// ParseCollectionDate retourne la date d'échéance demandée, ou à défaut le premier
+// jour ouvré suivant le délai de préavis ; une échéance plus proche est refusée
+func ParseCollectionDate(value string, now time.Time, prenotificationDays int) (time.Time, error) {
+	loc := utils.CalendarLocation()
+	today := time.Date(now.In(loc).Year(), now.In(loc).Month(), now.In(loc).Day(), 0, 0, 0, 0, loc)
+	earliest := today.AddDate(0, 0, prenotificationDays)
+	for earliest.Weekday() == time.Saturday || earliest.Weekday() == time.Sunday {
+		earliest = earliest.AddDate(0, 0, 1)
+	}
+	if value == "" {
+		return earliest, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date d'échéance invalide : format YYYY-MM-DD attendu")
+	}
+	if date.Before(earliest) {
+		return time.Time{}, fmt.Errorf("date d'échéance trop proche : le préavis de %d jours impose le %s au plus tôt",
+			prenotificationDays, earliest.Format("02/01/2006"))
+	}
+	return date, nil
+}
+
+// collectableInvoices restreint une requête aux factures émises, en euros, sans
+// avoir et sans prélèvement en cours ou encaissé
+func collectableInvoices(tx *gorm.DB) *gorm.DB {
+	return tx.Where("kind = ? AND status = ? AND currency = ?",
+		models.InvoiceKindInvoice, models.InvoiceStatusIssued, models.CurrencyEUR).
+		Where("NOT EXISTS (SELECT 1 FROM invoices AS credit_notes WHERE credit_notes.credited_invoice_id = invoices.id AND credit_notes.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.invoice_id = invoices.id AND payments.status IN ? AND payments.deleted_at IS NULL)",
+			[]models.PaymentStatus{models.PaymentStatusPending, models.PaymentStatusProcessing, models.PaymentStatusCompleted})
+}
+
+// ExportInvoiceDebits génère le fichier de prélèvements SEPA (pain.008) des factures
+// demandées, ou de toutes les factures à prélever des familles ayant un mandat actif.
+// Chaque prélèvement est enregistré comme un paiement en attente et la famille est
+// prévenue du montant et de la date d'échéance.
+func ExportInvoiceDebits(db *gorm.DB, invoiceIDs []uint, collectionDate time.Time, adminID uint) (*models.SEPADebitBatch, error) {
+	settings, err := CurrentSEPASettings()
+	if err != nil {
+		return nil, err
+	}
+	creditorID, err := utils.ValidateCreditorID(settings.CreditorID)
+	if err != nil {
+		return nil, ErrSEPACreditorNotConfigured
+	}
+
+	var batch models.SEPADebitBatch
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var invoices []models.Invoice
+		if len(invoiceIDs) > 0 {
+			if err := tx.Where("id IN ?", invoiceIDs).Find(&invoices).Error; err != nil {
+				return err
+			}
+			if len(invoices) != len(uniqueIDs(invoiceIDs)) {
+				return gorm.ErrRecordNotFound
+			}
+			var count int64
+			if err := collectableInvoices(tx.Model(&models.Invoice{})).Where("id IN ?", invoiceIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != int64(len(invoices)) {
+				return ErrInvoiceNotCollectable
+			}
+		} else {
+			if err := collectableInvoices(tx).Where("famille_id IN (?)",
+				tx.Model(&models.SEPAMandate{}).Select("famille_id").Where("status = ?", models.MandateStatusActive)).
+				Order("famille_id, issue_date, id").Find(&invoices).Error; err != nil {
+				return err
+			}
+		}
+
+		var familleIDs []uint
+		for _, invoice := range invoices {
+			if !invoice.AmountDue().IsPositive() {
+				if len(invoiceIDs) > 0 {
+					return ErrInvoiceNotCollectable
+				}
+				continue
+			}
+			familleIDs = append(familleIDs, invoice.FamilleID)
+		}
+		if len(familleIDs) == 0 {
+			return ErrNoInvoiceToCollect
+		}
+		var mandates []models.SEPAMandate
+		if err := tx.Where("famille_id IN ? AND status = ?", familleIDs, models.MandateStatusActive).Find(&mandates).Error; err != nil {
+			return err
+		}
+		byFamille := make(map[uint]models.SEPAMandate, len(mandates))
+		for _, mandate := range mandates {
+			byFamille[mandate.FamilleID] = mandate
+		}
+		var missing []string
+		for _, id := range uniqueIDs(familleIDs) {
+			if _, ok := byFamille[id]; !ok {
+				missing = append(missing, fmt.Sprint(id))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w (familles %s)", ErrNoActiveMandate, strings.Join(missing, ", "))
+		}
+		firstCollection, err := firstCollections(tx, mandates)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		loc := utils.CalendarLocation()
+		batch = models.SEPADebitBatch{
+			MessageID:      fmt.Sprintf("DEBIT-TMP-%d", now.UnixNano()),
+			CollectionDate: collectionDate,
+			PrenotifiedAt:  now,
+			Total:          models.NewMoney(0, models.CurrencyEUR),
+			CreatedByID:    adminID,
+		}
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+		batch.MessageID = fmt.Sprintf("DEBIT-%s-%06d", now.In(loc).Format("20060102"), batch.ID)
+
+		debit := utils.SEPADirectDebit{
+			MessageID:      batch.MessageID,
+			CreatedAt:      now.In(loc),
+			CollectionDate: collectionDate,
+			CreditorName:   settings.AccountName,
+			CreditorIBAN:   settings.AccountIBAN,
+			CreditorBIC:    settings.AccountBIC,
+			CreditorID:     creditorID,
+		}
+		for i := range invoices {
+			invoice := &invoices[i]
+			amount := invoice.AmountDue()
+			if !amount.IsPositive() {
+				continue
+			}
+			mandate := byFamille[invoice.FamilleID]
+			invoiceID, mandateID, batchID := invoice.ID, mandate.ID, batch.ID
+			payment := models.Payment{
+				Amount:       amount,
+				Status:       models.PaymentStatusPending,
+				Type:         models.PaymentTypeInvoice,
+				Description:  "Prélèvement SEPA - facture " + *invoice.Number,
+				Provider:     sepaDebitProvider,
+				UserID:       invoice.FamilleID,
+				InvoiceID:    &invoiceID,
+				MandateID:    &mandateID,
+				DebitBatchID: &batchID,
+			}
+			if err := tx.Omit("User", "Course").Create(&payment).Error; err != nil {
+				return err
+			}
+			payment.ProviderIntentID = fmt.Sprintf("DEBIT-%d", payment.ID)
+			if err := tx.Model(&payment).Update("provider_intent_id", payment.ProviderIntentID).Error; err != nil {
+				return err
+			}
+
+			sequenceType := utils.SEPASequenceRecurring
+			if firstCollection[mandate.ID] {
+				sequenceType = utils.SEPASequenceFirst
+			}
+			debit.Transactions = append(debit.Transactions, utils.SEPADebitTransaction{
+				EndToEndID:      payment.ProviderIntentID,
+				AmountCents:     amount.Cents,
+				SequenceType:    sequenceType,
+				MandateID:       mandate.Reference,
+				MandateSignedAt: mandate.SignedAt.In(loc),
+				DebtorName:      mandate.DebtorName,
+				DebtorIBAN:      mandate.IBAN,
+				DebtorBIC:       mandate.BIC,
+				Remittance:      "Facture " + *invoice.Number,
+			})
+			batch.PaymentCount++
+			batch.Total = batch.Total.Add(amount)
+
+			if err := Notify(tx, invoice.FamilleID, models.NotificationDebitScheduled, "Prélèvement à venir",
+				fmt.Sprintf("Un prélèvement SEPA de %s sera effectué le %s au titre de la facture %s (mandat %s)",
+					amount, collectionDate.In(loc).Format("02/01/2006"), *invoice.Number, mandate.Reference),
+				paymentLink(payment.ID)); err != nil {
+				return err
+			}
+		}
+		content, err := debit.Pain008()
+		if err != nil {
+			return err
+		}
+		batch.Content = string(content)
+		return tx.Omit("Payments").Save(&batch).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// firstCollections indique, par mandat, si le prochain prélèvement est le premier :
+// aucun prélèvement n'a encore été présenté avec succès ou n'est en cours
+func firstCollections(tx *gorm.DB, mandates []models.SEPAMandate) (map[uint]bool, error) {
+	first := make(map[uint]bool, len(mandates))
+	ids := make([]uint, len(mandates))
+	for i, mandate := range mandates {
+		ids[i] = mandate.ID
+		first[mandate.ID] = true
+	}
+	var used []uint
+	if err := tx.Model(&models.Payment{}).Where("mandate_id IN ? AND status <> ?", ids, models.PaymentStatusFailed).
+		Distinct().Pluck("mandate_id", &used).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range used {
+		first[id] = false
+	}
+	return first, nil
+}
+
+// ImportDebitReturns applique un avis de la banque (camt.054) aux prélèvements
+// exportés : les opérations créditées et comptabilisées soldent le paiement, les
+// rejets et retours (R-transactions) le font passer à l'état échoué avec leur motif.
+// Une opération déjà prise en compte est ignorée, ce qui permet de réimporter un fichier.
+func ImportDebitReturns(db *gorm.DB, content []byte) (*models.SEPAReturnImportResponse, error) {
+	transactions, err := utils.ParseCamt054(content)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.SEPAReturnImportResponse{
+		Completed: []uint{},
+		Failed:    []uint{},
+		Ignored:   []string{},
+		Unmatched: []string{},
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, transaction := range transactions {
+			reference := transaction.EndToEndID
+			var payment models.Payment
+			err := tx.Where("provider = ? AND provider_intent_id = ?", sepaDebitProvider, reference).First(&payment).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Unmatched = append(result.Unmatched, reference)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if transaction.AmountCents != payment.Amount.Cents {
+				result.Unmatched = append(result.Unmatched, fmt.Sprintf("%s (montant %s au lieu de %s)",
+					reference, models.NewMoney(transaction.AmountCents, payment.Amount.Currency), payment.Amount))
+				continue
+			}
+			if !transaction.Booked {
+				result.Ignored = append(result.Ignored, reference+" (opération non comptabilisée)")
+				continue
+			}
+
+			switch {
+			case transaction.Returned && payment.Status != models.PaymentStatusFailed:
+				if err := returnDebit(tx, &payment, utils.SEPAReturnReason(transaction.ReturnReason)); err != nil {
+					return err
+				}
+				result.Failed = append(result.Failed, payment.ID)
+			case !transaction.Returned && payment.Status == models.PaymentStatusPending:
+				if err := completePayment(tx, &payment); err != nil {
+					return err
+				}
+				result.Completed = append(result.Completed, payment.ID)
+			default:
+				result.Ignored = append(result.Ignored, reference+" (déjà pris en compte)")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func returnDebit(tx *gorm.DB, payment *models.Payment, reason string) error {
+	if err := payment.ReturnDebit(reason); err != nil {
+		return err
+	}
+	if err := savePayment(tx, payment); err != nil {
+		return err
+	}
+	if err := postDebitReturn(tx, payment, time.Now()); err != nil {
+		return err
+	}
+	return Notify(tx, payment.UserID, models.NotificationPaymentFailed, "Prélèvement rejeté",
+		fmt.Sprintf("Votre prélèvement de %s a été rejeté : %s", payment.Amount, reason), paymentLink(payment.ID))
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// taxCertificateShare est la part d'un cours réglée pendant l'année
+type taxCertificateShare struct {
+	enseignantID uint
+	minutes      float64
+	amount       models.Money
+}
+
+// taxYearBounds retourne les bornes [1er janvier, 1er janvier suivant[ de l'année civile, en heure locale
+func taxYearBounds(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, utils.CalendarLocation())
+	return start, start.AddDate(1, 0, 0)
+}
+
+// taxCertificateShares calcule les sommes effectivement versées par une famille
+// pendant l'année pour des cours, et les heures correspondantes :
+//   - les paiements encaissés d'un cours, pour la durée du cours ;
+//   - les règlements de factures (paiements et acomptes imputés, versés dans
+//     l'année), répartis entre les lignes de cours au prorata de leur montant
+//     TTC, les heures étant retenues dans la même proportion ;
+//   - les heures prépayées consommées par les cours de l'année, pour la valeur
+//     payée de ces heures.
+//
+// Les paiements remboursés ou échoués ne sont pas retenus.
+func taxCertificateShares(db *gorm.DB, familleID uint, year int) ([]taxCertificateShare, error) {
+	start, end := taxYearBounds(year)
+	var shares []taxCertificateShare
+
+	var coursePayments []models.Payment
+	if err := db.Preload("Course").
+		Where("user_id = ? AND status = ? AND type = ? AND course_id IS NOT NULL AND payment_date >= ? AND payment_date < ?",
+			familleID, models.PaymentStatusCompleted, models.PaymentTypeCourse, start, end).
+		Find(&coursePayments).Error; err != nil {
+		return nil, err
+	}
+	for _, payment := range coursePayments {
+		if payment.Course == nil {
+			continue
+		}
+		shares = append(shares, taxCertificateShare{
+			enseignantID: payment.Course.EnseignantID,
+			minutes:      float64(payment.Course.Duration),
+			amount:       payment.Amount.Sub(payment.RefundedAmount),
+		})
+	}
+
+	var debits []models.WalletTransaction
+	if err := db.Where("famille_id = ? AND type = ? AND course_id IS NOT NULL AND created_at >= ? AND created_at < ?",
+		familleID, models.WalletTransactionCourse, start, end).Find(&debits).Error; err != nil {
+		return nil, err
+	}
+	if len(debits) > 0 {
+		debitCourseIDs := make([]uint, len(debits))
+		for i, debit := range debits {
+			debitCourseIDs[i] = *debit.CourseID
+		}
+		var courses []models.Course
+		if err := db.Unscoped().Select("id", "enseignant_id").Where("id IN ?", debitCourseIDs).Find(&courses).Error; err != nil {
+			return nil, err
+		}
+		debitTeachers := map[uint]uint{}
+		for _, course := range courses {
+			debitTeachers[course.ID] = course.EnseignantID
+		}
+		for _, debit := range debits {
+			// Les heures offertes n'ont pas été payées
+			if !debit.Amount.IsNegative() {
+				continue
+			}
+			shares = append(shares, taxCertificateShare{
+				enseignantID: debitTeachers[*debit.CourseID],
+				minutes:      float64(-debit.Minutes),
+				amount:       debit.Amount.Neg(),
+			})
+		}
+	}
+
+	// Sommes versées dans l'année par facture : règlements directs puis acomptes imputés
+	paid := map[uint]models.Money{}
+	var invoicePayments []models.Payment
+	if err := db.Where("user_id = ? AND status = ? AND type <> ? AND invoice_id IS NOT NULL AND payment_date >= ? AND payment_date < ?",
+		familleID, models.PaymentStatusCompleted, models.PaymentTypeRefund, start, end).Find(&invoicePayments).Error; err != nil {
+		return nil, err
+	}
+	for _, payment := range invoicePayments {
+		paid[*payment.InvoiceID] = paid[*payment.InvoiceID].Add(payment.Amount.Sub(payment.RefundedAmount))
+	}
+	var allocations []models.AdvanceAllocation
+	if err := db.Joins("JOIN payments ON payments.id = advance_allocations.payment_id AND payments.deleted_at IS NULL").
+		Where("payments.user_id = ? AND payments.status = ? AND payments.payment_date >= ? AND payments.payment_date < ?",
+			familleID, models.PaymentStatusCompleted, start, end).
+		Where("advance_allocations.invoice_id IS NOT NULL AND advance_allocations.released_at IS NULL").
+		Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+	for _, allocation := range allocations {
+		paid[*allocation.InvoiceID] = paid[*allocation.InvoiceID].Add(allocation.Amount)
+	}
+	if len(paid) == 0 {
+		return shares, nil
+	}
+
+	invoiceIDs := make([]uint, 0, len(paid))
+	for id := range paid {
+		invoiceIDs = append(invoiceIDs, id)
+	}
+	var invoices []models.Invoice
+	if err := db.Preload("Lines", "course_id IS NOT NULL").Where("id IN ?", invoiceIDs).Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	var courseIDs []uint
+	for _, invoice := range invoices {
+		for _, line := range invoice.Lines {
+			courseIDs = append(courseIDs, *line.CourseID)
+		}
+	}
+	teachers := map[uint]uint{}
+	if len(courseIDs) > 0 {
+		var courses []models.Course
+		if err := db.Unscoped().Select("id", "enseignant_id").Where("id IN ?", courseIDs).Find(&courses).Error; err != nil {
+			return nil, err
+		}
+		for _, course := range courses {
+			teachers[course.ID] = course.EnseignantID
+		}
+	}
+	for _, invoice := range invoices {
+		if len(invoice.Lines) == 0 || !invoice.TotalTTC.IsPositive() {
+			continue
+		}
+		amount := paid[invoice.ID]
+		if amount.Cents > invoice.TotalTTC.Cents {
+			amount = invoice.TotalTTC
+		}
+		ratio := float64(amount.Cents) / float64(invoice.TotalTTC.Cents)
+		weights := make([]int64, len(invoice.Lines))
+		for i, line := range invoice.Lines {
+			weights[i] = line.TotalHT.Add(line.TotalHT.Percent(line.VATRate)).Cents
+		}
+		// Seule la part des lignes de cours est retenue lorsque la facture comporte d'autres lignes
+		var courseCents int64
+		for _, weight := range weights {
+			courseCents += weight
+		}
+		if courseCents < invoice.TotalTTC.Cents {
+			amount = models.NewMoney(int64(math.Round(float64(courseCents)*ratio)), amount.Currency)
+		}
+		for i, part := range amount.Allocate(weights) {
+			line := invoice.Lines[i]
+			shares = append(shares, taxCertificateShare{
+				enseignantID: teachers[*line.CourseID],
+				minutes:      line.Quantity * 60 * ratio,
+				amount:       part,
+			})
+		}
+	}
+	return shares, nil
+}
+
+// buildTaxCertificateLines regroupe les parts par enseignant
+func buildTaxCertificateLines(db *gorm.DB, shares []taxCertificateShare) ([]models.TaxCertificateLine, error) {
+	type total struct {
+		minutes float64
+		amount  models.Money
+	}
+	totals := map[uint]*total{}
+	var ids []uint
+	for _, share := range shares {
+		if _, ok := totals[share.enseignantID]; !ok {
+			totals[share.enseignantID] = &total{}
+			ids = append(ids, share.enseignantID)
+		}
+		totals[share.enseignantID].minutes += share.minutes
+		totals[share.enseignantID].amount = totals[share.enseignantID].amount.Add(share.amount)
+	}
+	var users []models.User
+	if err := db.Unscoped().Select("id", "username").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	names := map[uint]string{}
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	sort.Slice(ids, func(i, j int) bool { return names[ids[i]] < names[ids[j]] })
+
+	var lines []models.TaxCertificateLine
+	for _, id := range ids {
+		if !totals[id].amount.IsPositive() {
+			continue
+		}
+		lines = append(lines, models.TaxCertificateLine{
+			Position:     len(lines) + 1,
+			TeacherName:  names[id],
+			Minutes:      int(math.Round(totals[id].minutes)),
+			Amount:       totals[id].amount,
+			EnseignantID: id,
+		})
+	}
+	return lines, nil
+}
+
+// taxCertificateFamilies retourne les familles ayant réglé des cours pendant
+// l'année, directement ou par leurs heures prépayées
+func taxCertificateFamilies(db *gorm.DB, year int) ([]uint, error) {
+	start, end := taxYearBounds(year)
+	var ids []uint
+	err := db.Model(&models.Payment{}).
+		Joins("JOIN familles ON familles.user_id = payments.user_id").
+		Where("payments.status = ? AND payments.payment_date >= ? AND payments.payment_date < ?", models.PaymentStatusCompleted, start, end).
+		Where("(payments.type = ? AND payments.course_id IS NOT NULL) OR payments.invoice_id IS NOT NULL OR payments.type = ?",
+			models.PaymentTypeCourse, models.PaymentTypeAdvance).
+		Distinct().Order("payments.user_id").Pluck("payments.user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	var walletIDs []uint
+	if err := db.Model(&models.WalletTransaction{}).
+		Where("type = ? AND created_at >= ? AND created_at < ?", models.WalletTransactionCourse, start, end).
+		Distinct().Order("famille_id").Pluck("famille_id", &walletIDs).Error; err != nil {
+		return nil, err
+	}
+	ids = uniqueIDs(append(ids, walletIDs...))
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// GenerateTaxCertificates calcule les attestations fiscales de l'année, pour une
+// famille ou pour toutes celles qui ont réglé des cours. Les brouillons existants
+// sont recalculés ; les attestations déjà publiées ne sont pas modifiées et sont
+// signalées dans le résultat, ou en erreur pour une famille désignée.
+func GenerateTaxCertificates(db *gorm.DB, year int, familleID *uint) (*models.TaxCertificateBatchResponse, error) {
+	result := &models.TaxCertificateBatchResponse{
+		Year:         year,
+		Certificates: []models.TaxCertificate{},
+		Skipped:      []uint{},
+	}
+	familleIDs := []uint{}
+	if familleID != nil {
+		familleIDs = append(familleIDs, *familleID)
+	} else {
+		ids, err := taxCertificateFamilies(db, year)
+		if err != nil {
+			return nil, err
+		}
+		familleIDs = ids
+	}
+
+	settings := CurrentInvoiceSettings()
+	declarationNumber := os.Getenv("SAP_DECLARATION_NUMBER")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range familleIDs {
+			var certificate models.TaxCertificate
+			err := tx.Where("famille_id = ? AND year = ?", id, year).First(&certificate).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if certificate.IsPublished() {
+				if familleID != nil {
+					return models.ErrTaxCertificatePublished
+				}
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			shares, err := taxCertificateShares(tx, id, year)
+			if err != nil {
+				return err
+			}
+			lines, err := buildTaxCertificateLines(tx, shares)
+			if err != nil {
+				return err
+			}
+			if certificate.ID != 0 {
+				if err := tx.Where("tax_certificate_id = ?", certificate.ID).Delete(&models.TaxCertificateLine{}).Error; err != nil {
+					return err
+				}
+			}
+			if len(lines) == 0 {
+				if certificate.ID != 0 {
+					if err := tx.Delete(&certificate).Error; err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			certificate.Year = year
+			certificate.FamilleID = id
+			certificate.Status = models.TaxCertificateStatusDraft
+			certificate.Number = fmt.Sprintf("ATT-%d-%06d", year, id)
+			certificate.GeneratedAt = time.Now()
+			certificate.TotalAmount = models.NewMoney(0, lines[0].Amount.Currency)
+			certificate.SellerName = settings.SellerName
+			certificate.SellerAddress = settings.SellerAddress
+			certificate.SellerSIRET = settings.SellerSIRET
+			certificate.DeclarationNumber = declarationNumber
+			certificate.CustomerName, certificate.CustomerAddress = invoiceCustomer(tx, id)
+			certificate.Lines = lines
+			certificate.ComputeTotals()
+			if err := tx.Omit(clause.Associations).Save(&certificate).Error; err != nil {
+				return err
+			}
+			for i := range certificate.Lines {
+				certificate.Lines[i].TaxCertificateID = certificate.ID
+			}
+			if err := tx.Create(&certificate.Lines).Error; err != nil {
+				return err
+			}
+			result.Certificates = append(result.Certificates, certificate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PublishTaxCertificates met à disposition des familles les attestations de
+// l'année encore en brouillon et les en informe
+func PublishTaxCertificates(db *gorm.DB, year int, familleID *uint, adminID uint) (*models.TaxCertificateBatchResponse, error) {
+	result := &models.TaxCertificateBatchResponse{
+		Year:         year,
+		Certificates: []models.TaxCertificate{},
+		Skipped:      []uint{},
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("year = ? AND status = ?", year, models.TaxCertificateStatusDraft)
+		if familleID != nil {
+			query = query.Where("famille_id = ?", *familleID)
+		}
+		var certificates []models.TaxCertificate
+		if err := query.Order("famille_id").Find(&certificates).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, certificate := range certificates {
+			certificate.Status = models.TaxCertificateStatusPublished
+			certificate.PublishedAt = &now
+			certificate.PublishedByID = &adminID
+			if err := tx.Omit(clause.Associations).Save(&certificate).Error; err != nil {
+				return err
+			}
+			if err := Notify(tx, certificate.FamilleID, models.NotificationTaxCertificatePublished, "Attestation fiscale disponible",
+				fmt.Sprintf("Votre attestation fiscale %d est disponible : %s versés au titre des services à la personne", year, certificate.TotalAmount),
+				taxCertificateLink(certificate.ID)); err != nil {
+				return err
+			}
+			result.Certificates = append(result.Certificates, certificate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LoadTaxCertificate charge une attestation avec ses lignes
+func LoadTaxCertificate(db *gorm.DB, id uint) (*models.TaxCertificate, error) {
+	var certificate models.TaxCertificate
+	err := db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		First(&certificate, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+func taxCertificateLink(id uint) string {
+	return fmt.Sprintf("/api/v1/tax-certificates/%d/pdf", id)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"api/models"
+	"api/utils"
+)
+
+// RenderTaxCertificatePDF produit l'attestation fiscale annuelle d'une famille au
+// titre des services à la personne (article D. 7233-1 du code du travail). Les
+// brouillons sont rendus avec la mention « BROUILLON ».
+func RenderTaxCertificatePDF(certificate *models.TaxCertificate) []byte {
+	heading := fmt.Sprintf("ATTESTATION FISCALE %d", certificate.Year)
+	if !certificate.IsPublished() {
+		heading += " - BROUILLON"
+	}
+	doc := &utils.PDFDocument{Title: heading}
+	doc.AddPage()
+
+	right := utils.PDFPageWidth - pdfMargin
+	y := 60.0
+	doc.Text(pdfMargin, y, 16, true, certificate.SellerName)
+	doc.TextRight(right, y, 14, true, heading)
+	y += pdfLineHeight + 4
+	for _, line := range []string{certificate.SellerAddress, prefixed("SIRET : ", certificate.SellerSIRET),
+		prefixed("Déclaration de services à la personne n° ", certificate.DeclarationNumber)} {
+		if line != "" {
+			doc.Text(pdfMargin, y, 9, false, line)
+			y += 12
+		}
+	}
+	doc.TextRight(right, 80, 10, false, "N° "+certificate.Number)
+
+	y += 20
+	doc.Text(pdfMargin, y, 10, true, "Bénéficiaire des prestations")
+	doc.Text(pdfMargin, y+14, 10, false, certificate.CustomerName)
+	doc.Text(pdfMargin, y+28, 9, false, certificate.CustomerAddress)
+	y += 56
+
+	paragraph := []string{
+		fmt.Sprintf("Nous attestons que %s a bénéficié de prestations de soutien scolaire à domicile,", certificate.CustomerName),
+		fmt.Sprintf("activité de services à la personne, et a versé du 1er janvier au 31 décembre %d la somme de %s", certificate.Year, certificate.TotalAmount),
+		fmt.Sprintf("pour %s d'intervention, détaillées ci-dessous par intervenant.", formatMinutes(certificate.TotalMinutes)),
+	}
+	for _, line := range paragraph {
+		doc.Text(pdfMargin, y, 10, false, line)
+		y += 14
+	}
+
+	columns := []float64{pdfMargin, 400, right}
+	tableHeader := func() {
+		y += 12
+		doc.Text(columns[0], y, 9, true, "Intervenant")
+		doc.TextRight(columns[1], y, 9, true, "Heures")
+		doc.TextRight(columns[2], y, 9, true, "Montant versé")
+		y += 6
+		doc.Line(pdfMargin, y, right, y, 0.8)
+		y += pdfLineHeight
+	}
+	tableHeader()
+	for _, line := range certificate.Lines {
+		if y > pdfBottom {
+			doc.AddPage()
+			y = 60
+			tableHeader()
+		}
+		doc.Text(columns[0], y, 9, false, line.TeacherName)
+		doc.TextRight(columns[1], y, 9, false, formatMinutes(line.Minutes))
+		doc.TextRight(columns[2], y, 9, false, line.Amount.String())
+		y += pdfLineHeight
+	}
+	doc.Line(pdfMargin, y-10, right, y-10, 0.5)
+	doc.TextRight(columns[1], y+4, 10, true, formatMinutes(certificate.TotalMinutes))
+	doc.TextRight(columns[2], y+4, 10, true, certificate.TotalAmount.String())
+	y += 36
+
+	if y > pdfBottom-60 {
+		doc.AddPage()
+		y = 60
+	}
+	footer := []string{
+		"Les sommes indiquées sont celles effectivement versées pendant l'année, hors frais d'annulation et pénalités.",
+		"Les sommes réglées au moyen de CESU préfinancés doivent être déduites du montant déclaré.",
+		"Cette attestation permet de bénéficier du crédit d'impôt prévu à l'article 199 sexdecies du code général des impôts.",
+	}
+	if certificate.PublishedAt != nil {
+		footer = append(footer, "Établie le "+certificate.PublishedAt.In(utils.CalendarLocation()).Format("02/01/2006")+".")
+	}
+	for _, line := range footer {
+		doc.Text(pdfMargin, y, 9, false, line)
+		y += 12
+	}
+	return doc.Bytes()
+}
+
+// TaxCertificateFileName retourne le nom du fichier PDF d'une attestation fiscale
+func TaxCertificateFileName(certificate *models.TaxCertificate) string {
+	return strings.ToLower(certificate.Number) + ".pdf"
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TimesheetApprovalDelay retourne le délai laissé aux familles pour approuver
+// ou contester une feuille de temps (TIMESHEET_APPROVAL_HOURS, 72h par défaut)
+func TimesheetApprovalDelay() time.Duration {
+	hours := 72
+	if env := os.Getenv("TIMESHEET_APPROVAL_HOURS"); env != "" {
+		if h, err := strconv.Atoi(env); err == nil && h > 0 {
+			hours = h
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// ApproveTimesheetEntry approuve une entrée (reviewerID 0 pour une approbation
+// automatique) : le cours passe à l'état terminé et devient facturable. Le cours et
+// l'entrée sont relus sous verrou ; un cours annulé entre-temps n'est pas facturé.
+func ApproveTimesheetEntry(db *gorm.DB, entry *models.TimesheetEntry, reviewerID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var course models.Course
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, entry.CourseID).Error; err != nil {
+			return err
+		}
+		if course.Status == models.CourseStatusCancelled {
+			return models.ErrTimesheetCourseCancelled
+		}
+		if err := tx.First(entry, entry.ID).Error; err != nil {
+			return err
+		}
+		if entry.Status != models.TimesheetStatusPending {
+			return models.ErrTimesheetNotPending
+		}
+		if err := course.Validate(); err != nil {
+			return err
+		}
+
+		eventType, message := models.CourseEventConfirmed, "Cours confirmé par la famille"
+		if reviewerID == 0 {
+			entry.AutoApprove()
+			eventType, message = models.CourseEventAutoConfirmed, "Cours confirmé automatiquement après expiration du délai"
+		} else {
+			entry.Approve(reviewerID)
+		}
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&course).Update("status", course.Status).Error; err != nil {
+			return err
+		}
+		if _, err := BillTimesheetEntry(tx, entry); err != nil {
+			return err
+		}
+		return RecordCourseEvent(tx, entry.CourseID, reviewerID, eventType, message)
+	})
+}
+
+// AutoApproveTimesheets approuve les entrées en attente dont le délai est dépassé ;
+// celles des cours annulés entre-temps sont annulées
+func AutoApproveTimesheets(db *gorm.DB, now time.Time) (int, error) {
+	var entries []models.TimesheetEntry
+	if err := db.Where("status = ? AND approval_deadline <= ?", models.TimesheetStatusPending, now).
+		Find(&entries).Error; err != nil {
+		return 0, err
+	}
+	approved := 0
+	for i := range entries {
+		err := ApproveTimesheetEntry(db, &entries[i], 0)
+		switch {
+		case errors.Is(err, models.ErrTimesheetCourseCancelled):
+			if err := voidCourseTimesheet(db, entries[i].CourseID); err != nil {
+				return approved, err
+			}
+			continue
+		case errors.Is(err, models.ErrTimesheetNotPending):
+			continue
+		case err != nil:
+			return approved, err
+		}
+		approved++
+	}
+	return approved, nil
+}
+
+// voidCourseTimesheet annule la déclaration en attente ou contestée d'un cours annulé
+func voidCourseTimesheet(tx *gorm.DB, courseID uint) error {
+	var entry models.TimesheetEntry
+	err := tx.Where("course_id = ? AND status IN ?", courseID,
+		[]models.TimesheetStatus{models.TimesheetStatusPending, models.TimesheetStatusDisputed}).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entry.Void()
+	return tx.Save(&entry).Error
+}
+
+// MonthlyTimesheetSummary agrège les entrées d'un enseignant pour un mois donné
+func MonthlyTimesheetSummary(db *gorm.DB, enseignantID uint, month time.Time) (models.TimesheetSummaryResponse, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0)
+
+	summary := models.TimesheetSummaryResponse{
+		EnseignantID: enseignantID,
+		Month:        start.Format("2006-01"),
+		Entries:      []models.TimesheetEntry{},
+	}
+	if err := db.Where("enseignant_id = ? AND declared_start >= ? AND declared_start < ?", enseignantID, start, end).
+		Order("declared_start").Find(&summary.Entries).Error; err != nil {
+		return summary, err
+	}
+
+	for _, e := range summary.Entries {
+		if e.Status == models.TimesheetStatusVoided {
+			continue
+		}
+		summary.TotalMinutes += e.DurationMinutes
+		switch e.Status {
+		case models.TimesheetStatusApproved:
+			summary.ApprovedMinutes += e.DurationMinutes
+		case models.TimesheetStatusPending:
+			summary.PendingMinutes += e.DurationMinutes
+		case models.TimesheetStatusDisputed:
+			summary.DisputedMinutes += e.DurationMinutes
+		}
+	}
+	summary.EntryCount = len(summary.Entries)
+	summary.ApprovedHours = float64(summary.ApprovedMinutes) / 60
+	return summary, nil
+}
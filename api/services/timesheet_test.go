@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"api/models"
+)
+
+func TestCancelCourseVoidsTimesheet(t *testing.T) {
+	db := newTestDB(t)
+	course, entry := newDeclaredCourse(t, db)
+	req := models.CourseCancelRequest{ReasonCode: models.CancellationReasonIllness}
+	if _, err := CancelCourse(db, course, course.FamilleID, models.CancellationByFamille, req); err != nil {
+		t.Fatalf("cancel course: %v", err)
+	}
+	if err := db.First(entry, entry.ID).Error; err != nil {
+		t.Fatalf("reload timesheet entry: %v", err)
+	}
+	if entry.Status != models.TimesheetStatusVoided {
+		t.Errorf("timesheet entry of a cancelled course = %s, want %s", entry.Status, models.TimesheetStatusVoided)
+	}
+	if err := ApproveTimesheetEntry(db, entry, course.FamilleID); !errors.Is(err, models.ErrTimesheetCourseCancelled) {
+		t.Errorf("approve timesheet of a cancelled course = %v, want %v", err, models.ErrTimesheetCourseCancelled)
+	}
+}
+
+func TestAutoApproveTimesheets(t *testing.T) {
+	tests := []struct {
+		name         string
+		courseStatus models.CourseStatus
+		wantApproved int
+		wantEntry    models.TimesheetStatus
+		wantCourse   models.CourseStatus
+		wantPayment  bool
+	}{
+		{"cours déclaré", models.CourseStatusInProgress, 1, models.TimesheetStatusApproved, models.CourseStatusCompleted, true},
+		{"cours annulé entre-temps", models.CourseStatusCancelled, 0, models.TimesheetStatusVoided, models.CourseStatusCancelled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			course, entry := newDeclaredCourse(t, db)
+			if err := db.Model(course).Update("status", tt.courseStatus).Error; err != nil {
+				t.Fatalf("set course status: %v", err)
+			}
+			approved, err := AutoApproveTimesheets(db, entry.ApprovalDeadline.Add(time.Minute))
+			if err != nil {
+				t.Fatalf("AutoApproveTimesheets: %v", err)
+			}
+			if approved != tt.wantApproved {
+				t.Errorf("approved = %d, want %d", approved, tt.wantApproved)
+			}
+			if err := db.First(entry, entry.ID).Error; err != nil {
+				t.Fatalf("reload timesheet entry: %v", err)
+			}
+			if entry.Status != tt.wantEntry {
+				t.Errorf("timesheet entry = %s, want %s", entry.Status, tt.wantEntry)
+			}
+			if err := db.First(course, course.ID).Error; err != nil {
+				t.Fatalf("reload course: %v", err)
+			}
+			if course.Status != tt.wantCourse {
+				t.Errorf("course = %s, want %s", course.Status, tt.wantCourse)
+			}
+			var payments int64
+			if err := db.Model(&models.Payment{}).Where("course_id = ? AND type = ?", course.ID, models.PaymentTypeCourse).
+				Count(&payments).Error; err != nil {
+				t.Fatalf("count payments: %v", err)
+			}
+			if (payments > 0) != tt.wantPayment {
+				t.Errorf("course payments = %d, want billed %v", payments, tt.wantPayment)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"api/models"
+	"api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WalletLowBalanceMinutes retourne le solde d'heures prépayées sous lequel la
+// famille est prévenue (WALLET_LOW_BALANCE_MINUTES, 2h par défaut)
+func WalletLowBalanceMinutes() int {
+	minutes := 120
+	if env := os.Getenv("WALLET_LOW_BALANCE_MINUTES"); env != "" {
+		if m, err := strconv.Atoi(env); err == nil && m >= 0 {
+			minutes = m
+		}
+	}
+	return minutes
+}
+
+// WalletExpiryNotice retourne le délai avant expiration à partir duquel la famille
+// est prévenue de ses heures inutilisées (WALLET_EXPIRY_NOTICE_DAYS, 14 jours par défaut)
+func WalletExpiryNotice() time.Duration {
+	days := 14
+	if env := os.Getenv("WALLET_EXPIRY_NOTICE_DAYS"); env != "" {
+		if d, err := strconv.Atoi(env); err == nil && d > 0 {
+			days = d
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PurchaseHourPackage enregistre l'achat d'une formule par une famille : un paiement
+// en attente est ouvert chez le prestataire et les heures sont créditées une fois
+// le paiement encaissé
+func PurchaseHourPackage(db *gorm.DB, pkg *models.HourPackage, familleID uint) (*models.HourPackagePurchase, error) {
+	if !pkg.IsActive {
+		return nil, models.ErrHourPackageInactive
+	}
+	provider, err := CurrentPaymentProvider()
+	if err != nil {
+		return nil, err
+	}
+	purchase := pkg.NewPurchase(familleID)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		payment := models.Payment{
+			Amount:      pkg.Price,
+			Type:        models.PaymentTypeHourPackage,
+			Description: "Forfait d'heures - " + pkg.Name,
+			Status:      models.PaymentStatusPending,
+			UserID:      familleID,
+		}
+		if err := tx.Omit("User", "Course").Create(&payment).Error; err != nil {
+			return err
+		}
+		if err := openPaymentIntent(tx, provider, &payment); err != nil {
+			return err
+		}
+		purchase.PaymentID = &payment.ID
+		if err := tx.Create(&purchase).Error; err != nil {
+			return err
+		}
+		purchase.Payment = &payment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// activateHourPackage crédite les heures d'une formule dont le paiement vient d'être encaissé
+func activateHourPackage(tx *gorm.DB, payment *models.Payment) error {
+	var purchase models.HourPackagePurchase
+	err := tx.Where("payment_id = ? AND status = ?", payment.ID, models.HourPackagePurchasePending).First(&purchase).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	validityDays := 0
+	if purchase.HourPackageID != nil {
+		var pkg models.HourPackage
+		if err := tx.Unscoped().First(&pkg, *purchase.HourPackageID).Error; err != nil {
+			return err
+		}
+		validityDays = pkg.ValidityDays
+	}
+	purchase.Activate(payment.PaymentDate, validityDays)
+	if err := tx.Save(&purchase).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.WalletTransaction{
+		Type:        models.WalletTransactionPurchase,
+		Minutes:     purchase.Minutes,
+		Amount:      purchase.Price,
+		Description: "Achat du forfait " + purchase.Name,
+		FamilleID:   purchase.FamilleID,
+		PurchaseID:  purchase.ID,
+	}).Error
+}
+
+// checkHourPackageRefundable refuse le remboursement intégral d'une formule dont
+// des heures ont déjà été utilisées ; un remboursement partiel reste possible
+func checkHourPackageRefundable(tx *gorm.DB, payment *models.Payment, amount models.Money) error {
+	if payment.Type != models.PaymentTypeHourPackage || amount.Cents < payment.RefundableAmount().Cents {
+		return nil
+	}
+	var purchase models.HourPackagePurchase
+	err := tx.Where("payment_id = ?", payment.ID).First(&purchase).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if purchase.Status != models.HourPackagePurchaseActive || purchase.RemainingMinutes < purchase.Minutes {
+		return models.ErrHourPackageUsed
+	}
+	return nil
+}
+
+// cancelHourPackage retire du porte-monnaie les heures restantes d'une formule
+// intégralement remboursée
+func cancelHourPackage(tx *gorm.DB, payment *models.Payment, actorID uint) error {
+	if payment.Type != models.PaymentTypeHourPackage || payment.Status != models.PaymentStatusRefunded {
+		return nil
+	}
+	var purchase models.HourPackagePurchase
+	err := tx.Where("payment_id = ? AND status IN ?", payment.ID,
+		[]models.HourPackagePurchaseStatus{models.HourPackagePurchasePending, models.HourPackagePurchaseActive}).First(&purchase).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	minutes, value := purchase.Close(models.HourPackagePurchaseCancelled)
+	if err := tx.Save(&purchase).Error; err != nil {
+		return err
+	}
+	if minutes == 0 {
+		return nil
+	}
+	return tx.Create(&models.WalletTransaction{
+		Type:        models.WalletTransactionCancellation,
+		Minutes:     -minutes,
+		Amount:      value.Neg(),
+		Description: "Remboursement du forfait " + purchase.Name,
+		FamilleID:   purchase.FamilleID,
+		PurchaseID:  purchase.ID,
+		ActorID:     actorID,
+	}).Error
+}
+
+// usableHourPurchases retourne les achats dont des heures restent disponibles, ceux
+// qui expirent en premier d'abord, verrouillés jusqu'à la fin de la transaction
+func usableHourPurchases(tx *gorm.DB, familleID uint, at time.Time) ([]models.HourPackagePurchase, error) {
+	var purchases []models.HourPackagePurchase
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("famille_id = ? AND status = ? AND remaining_minutes > 0 AND (expires_at IS NULL OR expires_at > ?)",
+			familleID, models.HourPackagePurchaseActive, at).
+		Order("expires_at IS NULL, expires_at, id").Find(&purchases).Error
+	return purchases, err
+}
+
+// consumeWalletHours retire jusqu'à minutes heures du porte-monnaie d'une famille et
+// enregistre un mouvement, sur le modèle de movement, par achat entamé. La famille
+// est prévenue lorsque son solde passe sous le seuil d'alerte.
+func consumeWalletHours(tx *gorm.DB, familleID uint, minutes int, at time.Time, movement models.WalletTransaction) ([]models.WalletTransaction, error) {
+	purchases, err := usableHourPurchases(tx, familleID, at)
+	if err != nil {
+		return nil, err
+	}
+	before := 0
+	for _, purchase := range purchases {
+		before += purchase.RemainingMinutes
+	}
+	var movements []models.WalletTransaction
+	consumed := 0
+	for i := range purchases {
+		if consumed == minutes {
+			break
+		}
+		purchase := &purchases[i]
+		part := min(minutes-consumed, purchase.RemainingMinutes)
+		value := purchase.Consume(part)
+		if err := tx.Save(purchase).Error; err != nil {
+			return nil, err
+		}
+		line := movement
+		line.FamilleID = familleID
+		line.PurchaseID = purchase.ID
+		line.Minutes = -part
+		line.Amount = value.Neg()
+		if err := tx.Create(&line).Error; err != nil {
+			return nil, err
+		}
+		movements = append(movements, line)
+		consumed += part
+	}
+	if err := notifyLowWalletBalance(tx, familleID, before, before-consumed); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// notifyLowWalletBalance prévient la famille lorsque son solde d'heures passe sous le seuil d'alerte
+func notifyLowWalletBalance(tx *gorm.DB, familleID uint, before, after int) error {
+	threshold := WalletLowBalanceMinutes()
+	if before < threshold || after >= threshold || before == after {
+		return nil
+	}
+	message := fmt.Sprintf("Il vous reste %s d'heures prépayées", formatMinutes(after))
+	if after == 0 {
+		message = "Vos heures prépayées sont épuisées : les prochains cours vous seront facturés"
+	}
+	return Notify(tx, familleID, models.NotificationWalletLowBalance, "Solde d'heures bas", message, walletLink(familleID))
+}
+
+// courseWalletMinutes retourne les minutes d'un cours couvertes par les heures
+// prépayées de la famille
+func courseWalletMinutes(tx *gorm.DB, courseID uint) (int, error) {
+	var minutes int64
+	if err := tx.Model(&models.WalletTransaction{}).Where("course_id = ? AND type = ?", courseID, models.WalletTransactionCourse).
+		Select("COALESCE(SUM(minutes), 0)").Scan(&minutes).Error; err != nil {
+		return 0, err
+	}
+	return int(-minutes), nil
+}
+
+// debitCourseHours débite du porte-monnaie de la famille la durée prévue d'un cours
+// confirmé, dans la limite des heures disponibles, et retourne les minutes couvertes.
+// Un cours n'est débité qu'une seule fois.
+func debitCourseHours(tx *gorm.DB, course *models.Course, at time.Time) (int, error) {
+	var debited struct {
+		Count   int64
+		Minutes int64
+	}
+	if err := tx.Model(&models.WalletTransaction{}).Where("course_id = ? AND type = ?", course.ID, models.WalletTransactionCourse).
+		Select("COUNT(*) AS count, COALESCE(SUM(minutes), 0) AS minutes").Scan(&debited).Error; err != nil {
+		return 0, err
+	}
+	if debited.Count > 0 {
+		return int(-debited.Minutes), nil
+	}
+	if course.Duration <= 0 {
+		return 0, nil
+	}
+	movements, err := consumeWalletHours(tx, course.FamilleID, course.Duration, at, models.WalletTransaction{
+		Type: models.WalletTransactionCourse,
+		Description: fmt.Sprintf("Cours du %s - %s", course.ScheduledTime.In(utils.CalendarLocation()).Format("02/01/2006"),
+			formatMinutes(course.Duration)),
+		CourseID: &course.ID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	covered := 0
+	for _, movement := range movements {
+		covered -= movement.Minutes
+	}
+	return covered, nil
+}
+
+// AdjustWallet crédite des heures offertes à une famille, ou lui en retire, sur
+// décision d'un administrateur
+func AdjustWallet(db *gorm.DB, familleID uint, req models.WalletAdjustmentRequest, adminID uint) ([]models.WalletTransaction, error) {
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, models.ErrWalletExpiryInPast
+	}
+	var movements []models.WalletTransaction
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if req.Minutes > 0 {
+			movement, err := grantWalletHours(tx, familleID, req.Minutes, req.Reason, req.ExpiresAt, adminID, now)
+			if err != nil {
+				return err
+			}
+			movements = []models.WalletTransaction{*movement}
+			return nil
+		}
+
+		available, err := walletMinutes(tx, familleID, now)
+		if err != nil {
+			return err
+		}
+		if available < -req.Minutes {
+			return models.ErrInsufficientWalletHours
+		}
+		movements, err = consumeWalletHours(tx, familleID, -req.Minutes, now, models.WalletTransaction{
+			Type:        models.WalletTransactionAdjustment,
+			Description: req.Reason,
+			ActorID:     adminID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// grantWalletHours crédite des heures offertes, sans valeur prépayée, au
+// porte-monnaie d'une famille
+func grantWalletHours(tx *gorm.DB, familleID uint, minutes int, reason string, expiresAt *time.Time, actorID uint, at time.Time) (*models.WalletTransaction, error) {
+	purchase := models.HourPackagePurchase{
+		Name:             reason,
+		Minutes:          minutes,
+		RemainingMinutes: minutes,
+		Price:            models.NewMoney(0, models.DefaultCurrency),
+		RemainingValue:   models.NewMoney(0, models.DefaultCurrency),
+		Status:           models.HourPackagePurchaseActive,
+		ActivatedAt:      &at,
+		ExpiresAt:        expiresAt,
+		FamilleID:        familleID,
+	}
+	if err := tx.Create(&purchase).Error; err != nil {
+		return nil, err
+	}
+	movement := models.WalletTransaction{
+		Type:        models.WalletTransactionAdjustment,
+		Minutes:     minutes,
+		Amount:      purchase.Price,
+		Description: reason,
+		FamilleID:   familleID,
+		PurchaseID:  purchase.ID,
+		ActorID:     actorID,
+	}
+	if err := tx.Create(&movement).Error; err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+// walletMinutes retourne le nombre de minutes disponibles dans le porte-monnaie d'une famille
+func walletMinutes(tx *gorm.DB, familleID uint, at time.Time) (int, error) {
+	var minutes int64
+	err := tx.Model(&models.HourPackagePurchase{}).
+		Where("famille_id = ? AND status = ? AND (expires_at IS NULL OR expires_at > ?)", familleID, models.HourPackagePurchaseActive, at).
+		Select("COALESCE(SUM(remaining_minutes), 0)").Scan(&minutes).Error
+	return int(minutes), err
+}
+
+// ExpireHourPackages prévient les familles dont des heures expirent bientôt, puis
+// retire les heures arrivées à expiration. Retourne le nombre d'achats expirés.
+func ExpireHourPackages(db *gorm.DB, now time.Time) (int, error) {
+	var expiring []models.HourPackagePurchase
+	if err := db.Where("status = ? AND remaining_minutes > 0 AND expires_at > ? AND expires_at <= ? AND expiry_notified_at IS NULL",
+		models.HourPackagePurchaseActive, now, now.Add(WalletExpiryNotice())).Find(&expiring).Error; err != nil {
+		return 0, err
+	}
+	for _, purchase := range expiring {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.HourPackagePurchase{}).Where("id = ?", purchase.ID).
+				UpdateColumn("expiry_notified_at", now).Error; err != nil {
+				return err
+			}
+			return Notify(tx, purchase.FamilleID, models.NotificationWalletHoursExpiring, "Heures prépayées bientôt expirées",
+				fmt.Sprintf("%s du forfait « %s » expirent le %s", formatMinutes(purchase.RemainingMinutes), purchase.Name,
+					purchase.ExpiresAt.In(utils.CalendarLocation()).Format("02/01/2006")), walletLink(purchase.FamilleID))
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var expired []models.HourPackagePurchase
+	if err := db.Where("status = ? AND expires_at <= ?", models.HourPackagePurchaseActive, now).
+		Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	for i := range expired {
+		purchase := &expired[i]
+		err := db.Transaction(func(tx *gorm.DB) error {
+			minutes, value := purchase.Close(models.HourPackagePurchaseExpired)
+			if err := tx.Save(purchase).Error; err != nil {
+				return err
+			}
+			if minutes == 0 {
+				return nil
+			}
+			if err := tx.Create(&models.WalletTransaction{
+				Type:        models.WalletTransactionExpiry,
+				Minutes:     -minutes,
+				Amount:      value.Neg(),
+				Description: "Expiration du forfait " + purchase.Name,
+				FamilleID:   purchase.FamilleID,
+				PurchaseID:  purchase.ID,
+			}).Error; err != nil {
+				return err
+			}
+			return Notify(tx, purchase.FamilleID, models.NotificationWalletHoursExpired, "Heures prépayées expirées",
+				fmt.Sprintf("%s non utilisées du forfait « %s » ont expiré", formatMinutes(minutes), purchase.Name), walletLink(purchase.FamilleID))
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(expired), nil
+}
+
+// WalletBalance calcule le solde du porte-monnaie d'une famille : heures disponibles
+// et leur valeur, prochaine expiration, et acomptes non encore imputés
+func WalletBalance(db *gorm.DB, familleID uint, now time.Time) (*models.WalletBalanceResponse, error) {
+	var purchases []models.HourPackagePurchase
+	if err := db.Preload("Payment").Where("famille_id = ? AND status IN ?", familleID,
+		[]models.HourPackagePurchaseStatus{models.HourPackagePurchasePending, models.HourPackagePurchaseActive}).
+		Order("expires_at IS NULL, expires_at, id").Find(&purchases).Error; err != nil {
+		return nil, err
+	}
+	balance := &models.WalletBalanceResponse{
+		FamilleID:     familleID,
+		Value:         models.NewMoney(0, models.DefaultCurrency),
+		AdvanceCredit: models.NewMoney(0, models.DefaultCurrency),
+		Purchases:     []models.HourPackagePurchase{},
+	}
+	for _, purchase := range purchases {
+		if purchase.Status == models.HourPackagePurchaseActive && !purchase.IsUsable(now) {
+			continue
+		}
+		balance.Purchases = append(balance.Purchases, purchase)
+		if purchase.Status != models.HourPackagePurchaseActive {
+			continue
+		}
+		balance.Minutes += purchase.RemainingMinutes
+		balance.Value = balance.Value.Add(purchase.RemainingValue)
+		if balance.NextExpiry == nil && purchase.ExpiresAt != nil {
+			balance.NextExpiry = purchase.ExpiresAt
+		}
+	}
+	var credited int64
+	if err := db.Model(&models.HourPackagePurchase{}).Where("famille_id = ? AND status <> ?", familleID, models.HourPackagePurchasePending).
+		Count(&credited).Error; err != nil {
+		return nil, err
+	}
+	balance.LowBalance = credited > 0 && balance.Minutes < WalletLowBalanceMinutes()
+
+	var missionIDs []uint
+	if err := db.Model(&models.Mission{}).Where("famille_id = ?", familleID).Pluck("id", &missionIDs).Error; err != nil {
+		return nil, err
+	}
+	missions := map[uint]bool{}
+	for _, id := range missionIDs {
+		missions[id] = true
+	}
+	advances, err := openAdvances(db, familleID, models.DefaultCurrency, now, missions)
+	if err != nil {
+		return nil, err
+	}
+	for _, advance := range advances {
+		balance.AdvanceCredit = balance.AdvanceCredit.Add(advance.remaining)
+	}
+	return balance, nil
+}
+
+// WalletStatement construit le relevé du porte-monnaie d'une famille sur la période :
+// solde d'ouverture, mouvements avec le solde progressif, et solde de clôture
+func WalletStatement(db *gorm.DB, familleID uint, start, end time.Time) (*models.WalletStatementResponse, error) {
+	var opening struct {
+		Minutes int64
+		Cents   int64
+	}
+	if err := db.Model(&models.WalletTransaction{}).Where("famille_id = ? AND created_at < ?", familleID, start).
+		Select("COALESCE(SUM(minutes), 0) AS minutes, COALESCE(SUM(amount_cents), 0) AS cents").
+		Scan(&opening).Error; err != nil {
+		return nil, err
+	}
+	var movements []models.WalletTransaction
+	if err := db.Where("famille_id = ? AND created_at >= ? AND created_at < ?", familleID, start, end).
+		Order("created_at, id").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	statement := &models.WalletStatementResponse{
+		FamilleID:      familleID,
+		From:           start,
+		To:             end,
+		OpeningMinutes: int(opening.Minutes),
+		OpeningValue:   models.NewMoney(opening.Cents, models.DefaultCurrency),
+		Lines:          []models.WalletStatementLine{},
+	}
+	minutes, value := statement.OpeningMinutes, statement.OpeningValue
+	for _, movement := range movements {
+		minutes += movement.Minutes
+		value = value.Add(movement.Amount)
+		statement.Lines = append(statement.Lines, models.WalletStatementLine{
+			WalletTransaction: movement,
+			BalanceMinutes:    minutes,
+			BalanceValue:      value,
+		})
+	}
+	statement.ClosingMinutes, statement.ClosingValue = minutes, value
+	return statement, nil
+}
+
+func walletLink(familleID uint) string {
+	return fmt.Sprintf("/api/v1/familles/%d/wallet", familleID)
+}
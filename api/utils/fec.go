@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fecColumns sont les 18 colonnes du fichier des écritures comptables, dans
+// l'ordre fixé par l'article A. 47 A-1 du livre des procédures fiscales
+var fecColumns = []string{
+	"JournalCode", "JournalLib", "EcritureNum", "EcritureDate", "CompteNum", "CompteLib",
+	"CompAuxNum", "CompAuxLib", "PieceRef", "PieceDate", "EcritureLib", "Debit", "Credit",
+	"EcritureLet", "DateLet", "ValidDate", "Montantdevise", "Idevise",
+}
+
+// FECLine est une ligne d'écriture du fichier des écritures comptables
+type FECLine struct {
+	JournalCode  string
+	JournalLib   string
+	EcritureNum  string
+	EcritureDate time.Time
+	CompteNum    string
+	CompteLib    string
+	CompAuxNum   string
+	CompAuxLib   string
+	PieceRef     string
+	PieceDate    time.Time
+	EcritureLib  string
+	DebitCents   int64
+	CreditCents  int64
+	ValidDate    time.Time
+	Currency     string // Montant en devise renseigné hors euro uniquement
+}
+
+// FECFile produit le fichier des écritures comptables au format texte : une ligne
+// d'en-tête puis une ligne par ligne d'écriture, champs séparés par une barre
+// verticale, dates au format AAAAMMJJ et montants avec une virgule décimale
+func FECFile(lines []FECLine) []byte {
+	var b strings.Builder
+	b.WriteString(strings.Join(fecColumns, "|"))
+	b.WriteString("\r\n")
+	for _, line := range lines {
+		var amount, currency string
+		if line.Currency != "" && line.Currency != "EUR" {
+			amount = FECAmount(line.DebitCents - line.CreditCents)
+			currency = line.Currency
+		}
+		fields := []string{
+			line.JournalCode, line.JournalLib, line.EcritureNum, FECDate(line.EcritureDate),
+			line.CompteNum, line.CompteLib, line.CompAuxNum, line.CompAuxLib,
+			line.PieceRef, FECDate(line.PieceDate), line.EcritureLib,
+			FECAmount(line.DebitCents), FECAmount(line.CreditCents),
+			"", "", FECDate(line.ValidDate), amount, currency,
+		}
+		for i, field := range fields {
+			fields[i] = fecText(field)
+		}
+		b.WriteString(strings.Join(fields, "|"))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// FECFileName retourne le nom réglementaire du fichier : SIREN, « FEC » et date
+// de clôture de l'exercice
+func FECFileName(siren string, closing time.Time) string {
+	return fmt.Sprintf("%sFEC%s.txt", siren, FECDate(closing))
+}
+
+// FECDate formate une date au format AAAAMMJJ, vide pour une date nulle
+func FECDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(CalendarLocation()).Format("20060102")
+}
+
+// FECAmount formate un montant en centimes avec une virgule décimale : 1234,50
+func FECAmount(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d,%02d", sign, cents/100, cents%100)
+}
+
+// fecText retire les séparateurs et sauts de ligne d'un champ
+func fecText(value string) string {
+	return strings.TrimSpace(strings.NewReplacer("|", " ", "\r", " ", "\n", " ", "\t", " ").Replace(value))
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "math"
+
+const earthRadiusMeters = 6371000
+
+// DistanceMeters retourne la distance orthodromique (formule de haversine) en mètres
+// entre deux points exprimés en degrés décimaux
+func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
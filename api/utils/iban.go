@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidIBAN = errors.New("IBAN invalide")
+	ErrInvalidBIC  = errors.New("BIC invalide : 8 ou 11 caractères attendus (ex. BNPAFRPPXXX)")
+
+	ErrInvalidCreditorID = errors.New("identifiant créancier SEPA invalide")
+)
+
+// ibanLengths donne la longueur de l'IBAN des pays de la zone SEPA (registre ISO 13616)
+var ibanLengths = map[string]int{
+	"AD": 24, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "EE": 20, "ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GI": 23,
+	"GL": 18, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IS": 26, "IT": 27, "LI": 21,
+	"LT": 20, "LU": 20, "LV": 21, "MC": 27, "MT": 31, "NL": 18, "NO": 15, "PL": 28,
+	"PT": 25, "RO": 24, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "VA": 22,
+}
+
+var (
+	ibanPattern       = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+	bicPattern        = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	creditorIDPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{3}[A-Z0-9]{1,28}$`)
+)
+
+// NormalizeIBAN retire les espaces et met l'IBAN en majuscules
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// ValidateIBAN normalise un IBAN et vérifie son pays, sa longueur et sa clé de
+// contrôle (modulo 97, ISO 13616)
+func ValidateIBAN(iban string) (string, error) {
+	iban = NormalizeIBAN(iban)
+	if !ibanPattern.MatchString(iban) {
+		return "", ErrInvalidIBAN
+	}
+	length, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return "", errors.New("IBAN invalide : pays hors zone SEPA")
+	}
+	if len(iban) != length {
+		return "", errors.New("IBAN invalide : longueur incorrecte pour ce pays")
+	}
+	if ibanChecksum(iban) != 1 {
+		return "", errors.New("IBAN invalide : clé de contrôle incorrecte")
+	}
+	return iban, nil
+}
+
+// ibanChecksum calcule le reste modulo 97 de l'IBAN réarrangé (les quatre premiers
+// caractères en fin de chaîne, les lettres converties en nombres de 10 à 35)
+func ibanChecksum(iban string) int {
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+// ValidateBIC normalise et vérifie le format d'un BIC (ISO 9362)
+func ValidateBIC(bic string) (string, error) {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if !bicPattern.MatchString(bic) {
+		return "", ErrInvalidBIC
+	}
+	return bic, nil
+}
+
+// ValidateCreditorID vérifie un identifiant créancier SEPA (ICS en France) : pays,
+// clé, code activité puis identifiant national. La clé porte sur l'identifiant
+// national suivi du pays, comme pour un IBAN, le code activité étant ignoré.
+func ValidateCreditorID(id string) (string, error) {
+	id = NormalizeIBAN(id)
+	if !creditorIDPattern.MatchString(id) {
+		return "", ErrInvalidCreditorID
+	}
+	if ibanChecksum(id[:4]+id[7:]) != 1 {
+		return "", ErrInvalidCreditorID
+	}
+	return id, nil
+}
+
+// FormatIBAN présente un IBAN par groupes de quatre caractères
+func FormatIBAN(iban string) string {
+	var groups []string
+	for i := 0; i < len(iban); i += 4 {
+		end := i + 4
+		if end > len(iban) {
+			end = len(iban)
+		}
+		groups = append(groups, iban[i:end])
+	}
+	return strings.Join(groups, " ")
+}
+
+// MaskIBAN masque un IBAN en ne laissant apparaître que le pays et les quatre derniers caractères
+func MaskIBAN(iban string) string {
+	if len(iban) < 8 {
+		return iban
+	}
+	return iban[:4] + strings.Repeat("*", len(iban)-8) + iban[len(iban)-4:]
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"France groupé par quatre", "FR14 2004 1010 0505 0001 3M02 606", "FR1420041010050500013M02606"},
+		{"France en minuscules", "fr1420041010050500013m02606", "FR1420041010050500013M02606"},
+		{"Allemagne", "DE89370400440532013000", "DE89370400440532013000"},
+		{"Royaume-Uni", "GB82WEST12345698765432", "GB82WEST12345698765432"},
+		{"Belgique", " BE68 5390 0754 7034 ", "BE68539007547034"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateIBAN(tt.input)
+			if err != nil {
+				t.Fatalf("ValidateIBAN(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateIBAN(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateIBANRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"vide", ""},
+		{"caractères invalides", "FR14-2004-1010-0505-0001-3M02-606"},
+		{"pays hors zone SEPA", "US64SVBKUS6S3300958879"},
+		{"longueur incorrecte", "FR142004101005050001"},
+		{"clé de contrôle incorrecte", "FR1520041010050500013M02606"},
+		{"chiffre modifié", "DE89370400440532013001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ValidateIBAN(tt.input); err == nil {
+				t.Errorf("ValidateIBAN(%q) = %q, want an error", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	for _, bic := range []string{"BNPAFRPP", "bnpafrppxxx", " DEUTDEFF500 "} {
+		if _, err := ValidateBIC(bic); err != nil {
+			t.Errorf("ValidateBIC(%q) returned error: %v", bic, err)
+		}
+	}
+	for _, bic := range []string{"", "BNPAFRP", "BNPAFRPPXX", "1NPAFRPP"} {
+		if _, err := ValidateBIC(bic); !errors.Is(err, ErrInvalidBIC) {
+			t.Errorf("ValidateBIC(%q) error = %v, want ErrInvalidBIC", bic, err)
+		}
+	}
+}
+
+func TestValidateCreditorID(t *testing.T) {
+	for _, id := range []string{"DE98ZZZ09999999999", "FR72 ZZZ 123456"} {
+		if _, err := ValidateCreditorID(id); err != nil {
+			t.Errorf("ValidateCreditorID(%q) returned error: %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "DE99ZZZ09999999999", "FR72ZZZ123457", "FR72"} {
+		if _, err := ValidateCreditorID(id); !errors.Is(err, ErrInvalidCreditorID) {
+			t.Errorf("ValidateCreditorID(%q) error = %v, want ErrInvalidCreditorID", id, err)
+		}
+	}
+}
+
+func TestFormatAndMaskIBAN(t *testing.T) {
+	if got := MaskIBAN("FR1420041010050500013M02606"); got != "FR14*******************2606" {
+		t.Errorf("MaskIBAN = %q", got)
+	}
+	if got := FormatIBAN("FR1420041010050500013M02606"); got != "FR14 2004 1010 0505 0001 3M02 606" {
+		t.Errorf("FormatIBAN = %q", got)
+	}
+}
@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ICalEvent représente un VEVENT d'un calendrier iCalendar (RFC 5545)
+type ICalEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Latitude     float64
+	Longitude    float64
+	HasGeo       bool
+	Start        time.Time
+	End          time.Time
+	Status       string // CONFIRMED, TENTATIVE ou CANCELLED
+	Sequence     int
+	LastModified time.Time
+}
+
+// ICalCalendar représente un VCALENDAR complet
+type ICalCalendar struct {
+	Name     string
+	Method   string
+	Location *time.Location
+	Events   []ICalEvent
+}
+
+// CalendarLocation retourne le fuseau horaire utilisé pour les calendriers
+// (variable APP_TIMEZONE, Europe/Paris par défaut)
+func CalendarLocation() *time.Location {
+	name := os.Getenv("APP_TIMEZONE")
+	if name == "" {
+		name = "Europe/Paris"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Render sérialise le calendrier au format text/calendar
+func (cal *ICalCalendar) Render() string {
+	loc := cal.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//Help Us//Plateforme Educative//FR")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	if cal.Method != "" {
+		writeICalLine(&b, "METHOD:"+cal.Method)
+	}
+	if cal.Name != "" {
+		writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(cal.Name))
+	}
+	if loc != time.UTC {
+		writeICalLine(&b, "X-WR-TIMEZONE:"+loc.String())
+		writeVTimezone(&b, loc, cal.Events)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, ev := range cal.Events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+ev.UID)
+		writeICalLine(&b, "DTSTAMP:"+stamp)
+		writeICalLine(&b, formatICalTime("DTSTART", ev.Start, loc))
+		writeICalLine(&b, formatICalTime("DTEND", ev.End, loc))
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(ev.Summary))
+		if ev.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(ev.Description))
+		}
+		if ev.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(ev.Location))
+		}
+		if ev.HasGeo {
+			writeICalLine(&b, fmt.Sprintf("GEO:%.6f;%.6f", ev.Latitude, ev.Longitude))
+		}
+		if ev.Status != "" {
+			writeICalLine(&b, "STATUS:"+ev.Status)
+		}
+		writeICalLine(&b, fmt.Sprintf("SEQUENCE:%d", ev.Sequence))
+		if !ev.LastModified.IsZero() {
+			writeICalLine(&b, "LAST-MODIFIED:"+ev.LastModified.UTC().Format("20060102T150405Z"))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func formatICalTime(prop string, t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return prop + ":" + t.UTC().Format("20060102T150405Z")
+	}
+	return prop + ";TZID=" + loc.String() + ":" + t.In(loc).Format("20060102T150405")
+}
+
+// escapeICalText échappe les caractères spéciaux d'une valeur TEXT (RFC 5545 §3.3.11)
+func escapeICalText(s string) string {
+	r := strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+		"\r", "",
+	)
+	return r.Replace(s)
+}
+
+// writeICalLine écrit une ligne terminée par CRLF en la repliant à 75 octets
+// sans couper un caractère UTF-8 (RFC 5545 §3.1)
+func writeICalLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Les lignes de continuation commencent par un espace
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// writeVTimezone décrit le fuseau horaire à partir de la base tz de Go.
+// Les règles de changement d'heure sont déduites des transitions de l'année
+// du premier évènement et exprimées sous forme de RRULE.
+func writeVTimezone(b *strings.Builder, loc *time.Location, events []ICalEvent) {
+	year := time.Now().In(loc).Year()
+	if len(events) > 0 {
+		year = events[0].Start.In(loc).Year()
+	}
+
+	writeICalLine(b, "BEGIN:VTIMEZONE")
+	writeICalLine(b, "TZID:"+loc.String())
+
+	transitions := findTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, 1, 1, 0, 0, 0, 0, loc).Zone()
+		writeICalLine(b, "BEGIN:STANDARD")
+		writeICalLine(b, "DTSTART:19700101T000000")
+		writeICalLine(b, "TZOFFSETFROM:"+formatICalOffset(offset))
+		writeICalLine(b, "TZOFFSETTO:"+formatICalOffset(offset))
+		writeICalLine(b, "TZNAME:"+name)
+		writeICalLine(b, "END:STANDARD")
+	}
+	for _, tr := range transitions {
+		component := "STANDARD"
+		if tr.isDST {
+			component = "DAYLIGHT"
+		}
+		// Heure locale du changement exprimée avec l'ancien décalage
+		local := tr.at.Add(time.Duration(tr.offsetFrom) * time.Second).UTC()
+		ordinal := weekOrdinal(local)
+		day := nthWeekdayOfMonth(1970, local.Month(), local.Weekday(), ordinal)
+		dtstart := time.Date(1970, local.Month(), day, local.Hour(), local.Minute(), 0, 0, time.UTC)
+		writeICalLine(b, "BEGIN:"+component)
+		writeICalLine(b, "DTSTART:"+dtstart.Format("20060102T150405"))
+		writeICalLine(b, fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(local.Month()), ordinal, icalWeekday(local.Weekday())))
+		writeICalLine(b, "TZOFFSETFROM:"+formatICalOffset(tr.offsetFrom))
+		writeICalLine(b, "TZOFFSETTO:"+formatICalOffset(tr.offsetTo))
+		writeICalLine(b, "TZNAME:"+tr.name)
+		writeICalLine(b, "END:"+component)
+	}
+
+	writeICalLine(b, "END:VTIMEZONE")
+}
+
+type tzTransition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	isDST      bool
+}
+
+// findTransitions recherche les changements de décalage UTC d'une année
+func findTransitions(loc *time.Location, year int) []tzTransition {
+	var result []tzTransition
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, loc)
+	_, janOffset := start.Zone()
+	_, julOffset := time.Date(year, 7, 1, 0, 0, 0, 0, loc).Zone()
+
+	prev := start
+	_, prevOffset := prev.Zone()
+	for t := start.Add(time.Hour); !t.After(end); t = t.Add(time.Hour) {
+		name, offset := t.Zone()
+		if offset != prevOffset {
+			// Affiner à la minute près
+			lo, hi := prev, t
+			for hi.Sub(lo) > time.Minute {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.Zone(); o == prevOffset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			isDST := offset > min(janOffset, julOffset)
+			result = append(result, tzTransition{at: hi.UTC(), offsetFrom: prevOffset, offsetTo: offset, name: name, isDST: isDST})
+			prevOffset = offset
+		}
+		prev = t
+	}
+	return result
+}
+
+// weekOrdinal retourne le rang du jour dans le mois (1 à 4, ou -1 pour le dernier)
+func weekOrdinal(t time.Time) int {
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		return -1
+	}
+	return (t.Day()-1)/7 + 1
+}
+
+// nthWeekdayOfMonth retourne le jour du mois correspondant au n-ième jour de semaine donné
+func nthWeekdayOfMonth(year int, month time.Month, weekday time.Weekday, ordinal int) int {
+	if ordinal == -1 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.Day() - (int(last.Weekday())-int(weekday)+7)%7
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return 1 + (int(weekday)-int(first.Weekday())+7)%7 + (ordinal-1)*7
+}
+
+func icalWeekday(d time.Weekday) string {
+	return [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[d]
+}
+
+func formatICalOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, (seconds%3600)/60)
+}
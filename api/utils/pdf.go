@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Dimensions d'une page A4 en points PDF
+const (
+	PDFPageWidth  = 595.28
+	PDFPageHeight = 841.89
+)
+
+// PDFDocument est un générateur PDF minimal, sans dépendance externe, limité au
+// texte en Helvetica (encodage WinAnsi, accents français compris) et aux traits.
+// Les coordonnées sont exprimées en points depuis le coin supérieur gauche.
+type PDFDocument struct {
+	Title string
+	pages []*bytes.Buffer
+}
+
+// AddPage ajoute une nouvelle page A4 qui devient la page courante
+func (d *PDFDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount retourne le nombre de pages du document
+func (d *PDFDocument) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *PDFDocument) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text écrit une ligne de texte ; bold sélectionne Helvetica-Bold
+func (d *PDFDocument) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font, size, x, PDFPageHeight-y, pdfEscape(text))
+}
+
+// TextRight écrit une ligne de texte alignée à droite sur l'abscisse x
+func (d *PDFDocument) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-PDFTextWidth(text, size), y, size, bold, text)
+}
+
+// Line trace un trait d'un point à un autre
+func (d *PDFDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, PDFPageHeight-y1, x2, PDFPageHeight-y2)
+}
+
+// Bytes sérialise le document au format PDF 1.4
+func (d *PDFDocument) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	var out bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1: catalogue, 2: arbre des pages, 3-4: polices, 5: informations, puis page/contenu par page
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	writeObject(fmt.Sprintf("<< /Title (%s) /Producer (Help Us) >>", pdfEscape(d.Title)))
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PDFPageWidth, PDFPageHeight, 7+2*i))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// PDFTextWidth estime la largeur d'un texte en Helvetica (largeur moyenne des glyphes)
+func PDFTextWidth(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '.' || r == ',' || r == ':' || r == 'i' || r == 'l' || r == 'j' || r == '\'':
+			width += 0.278
+		case r >= '0' && r <= '9':
+			width += 0.556
+		case r >= 'A' && r <= 'Z':
+			width += 0.667
+		default:
+			width += 0.52
+		}
+	}
+	return width * size
+}
+
+// winAnsi associe les caractères hors Latin-1 usuels à leur code WinAnsi
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, 'œ': 0x9C, 'Œ': 0x8C,
+	'\u202f': ' ', // espace fine insécable
+}
+
+// pdfEscape convertit une chaîne UTF-8 en chaîne littérale PDF encodée en WinAnsi
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&b, "\\%03o", winAnsi[r])
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SEPACreditTransfer décrit un lot de virements SEPA émis depuis un même compte
+type SEPACreditTransfer struct {
+	MessageID     string // Unique, 35 caractères au plus
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	DebtorName    string
+	DebtorIBAN    string
+	DebtorBIC     string // Facultatif
+	Transactions  []SEPATransaction
+}
+
+// SEPATransaction est un virement du lot
+type SEPATransaction struct {
+	EndToEndID   string // Référence de bout en bout, 35 caractères au plus
+	AmountCents  int64
+	CreditorName string
+	CreditorIBAN string
+	CreditorBIC  string // Facultatif
+	Remittance   string // Libellé transmis au bénéficiaire
+}
+
+// Structure XML du message pain.001.001.03 (ISO 20022), dans l'ordre imposé par le schéma
+type pain001Document struct {
+	XMLName xml.Name          `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Initn   pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	GroupHeader pain001GroupHeader `xml:"GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID        string    `xml:"MsgId"`
+	CreationDateTime string    `xml:"CreDtTm"`
+	NumberOfTxs      int       `xml:"NbOfTxs"`
+	ControlSum       string    `xml:"CtrlSum"`
+	InitiatingParty  sepaParty `xml:"InitgPty"`
+}
+
+type sepaParty struct {
+	Name string `xml:"Nm"`
+}
+
+type sepaAccount struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type sepaAgent struct {
+	BIC   string     `xml:"FinInstnId>BIC,omitempty"`
+	Other *sepaOther `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type sepaOther struct {
+	ID string `xml:"Id"`
+}
+
+type pain001PaymentInfo struct {
+	PaymentInfoID   string               `xml:"PmtInfId"`
+	PaymentMethod   string               `xml:"PmtMtd"`
+	BatchBooking    bool                 `xml:"BtchBookg"`
+	NumberOfTxs     int                  `xml:"NbOfTxs"`
+	ControlSum      string               `xml:"CtrlSum"`
+	ServiceLevel    string               `xml:"PmtTpInf>SvcLvl>Cd"`
+	ExecutionDate   string               `xml:"ReqdExctnDt"`
+	Debtor          sepaParty            `xml:"Dbtr"`
+	DebtorAccount   sepaAccount          `xml:"DbtrAcct"`
+	DebtorAgent     sepaAgent            `xml:"DbtrAgt"`
+	ChargeBearer    string               `xml:"ChrgBr"`
+	CreditTransfers []pain001Transaction `xml:"CdtTrfTxInf"`
+}
+
+type sepaAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type pain001Transaction struct {
+	EndToEndID      string      `xml:"PmtId>EndToEndId"`
+	Amount          sepaAmount  `xml:"Amt>InstdAmt"`
+	CreditorAgent   *sepaAgent  `xml:"CdtrAgt,omitempty"`
+	Creditor        sepaParty   `xml:"Cdtr"`
+	CreditorAccount sepaAccount `xml:"CdtrAcct"`
+	Remittance      string      `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+// Pain001 produit le fichier XML pain.001.001.03 du lot, en euros. Les textes sont
+// ramenés au jeu de caractères SEPA et tronqués aux longueurs du schéma.
+func (t *SEPACreditTransfer) Pain001() ([]byte, error) {
+	if len(t.Transactions) == 0 {
+		return nil, errors.New("le lot de virements est vide")
+	}
+	var total int64
+	transactions := make([]pain001Transaction, len(t.Transactions))
+	for i, tx := range t.Transactions {
+		if tx.AmountCents <= 0 {
+			return nil, fmt.Errorf("montant invalide pour le virement %s", tx.EndToEndID)
+		}
+		total += tx.AmountCents
+		transactions[i] = pain001Transaction{
+			EndToEndID:      SEPAText(tx.EndToEndID, 35),
+			Amount:          sepaAmount{Currency: "EUR", Value: SEPAAmount(tx.AmountCents)},
+			Creditor:        sepaParty{Name: SEPAText(tx.CreditorName, 70)},
+			CreditorAccount: sepaAccount{IBAN: tx.CreditorIBAN},
+			Remittance:      SEPAText(tx.Remittance, 140),
+		}
+		if tx.CreditorBIC != "" {
+			transactions[i].CreditorAgent = &sepaAgent{BIC: tx.CreditorBIC}
+		}
+	}
+
+	messageID := SEPAText(t.MessageID, 35)
+	doc := pain001Document{Initn: pain001Initiation{
+		GroupHeader: pain001GroupHeader{
+			MessageID:        messageID,
+			CreationDateTime: t.CreatedAt.Format("2006-01-02T15:04:05"),
+			NumberOfTxs:      len(transactions),
+			ControlSum:       SEPAAmount(total),
+			InitiatingParty:  sepaParty{Name: SEPAText(t.DebtorName, 70)},
+		},
+		PaymentInfo: pain001PaymentInfo{
+			PaymentInfoID:   messageID,
+			PaymentMethod:   "TRF",
+			BatchBooking:    true,
+			NumberOfTxs:     len(transactions),
+			ControlSum:      SEPAAmount(total),
+			ServiceLevel:    "SEPA",
+			ExecutionDate:   t.ExecutionDate.Format("2006-01-02"),
+			Debtor:          sepaParty{Name: SEPAText(t.DebtorName, 70)},
+			DebtorAccount:   sepaAccount{IBAN: t.DebtorIBAN},
+			DebtorAgent:     sepaAgentFor(t.DebtorBIC),
+			ChargeBearer:    "SLEV",
+			CreditTransfers: transactions,
+		},
+	}}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// SEPAAmount formate un montant en centimes au format décimal du schéma (1234.50)
+func SEPAAmount(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// sepaTransliteration ramène les lettres accentuées courantes à leur équivalent latin de base
+var sepaTransliteration = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "á", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "í", "i", "ì", "i",
+	"ô", "o", "ö", "o", "ó", "o", "ò", "o", "õ", "o", "ø", "o",
+	"ù", "u", "û", "u", "ü", "u", "ú", "u",
+	"ç", "c", "ñ", "n", "ÿ", "y", "œ", "oe", "æ", "ae", "ß", "ss",
+	"À", "A", "Â", "A", "Ä", "A", "Á", "A", "Ã", "A", "Å", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Î", "I", "Ï", "I", "Í", "I", "Ì", "I",
+	"Ô", "O", "Ö", "O", "Ó", "O", "Ò", "O", "Õ", "O", "Ø", "O",
+	"Ù", "U", "Û", "U", "Ü", "U", "Ú", "U",
+	"Ç", "C", "Ñ", "N", "Œ", "OE", "Æ", "AE",
+	"’", "'", "°", " ",
+)
+
+// SEPAText convertit un texte au jeu de caractères latin restreint accepté par
+// toutes les banques SEPA et le tronque à max caractères
+func SEPAText(text string, max int) string {
+	text = sepaTransliteration.Replace(text)
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			strings.ContainsRune("/-?:().,'+ ", r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	result := strings.Join(strings.Fields(b.String()), " ")
+	if len(result) > max {
+		result = strings.TrimSpace(result[:max])
+	}
+	return result
+}
@@ -0,0 +1,321 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCamt054 = errors.New("fichier camt.054 illisible")
+
+// Types de séquence d'un prélèvement SEPA : premier prélèvement d'un mandat ou prélèvement récurrent
+const (
+	SEPASequenceFirst     = "FRST"
+	SEPASequenceRecurring = "RCUR"
+)
+
+// SEPADirectDebit décrit un lot de prélèvements SEPA (schéma CORE) encaissés sur un même compte
+type SEPADirectDebit struct {
+	MessageID      string // Unique, 35 caractères au plus
+	CreatedAt      time.Time
+	CollectionDate time.Time
+	CreditorName   string
+	CreditorIBAN   string
+	CreditorBIC    string // Facultatif
+	CreditorID     string // Identifiant créancier SEPA (ICS)
+	Transactions   []SEPADebitTransaction
+}
+
+// SEPADebitTransaction est un prélèvement du lot
+type SEPADebitTransaction struct {
+	EndToEndID      string
+	AmountCents     int64
+	SequenceType    string // SEPASequenceFirst ou SEPASequenceRecurring
+	MandateID       string // Référence unique du mandat (RUM)
+	MandateSignedAt time.Time
+	DebtorName      string
+	DebtorIBAN      string
+	DebtorBIC       string // Facultatif
+	Remittance      string
+}
+
+// Structure XML du message pain.008.001.02 (ISO 20022), dans l'ordre imposé par le schéma
+type pain008Document struct {
+	XMLName xml.Name          `xml:"urn:iso:std:iso:20022:tech:xsd:pain.008.001.02 Document"`
+	Initn   pain008Initiation `xml:"CstmrDrctDbtInitn"`
+}
+
+type pain008Initiation struct {
+	GroupHeader pain001GroupHeader   `xml:"GrpHdr"`
+	PaymentInfo []pain008PaymentInfo `xml:"PmtInf"`
+}
+
+type pain008PaymentInfo struct {
+	PaymentInfoID      string               `xml:"PmtInfId"`
+	PaymentMethod      string               `xml:"PmtMtd"`
+	BatchBooking       bool                 `xml:"BtchBookg"`
+	NumberOfTxs        int                  `xml:"NbOfTxs"`
+	ControlSum         string               `xml:"CtrlSum"`
+	ServiceLevel       string               `xml:"PmtTpInf>SvcLvl>Cd"`
+	LocalInstrument    string               `xml:"PmtTpInf>LclInstrm>Cd"`
+	SequenceType       string               `xml:"PmtTpInf>SeqTp"`
+	CollectionDate     string               `xml:"ReqdColltnDt"`
+	Creditor           sepaParty            `xml:"Cdtr"`
+	CreditorAccount    sepaAccount          `xml:"CdtrAcct"`
+	CreditorAgent      sepaAgent            `xml:"CdtrAgt"`
+	ChargeBearer       string               `xml:"ChrgBr"`
+	CreditorSchemeID   string               `xml:"CdtrSchmeId>Id>PrvtId>Othr>Id"`
+	CreditorSchemeName string               `xml:"CdtrSchmeId>Id>PrvtId>Othr>SchmeNm>Prtry"`
+	DirectDebits       []pain008Transaction `xml:"DrctDbtTxInf"`
+}
+
+type pain008Transaction struct {
+	EndToEndID      string      `xml:"PmtId>EndToEndId"`
+	Amount          sepaAmount  `xml:"InstdAmt"`
+	MandateID       string      `xml:"DrctDbtTx>MndtRltdInf>MndtId"`
+	MandateSignedAt string      `xml:"DrctDbtTx>MndtRltdInf>DtOfSgntr"`
+	DebtorAgent     sepaAgent   `xml:"DbtrAgt"`
+	Debtor          sepaParty   `xml:"Dbtr"`
+	DebtorAccount   sepaAccount `xml:"DbtrAcct"`
+	Remittance      string      `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+// Pain008 produit le fichier XML pain.008.001.02 du lot, en euros, avec un bloc de
+// paiement par type de séquence (premiers prélèvements puis récurrents)
+func (d *SEPADirectDebit) Pain008() ([]byte, error) {
+	if len(d.Transactions) == 0 {
+		return nil, errors.New("le lot de prélèvements est vide")
+	}
+	messageID := SEPAText(d.MessageID, 35)
+	var total int64
+	blocks := map[string]*pain008PaymentInfo{}
+	sums := map[string]int64{}
+	for _, tx := range d.Transactions {
+		if tx.AmountCents <= 0 {
+			return nil, fmt.Errorf("montant invalide pour le prélèvement %s", tx.EndToEndID)
+		}
+		if tx.SequenceType != SEPASequenceFirst && tx.SequenceType != SEPASequenceRecurring {
+			return nil, fmt.Errorf("type de séquence invalide pour le prélèvement %s", tx.EndToEndID)
+		}
+		block, ok := blocks[tx.SequenceType]
+		if !ok {
+			block = &pain008PaymentInfo{
+				PaymentInfoID:      SEPAText(messageID+"-"+tx.SequenceType, 35),
+				PaymentMethod:      "DD",
+				BatchBooking:       true,
+				ServiceLevel:       "SEPA",
+				LocalInstrument:    "CORE",
+				SequenceType:       tx.SequenceType,
+				CollectionDate:     d.CollectionDate.Format("2006-01-02"),
+				Creditor:           sepaParty{Name: SEPAText(d.CreditorName, 70)},
+				CreditorAccount:    sepaAccount{IBAN: d.CreditorIBAN},
+				CreditorAgent:      sepaAgentFor(d.CreditorBIC),
+				ChargeBearer:       "SLEV",
+				CreditorSchemeID:   d.CreditorID,
+				CreditorSchemeName: "SEPA",
+			}
+			blocks[tx.SequenceType] = block
+		}
+		block.DirectDebits = append(block.DirectDebits, pain008Transaction{
+			EndToEndID:      SEPAText(tx.EndToEndID, 35),
+			Amount:          sepaAmount{Currency: "EUR", Value: SEPAAmount(tx.AmountCents)},
+			MandateID:       SEPAText(tx.MandateID, 35),
+			MandateSignedAt: tx.MandateSignedAt.Format("2006-01-02"),
+			DebtorAgent:     sepaAgentFor(tx.DebtorBIC),
+			Debtor:          sepaParty{Name: SEPAText(tx.DebtorName, 70)},
+			DebtorAccount:   sepaAccount{IBAN: tx.DebtorIBAN},
+			Remittance:      SEPAText(tx.Remittance, 140),
+		})
+		block.NumberOfTxs++
+		sums[tx.SequenceType] += tx.AmountCents
+		total += tx.AmountCents
+	}
+
+	doc := pain008Document{Initn: pain008Initiation{
+		GroupHeader: pain001GroupHeader{
+			MessageID:        messageID,
+			CreationDateTime: d.CreatedAt.Format("2006-01-02T15:04:05"),
+			NumberOfTxs:      len(d.Transactions),
+			ControlSum:       SEPAAmount(total),
+			InitiatingParty:  sepaParty{Name: SEPAText(d.CreditorName, 70)},
+		},
+	}}
+	for _, sequenceType := range []string{SEPASequenceFirst, SEPASequenceRecurring} {
+		block, ok := blocks[sequenceType]
+		if !ok {
+			continue
+		}
+		block.ControlSum = SEPAAmount(sums[sequenceType])
+		doc.Initn.PaymentInfo = append(doc.Initn.PaymentInfo, *block)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// sepaAgentFor désigne la banque par son BIC, ou par la mention NOTPROVIDED
+// lorsqu'il n'est pas connu (l'IBAN suffit dans l'espace SEPA)
+func sepaAgentFor(bic string) sepaAgent {
+	if bic == "" {
+		return sepaAgent{Other: &sepaOther{ID: "NOTPROVIDED"}}
+	}
+	return sepaAgent{BIC: bic}
+}
+
+// parseSEPAAmount convertit un montant décimal du schéma (1234.5) en centimes
+func parseSEPAAmount(value string) (int64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(amount * 100)), nil
+}
+
+// SEPANotifiedTransaction est une opération relevée dans un avis de crédit ou de débit camt.054
+type SEPANotifiedTransaction struct {
+	EndToEndID   string
+	AmountCents  int64
+	Currency     string
+	Booked       bool   // Écriture comptabilisée (BOOK), et non simplement annoncée
+	Returned     bool   // Rejet, retour ou remboursement d'un prélèvement (R-transaction)
+	ReturnReason string // Code ISO du motif de retour (AM04, MD06...)
+	BookingDate  time.Time
+}
+
+// Structure XML, limitée aux éléments utiles, d'un avis camt.054 (toutes versions :
+// l'espace de noms n'est pas vérifié)
+type camt054Document struct {
+	Notifications []struct {
+		Entries []camt054Entry `xml:"Ntry"`
+	} `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+}
+
+type camt054Entry struct {
+	Amount          sepaAmount    `xml:"Amt"`
+	CreditDebit     string        `xml:"CdtDbtInd"`
+	Reversal        bool          `xml:"RvslInd"`
+	Status          camt054Status `xml:"Sts"`
+	BookingDate     string        `xml:"BookgDt>Dt"`
+	BookingDateTime string        `xml:"BookgDt>DtTm"`
+	Details         []struct {
+		Transactions []camt054Transaction `xml:"TxDtls"`
+	} `xml:"NtryDtls"`
+}
+
+type camt054Transaction struct {
+	EndToEndID    string      `xml:"Refs>EndToEndId"`
+	Amount        *sepaAmount `xml:"Amt"`
+	AmountDetails *sepaAmount `xml:"AmtDtls>TxAmt>Amt"`
+	CreditDebit   string      `xml:"CdtDbtInd"`
+	Return        *struct {
+		Reason string `xml:"Rsn>Cd"`
+	} `xml:"RtrInf"`
+}
+
+// camt054Status est un code direct (BOOK) jusqu'à la version 07, imbriqué dans Cd ensuite
+type camt054Status struct {
+	Code  string `xml:"Cd"`
+	Value string `xml:",chardata"`
+}
+
+// ParseCamt054 extrait les opérations d'un avis camt.054. Une opération au débit
+// du compte, ou accompagnée d'un motif de retour, est un prélèvement rejeté ou retourné.
+func ParseCamt054(content []byte) ([]SEPANotifiedTransaction, error) {
+	var doc camt054Document
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidCamt054, err)
+	}
+	var transactions []SEPANotifiedTransaction
+	for _, notification := range doc.Notifications {
+		for _, entry := range notification.Entries {
+			status := strings.TrimSpace(entry.Status.Value)
+			if entry.Status.Code != "" {
+				status = entry.Status.Code
+			}
+			bookingDate := entry.BookingDate
+			if bookingDate == "" && len(entry.BookingDateTime) >= 10 {
+				bookingDate = entry.BookingDateTime[:10]
+			}
+			booked, _ := time.ParseInLocation("2006-01-02", bookingDate, CalendarLocation())
+
+			var details []camt054Transaction
+			for _, group := range entry.Details {
+				details = append(details, group.Transactions...)
+			}
+			for _, detail := range details {
+				amount := detail.Amount
+				if amount == nil {
+					amount = detail.AmountDetails
+				}
+				if amount == nil && len(details) == 1 {
+					amount = &entry.Amount
+				}
+				if amount == nil {
+					continue
+				}
+				cents, err := parseSEPAAmount(amount.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%w : montant illisible pour l'opération %s", ErrInvalidCamt054, detail.EndToEndID)
+				}
+				creditDebit := detail.CreditDebit
+				if creditDebit == "" {
+					creditDebit = entry.CreditDebit
+				}
+				transaction := SEPANotifiedTransaction{
+					EndToEndID:  strings.TrimSpace(detail.EndToEndID),
+					AmountCents: cents,
+					Currency:    amount.Currency,
+					Booked:      status == "BOOK",
+					Returned:    detail.Return != nil || creditDebit == "DBIT" || entry.Reversal,
+					BookingDate: booked,
+				}
+				if detail.Return != nil {
+					transaction.ReturnReason = strings.TrimSpace(detail.Return.Reason)
+				}
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// sepaReturnReasons donne le libellé des motifs de rejet les plus fréquents
+var sepaReturnReasons = map[string]string{
+	"AC01": "IBAN du débiteur incorrect",
+	"AC04": "compte du débiteur clôturé",
+	"AC06": "compte du débiteur bloqué",
+	"AG01": "opération interdite sur ce compte",
+	"AG02": "code opération invalide",
+	"AM04": "provision insuffisante",
+	"AM05": "prélèvement en double",
+	"BE05": "créancier non reconnu",
+	"FF01": "format de fichier invalide",
+	"MD01": "absence de mandat",
+	"MD02": "données du mandat incomplètes ou incorrectes",
+	"MD06": "remboursement demandé par le débiteur",
+	"MD07": "débiteur décédé",
+	"MS02": "refus du débiteur",
+	"MS03": "motif non communiqué par la banque",
+	"RC01": "BIC incorrect",
+	"RR01": "identification du débiteur manquante (motif réglementaire)",
+	"RR04": "motif réglementaire",
+	"SL01": "service spécifique de la banque du débiteur",
+}
+
+// SEPAReturnReason décrit un motif de rejet de prélèvement à partir de son code ISO
+func SEPAReturnReason(code string) string {
+	if label, ok := sepaReturnReasons[code]; ok {
+		return fmt.Sprintf("%s (%s)", label, code)
+	}
+	if code == "" {
+		return "prélèvement rejeté par la banque"
+	}
+	return "prélèvement rejeté par la banque (" + code + ")"
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPain008(t *testing.T) {
+	signedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	debit := SEPADirectDebit{
+		MessageID:      "PRLV-2026-10-000001",
+		CreatedAt:      time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		CollectionDate: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+		CreditorName:   "Help Us",
+		CreditorIBAN:   "FR1420041010050500013M02606",
+		CreditorBIC:    "PSSTFRPPPAR",
+		CreditorID:     "FR72ZZZ123456",
+		Transactions: []SEPADebitTransaction{
+			{EndToEndID: "FA-2026-000002", AmountCents: 3000, SequenceType: SEPASequenceRecurring,
+				MandateID: "RUM-2", MandateSignedAt: signedAt, DebtorName: "Famille Lefèvre",
+				DebtorIBAN: "DE89370400440532013000", Remittance: "Facture FA-2026-000002"},
+			{EndToEndID: "FA-2026-000001", AmountCents: 12050, SequenceType: SEPASequenceFirst,
+				MandateID: "RUM-1", MandateSignedAt: signedAt, DebtorName: "Famille Martin",
+				DebtorIBAN: "BE68539007547034", DebtorBIC: "GKCCBEBB"},
+			{EndToEndID: "FA-2026-000003", AmountCents: 999, SequenceType: SEPASequenceRecurring,
+				MandateID: "RUM-3", MandateSignedAt: signedAt, DebtorName: "Famille Petit",
+				DebtorIBAN: "GB82WEST12345698765432"},
+		},
+	}
+	out, err := debit.Pain008()
+	if err != nil {
+		t.Fatalf("Pain008 returned error: %v", err)
+	}
+	content := string(out)
+	for _, want := range []string{
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.008.001.02">`,
+		`<LclInstrm>`,
+		`<Id>FR72ZZZ123456</Id>`,
+		`<Prtry>SEPA</Prtry>`,
+		`<Nm>Famille Lefevre</Nm>`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("pain.008 does not contain %s", want)
+		}
+	}
+
+	var doc pain008Document
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("pain.008 is not valid XML: %v", err)
+	}
+	header := doc.Initn.GroupHeader
+	if header.MessageID != "PRLV-2026-10-000001" || header.NumberOfTxs != 3 || header.ControlSum != "160.49" ||
+		header.CreationDateTime != "2026-10-18T09:30:00" {
+		t.Errorf("group header = %+v", header)
+	}
+
+	// Un bloc par type de séquence : premiers prélèvements puis récurrents
+	blocks := doc.Initn.PaymentInfo
+	if len(blocks) != 2 {
+		t.Fatalf("got %d payment blocks, want 2", len(blocks))
+	}
+	first, recurring := blocks[0], blocks[1]
+	if first.SequenceType != SEPASequenceFirst || first.NumberOfTxs != 1 || first.ControlSum != "120.50" ||
+		first.PaymentInfoID != "PRLV-2026-10-000001-FRST" {
+		t.Errorf("first block = %+v", first)
+	}
+	if recurring.SequenceType != SEPASequenceRecurring || recurring.NumberOfTxs != 2 || recurring.ControlSum != "39.99" {
+		t.Errorf("recurring block = %+v", recurring)
+	}
+	for _, block := range blocks {
+		if block.PaymentMethod != "DD" || block.LocalInstrument != "CORE" || block.ServiceLevel != "SEPA" ||
+			block.CollectionDate != "2026-10-25" || block.CreditorAgent.BIC != "PSSTFRPPPAR" ||
+			block.CreditorSchemeID != "FR72ZZZ123456" || block.CreditorAccount.IBAN != "FR1420041010050500013M02606" {
+			t.Errorf("block %s = %+v", block.SequenceType, block)
+		}
+	}
+
+	martin := first.DirectDebits[0]
+	if martin.EndToEndID != "FA-2026-000001" || martin.Amount.Value != "120.50" || martin.Amount.Currency != "EUR" ||
+		martin.MandateID != "RUM-1" || martin.MandateSignedAt != "2026-09-01" || martin.DebtorAgent.BIC != "GKCCBEBB" {
+		t.Errorf("first debit = %+v", martin)
+	}
+	if len(recurring.DirectDebits) != 2 || recurring.DirectDebits[0].EndToEndID != "FA-2026-000002" ||
+		recurring.DirectDebits[1].EndToEndID != "FA-2026-000003" {
+		t.Fatalf("recurring debits = %+v", recurring.DirectDebits)
+	}
+	petit := recurring.DirectDebits[1]
+	if petit.DebtorAgent.Other == nil || petit.DebtorAgent.Other.ID != "NOTPROVIDED" || petit.Remittance != "" {
+		t.Errorf("debit without BIC = %+v", petit)
+	}
+}
+
+func TestPain008Rejects(t *testing.T) {
+	if _, err := (&SEPADirectDebit{MessageID: "VIDE"}).Pain008(); err == nil {
+		t.Error("Pain008 of an empty batch should fail")
+	}
+	for _, tx := range []SEPADebitTransaction{
+		{EndToEndID: "NUL", AmountCents: 0, SequenceType: SEPASequenceFirst},
+		{EndToEndID: "SEQ", AmountCents: 100, SequenceType: "OOFF"},
+	} {
+		debit := SEPADirectDebit{MessageID: "KO", Transactions: []SEPADebitTransaction{tx}}
+		if _, err := debit.Pain008(); err == nil {
+			t.Errorf("Pain008 with transaction %s should fail", tx.EndToEndID)
+		}
+	}
+}
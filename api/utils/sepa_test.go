@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSEPAAmount(t *testing.T) {
+	tests := map[int64]string{0: "0.00", 5: "0.05", 4550: "45.50", 123456: "1234.56"}
+	for cents, want := range tests {
+		if got := SEPAAmount(cents); got != want {
+			t.Errorf("SEPAAmount(%d) = %q, want %q", cents, got, want)
+		}
+	}
+}
+
+func TestSEPAText(t *testing.T) {
+	tests := []struct {
+		input string
+		max   int
+		want  string
+	}{
+		{"Hélène Dûpont-Lœuvre", 70, "Helene Dupont-Loeuvre"},
+		{"Cours n°12 & co  <test>", 70, "Cours n 12 co test"},
+		{"Paiement   du   mois", 10, "Paiement d"},
+		{"abc def", 4, "abc"},
+	}
+	for _, tt := range tests {
+		if got := SEPAText(tt.input, tt.max); got != tt.want {
+			t.Errorf("SEPAText(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestPain001(t *testing.T) {
+	transfer := SEPACreditTransfer{
+		MessageID:     "VIR-2026-10-000001",
+		CreatedAt:     time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		ExecutionDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+		DebtorName:    "Help Us",
+		DebtorIBAN:    "FR1420041010050500013M02606",
+		Transactions: []SEPATransaction{
+			{EndToEndID: "RELEVE-1", AmountCents: 45050, CreditorName: "Éloïse Mårtin",
+				CreditorIBAN: "DE89370400440532013000", CreditorBIC: "COBADEFFXXX", Remittance: "Relevé octobre 2026"},
+			{EndToEndID: "RELEVE-2", AmountCents: 1999, CreditorName: "Jean Dupont",
+				CreditorIBAN: "BE68539007547034"},
+		},
+	}
+	out, err := transfer.Pain001()
+	if err != nil {
+		t.Fatalf("Pain001 returned error: %v", err)
+	}
+	content := string(out)
+	if !strings.HasPrefix(content, xml.Header) {
+		t.Errorf("missing XML header")
+	}
+	for _, want := range []string{
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">`,
+		`<CreDtTm>2026-10-18T09:30:00</CreDtTm>`,
+		`<InstdAmt Ccy="EUR">450.50</InstdAmt>`,
+		`<Nm>Eloise Martin</Nm>`,
+		`<Ustrd>Releve octobre 2026</Ustrd>`,
+		`<Id>NOTPROVIDED</Id>`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("pain.001 does not contain %s", want)
+		}
+	}
+
+	var doc pain001Document
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("pain.001 is not valid XML: %v", err)
+	}
+	header, info := doc.Initn.GroupHeader, doc.Initn.PaymentInfo
+	if header.MessageID != "VIR-2026-10-000001" || header.NumberOfTxs != 2 || header.ControlSum != "470.49" {
+		t.Errorf("group header = %+v", header)
+	}
+	if info.NumberOfTxs != 2 || info.ControlSum != "470.49" || info.ExecutionDate != "2026-10-20" ||
+		info.PaymentMethod != "TRF" || info.ServiceLevel != "SEPA" || info.ChargeBearer != "SLEV" {
+		t.Errorf("payment information = %+v", info)
+	}
+	if info.DebtorAccount.IBAN != "FR1420041010050500013M02606" {
+		t.Errorf("debtor IBAN = %q", info.DebtorAccount.IBAN)
+	}
+	if len(info.CreditTransfers) != 2 {
+		t.Fatalf("got %d credit transfers, want 2", len(info.CreditTransfers))
+	}
+	first, second := info.CreditTransfers[0], info.CreditTransfers[1]
+	if first.CreditorAgent == nil || first.CreditorAgent.BIC != "COBADEFFXXX" {
+		t.Errorf("first creditor agent = %+v, want BIC COBADEFFXXX", first.CreditorAgent)
+	}
+	if second.CreditorAgent != nil {
+		t.Errorf("second creditor agent = %+v, want none", second.CreditorAgent)
+	}
+	if second.Amount.Value != "19.99" || second.CreditorAccount.IBAN != "BE68539007547034" || second.Remittance != "" {
+		t.Errorf("second transfer = %+v", second)
+	}
+}
+
+func TestPain001Rejects(t *testing.T) {
+	if _, err := (&SEPACreditTransfer{MessageID: "VIDE"}).Pain001(); err == nil {
+		t.Error("Pain001 of an empty batch should fail")
+	}
+	transfer := SEPACreditTransfer{MessageID: "NUL", Transactions: []SEPATransaction{{EndToEndID: "X", AmountCents: 0}}}
+	if _, err := transfer.Pain001(); err == nil {
+		t.Error("Pain001 with a zero amount should fail")
+	}
+}